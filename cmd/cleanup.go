@@ -5,6 +5,7 @@ import (
 
 	"github.com/abiswas97/sentei/internal/cleanup"
 	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/report"
 )
 
 const (
@@ -25,6 +26,9 @@ func RunCleanup(args []string) error {
 		return err
 	}
 	repoPath := ParseCleanupRepoPath(args)
+	if out := newReport(ParseCleanupFormat(args)); out != nil {
+		return reportCleanup(out, opts, repoPath)
+	}
 	return RunCleanupWithOpts(opts, repoPath)
 }
 
@@ -36,6 +40,12 @@ func RunCleanupWithOpts(opts *cleanup.Options, repoPath string) error {
 	fmt.Println()
 
 	runner := &git.GitRunner{}
+	if opts.Protection == nil {
+		opts.Protection = LoadProtectionPolicy(runner, repoPath, git.DetectDefaultBranch(runner, repoPath))
+	}
+	if opts.ArchiveRetention == 0 {
+		opts.ArchiveRetention = loadArchiveConfig(runner, repoPath).ArchiveRetention()
+	}
 	result := cleanup.Run(runner, repoPath, *opts, printEvent)
 
 	fmt.Println()
@@ -51,6 +61,34 @@ func RunCleanupWithOpts(opts *cleanup.Options, repoPath string) error {
 	return nil
 }
 
+// reportCleanup is RunCleanupWithOpts for machine formats. A dry run writes
+// the cleanup preview; a real run streams events under NDJSON and finishes
+// with the cleanup result.
+func reportCleanup(out *report.Writer, opts *cleanup.Options, repoPath string) error {
+	runner := &git.GitRunner{}
+	if opts.Protection == nil {
+		opts.Protection = LoadProtectionPolicy(runner, repoPath, git.DetectDefaultBranch(runner, repoPath))
+	}
+	if opts.ArchiveRetention == 0 {
+		opts.ArchiveRetention = loadArchiveConfig(runner, repoPath).ArchiveRetention()
+	}
+
+	if opts.DryRun {
+		preview, err := cleanup.DryRun(runner, repoPath, opts.Protection, opts.ArchiveRetention)
+		if err != nil {
+			return err
+		}
+		return out.Write(report.KindCleanupPreview, report.NewCleanupPreview(preview))
+	}
+
+	result := cleanup.Run(runner, repoPath, *opts, func(e cleanup.Event) {
+		if out.Streaming() {
+			_ = out.Write(report.KindCleanupEvent, report.NewCleanupEvent(e))
+		}
+	})
+	return out.Write(report.KindCleanup, report.NewCleanupResult(result, *opts))
+}
+
 func printEvent(e cleanup.Event) {
 	switch e.Level {
 	case cleanup.LevelStep:
@@ -3,30 +3,60 @@ package cmd
 import (
 	"flag"
 	"fmt"
+	"io"
 
 	"github.com/abiswas97/sentei/internal/cleanup"
 	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/report"
 )
 
+// cleanupFlags is the cleanup command's flag set. The options and the
+// positional repo path are parsed separately, so both go through this one
+// declaration to stay in step.
+type cleanupFlags struct {
+	fs     *flag.FlagSet
+	mode   *string
+	force  *bool
+	dryRun *bool
+	format *string
+}
+
+func newCleanupFlags() *cleanupFlags {
+	fs := flag.NewFlagSet("cleanup", flag.ContinueOnError)
+	return &cleanupFlags{
+		fs:     fs,
+		mode:   fs.String("mode", "", "Cleanup mode: safe or aggressive"),
+		force:  fs.Bool("force", false, "Force-delete unmerged branches (aggressive mode)"),
+		dryRun: fs.Bool("dry-run", false, "Show what would be done without making changes"),
+		format: formatFlag(fs),
+	}
+}
+
+// CleanupFlags describes the cleanup command's flags for usage and shell
+// completion.
+func CleanupFlags() []cli.Flag {
+	return cli.FlagsOf(newCleanupFlags().fs,
+		cli.Flag{Name: "mode", Kind: cli.FlagEnum, Values: []string{string(cleanup.ModeSafe), string(cleanup.ModeAggressive)}})
+}
+
 // ParseCleanupFlags parses cleanup-specific flags and returns CleanupOptions.
 // Returns an error if validation fails (e.g., invalid mode).
 func ParseCleanupFlags(args []string) (*cleanup.Options, error) {
-	fs := flag.NewFlagSet("cleanup", flag.ContinueOnError)
-	mode := fs.String("mode", "", "Cleanup mode: safe or aggressive")
-	force := fs.Bool("force", false, "Force-delete unmerged branches (aggressive mode)")
-	dryRun := fs.Bool("dry-run", false, "Show what would be done without making changes")
-
-	if err := fs.Parse(args); err != nil {
+	f := newCleanupFlags()
+	if err := f.fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if _, err := report.ParseFormat(*f.format); err != nil {
 		return nil, err
 	}
 
 	opts := &cleanup.Options{
-		Force:  *force,
-		DryRun: *dryRun,
+		Force:  *f.force,
+		DryRun: *f.dryRun,
 	}
 
-	if *mode != "" {
-		m := cleanup.Mode(*mode)
+	if *f.mode != "" {
+		m := cleanup.Mode(*f.mode)
 		if m != cleanup.ModeSafe && m != cleanup.ModeAggressive {
 			return nil, fmt.Errorf("invalid value for --mode: must be 'safe' or 'aggressive'")
 		}
@@ -38,17 +68,28 @@ func ParseCleanupFlags(args []string) (*cleanup.Options, error) {
 
 // ParseCleanupRepoPath extracts the positional repo path from cleanup args.
 func ParseCleanupRepoPath(args []string) string {
-	fs := flag.NewFlagSet("cleanup", flag.ContinueOnError)
-	fs.String("mode", "", "")
-	fs.Bool("force", false, "")
-	fs.Bool("dry-run", false, "")
-	_ = fs.Parse(args)
-	if fs.NArg() > 0 {
-		return fs.Arg(0)
+	f := newCleanupFlags()
+	f.fs.SetOutput(io.Discard)
+	_ = f.fs.Parse(args)
+	if f.fs.NArg() > 0 {
+		return f.fs.Arg(0)
 	}
 	return "."
 }
 
+// ParseCleanupFormat extracts the --format value from cleanup args. Invalid
+// values fall back to text; ParseCleanupFlags reports them.
+func ParseCleanupFormat(args []string) report.Format {
+	f := newCleanupFlags()
+	f.fs.SetOutput(io.Discard)
+	_ = f.fs.Parse(args)
+	format, err := report.ParseFormat(*f.format)
+	if err != nil {
+		return report.FormatText
+	}
+	return format
+}
+
 // ValidateCleanupForNonInteractive checks that all required flags are present
 // for non-interactive execution.
 func ValidateCleanupForNonInteractive(opts *cleanup.Options) error {
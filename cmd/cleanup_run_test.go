@@ -111,7 +111,7 @@ func TestCleanupDryRun_RealRepo(t *testing.T) {
 	// Drop the worktree but keep the branch: a real aggressive candidate.
 	mustGit(t, bareRepo, "worktree", "remove", "--force", filepath.Join(bareRepo, "feature-merged-branch"))
 
-	result, err := cleanup.DryRun(&git.GitRunner{}, bareRepo)
+	result, err := cleanup.DryRun(&git.GitRunner{}, bareRepo, nil, 0)
 	if err != nil {
 		t.Fatalf("DryRun() error: %v", err)
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/abiswas97/sentei/internal/git"
 	"github.com/abiswas97/sentei/internal/progress"
 	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/report"
 )
 
 // RunClone executes the clone command in non-interactive mode.
@@ -38,6 +39,20 @@ func RunClone(args []string) error {
 		Name:     name,
 	}
 
+	if out := newReport(opts.Format); out != nil {
+		result := repo.Clone(runner, cloneOpts, out.Events())
+		if err := out.Write(report.KindClone, report.NewCloneResult(result)); err != nil {
+			return err
+		}
+		if err := cloneResultError(result); err != nil {
+			return err
+		}
+		if progress.PhasesHaveFailures(result.Phases) {
+			return fmt.Errorf("clone failed")
+		}
+		return nil
+	}
+
 	result := repo.Clone(runner, cloneOpts, printCloneEvent)
 	if err := cloneResultError(result); err != nil {
 		return err
@@ -5,27 +5,57 @@ import (
 	"fmt"
 
 	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/report"
 )
 
 // CloneOptions holds the parsed flags for the clone command.
 type CloneOptions struct {
-	URL  string
-	Name string
+	URL    string
+	Name   string
+	Format report.Format
+}
+
+// cloneFlags is the clone command's flag set, shared by the parser and the
+// completion metadata.
+type cloneFlags struct {
+	fs     *flag.FlagSet
+	url    *string
+	name   *string
+	format *string
+}
+
+func newCloneFlags() *cloneFlags {
+	fs := flag.NewFlagSet("clone", flag.ContinueOnError)
+	return &cloneFlags{
+		fs:     fs,
+		url:    fs.String("url", "", "Git repository URL to clone"),
+		name:   fs.String("name", "", "Directory name for the cloned repo (derived from URL if empty)"),
+		format: formatFlag(fs),
+	}
+}
+
+// CloneFlags describes the clone command's flags for usage and shell
+// completion.
+func CloneFlags() []cli.Flag {
+	return cli.FlagsOf(newCloneFlags().fs)
 }
 
 // ParseCloneFlags parses clone-specific flags and returns CloneOptions.
 func ParseCloneFlags(args []string) (*CloneOptions, error) {
-	fs := flag.NewFlagSet("clone", flag.ContinueOnError)
-	url := fs.String("url", "", "Git repository URL to clone")
-	name := fs.String("name", "", "Directory name for the cloned repo (derived from URL if empty)")
+	fl := newCloneFlags()
+	if err := fl.fs.Parse(args); err != nil {
+		return nil, err
+	}
 
-	if err := fs.Parse(args); err != nil {
+	f, err := report.ParseFormat(*fl.format)
+	if err != nil {
 		return nil, err
 	}
 
 	return &CloneOptions{
-		URL:  *url,
-		Name: *name,
+		URL:    *fl.url,
+		Name:   *fl.name,
+		Format: f,
 	}, nil
 }
 
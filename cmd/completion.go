@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/config"
+	"github.com/abiswas97/sentei/internal/ecosystem"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/repo"
+)
+
+// RunCompletion prints the completion script for the shell named in args,
+// generated from the registry's commands and flags. The scripts call back
+// with `completion __values <kind>` for branch and ecosystem names.
+func RunCompletion(registry *cli.Registry, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: sentei completion %s", strings.Join(cli.Shells, "|"))
+	}
+	if args[0] == cli.ValuesCommand {
+		if len(args) < 2 {
+			return errors.New("missing completion value kind")
+		}
+		values, err := completionValues(&git.GitRunner{}, ".", args[1])
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			fmt.Println(v)
+		}
+		return nil
+	}
+
+	script, err := registry.CompletionScript(args[0])
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(os.Stdout, script)
+	return err
+}
+
+// completionValues lists candidates of kind ("branches", "ecosystems" or
+// "profiles") for the repository at dir. Outside a repository branches are
+// empty, ecosystems are the built-in ones and profiles the global ones.
+func completionValues(runner git.CommandRunner, dir, kind string) ([]string, error) {
+	repoPath := dir
+	if absPath, err := filepath.Abs(repoPath); err == nil {
+		repoPath = absPath
+	}
+	if repo.DetectContext(runner, repoPath) == repo.ContextBareRepo {
+		repoPath = repo.ResolveBareRoot(runner, repoPath)
+	}
+
+	switch kind {
+	case "branches":
+		out, err := runner.Run(repoPath, "for-each-ref", "--format=%(refname:short)", "refs/heads/")
+		if err != nil {
+			return nil, nil
+		}
+		var branches []string
+		for _, line := range strings.Split(out, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				branches = append(branches, line)
+			}
+		}
+		return branches, nil
+
+	case "ecosystems":
+		cfg, err := config.LoadConfig(repoPath)
+		if err != nil {
+			return nil, fmt.Errorf("loading config: %w", err)
+		}
+		var names []string
+		for _, eco := range ecosystem.NewRegistry(cfg.Ecosystems).All() {
+			names = append(names, eco.Name)
+		}
+		return names, nil
+
+	case "profiles":
+		cfg, err := config.LoadConfig(repoPath)
+		if err != nil {
+			return nil, fmt.Errorf("loading config: %w", err)
+		}
+		return cfg.ProfileNames(), nil
+	}
+	return nil, fmt.Errorf("unknown completion value kind %q: must be branches, ecosystems or profiles", kind)
+}
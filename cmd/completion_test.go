@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/git"
+)
+
+func TestCompletionValues(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+	runner := &git.GitRunner{}
+
+	// From inside a worktree, as when completing in one.
+	branches, err := completionValues(runner, filepath.Join(bareRepo, "feature-merged-branch"), "branches")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(branches, "main") || !slices.Contains(branches, "feature/merged-branch") {
+		t.Errorf("branches = %v, want main and feature/merged-branch", branches)
+	}
+
+	ecosystems, err := completionValues(runner, bareRepo, "ecosystems")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(ecosystems, "cargo") {
+		t.Errorf("ecosystems = %v, want the built-in cargo ecosystem", ecosystems)
+	}
+
+	if _, err := completionValues(runner, bareRepo, "colours"); err == nil {
+		t.Error("an unknown kind should be an error")
+	}
+}
+
+func TestRunCompletion_UnknownShell(t *testing.T) {
+	if err := RunCompletion(cli.NewRegistry(), []string{"tcsh"}); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
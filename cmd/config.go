@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/config"
+	"github.com/abiswas97/sentei/internal/ecosystem"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/integration"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// RunConfig shows, validates or scaffolds the sentei configuration.
+func RunConfig(args []string) error {
+	opts, err := ParseConfigFlags(args)
+	if err != nil {
+		return err
+	}
+	return runConfig(&git.GitRunner{}, opts, os.Stdout)
+}
+
+func runConfig(runner git.CommandRunner, opts *ConfigOptions, out io.Writer) error {
+	repoPath := opts.RepoPath
+	if absPath, err := filepath.Abs(repoPath); err == nil {
+		repoPath = absPath
+	}
+
+	switch opts.Action {
+	case "show":
+		return runConfigShow(runner, repoPath, opts.Format, out)
+	case "validate":
+		return runConfigValidate(runner, repoPath, opts.Format, out)
+	case "init":
+		return runConfigInit(runner, repoPath, opts.Overwrite, out)
+	}
+	return fmt.Errorf("unknown config action %q", opts.Action)
+}
+
+// runConfigShow prints the merged config, each field annotated with the
+// layer it came from.
+func runConfigShow(runner git.CommandRunner, repoPath string, format report.Format, out io.Writer) error {
+	exp, err := config.Explain(repoPath, config.WithRunner(runner))
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	if format.Machine() {
+		values, err := exp.Values()
+		if err != nil {
+			return err
+		}
+		doc := report.Config{Values: values, Sources: exp.Sources}
+		for _, l := range exp.Layers {
+			doc.Layers = append(doc.Layers, report.ConfigLayer{Source: l.Source, Path: l.Path, Found: l.Found})
+		}
+		return report.NewWriter(out, format).Write(report.KindConfig, doc)
+	}
+
+	data, err := exp.YAML()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "# Layers, later overriding earlier:")
+	for _, l := range exp.Layers {
+		switch {
+		case l.Path == "":
+			fmt.Fprintf(out, "#   %-9s built in\n", l.Source)
+		case l.Found:
+			fmt.Fprintf(out, "#   %-9s %s\n", l.Source, l.Path)
+		default:
+			fmt.Fprintf(out, "#   %-9s %s (not found)\n", l.Source, l.Path)
+		}
+	}
+	fmt.Fprintf(out, "# Each field is annotated with its layer; %q means no layer set it.\n\n", config.SourceDefault)
+	_, err = out.Write(data)
+	return err
+}
+
+// runConfigValidate checks the config files strictly and fails when it finds
+// anything.
+func runConfigValidate(runner git.CommandRunner, repoPath string, format report.Format, out io.Writer) error {
+	problems, err := config.Check(repoPath, config.WithRunner(runner), config.WithKnownIntegrations(integration.Names()))
+	if err != nil {
+		return err
+	}
+
+	if format.Machine() {
+		doc := report.ConfigCheck{Valid: len(problems) == 0, Problems: []report.ConfigProblem{}}
+		for _, p := range problems {
+			doc.Problems = append(doc.Problems, report.ConfigProblem{Path: p.Path, Message: p.Message})
+		}
+		if err := report.NewWriter(out, format).Write(report.KindConfigCheck, doc); err != nil {
+			return err
+		}
+	} else if len(problems) == 0 {
+		fmt.Fprintln(out, "Config is valid.")
+	} else {
+		for _, p := range problems {
+			fmt.Fprintf(out, "  %s\n", p)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("config has %d problem(s)", len(problems))
+	}
+	return nil
+}
+
+// runConfigInit writes a commented .sentei.yaml at the repository root,
+// listing the ecosystems detected in the current worktree.
+func runConfigInit(runner git.CommandRunner, repoPath string, overwrite bool, out io.Writer) error {
+	path := config.RepoConfigPath(repoPath, config.WithRunner(runner))
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return fmt.Errorf("%s already exists (use --overwrite to replace it)", path)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(repoPath, config.WithRunner(runner))
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	// Detect in the worktree's top level; a bare repository root has no
+	// files, so it detects nothing.
+	worktreeDir := repoPath
+	if top, err := runner.Run(repoPath, "rev-parse", "--show-toplevel"); err == nil && strings.TrimSpace(top) != "" {
+		worktreeDir = strings.TrimSpace(top)
+	}
+	detected, err := ecosystem.NewRegistry(cfg.Ecosystems).Detect(worktreeDir)
+	if err != nil {
+		return err
+	}
+
+	ecosystems := make([]config.EcosystemConfig, len(detected))
+	names := make([]string, len(detected))
+	for i, eco := range detected {
+		ecosystems[i] = eco.Config
+		names[i] = eco.Name
+	}
+	if err := os.WriteFile(path, config.Scaffold(ecosystems), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	fmt.Fprintf(out, "Wrote %s\n", path)
+	if len(names) > 0 {
+		fmt.Fprintf(out, "Detected ecosystems: %s\n", strings.Join(names, ", "))
+	} else {
+		fmt.Fprintln(out, "No ecosystems detected; the file lists every setting commented out.")
+	}
+	return nil
+}
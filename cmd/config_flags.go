@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// ConfigActions are the config command's subcommands.
+var ConfigActions = []string{"show", "validate", "init"}
+
+// ConfigOptions holds parsed flags for the config command.
+type ConfigOptions struct {
+	Action    string // one of ConfigActions
+	Format    report.Format
+	Overwrite bool
+	RepoPath  string
+}
+
+// configFlags is the config command's flag set, shared by the parser and
+// the completion metadata.
+type configFlags struct {
+	fs        *flag.FlagSet
+	format    *string
+	overwrite *bool
+}
+
+func newConfigFlags() *configFlags {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	return &configFlags{
+		fs:        fs,
+		format:    formatFlag(fs),
+		overwrite: fs.Bool("overwrite", false, "Let init replace an existing .sentei.yaml"),
+	}
+}
+
+// ConfigFlags describes the config command's flags for usage and shell
+// completion.
+func ConfigFlags() []cli.Flag {
+	return cli.FlagsOf(newConfigFlags().fs)
+}
+
+// ParseConfigFlags parses `config <action> [flags] [repo]`.
+func ParseConfigFlags(args []string) (*ConfigOptions, error) {
+	usage := fmt.Errorf("usage: sentei config %s [flags] [repo]", strings.Join(ConfigActions, "|"))
+	if len(args) == 0 {
+		return nil, usage
+	}
+	if !slices.Contains(ConfigActions, args[0]) {
+		return nil, fmt.Errorf("unknown config action %q: %w", args[0], usage)
+	}
+
+	fl := newConfigFlags()
+	if err := fl.fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	format, err := report.ParseFormat(*fl.format)
+	if err != nil {
+		return nil, err
+	}
+	if args[0] == "init" && format.Machine() {
+		return nil, fmt.Errorf("--format json/ndjson is not supported by config init")
+	}
+	if *fl.overwrite && args[0] != "init" {
+		return nil, fmt.Errorf("--overwrite only applies to config init")
+	}
+
+	opts := &ConfigOptions{
+		Action:    args[0],
+		Format:    format,
+		Overwrite: *fl.overwrite,
+		RepoPath:  ".",
+	}
+	if fl.fs.NArg() > 0 {
+		opts.RepoPath = fl.fs.Arg(0)
+	}
+	return opts, nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+func TestParseConfigFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    ConfigOptions
+		wantErr string
+	}{
+		{name: "show", args: []string{"show"}, want: ConfigOptions{Action: "show", Format: report.FormatText, RepoPath: "."}},
+		{name: "validate json with path", args: []string{"validate", "--format", "json", "/repo"}, want: ConfigOptions{Action: "validate", Format: report.FormatJSON, RepoPath: "/repo"}},
+		{name: "init overwrite", args: []string{"init", "--overwrite"}, want: ConfigOptions{Action: "init", Format: report.FormatText, Overwrite: true, RepoPath: "."}},
+		{name: "missing action", args: nil, wantErr: "usage"},
+		{name: "unknown action", args: []string{"edit"}, wantErr: `unknown config action "edit"`},
+		{name: "overwrite outside init", args: []string{"show", "--overwrite"}, wantErr: "only applies to config init"},
+		{name: "init json", args: []string{"init", "--format", "json"}, wantErr: "not supported"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseConfigFlags(tc.args)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != tc.want {
+				t.Errorf("got %+v, want %+v", *got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunConfig_InitDetectsEcosystemsAndKeepsExistingFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	repoDir := setupNonBareRepo(t)
+	mustWriteFile(t, filepath.Join(repoDir, "go.mod"), "module example\n")
+	var out bytes.Buffer
+
+	opts := &ConfigOptions{Action: "init", RepoPath: repoDir}
+	if err := runConfig(&git.GitRunner{}, opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Detected ecosystems: go") {
+		t.Errorf("init should report the detected ecosystems, got:\n%s", out.String())
+	}
+	data, err := os.ReadFile(filepath.Join(repoDir, ".sentei.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "  - name: go\n") {
+		t.Errorf("scaffold should list go:\n%s", data)
+	}
+
+	if err := runConfig(&git.GitRunner{}, opts, &out); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("second init should refuse to overwrite, got %v", err)
+	}
+	opts.Overwrite = true
+	if err := runConfig(&git.GitRunner{}, opts, &out); err != nil {
+		t.Errorf("init --overwrite: %v", err)
+	}
+
+	out.Reset()
+	if err := runConfig(&git.GitRunner{}, &ConfigOptions{Action: "validate", RepoPath: repoDir}, &out); err != nil {
+		t.Errorf("the scaffold should validate, got %v:\n%s", err, out.String())
+	}
+}
+
+func TestRunConfig_ValidateFailsOnProblems(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	repoDir := setupNonBareRepo(t)
+	mustWriteFile(t, filepath.Join(repoDir, ".sentei.yaml"), "protected_branchs: [main]\nintegrations_enabled: [future-tool]\n")
+	var out bytes.Buffer
+
+	err := runConfig(&git.GitRunner{}, &ConfigOptions{Action: "validate", Format: report.FormatJSON, RepoPath: repoDir}, &out)
+	if err == nil || !strings.Contains(err.Error(), "2 problem(s)") {
+		t.Fatalf("error = %v, want 2 problems", err)
+	}
+
+	var rec struct {
+		Kind string             `json:"kind"`
+		Data report.ConfigCheck `json:"data"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &rec); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if rec.Kind != report.KindConfigCheck || rec.Data.Valid || len(rec.Data.Problems) != 2 {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+	if !strings.Contains(rec.Data.Problems[0].Message, `unknown key "protected_branchs"`) {
+		t.Errorf("first problem = %q, want the unknown key", rec.Data.Problems[0].Message)
+	}
+}
+
+func TestRunConfig_ShowAnnotatesSources(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	repoDir := setupNonBareRepo(t)
+	mustWriteFile(t, filepath.Join(repoDir, ".sentei.yaml"), "protected_branches: [\"release/*\"]\n")
+	var out bytes.Buffer
+
+	if err := runConfig(&git.GitRunner{}, &ConfigOptions{Action: "show", RepoPath: repoDir}, &out); err != nil {
+		t.Fatal(err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"per-repo  " + filepath.Join(repoDir, ".sentei.yaml"),
+		"(not found)",
+		"protected_branches: [release/*] # per-repo",
+		"archive_retention_days: 30 # default",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("show output missing %q:\n%s", want, text)
+		}
+	}
+
+	out.Reset()
+	if err := runConfig(&git.GitRunner{}, &ConfigOptions{Action: "show", Format: report.FormatJSON, RepoPath: repoDir}, &out); err != nil {
+		t.Fatal(err)
+	}
+	var rec struct {
+		Data report.Config `json:"data"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &rec); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if rec.Data.Sources["protected_branches"] != "per-repo" || rec.Data.Values["archive_retention_days"] != float64(30) {
+		t.Errorf("unexpected document: sources=%v values[archive_retention_days]=%v", rec.Data.Sources["protected_branches"], rec.Data.Values["archive_retention_days"])
+	}
+}
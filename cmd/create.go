@@ -4,13 +4,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/abiswas97/sentei/internal/config"
 	"github.com/abiswas97/sentei/internal/creator"
 	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/integration"
 	"github.com/abiswas97/sentei/internal/progress"
 	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/report"
 )
 
 // RunCreate executes the create worktree command in non-interactive mode.
@@ -19,8 +22,11 @@ func RunCreate(args []string) error {
 	if err != nil {
 		return err
 	}
-	if err := ValidateCreateForNonInteractive(opts); err != nil {
-		return err
+	// A profile may supply --base, so validation waits for it.
+	if opts.Profile == "" {
+		if err := ValidateCreateForNonInteractive(opts); err != nil {
+			return err
+		}
 	}
 
 	repoPath := "."
@@ -38,26 +44,71 @@ func RunCreate(args []string) error {
 	if context != repo.ContextBareRepo {
 		return fmt.Errorf("create requires a bare repository (detected: %v)", context)
 	}
+	if opts.FromFile != "" {
+		return runCreateBatch(runner, shell, &repo.DefaultGhRunner{}, repoPath, opts, os.Stdin, os.Stdout)
+	}
+
+	var cfg *config.Config
+	if opts.Profile != "" {
+		cfg, err = config.LoadConfig(repoPath,
+			config.WithRunner(runner),
+			config.WithKnownIntegrations(integration.Names()),
+		)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		profile, err := cfg.Profile(opts.Profile)
+		if err != nil {
+			return err
+		}
+		opts = opts.WithProfile(profile)
+		if err := ValidateCreateForNonInteractive(opts); err != nil {
+			return fmt.Errorf("%w (profile %q does not set it)", err, opts.Profile)
+		}
+	}
+
+	source, err := creator.ResolveSource(runner, &repo.DefaultGhRunner{}, repoPath, opts.Track, opts.PR)
+	if err != nil {
+		return err
+	}
 
 	// Build creator.Options from CLI flags.
 	creatorOpts := creator.Options{
 		BranchName:   opts.Branch,
 		BaseBranch:   opts.Base,
+		Source:       source,
 		RepoPath:     repoPath,
 		MergeBase:    opts.MergeBase,
 		CopyEnvFiles: opts.CopyEnv,
 	}
+	from := opts.Base
+	if source != nil {
+		if creatorOpts.BranchName == "" {
+			creatorOpts.BranchName = source.Branch
+		}
+		from = source.Label
+	}
+	if opts.Profile != "" {
+		from += fmt.Sprintf(" (profile %s)", opts.Profile)
+	}
 
 	// Resolve ecosystems from config if requested.
-	if len(opts.Ecosystems) > 0 {
-		cfg, err := config.LoadConfig(repoPath,
+	if len(opts.Ecosystems) > 0 && cfg == nil {
+		cfg, err = config.LoadConfig(repoPath,
 			config.WithRunner(runner),
 		)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to load config: %v\n", err)
 		}
-		if cfg != nil {
-			creatorOpts.Ecosystems = matchEcosystems(cfg.Ecosystems, opts.Ecosystems)
+	}
+	if len(opts.Ecosystems) > 0 && cfg != nil {
+		creatorOpts.Ecosystems = matchEcosystems(cfg.Ecosystems, opts.Ecosystems)
+	}
+	// Only a profile names integrations, so cfg is loaded.
+	if len(opts.Integrations) > 0 {
+		creatorOpts.Integrations, err = matchIntegrations(integration.Resolve(cfg.Integrations), opts.Integrations)
+		if err != nil {
+			return fmt.Errorf("profile %q: %w", opts.Profile, err)
 		}
 	}
 
@@ -69,7 +120,15 @@ func RunCreate(args []string) error {
 		}
 	}
 
-	fmt.Printf("Creating worktree %q from %s...\n", opts.Branch, opts.Base)
+	if out := newReport(opts.Format); out != nil {
+		result := creator.Run(runner, shell, creatorOpts, out.Events())
+		if err := out.Write(report.KindCreate, report.NewCreateResult(result)); err != nil {
+			return err
+		}
+		return createResultError(result)
+	}
+
+	fmt.Printf("Creating worktree %q from %s...\n", creatorOpts.BranchName, from)
 
 	result := creator.Run(runner, shell, creatorOpts, func(e progress.Event) {
 		printCreateEvent(e)
@@ -80,6 +139,9 @@ func RunCreate(args []string) error {
 	}
 
 	fmt.Printf("\n%sWorktree created:%s %s\n", green, nc, result.WorktreePath)
+	for _, f := range result.EnvFiles {
+		fmt.Printf("  %senv%s %s\n", dim, nc, f)
+	}
 	return nil
 }
 
@@ -129,6 +191,21 @@ func matchEcosystems(available []config.EcosystemConfig, requested []string) []c
 	return matched
 }
 
+// matchIntegrations picks the named integrations, in the order given. Unlike
+// ecosystems, an unknown name is an error: it would silently skip a setup
+// the profile asked for.
+func matchIntegrations(available []integration.Integration, names []string) ([]integration.Integration, error) {
+	matched := make([]integration.Integration, 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(available, func(integ integration.Integration) bool { return integ.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown integration %q", name)
+		}
+		matched = append(matched, available[i])
+	}
+	return matched, nil
+}
+
 // findSource picks a source worktree for env file copying (prefers main/master).
 func findSource(worktrees []git.Worktree) string {
 	for _, wt := range worktrees {
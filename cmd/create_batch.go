@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/abiswas97/sentei/internal/config"
+	"github.com/abiswas97/sentei/internal/creator"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// runCreateBatch creates every worktree the --from-file manifest declares
+// under one plan, then prints which were created. It fails when any entry
+// did.
+func runCreateBatch(runner git.CommandRunner, shell git.ShellRunner, gh repo.GhRunner, repoPath string, opts *CreateOptions, stdin io.Reader, out io.Writer) error {
+	data, err := readCreateManifest(opts.FromFile, stdin)
+	if err != nil {
+		return err
+	}
+	entries, err := ParseCreateManifest(data)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(repoPath, config.WithRunner(runner))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load config: %v\n", err)
+	}
+	sourceWorktree := ""
+	for _, entry := range entries {
+		if entry.CopyEnv {
+			if worktrees, err := git.ListWorktrees(runner, repoPath); err == nil {
+				sourceWorktree = findSource(worktrees)
+			}
+			break
+		}
+	}
+
+	batch := make([]creator.Options, 0, len(entries))
+	for i, entry := range entries {
+		source, err := creator.ResolveSource(runner, gh, repoPath, entry.Track, entry.PR)
+		if err != nil {
+			return fmt.Errorf("manifest entry %d: %w", i+1, err)
+		}
+		creatorOpts := creator.Options{
+			BranchName:   entry.Branch,
+			BaseBranch:   entry.Base,
+			Source:       source,
+			RepoPath:     repoPath,
+			MergeBase:    entry.MergeBase,
+			CopyEnvFiles: entry.CopyEnv,
+		}
+		if cfg != nil && len(entry.Ecosystems) > 0 {
+			creatorOpts.Ecosystems = matchEcosystems(cfg.Ecosystems, entry.Ecosystems)
+		}
+		if entry.CopyEnv {
+			creatorOpts.SourceWorktree = sourceWorktree
+		}
+		batch = append(batch, creatorOpts)
+	}
+
+	if opts.Format.Machine() {
+		w := report.NewWriter(out, opts.Format)
+		result := creator.RunBatch(runner, shell, batch, w.Events())
+		if err := w.Write(report.KindCreateBatch, report.NewCreateBatchResult(result)); err != nil {
+			return err
+		}
+		return createBatchError(result)
+	}
+
+	fmt.Fprintf(out, "Creating %d worktree(s), up to %d at a time...\n", len(batch), creator.BatchConcurrency)
+	result := creator.RunBatch(runner, shell, batch, func(e progress.Event) {
+		printCreateBatchEvent(out, e)
+	})
+	if result.Err == nil {
+		printCreateBatchSummary(out, result)
+	}
+	return createBatchError(result)
+}
+
+func createBatchError(result creator.BatchResult) error {
+	if result.Err != nil {
+		return fmt.Errorf("create failed: %w", result.Err)
+	}
+	failed := 0
+	for _, wt := range result.Worktrees {
+		if wt.HasFailures() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d worktree(s) completed with errors", failed, len(result.Worktrees))
+	}
+	return nil
+}
+
+// printCreateBatchEvent prints one transition. Worktrees are created
+// concurrently, so every line names its phase, which carries the branch.
+func printCreateBatchEvent(out io.Writer, e progress.Event) {
+	if e.Close {
+		return
+	}
+	switch e.Status {
+	case progress.StepRunning:
+		fmt.Fprintf(out, "%s→%s %s · %s\n", blue, nc, e.PhaseLabel, e.StepLabel)
+	case progress.StepDone:
+		fmt.Fprintf(out, "%s✓%s %s · %s\n", green, nc, e.PhaseLabel, e.StepLabel)
+	case progress.StepFailed:
+		msg := ""
+		if e.Error != nil {
+			msg = " — " + e.Error.Error()
+		}
+		fmt.Fprintf(out, "%s✗%s %s · %s%s\n", yellow, nc, e.PhaseLabel, e.StepLabel, msg)
+	case progress.StepSkipped:
+		fmt.Fprintf(out, "  %s%s · %s (skipped)%s\n", dim, e.PhaseLabel, e.StepLabel, nc)
+	}
+}
+
+// printCreateBatchSummary lists every entry with its worktree, or with the
+// first thing that went wrong.
+func printCreateBatchSummary(out io.Writer, result creator.BatchResult) {
+	created := 0
+	for _, wt := range result.Worktrees {
+		if wt.WorktreePath != "" {
+			created++
+		}
+	}
+	fmt.Fprintf(out, "\nCreated %d of %d worktree(s):\n", created, len(result.Worktrees))
+	for _, wt := range result.Worktrees {
+		switch {
+		case !wt.HasFailures():
+			fmt.Fprintf(out, "  %s✓%s %s  %s\n", green, nc, wt.Branch, wt.WorktreePath)
+		case wt.WorktreePath != "":
+			fmt.Fprintf(out, "  %s~%s %s  %s — %s\n", yellow, nc, wt.Branch, wt.WorktreePath, batchProblem(wt))
+		default:
+			fmt.Fprintf(out, "  %s✗%s %s — %s\n", yellow, nc, wt.Branch, batchProblem(wt))
+		}
+	}
+}
+
+// batchProblem describes an entry's first failed step, or the reason its
+// creation was skipped.
+func batchProblem(wt creator.BatchWorktree) string {
+	if _, step, ok := progress.FirstFailure(wt.Phases); ok {
+		if step.Error != nil {
+			return fmt.Sprintf("%s: %v", step.Name, step.Error)
+		}
+		return step.Name + " failed"
+	}
+	for _, phase := range wt.Phases {
+		for _, step := range phase.Steps {
+			if step.Status == progress.StepSkipped && step.Message != "" {
+				return step.Message
+			}
+		}
+	}
+	return "not created"
+}
@@ -3,55 +3,164 @@ package cmd
 import (
 	"flag"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/config"
+	"github.com/abiswas97/sentei/internal/report"
 )
 
 // CreateOptions holds parsed flags for the create command.
 type CreateOptions struct {
 	Branch     string
 	Base       string
+	Track      string // remote branch to start from, e.g. origin/feature-x
+	PR         int    // pull request to start from
 	Ecosystems []string
 	MergeBase  bool
 	CopyEnv    bool
-	RepoPath   string // positional arg: path to bare repo
+	Profile    string // named set of the options above, from the config
+	// Integrations to set up, from the profile; nil sets up none.
+	Integrations []string
+	RepoPath     string // positional arg: path to bare repo
+	FromFile     string // manifest declaring several worktrees; "-" for stdin
+	Format       report.Format
+
+	explicit map[string]bool // flags given on the command line
+}
+
+// createFlags is the create command's flag set, shared by the parser and
+// the completion metadata.
+type createFlags struct {
+	fs         *flag.FlagSet
+	branch     *string
+	base       *string
+	track      *string
+	pr         *int
+	ecosystems *string
+	mergeBase  *bool
+	copyEnv    *bool
+	profile    *string
+	fromFile   *string
+	format     *string
+}
+
+func newCreateFlags() *createFlags {
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	return &createFlags{
+		fs:         fs,
+		branch:     fs.String("branch", "", "Branch name for the new worktree"),
+		base:       fs.String("base", "", "Base branch to create from"),
+		track:      fs.String("track", "", "Remote branch to check out and track, e.g. origin/feature-x"),
+		pr:         fs.Int("pr", 0, "Pull request number to check out, resolved through gh"),
+		ecosystems: fs.String("ecosystems", "", "Comma-separated list of ecosystems to install"),
+		mergeBase:  fs.Bool("merge-base", false, "Merge base branch into the new worktree"),
+		copyEnv:    fs.Bool("copy-env", false, "Copy environment files from source worktree"),
+		profile:    fs.String("profile", "", "Profile from the config supplying defaults for the other flags"),
+		fromFile:   fs.String("from-file", "", "YAML manifest of worktrees to create together; - reads stdin"),
+		format:     formatFlag(fs),
+	}
+}
+
+// CreateFlags describes the create command's flags for usage and shell
+// completion.
+func CreateFlags() []cli.Flag {
+	return cli.FlagsOf(newCreateFlags().fs,
+		cli.Flag{Name: "branch", Kind: cli.FlagBranch},
+		cli.Flag{Name: "base", Kind: cli.FlagBranch},
+		cli.Flag{Name: "from-file", Kind: cli.FlagPath},
+		cli.Flag{Name: "profile", Kind: cli.FlagProfile},
+		cli.Flag{Name: "ecosystems", Kind: cli.FlagEcosystems})
 }
 
 // ParseCreateFlags parses create-specific flags and returns CreateOptions.
 func ParseCreateFlags(args []string) (*CreateOptions, error) {
-	fs := flag.NewFlagSet("create", flag.ContinueOnError)
-	branch := fs.String("branch", "", "Branch name for the new worktree")
-	base := fs.String("base", "", "Base branch to create from")
-	ecosystems := fs.String("ecosystems", "", "Comma-separated list of ecosystems to install")
-	mergeBase := fs.Bool("merge-base", false, "Merge base branch into the new worktree")
-	copyEnv := fs.Bool("copy-env", false, "Copy environment files from source worktree")
+	fl := newCreateFlags()
+	if err := fl.fs.Parse(args); err != nil {
+		return nil, err
+	}
 
-	if err := fs.Parse(args); err != nil {
+	f, err := report.ParseFormat(*fl.format)
+	if err != nil {
 		return nil, err
 	}
 
+	sources := 0
+	for _, set := range []bool{*fl.base != "", *fl.track != "", *fl.pr != 0} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return nil, fmt.Errorf("--base, --track and --pr are mutually exclusive")
+	}
+	if *fl.pr < 0 {
+		return nil, fmt.Errorf("invalid --pr %d", *fl.pr)
+	}
+	if *fl.mergeBase && (*fl.track != "" || *fl.pr != 0) {
+		return nil, fmt.Errorf("--merge-base only applies with --base")
+	}
+	if *fl.fromFile != "" && (*fl.branch != "" || sources > 0 || *fl.ecosystems != "" || *fl.mergeBase || *fl.copyEnv || *fl.profile != "") {
+		return nil, fmt.Errorf("--from-file declares every worktree's options; it cannot be combined with other create flags")
+	}
+
 	opts := &CreateOptions{
-		Branch:    *branch,
-		Base:      *base,
-		MergeBase: *mergeBase,
-		CopyEnv:   *copyEnv,
+		Branch:    *fl.branch,
+		Base:      *fl.base,
+		Track:     *fl.track,
+		PR:        *fl.pr,
+		MergeBase: *fl.mergeBase,
+		CopyEnv:   *fl.copyEnv,
+		Profile:   *fl.profile,
+		FromFile:  *fl.fromFile,
+		Format:    f,
+		explicit:  make(map[string]bool),
 	}
+	fl.fs.Visit(func(f *flag.Flag) { opts.explicit[f.Name] = true })
 
-	if *ecosystems != "" {
-		opts.Ecosystems = strings.Split(*ecosystems, ",")
+	if *fl.ecosystems != "" {
+		opts.Ecosystems = strings.Split(*fl.ecosystems, ",")
 	}
 
-	if fs.NArg() > 0 {
-		opts.RepoPath = fs.Arg(0)
+	if fl.fs.NArg() > 0 {
+		opts.RepoPath = fl.fs.Arg(0)
 	}
 
 	return opts, nil
 }
 
+// WithProfile returns a copy of opts with the profile filling in what no
+// flag set. Its base applies only when no --base, --track or --pr was given,
+// and its merge_base only to a --base.
+func (o CreateOptions) WithProfile(p config.Profile) *CreateOptions {
+	fromRemote := o.Track != "" || o.PR != 0
+	if o.Base == "" && !fromRemote {
+		o.Base = p.Base
+	}
+	if o.Ecosystems == nil {
+		o.Ecosystems = p.Ecosystems
+	}
+	if o.Integrations == nil {
+		o.Integrations = p.Integrations
+	}
+	if p.MergeBase != nil && !o.explicit["merge-base"] && !fromRemote {
+		o.MergeBase = *p.MergeBase
+	}
+	if p.CopyEnv != nil && !o.explicit["copy-env"] {
+		o.CopyEnv = *p.CopyEnv
+	}
+	return &o
+}
+
 // ValidateCreateForNonInteractive checks that all required flags are present
-// for non-interactive execution.
+// for non-interactive execution. --track and --pr name the branch
+// themselves, so --branch is optional with them; --from-file needs nothing
+// else.
 func ValidateCreateForNonInteractive(opts *CreateOptions) error {
+	if opts.FromFile != "" || opts.Track != "" || opts.PR != 0 {
+		return nil
+	}
 	if opts.Branch == "" {
 		return fmt.Errorf("missing required flag: --branch")
 	}
@@ -70,14 +179,27 @@ func CreateCLICommand(opts *CreateOptions) string {
 	if opts.Base != "" {
 		flags["base"] = opts.Base
 	}
+	if opts.Track != "" {
+		flags["track"] = opts.Track
+	}
+	if opts.PR != 0 {
+		flags["pr"] = strconv.Itoa(opts.PR)
+	}
 	if len(opts.Ecosystems) > 0 {
 		flags["ecosystems"] = strings.Join(opts.Ecosystems, ",")
 	}
-	if opts.MergeBase {
-		flags["merge-base"] = "true"
+	// Under a profile, a flag turned off explicitly overrides the profile.
+	if opts.MergeBase || opts.Profile != "" && opts.explicit["merge-base"] {
+		flags["merge-base"] = strconv.FormatBool(opts.MergeBase)
+	}
+	if opts.CopyEnv || opts.Profile != "" && opts.explicit["copy-env"] {
+		flags["copy-env"] = strconv.FormatBool(opts.CopyEnv)
+	}
+	if opts.Profile != "" {
+		flags["profile"] = opts.Profile
 	}
-	if opts.CopyEnv {
-		flags["copy-env"] = "true"
+	if opts.FromFile != "" {
+		flags["from-file"] = opts.FromFile
 	}
 	cmd := cli.BuildFlagString("sentei create", flags)
 	if opts.RepoPath != "" {
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/abiswas97/sentei/internal/config"
 )
 
 func TestParseCreateFlags_BranchOnly(t *testing.T) {
@@ -185,3 +188,121 @@ func TestCreateCLICommand_NoOptionalFlags(t *testing.T) {
 		t.Errorf("should not contain --ecosystems when empty, got %s", cmd)
 	}
 }
+
+func TestParseCreateFlags_TrackAndPR(t *testing.T) {
+	opts, err := ParseCreateFlags([]string{"--track", "origin/feature-x"})
+	if err != nil || opts.Track != "origin/feature-x" || opts.PR != 0 {
+		t.Fatalf("--track parsed as %+v, %v", opts, err)
+	}
+	opts, err = ParseCreateFlags([]string{"--pr", "42", "--branch", "review"})
+	if err != nil || opts.PR != 42 || opts.Branch != "review" {
+		t.Fatalf("--pr parsed as %+v, %v", opts, err)
+	}
+
+	for _, args := range [][]string{
+		{"--base", "main", "--track", "origin/x"},
+		{"--base", "main", "--pr", "1"},
+		{"--track", "origin/x", "--pr", "1"},
+		{"--track", "origin/x", "--merge-base"},
+		{"--pr", "-3"},
+	} {
+		if _, err := ParseCreateFlags(args); err == nil {
+			t.Errorf("ParseCreateFlags(%v) succeeded, want an error", args)
+		}
+	}
+}
+
+func TestValidateCreateForNonInteractive_SourceNamesBranch(t *testing.T) {
+	for _, opts := range []*CreateOptions{{Track: "origin/feature-x"}, {PR: 42}} {
+		if err := ValidateCreateForNonInteractive(opts); err != nil {
+			t.Errorf("ValidateCreateForNonInteractive(%+v) = %v, want nil", opts, err)
+		}
+	}
+}
+
+func TestCreateCLICommand_TrackAndPR(t *testing.T) {
+	if cmd := CreateCLICommand(&CreateOptions{Track: "origin/feature-x"}); !strings.Contains(cmd, "--track origin/feature-x") {
+		t.Errorf("expected '--track origin/feature-x', got %s", cmd)
+	}
+	if cmd := CreateCLICommand(&CreateOptions{PR: 42, Branch: "review"}); !strings.Contains(cmd, "--pr 42") || !strings.Contains(cmd, "--branch review") {
+		t.Errorf("expected '--pr 42 --branch review', got %s", cmd)
+	}
+}
+
+func TestParseCreateFlags_FromFileStandsAlone(t *testing.T) {
+	opts, err := ParseCreateFlags([]string{"--from-file", "-", "--format", "json", "/repo"})
+	if err != nil || opts.FromFile != "-" || opts.RepoPath != "/repo" {
+		t.Fatalf("--from-file parsed as %+v, %v", opts, err)
+	}
+	if err := ValidateCreateForNonInteractive(opts); err != nil {
+		t.Errorf("--from-file needs no other flags, got %v", err)
+	}
+	for _, extra := range [][]string{{"--branch", "a"}, {"--base", "main"}, {"--pr", "1"}, {"--copy-env"}, {"--ecosystems", "go"}} {
+		if _, err := ParseCreateFlags(append([]string{"--from-file", "b.yaml"}, extra...)); err == nil {
+			t.Errorf("--from-file with %v succeeded, want an error", extra)
+		}
+	}
+}
+
+func TestCreateOptions_WithProfile(t *testing.T) {
+	on, off := true, false
+	profile := config.Profile{Base: "main", Ecosystems: []string{"pnpm"}, Integrations: []string{}, MergeBase: &on, CopyEnv: &on}
+
+	opts, err := ParseCreateFlags([]string{"--branch", "feat", "--profile", "review"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := opts.WithProfile(profile)
+	if got.Base != "main" || !slices.Equal(got.Ecosystems, []string{"pnpm"}) || got.Integrations == nil || !got.MergeBase || !got.CopyEnv {
+		t.Errorf("profile should fill unset options, got %+v", got)
+	}
+	if opts.Base != "" {
+		t.Error("WithProfile must not modify its receiver")
+	}
+
+	opts, err = ParseCreateFlags([]string{"--branch", "feat", "--base", "develop", "--ecosystems", "go", "--copy-env=false", "--profile", "review"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = opts.WithProfile(profile)
+	if got.Base != "develop" || !slices.Equal(got.Ecosystems, []string{"go"}) || got.CopyEnv || !got.MergeBase {
+		t.Errorf("flags should win over the profile, got %+v", got)
+	}
+
+	opts, err = ParseCreateFlags([]string{"--track", "origin/feat", "--profile", "review"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = opts.WithProfile(profile)
+	if got.Base != "" || got.MergeBase {
+		t.Errorf("a remote source takes neither the profile's base nor merge_base, got %+v", got)
+	}
+
+	profile.CopyEnv = &off
+	opts = &CreateOptions{Branch: "feat", CopyEnv: true, Profile: "review"}
+	if got := opts.WithProfile(profile); got.CopyEnv {
+		t.Error("options built without flags take the profile's booleans")
+	}
+}
+
+func TestCreateCLICommand_Profile(t *testing.T) {
+	opts, err := ParseCreateFlags([]string{"--branch", "feat", "--profile", "review", "--copy-env=false"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := CreateCLICommand(opts), "sentei create --branch feat --copy-env=false --profile review"; got != want {
+		t.Errorf("CreateCLICommand() = %q, want %q", got, want)
+	}
+
+	opts, err = ParseCreateFlags([]string{"--branch", "feat", "--copy-env=false"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := CreateCLICommand(opts), "sentei create --branch feat"; got != want {
+		t.Errorf("without a profile a false flag is the default, got %q, want %q", got, want)
+	}
+
+	if _, err := ParseCreateFlags([]string{"--from-file", "b.yaml", "--profile", "review"}); err == nil {
+		t.Error("--from-file with --profile succeeded, want an error")
+	}
+}
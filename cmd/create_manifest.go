@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// createManifest is the document `create --from-file` reads: settings shared
+// by every entry, then one entry per worktree.
+type createManifest struct {
+	Defaults  createManifestEntry   `yaml:"defaults"`
+	Worktrees []createManifestEntry `yaml:"worktrees"`
+}
+
+// createManifestEntry mirrors the create flags. The booleans are pointers so
+// an entry can turn off what the defaults turn on.
+type createManifestEntry struct {
+	Branch     string   `yaml:"branch"`
+	Base       string   `yaml:"base"`
+	Track      string   `yaml:"track"`
+	PR         int      `yaml:"pr"`
+	Ecosystems []string `yaml:"ecosystems"`
+	MergeBase  *bool    `yaml:"merge_base"`
+	CopyEnv    *bool    `yaml:"copy_env"`
+}
+
+// readCreateManifest reads the --from-file argument, "-" meaning stdin.
+func readCreateManifest(path string, stdin io.Reader) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading manifest from stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	return data, nil
+}
+
+// ParseCreateManifest decodes a --from-file manifest into one CreateOptions
+// per worktree, with the defaults applied. Unknown keys are errors, and each
+// entry follows the flags' rules: exactly one of base, track or pr, a branch
+// unless track or pr names it, and merge_base only with base.
+func ParseCreateManifest(data []byte) ([]*CreateOptions, error) {
+	var m createManifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("manifest declares no worktrees")
+		}
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	d := m.Defaults
+	if d.Branch != "" || d.Track != "" || d.PR != 0 {
+		return nil, errors.New("manifest defaults can only set base, ecosystems, merge_base and copy_env")
+	}
+	if len(m.Worktrees) == 0 {
+		return nil, errors.New("manifest declares no worktrees")
+	}
+
+	entries := make([]*CreateOptions, 0, len(m.Worktrees))
+	for i, e := range m.Worktrees {
+		opts := &CreateOptions{Branch: e.Branch, Base: e.Base, Track: e.Track, PR: e.PR, Ecosystems: e.Ecosystems}
+		fromRemote := e.Track != "" || e.PR != 0
+		if opts.Base == "" && !fromRemote {
+			opts.Base = d.Base
+		}
+		if opts.Ecosystems == nil {
+			opts.Ecosystems = d.Ecosystems
+		}
+		switch {
+		case e.MergeBase != nil:
+			opts.MergeBase = *e.MergeBase
+		case d.MergeBase != nil && !fromRemote:
+			opts.MergeBase = *d.MergeBase
+		}
+		if e.CopyEnv != nil {
+			opts.CopyEnv = *e.CopyEnv
+		} else if d.CopyEnv != nil {
+			opts.CopyEnv = *d.CopyEnv
+		}
+
+		if err := checkManifestEntry(opts); err != nil {
+			return nil, fmt.Errorf("manifest entry %d: %w", i+1, err)
+		}
+		entries = append(entries, opts)
+	}
+	return entries, nil
+}
+
+func checkManifestEntry(opts *CreateOptions) error {
+	sources := 0
+	for _, set := range []bool{opts.Base != "", opts.Track != "", opts.PR != 0} {
+		if set {
+			sources++
+		}
+	}
+	switch {
+	case sources == 0:
+		return errors.New("needs one of base, track or pr")
+	case sources > 1:
+		return errors.New("base, track and pr are mutually exclusive")
+	case opts.PR < 0:
+		return fmt.Errorf("invalid pr %d", opts.PR)
+	case opts.Branch == "" && opts.Base != "":
+		return errors.New("needs a branch")
+	case opts.MergeBase && opts.Base == "":
+		return errors.New("merge_base only applies with base")
+	}
+	return nil
+}
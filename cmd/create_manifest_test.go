@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCreateManifest_AppliesDefaults(t *testing.T) {
+	manifest := `
+defaults:
+  base: main
+  ecosystems: [go]
+  merge_base: true
+  copy_env: true
+worktrees:
+  - branch: stack/1
+  - branch: stack/2
+    base: stack/1
+    copy_env: false
+  - track: origin/feature-x
+  - pr: 42
+    branch: review-42
+    ecosystems: []
+`
+	entries, err := ParseCreateManifest([]byte(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CreateOptions{
+		{Branch: "stack/1", Base: "main", Ecosystems: []string{"go"}, MergeBase: true, CopyEnv: true},
+		{Branch: "stack/2", Base: "stack/1", Ecosystems: []string{"go"}, MergeBase: true},
+		{Track: "origin/feature-x", Ecosystems: []string{"go"}, CopyEnv: true},
+		{Branch: "review-42", PR: 42, Ecosystems: []string{}, CopyEnv: true},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(*entries[i], want[i]) {
+			t.Errorf("entry %d = %+v, want %+v", i+1, *entries[i], want[i])
+		}
+	}
+}
+
+func TestParseCreateManifest_Rejects(t *testing.T) {
+	cases := []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{"empty", "", "no worktrees"},
+		{"no entries", "defaults: {base: main}\n", "no worktrees"},
+		{"unknown key", "worktrees:\n  - branch: a\n    base: main\n    bsae: x\n", "bsae"},
+		{"branch in defaults", "defaults: {branch: a}\nworktrees:\n  - base: main\n", "defaults can only set"},
+		{"no source", "worktrees:\n  - branch: a\n", "entry 1: needs one of base, track or pr"},
+		{"two sources", "worktrees:\n  - branch: a\n    base: main\n    pr: 3\n", "mutually exclusive"},
+		{"no branch", "defaults: {base: main}\nworktrees:\n  - copy_env: true\n", "needs a branch"},
+		{"merge base with track", "worktrees:\n  - track: origin/x\n    merge_base: true\n", "merge_base only applies with base"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseCreateManifest([]byte(tc.manifest))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("error = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"os"
 	"path/filepath"
@@ -11,6 +12,7 @@ import (
 	"github.com/abiswas97/sentei/internal/config"
 	"github.com/abiswas97/sentei/internal/creator"
 	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/report"
 )
 
 func TestCreateResultErrorPropagatesContractError(t *testing.T) {
@@ -206,3 +208,151 @@ func TestRunCreate_FailsForMissingBaseBranch(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+func TestRunCreate_TracksRemoteBranch(t *testing.T) {
+	upstream := setupBareRepo(t)
+	tmpDir := t.TempDir()
+	bareRepo := filepath.Join(tmpDir, "work.git")
+	mustGit(t, tmpDir, "clone", "--bare", upstream, bareRepo)
+	mustGit(t, bareRepo, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+
+	// The branch is pushed after the bare clone, so only create's fetch
+	// can bring it in.
+	cloneDir := filepath.Join(tmpDir, "clone")
+	mustGit(t, tmpDir, "clone", upstream, cloneDir)
+	mustGit(t, cloneDir, "checkout", "-b", "feature/remote")
+	mustWriteFile(t, filepath.Join(cloneDir, "remote.txt"), "from the remote\n")
+	mustGit(t, cloneDir, "add", ".")
+	mustGit(t, cloneDir, "-c", "user.email=test@test.com", "-c", "user.name=Test", "commit", "-m", "remote work")
+	mustGit(t, cloneDir, "push", "origin", "feature/remote")
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunCreate([]string{"--track", "origin/feature/remote", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, `Creating worktree "feature/remote" from origin/feature/remote`) {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	wtPath := filepath.Join(bareRepo, "feature-remote")
+	if _, err := os.Stat(filepath.Join(wtPath, "remote.txt")); err != nil {
+		t.Fatalf("worktree is not at the remote branch: %v", err)
+	}
+	upstreamRef, gitErr := (&git.GitRunner{}).Run(wtPath, "rev-parse", "--abbrev-ref", "@{upstream}")
+	if gitErr != nil || upstreamRef != "origin/feature/remote" {
+		t.Errorf("upstream = %q, %v; want origin/feature/remote", upstreamRef, gitErr)
+	}
+}
+
+func TestRunCreate_UnknownRemoteFailsBeforeCreating(t *testing.T) {
+	bareRepo := setupBareRepo(t)
+	err := RunCreate([]string{"--track", "upstream/feature", bareRepo})
+	if err == nil || !strings.Contains(err.Error(), "does not name a remote branch") {
+		t.Fatalf("RunCreate error = %v, want an unknown-remote error", err)
+	}
+}
+
+func TestRunCreateBatch_CreatesStackAndReportsFailures(t *testing.T) {
+	bareRepo := setupBareRepo(t)
+	manifest := `
+defaults: {base: main}
+worktrees:
+  - branch: stack/2
+    base: stack/1
+  - branch: stack/1
+  - branch: broken
+    base: no-such-base
+`
+	var out strings.Builder
+	err := runCreateBatch(&git.GitRunner{}, &git.DefaultShellRunner{}, nil, bareRepo,
+		&CreateOptions{FromFile: "-"}, strings.NewReader(manifest), &out)
+
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 worktree(s) completed with errors") {
+		t.Fatalf("error = %v, want one failed entry\n%s", err, out.String())
+	}
+	for _, want := range []string{"Created 2 of 3 worktree(s):", "stack/1  " + filepath.Join(bareRepo, "stack-1"), "broken — Create worktree:"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	head, gitErr := (&git.GitRunner{}).Run(filepath.Join(bareRepo, "stack-2"), "rev-parse", "--abbrev-ref", "HEAD")
+	if gitErr != nil || head != "stack/2" {
+		t.Errorf("stack-2 worktree HEAD = %q, %v", head, gitErr)
+	}
+}
+
+func TestRunCreateBatch_JSON(t *testing.T) {
+	bareRepo := setupBareRepo(t)
+	manifestPath := filepath.Join(t.TempDir(), "branches.yaml")
+	mustWriteFile(t, manifestPath, "worktrees:\n  - {branch: one, base: main}\n  - {branch: two, base: main}\n")
+
+	var out strings.Builder
+	err := runCreateBatch(&git.GitRunner{}, &git.DefaultShellRunner{}, nil, bareRepo,
+		&CreateOptions{FromFile: manifestPath, Format: report.FormatJSON}, nil, &out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rec struct {
+		Kind string                   `json:"kind"`
+		Data report.CreateBatchResult `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out.String()), &rec); err != nil {
+		t.Fatalf("decoding %q: %v", out.String(), err)
+	}
+	if rec.Kind != report.KindCreateBatch || rec.Data.Failed || len(rec.Data.Worktrees) != 2 || rec.Data.Worktrees[1].Branch != "two" {
+		t.Errorf("record = %+v", rec)
+	}
+}
+
+func TestRunCreate_Profile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	bareRepo := setupBareRepo(t)
+	mustWriteFile(t, config.RepoConfigPath(bareRepo, config.WithRunner(&git.GitRunner{})), `
+profiles:
+  review:
+    base: main
+    integrations: []
+  no-base:
+    copy_env: true
+  bad-integration:
+    base: main
+    integrations: [future-tool]
+`)
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunCreate([]string{"--branch", "feature/p", "--profile", "review", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, `Creating worktree "feature/p" from main (profile review)`) {
+		t.Errorf("output should name the profile:\n%s", out)
+	}
+	if _, statErr := os.Stat(filepath.Join(bareRepo, "feature-p")); statErr != nil {
+		t.Errorf("worktree not created: %v", statErr)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--branch", "feature/q", "--profile", "nope"}, `unknown profile "nope"`},
+		{[]string{"--branch", "feature/q", "--profile", "no-base"}, `missing required flag: --base (profile "no-base" does not set it)`},
+		{[]string{"--branch", "feature/q", "--profile", "bad-integration"}, `profile "bad-integration": unknown integration "future-tool"`},
+	} {
+		captureStderr(t, func() {
+			captureStdout(t, func() {
+				err = RunCreate(append(tc.args, bareRepo))
+			})
+		})
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("RunCreate(%v) error = %v, want %q", tc.args, err, tc.want)
+		}
+	}
+	if _, statErr := os.Stat(filepath.Join(bareRepo, "feature-q")); statErr == nil {
+		t.Error("a rejected profile should create nothing")
+	}
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/config"
+	"github.com/abiswas97/sentei/internal/doctor"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/integration"
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/report"
+	"github.com/abiswas97/sentei/internal/state"
+)
+
+// RunDoctor checks the bare layout of the repo at the optional positional
+// path and, with --fix, repairs what it can.
+func RunDoctor(args []string) error {
+	opts, err := ParseDoctorFlags(args)
+	if err != nil {
+		return err
+	}
+	return runDoctor(&git.GitRunner{}, &git.DefaultShellRunner{}, opts, os.Stdout)
+}
+
+// runDoctor diagnoses the layout, repairs it when asked and diagnoses again,
+// so the checks printed are the ones that still hold. It fails while any
+// check fails.
+func runDoctor(runner git.CommandRunner, shell integration.DepDetector, opts *DoctorOptions, out io.Writer) error {
+	root, enabled, diagnosis, err := diagnoseLayout(runner, shell, opts.RepoPath)
+	if err != nil {
+		return err
+	}
+
+	var w *report.Writer
+	if opts.Format.Machine() {
+		w = report.NewWriter(out, opts.Format)
+	}
+
+	var phases []progress.Phase
+	if opts.Fix && len(diagnosis.Fixable()) > 0 {
+		emit := func(e progress.Event) { printDoctorEvent(out, e) }
+		if w != nil {
+			emit = w.Events()
+		} else {
+			printDoctorReport(out, diagnosis)
+			fmt.Fprintln(out, "\nRepairing...")
+		}
+		var runErr error
+		phases, runErr = doctor.Repair(runner, diagnosis, emit)
+		if runErr != nil {
+			return fmt.Errorf("repair failed: %w", runErr)
+		}
+		if diagnosis, err = doctor.Diagnose(runner, shell, root, enabled); err != nil {
+			return err
+		}
+		if w == nil {
+			fmt.Fprintln(out)
+		}
+	}
+
+	if w != nil {
+		if err := w.Write(report.KindDoctor, report.NewDoctor(diagnosis, phases)); err != nil {
+			return err
+		}
+	} else {
+		printDoctorReport(out, diagnosis)
+		if n := len(diagnosis.Fixable()); n > 0 && !opts.Fix {
+			fmt.Fprintf(out, "\n%d problem(s) can be repaired: sentei doctor --fix\n", n)
+		}
+	}
+
+	if _, step, ok := progress.FirstFailure(phases); ok {
+		return fmt.Errorf("repair failed: %s: %v", step.Name, step.Error)
+	}
+	if diagnosis.Worst() == doctor.StatusFail {
+		return fmt.Errorf("%s has layout problems", root)
+	}
+	return nil
+}
+
+// DoctorPrompt describes the repairs doctor --fix is about to make, for the
+// confirmation asked before it runs; a plain check, or a layout with nothing
+// to repair, needs none.
+func DoctorPrompt(args []string) (string, bool, error) {
+	opts, err := ParseDoctorFlags(args)
+	if err != nil || !opts.Fix {
+		return "", false, err
+	}
+	return doctorPrompt(&git.GitRunner{}, &git.DefaultShellRunner{}, opts)
+}
+
+func doctorPrompt(runner git.CommandRunner, shell integration.DepDetector, opts *DoctorOptions) (string, bool, error) {
+	root, _, diagnosis, err := diagnoseLayout(runner, shell, opts.RepoPath)
+	if err != nil {
+		return "", false, err
+	}
+	fixable := diagnosis.Fixable()
+	if len(fixable) == 0 {
+		return "", false, nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Repair %d problem(s) in %s:", len(fixable), root)
+	for _, c := range fixable {
+		fmt.Fprintf(&b, "\n  - %s: %s", c.Name, c.Fix)
+	}
+	return b.String(), true, nil
+}
+
+// diagnoseLayout finds the bare root for repoPath and checks it against the
+// integrations enabled there.
+func diagnoseLayout(runner git.CommandRunner, shell integration.DepDetector, repoPath string) (string, []integration.Integration, doctor.Report, error) {
+	if absPath, err := filepath.Abs(repoPath); err == nil {
+		repoPath = absPath
+	}
+	root, err := doctor.FindRoot(runner, repoPath)
+	if err != nil {
+		return "", nil, doctor.Report{}, err
+	}
+	enabled, err := enabledIntegrations(runner, root, filepath.Join(root, ".bare"))
+	if err != nil {
+		return "", nil, doctor.Report{}, err
+	}
+	diagnosis, err := doctor.Diagnose(runner, shell, root, enabled)
+	return root, enabled, diagnosis, err
+}
+
+// enabledIntegrations returns the configured integrations the state in
+// commonDir records as enabled.
+func enabledIntegrations(runner git.CommandRunner, repoPath, commonDir string) ([]integration.Integration, error) {
+	cfg, err := config.LoadConfig(repoPath, config.WithRunner(runner))
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	st, err := state.Load(commonDir)
+	if err != nil {
+		return nil, err
+	}
+	var enabled []integration.Integration
+	for _, integ := range integration.Resolve(cfg.Integrations) {
+		if st.HasIntegration(integ.Name) {
+			enabled = append(enabled, integ)
+		}
+	}
+	return enabled, nil
+}
+
+func printDoctorReport(out io.Writer, r doctor.Report) {
+	fmt.Fprintf(out, "Checking %s\n\n", r.Root)
+	for _, c := range r.Checks {
+		indicator := green + "✓" + nc
+		switch c.Status {
+		case doctor.StatusWarn:
+			indicator = yellow + "⚠" + nc
+		case doctor.StatusFail:
+			indicator = yellow + "✗" + nc
+		}
+		fmt.Fprintf(out, "%s %-26s %s\n", indicator, c.Name, c.Detail)
+		for _, problem := range c.Problems {
+			fmt.Fprintf(out, "    %s%s%s\n", dim, problem, nc)
+		}
+		switch {
+		case c.Fix != "":
+			fmt.Fprintf(out, "    fix: %s\n", c.Fix)
+		case c.Hint != "":
+			fmt.Fprintf(out, "    hint: %s\n", c.Hint)
+		}
+	}
+}
+
+func printDoctorEvent(out io.Writer, e progress.Event) {
+	switch e.Status {
+	case progress.StepDone:
+		msg := ""
+		if e.Message != "" {
+			msg = fmt.Sprintf(" (%s)", e.Message)
+		}
+		fmt.Fprintf(out, "%s✓%s %s%s\n", green, nc, e.StepLabel, msg)
+	case progress.StepFailed:
+		fmt.Fprintf(out, "%s✗%s %s: %v\n", yellow, nc, e.StepLabel, e.Error)
+	case progress.StepSkipped:
+		fmt.Fprintf(out, "%s⊘%s %s (%s)\n", dim, nc, e.StepLabel, e.Message)
+	}
+}
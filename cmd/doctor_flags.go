@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"flag"
+
+	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// DoctorOptions holds parsed flags for the doctor command.
+type DoctorOptions struct {
+	Fix      bool
+	Format   report.Format
+	RepoPath string
+}
+
+// doctorFlags is the doctor command's flag set, shared by the parser and the
+// completion metadata.
+type doctorFlags struct {
+	fs     *flag.FlagSet
+	fix    *bool
+	format *string
+}
+
+func newDoctorFlags() *doctorFlags {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	return &doctorFlags{
+		fs:     fs,
+		fix:    fs.Bool("fix", false, "Repair the problems that have a fix, then check again"),
+		format: formatFlag(fs),
+	}
+}
+
+// DoctorFlags describes the doctor command's flags for usage and shell
+// completion.
+func DoctorFlags() []cli.Flag {
+	return cli.FlagsOf(newDoctorFlags().fs)
+}
+
+// ParseDoctorFlags parses `doctor [flags] [repo]`. The repo defaults to the
+// current directory.
+func ParseDoctorFlags(args []string) (*DoctorOptions, error) {
+	fl := newDoctorFlags()
+	if err := fl.fs.Parse(args); err != nil {
+		return nil, err
+	}
+	format, err := report.ParseFormat(*fl.format)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &DoctorOptions{Fix: *fl.fix, Format: format, RepoPath: "."}
+	if fl.fs.NArg() > 0 {
+		opts.RepoPath = fl.fs.Arg(0)
+	}
+	return opts, nil
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// setupDoctorLayout clones setupBareRepo into sentei's bare layout with a
+// worktree for main, and drops origin's fetch refspec so there is something
+// to repair.
+func setupDoctorLayout(t *testing.T) string {
+	t.Helper()
+	origin := setupBareRepo(t)
+	root := filepath.Join(t.TempDir(), "repo")
+	bare := filepath.Join(root, ".bare")
+	mustGit(t, filepath.Dir(root), "clone", "--bare", origin, bare)
+	mustWriteFile(t, filepath.Join(root, ".git"), "gitdir: .bare\n")
+	mustGit(t, bare, "worktree", "add", filepath.Join(root, "main"), "main")
+	return root
+}
+
+func TestParseDoctorFlags(t *testing.T) {
+	opts, err := ParseDoctorFlags([]string{"--fix", "--format", "json", "/some/repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !opts.Fix || opts.Format != report.FormatJSON || opts.RepoPath != "/some/repo" {
+		t.Errorf("opts = %+v", opts)
+	}
+	if opts, _ := ParseDoctorFlags(nil); opts.RepoPath != "." {
+		t.Errorf("default RepoPath = %q, want .", opts.RepoPath)
+	}
+}
+
+func TestRunDoctor_ReportsWithoutChanging(t *testing.T) {
+	root := setupDoctorLayout(t)
+	var out bytes.Buffer
+	if err := runDoctor(&git.GitRunner{}, &git.DefaultShellRunner{}, &DoctorOptions{RepoPath: filepath.Join(root, "main")}, &out); err != nil {
+		t.Fatalf("warnings alone should not fail: %v\n%s", err, out.String())
+	}
+	for _, want := range []string{"Origin fetch refspec", "fix: add +refs/heads/*", "sentei doctor --fix"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if refspec, _ := (&git.GitRunner{}).Run(filepath.Join(root, ".bare"), "config", "--get-all", "remote.origin.fetch"); strings.TrimSpace(refspec) != "" {
+		t.Fatalf("refspec added without --fix: %q", refspec)
+	}
+}
+
+func TestRunDoctor_FailsOnBrokenPointer(t *testing.T) {
+	root := setupDoctorLayout(t)
+	mustWriteFile(t, filepath.Join(root, ".git"), "gitdir: /elsewhere/.bare\n")
+	var out bytes.Buffer
+	err := runDoctor(&git.GitRunner{}, &git.DefaultShellRunner{}, &DoctorOptions{RepoPath: root}, &out)
+	if err == nil || !strings.Contains(err.Error(), "layout problems") {
+		t.Fatalf("err = %v\n%s", err, out.String())
+	}
+}
+
+func TestRunDoctor_FixWritesRepairedReport(t *testing.T) {
+	root := setupDoctorLayout(t)
+	mustWriteFile(t, filepath.Join(root, ".git"), "gitdir: /elsewhere/.bare\n")
+	var out bytes.Buffer
+	opts := &DoctorOptions{Fix: true, Format: report.FormatJSON, RepoPath: root}
+	if err := runDoctor(&git.GitRunner{}, &git.DefaultShellRunner{}, opts, &out); err != nil {
+		t.Fatalf("runDoctor: %v\n%s", err, out.String())
+	}
+
+	var rec struct {
+		Kind string        `json:"kind"`
+		Data report.Doctor `json:"data"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &rec); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out.String())
+	}
+	if rec.Kind != report.KindDoctor || !rec.Data.Healthy || len(rec.Data.Phases) != 1 {
+		t.Fatalf("record = %+v", rec)
+	}
+	for _, c := range rec.Data.Checks {
+		if c.Status != "ok" {
+			t.Errorf("after --fix %s = %s (%s)", c.ID, c.Status, c.Detail)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, ".git")); string(data) != "gitdir: .bare\n" {
+		t.Fatalf(".git = %q", data)
+	}
+}
+
+func TestDoctorPrompt_ListsRepairsOnlyWithFix(t *testing.T) {
+	root := setupDoctorLayout(t)
+	if _, needed, err := DoctorPrompt([]string{root}); err != nil || needed {
+		t.Fatalf("a plain check must not ask: needed=%v err=%v", needed, err)
+	}
+
+	prompt, needed, err := doctorPrompt(&git.GitRunner{}, &git.DefaultShellRunner{}, &DoctorOptions{Fix: true, RepoPath: root})
+	if err != nil || !needed {
+		t.Fatalf("needed=%v err=%v", needed, err)
+	}
+	if !strings.Contains(prompt, "Origin fetch refspec: add +refs/heads/*") {
+		t.Errorf("prompt = %q, want the refspec repair listed", prompt)
+	}
+	if refspec, _ := (&git.GitRunner{}).Run(filepath.Join(root, ".bare"), "config", "--get-all", "remote.origin.fetch"); strings.TrimSpace(refspec) != "" {
+		t.Fatalf("the prompt must not repair anything: refspec %q", refspec)
+	}
+}
@@ -1,29 +1,68 @@
 package cmd
 
 import (
+	"flag"
 	"fmt"
-	"os"
 	"strings"
 
+	"github.com/abiswas97/sentei/internal/cli"
 	"github.com/abiswas97/sentei/internal/config"
 	"github.com/abiswas97/sentei/internal/ecosystem"
+	"github.com/abiswas97/sentei/internal/report"
 )
 
-func RunEcosystems(args []string) {
+// newEcosystemsFlags declares the ecosystems command's flags: --format only.
+func newEcosystemsFlags() (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet("ecosystems", flag.ContinueOnError)
+	return fs, formatFlag(fs)
+}
+
+// EcosystemsFlags describes the ecosystems command's flags for usage and shell
+// completion.
+func EcosystemsFlags() []cli.Flag {
+	fs, _ := newEcosystemsFlags()
+	return cli.FlagsOf(fs)
+}
+
+// RunEcosystems lists the registered ecosystems for the repo at the optional
+// positional path.
+func RunEcosystems(args []string) error {
+	fs, formatValue := newEcosystemsFlags()
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	format, err := report.ParseFormat(*formatValue)
+	if err != nil {
+		return err
+	}
+
 	repoPath := "."
-	if len(args) > 0 {
-		repoPath = args[0]
+	if fs.NArg() > 0 {
+		repoPath = fs.Arg(0)
 	}
 
 	cfg, err := config.LoadConfig(repoPath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("loading config: %w", err)
 	}
 
 	reg := ecosystem.NewRegistry(cfg.Ecosystems)
 	all := reg.All()
 
+	if out := newReport(format); out != nil {
+		docs := make([]report.Ecosystem, len(all))
+		for i, eco := range all {
+			docs[i] = report.Ecosystem{
+				Name:        eco.Name,
+				DetectFiles: eco.Config.Detect.Files,
+				Install:     eco.Config.Install.Command,
+				Source:      eco.Config.Source,
+				Enabled:     eco.Config.IsEnabled(),
+			}
+		}
+		return out.Write(report.KindEcosystems, docs)
+	}
+
 	fmt.Printf("Ecosystems (%d registered)\n\n", len(all))
 	fmt.Printf("  %-14s %-24s %-30s %-10s %s\n", "NAME", "DETECT FILES", "INSTALL", "SOURCE", "STATUS")
 
@@ -42,6 +81,7 @@ func RunEcosystems(args []string) {
 			status,
 		)
 	}
+	return nil
 }
 
 func truncate(s string, max int) string {
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"flag"
+	"os"
+
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// formatFlag registers the --format flag every CLI command accepts.
+func formatFlag(fs *flag.FlagSet) *string {
+	return fs.String("format", string(report.FormatText), "Output format: text, json or ndjson")
+}
+
+// newReport returns a record writer on stdout for a machine format, or nil
+// for text so callers can branch on `out != nil`.
+func newReport(format report.Format) *report.Writer {
+	if !format.Machine() {
+		return nil
+	}
+	return report.NewWriter(os.Stdout, format)
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// decodeRecord decodes a single JSON record written by a --format json run.
+func decodeRecord(t *testing.T, out string, data any) report.Record {
+	t.Helper()
+	rec := report.Record{Data: data}
+	if err := json.Unmarshal([]byte(out), &rec); err != nil {
+		t.Fatalf("output is not a JSON record: %v\n%s", err, out)
+	}
+	if rec.Schema != report.SchemaVersion {
+		t.Errorf("schema = %d, want %d", rec.Schema, report.SchemaVersion)
+	}
+	return rec
+}
+
+func TestRunEcosystems_FormatJSON(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	mustWriteFile(t, filepath.Join(dir, ".sentei.yaml"), "ecosystems:\n  - name: pnpm\n    enabled: false\n")
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunEcosystems([]string{"--format", "json", dir})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ecosystems []report.Ecosystem
+	rec := decodeRecord(t, out, &ecosystems)
+	if rec.Kind != report.KindEcosystems {
+		t.Errorf("kind = %q", rec.Kind)
+	}
+	var pnpm *report.Ecosystem
+	for i := range ecosystems {
+		if ecosystems[i].Name == "pnpm" {
+			pnpm = &ecosystems[i]
+		}
+	}
+	if pnpm == nil || pnpm.Enabled {
+		t.Errorf("pnpm must be listed as disabled, got %+v", ecosystems)
+	}
+}
+
+func TestRunRemove_DryRunFormatJSON(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunRemove([]string{"--merged", "--dry-run", "--format", "json", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc report.Remove
+	rec := decodeRecord(t, out, &doc)
+	if rec.Kind != report.KindRemove || !doc.DryRun || doc.Result != nil {
+		t.Fatalf("record = %+v, doc = %+v", rec, doc)
+	}
+	if len(doc.Worktrees) != 1 || doc.Worktrees[0].Branch != "feature/merged-branch" {
+		t.Errorf("worktrees = %+v, want the merged branch only", doc.Worktrees)
+	}
+}
+
+func TestRunRemove_FormatNDJSONStreamsProgress(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunRemove([]string{"--merged", "--format", "ndjson", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	kinds := make([]string, len(lines))
+	for i, line := range lines {
+		var rec report.Record
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("line %d is not a record: %v\n%s", i, err, line)
+		}
+		kinds[i] = rec.Kind
+	}
+	if len(kinds) < 2 || kinds[0] != report.KindEvent || kinds[len(kinds)-1] != report.KindRemove {
+		t.Fatalf("kinds = %v, want events followed by the remove result", kinds)
+	}
+
+	var last struct{ Data report.Remove }
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &last); err != nil {
+		t.Fatal(err)
+	}
+	if last.Data.Result == nil || last.Data.Result.Succeeded != 1 {
+		t.Errorf("result = %+v, want one successful removal", last.Data.Result)
+	}
+	if _, statErr := os.Stat(filepath.Join(bareRepo, "feature-merged-branch")); !os.IsNotExist(statErr) {
+		t.Errorf("worktree should be removed, stat err = %v", statErr)
+	}
+}
+
+func TestRunCleanup_DryRunFormatJSONWritesPreview(t *testing.T) {
+	bareRepo := setupBareRepo(t)
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunCleanup([]string{"--mode", "safe", "--dry-run", "--format", "json", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var preview report.CleanupPreview
+	rec := decodeRecord(t, out, &preview)
+	if rec.Kind != report.KindCleanupPreview {
+		t.Errorf("kind = %q, want %q", rec.Kind, report.KindCleanupPreview)
+	}
+	if preview.GoneBranches == nil || preview.Errors == nil {
+		t.Errorf("list fields must encode as [], got %+v", preview)
+	}
+}
+
+func TestParseFlags_RejectInvalidFormat(t *testing.T) {
+	if _, err := ParseRemoveFlags([]string{"--format", "yaml"}); err == nil {
+		t.Error("remove: expected an error for --format yaml")
+	}
+	if _, err := ParseCleanupFlags([]string{"--format", "yaml"}); err == nil {
+		t.Error("cleanup: expected an error for --format yaml")
+	}
+	if _, err := ParseCreateFlags([]string{"--format", "yaml"}); err == nil {
+		t.Error("create: expected an error for --format yaml")
+	}
+}
@@ -3,23 +3,65 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"strings"
 
+	"github.com/abiswas97/sentei/internal/config"
+	"github.com/abiswas97/sentei/internal/git"
 	"github.com/abiswas97/sentei/internal/integration"
+	"github.com/abiswas97/sentei/internal/report"
 )
 
-func RunIntegrations() {
-	all := integration.All()
+// RunIntegrations lists the built-in integrations and those declared in the
+// config for the repo at the optional positional path, and whether each is
+// installed. `integrations refresh` updates the enabled integrations'
+// indexes instead.
+func RunIntegrations(args []string) error {
+	opts, err := ParseIntegrationsFlags(args)
+	if err != nil {
+		return err
+	}
+	if opts.Action == "refresh" {
+		return runIntegrationsRefresh(&git.GitRunner{}, &git.DefaultShellRunner{}, opts, os.Stdout)
+	}
+	format := opts.Format
+
+	cfg, err := config.LoadConfig(opts.RepoPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	all := integration.Resolve(cfg.Integrations)
+
+	if out := newReport(format); out != nil {
+		docs := make([]report.Integration, len(all))
+		for i, integ := range all {
+			docs[i] = report.Integration{
+				Name:        integ.Name,
+				Description: integ.Description,
+				URL:         integ.URL,
+				Installed:   detectStatus(integ) == "installed",
+				Source:      integ.Source,
+			}
+		}
+		return out.Write(report.KindIntegrations, docs)
+	}
 
 	fmt.Printf("Integrations (%d registered)\n\n", len(all))
-	fmt.Printf("  %-22s %-12s %s\n", "NAME", "STATUS", "DESCRIPTION")
+	fmt.Printf("  %-22s %-12s %-10s %s\n", "NAME", "STATUS", "SOURCE", "DESCRIPTION")
 
 	for _, integ := range all {
 		status := detectStatus(integ)
-		fmt.Printf("  %-22s %-12s %s\n", integ.Name, status, integ.Description)
-		fmt.Printf("  %-22s %-12s %s\n", "", "", integ.URL)
+		description := integ.Description
+		if description == "" {
+			description = integ.ShortDescription
+		}
+		fmt.Printf("  %-22s %-12s %-10s %s\n", integ.Name, status, integ.Source, description)
+		if integ.URL != "" {
+			fmt.Printf("  %-22s %-12s %-10s %s\n", "", "", "", integ.URL)
+		}
 	}
+	return nil
 }
 
 func detectStatus(integ integration.Integration) string {
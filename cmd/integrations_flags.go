@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// IntegrationsActions are the integrations command's subcommands; without
+// one it lists the integrations.
+var IntegrationsActions = []string{"refresh"}
+
+// IntegrationsOptions holds parsed flags for the integrations command.
+type IntegrationsOptions struct {
+	Action   string // "" to list, or one of IntegrationsActions
+	Format   report.Format
+	All      bool   // refresh every worktree
+	Worktree string // refresh this worktree
+	RepoPath string
+}
+
+// integrationsFlags is the integrations command's flag set, shared by the
+// parser and the completion metadata.
+type integrationsFlags struct {
+	fs       *flag.FlagSet
+	format   *string
+	all      *bool
+	worktree *string
+}
+
+func newIntegrationsFlags() *integrationsFlags {
+	fs := flag.NewFlagSet("integrations", flag.ContinueOnError)
+	return &integrationsFlags{
+		fs:       fs,
+		format:   formatFlag(fs),
+		all:      fs.Bool("all", false, "Let refresh update every worktree"),
+		worktree: fs.String("worktree", "", "Let refresh update only the worktree at this path"),
+	}
+}
+
+// IntegrationsFlags describes the integrations command's flags for usage and
+// shell completion.
+func IntegrationsFlags() []cli.Flag {
+	return cli.FlagsOf(newIntegrationsFlags().fs, cli.Flag{Name: "worktree", Kind: cli.FlagPath})
+}
+
+// ParseIntegrationsFlags parses `integrations [refresh] [flags] [repo]`.
+func ParseIntegrationsFlags(args []string) (*IntegrationsOptions, error) {
+	opts := &IntegrationsOptions{RepoPath: "."}
+	if len(args) > 0 && args[0] == "refresh" {
+		opts.Action, args = args[0], args[1:]
+	}
+
+	fl := newIntegrationsFlags()
+	if err := fl.fs.Parse(args); err != nil {
+		return nil, err
+	}
+	format, err := report.ParseFormat(*fl.format)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Action == "" && (*fl.all || *fl.worktree != "") {
+		return nil, fmt.Errorf("--all and --worktree only apply to integrations refresh")
+	}
+	if *fl.all && *fl.worktree != "" {
+		return nil, fmt.Errorf("--all and --worktree are mutually exclusive")
+	}
+
+	opts.Format = format
+	opts.All = *fl.all
+	opts.Worktree = *fl.worktree
+	if fl.fs.NArg() > 0 {
+		opts.RepoPath = fl.fs.Arg(0)
+	}
+	return opts, nil
+}
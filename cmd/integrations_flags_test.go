@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+func TestParseIntegrationsFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    IntegrationsOptions
+		wantErr string
+	}{
+		{name: "list", args: nil, want: IntegrationsOptions{Format: report.FormatText, RepoPath: "."}},
+		{name: "list with path", args: []string{"--format", "json", "/repo"}, want: IntegrationsOptions{Format: report.FormatJSON, RepoPath: "/repo"}},
+		{name: "refresh current", args: []string{"refresh"}, want: IntegrationsOptions{Action: "refresh", Format: report.FormatText, RepoPath: "."}},
+		{name: "refresh all", args: []string{"refresh", "--all", "/repo"}, want: IntegrationsOptions{Action: "refresh", Format: report.FormatText, All: true, RepoPath: "/repo"}},
+		{name: "refresh worktree", args: []string{"refresh", "--worktree", "/repo/feat"}, want: IntegrationsOptions{Action: "refresh", Format: report.FormatText, Worktree: "/repo/feat", RepoPath: "."}},
+		{name: "all without refresh", args: []string{"--all"}, wantErr: "only apply to integrations refresh"},
+		{name: "all and worktree", args: []string{"refresh", "--all", "--worktree", "/repo/feat"}, wantErr: "mutually exclusive"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseIntegrationsFlags(tc.args)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != tc.want {
+				t.Errorf("got %+v, want %+v", *got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/fileutil"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/integration"
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// runIntegrationsRefresh re-runs the enabled integrations' refresh commands
+// in the selected worktrees, several worktrees at a time. It fails when any
+// worktree failed.
+func runIntegrationsRefresh(runner git.CommandRunner, shell git.ShellRunner, opts *IntegrationsOptions, out io.Writer) error {
+	repoPath, enabled, targets, err := refreshScope(runner, opts)
+	if err != nil {
+		return err
+	}
+	prepared, err := integration.PrepareRefresh(repoPath, enabled, targets)
+	if err != nil {
+		return err
+	}
+
+	var w *report.Writer
+	if opts.Format.Machine() {
+		w = report.NewWriter(out, opts.Format)
+	}
+	if prepared.Empty() {
+		if w != nil {
+			return w.Write(report.KindIntegrationsRefresh, report.IntegrationsRefresh{Phases: []report.Phase{}})
+		}
+		if len(enabled) == 0 {
+			fmt.Fprintln(out, "No integrations are enabled for this repository.")
+		} else {
+			fmt.Fprintln(out, "None of the enabled integrations has a refresh command.")
+		}
+		return nil
+	}
+
+	var phases []progress.Phase
+	var runErr error
+	if w != nil {
+		phases, runErr = prepared.Run(shell, w.Events())
+		doc := report.IntegrationsRefresh{Phases: report.NewPhases(phases)}
+		if err := w.Write(report.KindIntegrationsRefresh, doc); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(out, "Refreshing integrations in %d worktree(s)...\n", len(targets))
+		phases, runErr = prepared.Run(shell, func(e progress.Event) { printRefreshEvent(out, e) })
+	}
+	if runErr != nil {
+		return fmt.Errorf("refresh failed: %w", runErr)
+	}
+
+	var failed []progress.Phase
+	for _, phase := range phases {
+		if phase.HasFailures() {
+			failed = append(failed, phase)
+		}
+	}
+	if len(failed) > 0 {
+		if w == nil {
+			fmt.Fprintf(out, "\n%sFailed in %d of %d worktree(s):%s\n", yellow, len(failed), len(phases), nc)
+			for _, phase := range failed {
+				for _, step := range phase.Steps {
+					if step.Status == progress.StepFailed {
+						fmt.Fprintf(out, "  %s: %s — %v\n", phase.Name, step.Name, step.Error)
+					}
+				}
+			}
+		}
+		return fmt.Errorf("refresh failed in %d worktree(s)", len(failed))
+	}
+	if w == nil {
+		fmt.Fprintf(out, "\n%sRefreshed %d worktree(s).%s\n", green, len(phases), nc)
+	}
+	return nil
+}
+
+// IntegrationsPrompt describes the refresh commands `integrations refresh`
+// is about to run and where, for the confirmation asked before it runs;
+// they may come from the repository's own .sentei.yaml. Listing needs none.
+func IntegrationsPrompt(args []string) (string, bool, error) {
+	opts, err := ParseIntegrationsFlags(args)
+	if err != nil || opts.Action != "refresh" {
+		return "", false, err
+	}
+	return integrationsPrompt(&git.GitRunner{}, opts)
+}
+
+func integrationsPrompt(runner git.CommandRunner, opts *IntegrationsOptions) (string, bool, error) {
+	_, enabled, targets, err := refreshScope(runner, opts)
+	if err != nil {
+		return "", false, err
+	}
+	var commands []string
+	for _, integ := range enabled {
+		if command := strings.TrimSpace(integ.Refresh.Command); command != "" {
+			commands = append(commands, fmt.Sprintf("\n  - %s: %s", integ.Name, command))
+		}
+	}
+	if len(commands) == 0 || len(targets) == 0 {
+		return "", false, nil
+	}
+	return fmt.Sprintf("Run these refresh commands in %d worktree(s):%s", len(targets), strings.Join(commands, "")), true, nil
+}
+
+// refreshScope resolves the repository, its enabled integrations and the
+// worktrees a refresh covers.
+func refreshScope(runner git.CommandRunner, opts *IntegrationsOptions) (string, []integration.Integration, []string, error) {
+	repoPath := opts.RepoPath
+	if absPath, err := filepath.Abs(repoPath); err == nil {
+		repoPath = absPath
+	}
+	commonDir, err := git.CommonDir(runner, repoPath)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	enabled, err := enabledIntegrations(runner, repoPath, commonDir)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	targets, err := refreshTargets(runner, repoPath, opts)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return repoPath, enabled, targets, nil
+}
+
+// refreshTargets resolves the worktrees to refresh: every worktree for --all,
+// the one at --worktree, or else the worktree repoPath is in.
+func refreshTargets(runner git.CommandRunner, repoPath string, opts *IntegrationsOptions) ([]string, error) {
+	worktrees, err := git.ListWorktrees(runner, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	var live []string
+	for _, wt := range worktrees {
+		if !wt.IsBare && !wt.IsPrunable {
+			live = append(live, wt.Path)
+		}
+	}
+	if opts.All {
+		return live, nil
+	}
+
+	target := opts.Worktree
+	if target == "" {
+		top, err := runner.Run(repoPath, "rev-parse", "--show-toplevel")
+		if err != nil || strings.TrimSpace(top) == "" {
+			return nil, errors.New("not inside a worktree: pass --worktree PATH or --all")
+		}
+		target = strings.TrimSpace(top)
+	}
+	for _, path := range live {
+		if fileutil.SamePath(path, target) {
+			return []string{path}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not a worktree of this repository", target)
+}
+
+// printRefreshEvent prints one refresh transition. Worktrees refresh
+// concurrently, so every line names its worktree.
+func printRefreshEvent(out io.Writer, e progress.Event) {
+	if e.Close {
+		return
+	}
+	where := filepath.Base(e.PhaseLabel)
+	switch e.Status {
+	case progress.StepRunning:
+		fmt.Fprintf(out, "%s→%s %s: %s\n", blue, nc, where, e.StepLabel)
+	case progress.StepDone:
+		fmt.Fprintf(out, "%s✓%s %s: %s\n", green, nc, where, e.StepLabel)
+	case progress.StepFailed:
+		msg := ""
+		if e.Error != nil {
+			msg = " — " + e.Error.Error()
+		}
+		fmt.Fprintf(out, "%s✗%s %s: %s%s\n", yellow, nc, where, e.StepLabel, msg)
+	case progress.StepSkipped:
+		fmt.Fprintf(out, "  %s%s: %s (skipped: %s)%s\n", dim, where, e.StepLabel, e.Message, nc)
+	}
+}
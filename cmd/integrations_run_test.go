@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/abiswas97/sentei/internal/git"
 	"github.com/abiswas97/sentei/internal/integration"
+	"github.com/abiswas97/sentei/internal/report"
+	"github.com/abiswas97/sentei/internal/state"
 )
 
 func TestDetectStatus(t *testing.T) {
@@ -31,7 +38,7 @@ func TestDetectStatus(t *testing.T) {
 
 func TestRunIntegrations_ListsAllIntegrations(t *testing.T) {
 	out := captureStdout(t, func() {
-		RunIntegrations()
+		RunIntegrations(nil)
 	})
 
 	if !strings.Contains(out, "Integrations (") {
@@ -46,3 +53,133 @@ func TestRunIntegrations_ListsAllIntegrations(t *testing.T) {
 		}
 	}
 }
+
+func TestRunIntegrations_ListsDeclaredIntegrations(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	mustWriteFile(t, filepath.Join(dir, ".sentei.yaml"), `
+integrations:
+  - name: ctags
+    short_description: Tag index for editors
+    detect:
+      binary: no-such-binary-zzz
+`)
+
+	out := captureStdout(t, func() {
+		if err := RunIntegrations([]string{dir}); err != nil {
+			t.Error(err)
+		}
+	})
+
+	if !strings.Contains(out, "Integrations (3 registered)") {
+		t.Errorf("expected the declared integration to be counted, got:\n%s", out)
+	}
+	for _, want := range []string{"ctags", "per-repo", "Tag index for editors", "built-in"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+// setupRefreshRepo returns a bare repo with worktrees "main" and "feature"
+// inside it and an enabled integration whose refresh only succeeds where a
+// file named ok exists, which is only in main.
+func setupRefreshRepo(t *testing.T) (bareRepo, mainWT, featureWT string) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	bareRepo = setupBareRepo(t)
+	mainWT = filepath.Join(bareRepo, "main")
+	featureWT = filepath.Join(bareRepo, "feature")
+	mustGit(t, bareRepo, "worktree", "add", mainWT, "main")
+	mustGit(t, bareRepo, "worktree", "add", "-b", "feature", featureWT, "main")
+	mustWriteFile(t, filepath.Join(mainWT, "ok"), "")
+
+	mustWriteFile(t, filepath.Join(filepath.Dir(bareRepo), ".sentei.yaml"), `
+integrations:
+  - name: stamp
+    detect: {binary: sh}
+    refresh: {command: "test -f ok && touch refreshed"}
+`)
+	if err := state.Save(bareRepo, &state.State{Integrations: []string{"stamp"}}); err != nil {
+		t.Fatal(err)
+	}
+	return bareRepo, mainWT, featureWT
+}
+
+func TestRunIntegrationsRefresh_ReportsFailingWorktrees(t *testing.T) {
+	bareRepo, mainWT, featureWT := setupRefreshRepo(t)
+	var out bytes.Buffer
+
+	opts := &IntegrationsOptions{Action: "refresh", All: true, RepoPath: bareRepo}
+	err := runIntegrationsRefresh(&git.GitRunner{}, &git.DefaultShellRunner{}, opts, &out)
+	if err == nil || !strings.Contains(err.Error(), "refresh failed in 1 worktree(s)") {
+		t.Fatalf("error = %v, want one failed worktree\n%s", err, out.String())
+	}
+	if _, err := os.Stat(filepath.Join(mainWT, "refreshed")); err != nil {
+		t.Errorf("main was not refreshed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(featureWT, "refreshed")); err == nil {
+		t.Error("feature should have failed before refreshing")
+	}
+	for _, want := range []string{"main: Refresh stamp", "Failed in 1 of 2 worktree(s)", featureWT + ": Refresh stamp"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunIntegrationsRefresh_SelectsWorktree(t *testing.T) {
+	bareRepo, mainWT, featureWT := setupRefreshRepo(t)
+	var out bytes.Buffer
+
+	opts := &IntegrationsOptions{Action: "refresh", Worktree: mainWT, Format: report.FormatJSON, RepoPath: bareRepo}
+	if err := runIntegrationsRefresh(&git.GitRunner{}, &git.DefaultShellRunner{}, opts, &out); err != nil {
+		t.Fatalf("refresh --worktree main: %v\n%s", err, out.String())
+	}
+	var rec struct {
+		Kind string                     `json:"kind"`
+		Data report.IntegrationsRefresh `json:"data"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &rec); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if rec.Kind != report.KindIntegrationsRefresh || len(rec.Data.Phases) != 1 || rec.Data.Phases[0].Steps[0].Status != "done" {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+	if _, err := os.Stat(filepath.Join(featureWT, "refreshed")); err == nil {
+		t.Error("feature should not have been refreshed")
+	}
+
+	// Without a flag, refresh targets the worktree it runs in.
+	out.Reset()
+	opts = &IntegrationsOptions{Action: "refresh", RepoPath: featureWT}
+	if err := runIntegrationsRefresh(&git.GitRunner{}, &git.DefaultShellRunner{}, opts, &out); err == nil || !strings.Contains(out.String(), "feature: Refresh stamp") {
+		t.Errorf("refresh in feature = %v, want its failure:\n%s", err, out.String())
+	}
+
+	opts = &IntegrationsOptions{Action: "refresh", RepoPath: bareRepo}
+	if err := runIntegrationsRefresh(&git.GitRunner{}, &git.DefaultShellRunner{}, opts, &out); err == nil || !strings.Contains(err.Error(), "not inside a worktree") {
+		t.Errorf("refresh in the bare repo = %v, want it to ask for a worktree", err)
+	}
+}
+
+func TestIntegrationsPrompt_NamesTheRefreshCommands(t *testing.T) {
+	bareRepo, mainWT, _ := setupRefreshRepo(t)
+	if _, needed, err := IntegrationsPrompt([]string{bareRepo}); err != nil || needed {
+		t.Fatalf("listing must not ask: needed=%v err=%v", needed, err)
+	}
+
+	opts := &IntegrationsOptions{Action: "refresh", All: true, RepoPath: bareRepo}
+	prompt, needed, err := integrationsPrompt(&git.GitRunner{}, opts)
+	if err != nil || !needed {
+		t.Fatalf("needed=%v err=%v", needed, err)
+	}
+	for _, want := range []string{"in 2 worktree(s)", "stamp: test -f ok && touch refreshed"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(mainWT, "refreshed")); err == nil {
+		t.Error("the prompt must not run anything")
+	}
+}
@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/dryrun"
+	"github.com/abiswas97/sentei/internal/fileutil"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/pr"
+	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/report"
+	"github.com/abiswas97/sentei/internal/worktree"
+)
+
+// listRow is one worktree plus the optional facts the selected columns need.
+type listRow struct {
+	wt        git.Worktree
+	protected bool
+	size      int64
+}
+
+// listColumn renders one column. cell is the table text; raw, when set, is
+// the TSV text for columns whose table form is lossy (ages, sizes).
+type listColumn struct {
+	header string
+	cell   func(r listRow) string
+	raw    func(r listRow) string
+}
+
+var defaultListColumns = []string{"status", "branch", "age", "subject"}
+
+var listColumns = map[string]listColumn{
+	"status": {header: "STATUS", cell: func(r listRow) string { return dryrun.StatusIndicator(r.wt) }},
+	"branch": {
+		header: "BRANCH",
+		cell: func(r listRow) string {
+			if r.protected {
+				return dryrun.BranchLabel(r.wt) + " [P]"
+			}
+			return dryrun.BranchLabel(r.wt)
+		},
+		raw: func(r listRow) string { return dryrun.BranchLabel(r.wt) },
+	},
+	"age": {
+		header: "AGE",
+		cell: func(r listRow) string {
+			if r.wt.EnrichmentError != "" {
+				return "error"
+			}
+			return dryrun.RelativeTime(r.wt.LastCommitDate)
+		},
+		raw: func(r listRow) string {
+			if r.wt.LastCommitDate.IsZero() {
+				return ""
+			}
+			return r.wt.LastCommitDate.Format(time.RFC3339)
+		},
+	},
+	"subject": {
+		header: "SUBJECT",
+		cell: func(r listRow) string {
+			if r.wt.EnrichmentError != "" {
+				return r.wt.EnrichmentError
+			}
+			return r.wt.LastCommitSubject
+		},
+	},
+	"path": {header: "PATH", cell: func(r listRow) string { return r.wt.Path }},
+	"head": {
+		header: "HEAD",
+		cell: func(r listRow) string {
+			if len(r.wt.HEAD) > 7 {
+				return r.wt.HEAD[:7]
+			}
+			return r.wt.HEAD
+		},
+		raw: func(r listRow) string { return r.wt.HEAD },
+	},
+	"ahead-behind": {
+		header: "AHEAD/BEHIND",
+		cell:   func(r listRow) string { return dryrun.AheadBehind(r.wt) },
+		// Two TSV fields, ahead and behind, both empty without a live upstream.
+		raw: func(r listRow) string {
+			if !r.wt.HasUpstream() {
+				return "\t"
+			}
+			return fmt.Sprintf("%d\t%d", r.wt.Ahead, r.wt.Behind)
+		},
+	},
+	"lock-reason": {header: "LOCK REASON", cell: func(r listRow) string { return r.wt.LockReason }},
+	"size": {
+		header: "SIZE",
+		cell:   func(r listRow) string { return fileutil.FormatSize(r.size) },
+		raw:    func(r listRow) string { return strconv.FormatInt(r.size, 10) },
+	},
+}
+
+// listColumnNames returns every column name, sorted, for help and errors.
+func listColumnNames() []string {
+	names := make([]string, 0, len(listColumns))
+	for name := range listColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RunList prints the repository's worktrees, oldest commit first, narrowed by
+// the filter flags.
+func RunList(args []string) error {
+	opts, err := ParseListFlags(args)
+	if err != nil {
+		return err
+	}
+
+	repoPath := "."
+	if opts.RepoPath != "" {
+		repoPath = opts.RepoPath
+	}
+	if absPath, err := filepath.Abs(repoPath); err == nil {
+		repoPath = absPath
+	}
+
+	runner := &git.GitRunner{}
+
+	switch repo.DetectContext(runner, repoPath) {
+	case repo.ContextBareRepo:
+		repoPath = repo.ResolveBareRoot(runner, repoPath)
+	case repo.ContextNonBareRepo:
+	default:
+		return fmt.Errorf("list requires a git repository: %s", repoPath)
+	}
+
+	worktrees, err := git.ListWorktrees(runner, repoPath)
+	if err != nil {
+		return fmt.Errorf("listing worktrees: %w", err)
+	}
+	worktrees = enrichForList(runner, repoPath, worktrees, !opts.NoCache)
+
+	defaultBranch := git.DetectDefaultBranch(runner, repoPath)
+	protection := LoadProtectionPolicy(runner, repoPath, defaultBranch)
+
+	var filters []worktreePredicate
+	if opts.Stale > 0 {
+		filters = append(filters, staleFilter(opts.Stale, time.Now()))
+	}
+	if opts.Merged {
+		filters = append(filters, mergedFilter(CheckMerged(runner, repoPath, defaultBranch)))
+	}
+	if opts.Behind {
+		filters = append(filters, behindFilter)
+	}
+	if opts.UpstreamGone {
+		filters = append(filters, upstreamGoneFilter)
+	}
+	if opts.Dirty {
+		filters = append(filters, dirtyFilter)
+	}
+	if opts.Locked {
+		filters = append(filters, lockedFilter)
+	}
+	if opts.BranchGlob != "" {
+		filters = append(filters, branchGlobFilter(opts.BranchGlob))
+	}
+	// Last, so gh only runs for worktrees every other filter kept.
+	var prChecker *pr.Checker
+	if opts.PRMerged {
+		prChecker = pr.NewChecker(&repo.DefaultGhRunner{}, repoPath)
+		filters = append(filters, mergedFilter(CheckPRMerged(prChecker, defaultBranch)))
+	}
+
+	var rows []listRow
+	for _, wt := range dryrun.SortByAge(worktrees) {
+		if wt.IsBare || !matchesAll(wt, filters) {
+			continue
+		}
+		row := listRow{wt: wt, protected: protection.IsProtected(wt.Branch)}
+		if opts.hasColumn("size") && !wt.IsPrunable {
+			row.size = fileutil.DirSize(wt.Path)
+		}
+		rows = append(rows, row)
+	}
+	warnPRUnavailable(prChecker)
+
+	switch opts.Format {
+	case ListFormatJSON, ListFormatNDJSON:
+		return writeListReport(report.NewWriter(os.Stdout, report.Format(opts.Format)), rows, opts, protection)
+	case ListFormatTSV:
+		return writeListTSV(os.Stdout, rows, opts.Columns)
+	}
+	if len(rows) == 0 {
+		fmt.Println("No worktrees matched.")
+		return nil
+	}
+	return writeListTable(os.Stdout, rows, opts.Columns)
+}
+
+func matchesAll(wt git.Worktree, filters []worktreePredicate) bool {
+	for _, match := range filters {
+		if !match(wt) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeListTable(w io.Writer, rows []listRow, columns []string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, name := range columns {
+		headers[i] = listColumns[name].header
+	}
+	if _, err := fmt.Fprintln(tw, strings.Join(headers, "\t")); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, name := range columns {
+			cells[i] = tableCell(listColumns[name].cell(row))
+		}
+		if _, err := fmt.Fprintln(tw, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// writeListTSV writes column names as the header row. ahead-behind expands to
+// two fields, ahead and behind, so every TSV field holds a single value.
+func writeListTSV(w io.Writer, rows []listRow, columns []string) error {
+	var headers []string
+	for _, name := range columns {
+		if name == "ahead-behind" {
+			headers = append(headers, "ahead", "behind")
+			continue
+		}
+		headers = append(headers, name)
+	}
+	if _, err := fmt.Fprintln(w, strings.Join(headers, "\t")); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, name := range columns {
+			col := listColumns[name]
+			if col.raw != nil {
+				cells[i] = col.raw(row)
+				continue
+			}
+			cells[i] = tableCell(col.cell(row))
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeListReport writes the full worktree documents. Column selection does
+// not narrow the documents; it only decides whether the costly size fact is
+// computed and included.
+func writeListReport(out *report.Writer, rows []listRow, opts *ListOptions, protection *git.ProtectionPolicy) error {
+	docs := make([]report.Worktree, len(rows))
+	for i, row := range rows {
+		doc := report.NewWorktree(row.wt, protection)
+		if opts.hasColumn("size") && !row.wt.IsPrunable {
+			size := row.size
+			doc.DiskSize = &size
+		}
+		docs[i] = doc
+	}
+	return out.Write(report.KindWorktrees, docs)
+}
+
+// tableCell keeps a value on one line and inside its column.
+func tableCell(s string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ").Replace(s)
+}
+
+// enrichForList enriches worktrees for display. With useCache, worktrees
+// unchanged since the last run reuse their cached status; list only reads,
+// so a stale flag costs nothing. A cache that cannot be read or written is
+// ignored.
+func enrichForList(runner git.CommandRunner, repoPath string, worktrees []git.Worktree, useCache bool) []git.Worktree {
+	if !useCache {
+		return worktree.EnrichWorktrees(runner, worktrees, worktree.DefaultEnrichConcurrency)
+	}
+	var cache *worktree.Cache
+	if commonDir, err := git.CommonDir(runner, repoPath); err == nil {
+		cache, _ = worktree.LoadCache(commonDir)
+	}
+	worktrees = worktree.EnrichRefs(runner, worktrees)
+	worktrees = worktree.EnrichStatus(runner, worktrees, worktree.DefaultEnrichConcurrency, cache, nil)
+	if cache != nil {
+		_ = cache.Save(worktrees)
+	}
+	return worktrees
+}
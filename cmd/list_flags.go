@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/cli"
+)
+
+// List output formats. table is the human default; json and ndjson write
+// report records; tsv is a header row plus one tab-separated row per worktree.
+const (
+	ListFormatTable  = "table"
+	ListFormatTSV    = "tsv"
+	ListFormatJSON   = "json"
+	ListFormatNDJSON = "ndjson"
+)
+
+// ListOptions holds parsed flags for the list command. Filters combine with
+// AND logic; no filters lists every worktree.
+type ListOptions struct {
+	Stale        time.Duration
+	Merged       bool
+	PRMerged     bool
+	Behind       bool
+	UpstreamGone bool
+	Dirty        bool
+	Locked       bool
+	BranchGlob   string
+	Columns      []string
+	Format       string
+	RepoPath     string
+	// NoCache checks every worktree's status instead of reusing the
+	// enrichment cache.
+	NoCache bool
+}
+
+// listFlags is the list command's flag set, shared by the parser and the
+// completion metadata.
+type listFlags struct {
+	fs           *flag.FlagSet
+	stale        *string
+	merged       *bool
+	prMerged     *bool
+	behind       *bool
+	upstreamGone *bool
+	dirty        *bool
+	locked       *bool
+	branchGlob   *string
+	columns      *string
+	format       *string
+	noCache      *bool
+}
+
+func newListFlags() *listFlags {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	return &listFlags{
+		fs:           fs,
+		stale:        fs.String("stale", "", "Only worktrees older than duration (e.g., 30d, 2w, 3m)"),
+		merged:       fs.Bool("merged", false, "Only worktrees whose branches are fully merged"),
+		prMerged:     fs.Bool("pr-merged", false, "Only worktrees whose pull request was merged (needs gh)"),
+		behind:       fs.Bool("behind", false, "Only worktrees whose branches are behind their upstream"),
+		upstreamGone: fs.Bool("upstream-gone", false, "Only worktrees whose upstream branch was deleted"),
+		dirty:        fs.Bool("dirty", false, "Only worktrees with uncommitted or untracked changes"),
+		locked:       fs.Bool("locked", false, "Only locked worktrees"),
+		branchGlob:   fs.String("branch-glob", "", "Only branches matching a glob (e.g., 'feature/*')"),
+		columns: fs.String("columns", strings.Join(defaultListColumns, ","),
+			"Comma-separated columns: "+strings.Join(listColumnNames(), ", ")),
+		format:  fs.String("format", ListFormatTable, "Output format: table, tsv, json or ndjson"),
+		noCache: fs.Bool("no-cache", false, "Check every worktree's status instead of reusing cached results"),
+	}
+}
+
+// ListFlags describes the list command's flags for usage and shell
+// completion.
+func ListFlags() []cli.Flag {
+	return cli.FlagsOf(newListFlags().fs,
+		cli.Flag{Name: "format", Kind: cli.FlagEnum, Values: []string{ListFormatTable, ListFormatTSV, ListFormatJSON, ListFormatNDJSON}})
+}
+
+// ParseListFlags parses list-specific flags and returns ListOptions.
+func ParseListFlags(args []string) (*ListOptions, error) {
+	fl := newListFlags()
+	if err := fl.fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	opts := &ListOptions{
+		Merged:       *fl.merged,
+		PRMerged:     *fl.prMerged,
+		Behind:       *fl.behind,
+		UpstreamGone: *fl.upstreamGone,
+		Dirty:        *fl.dirty,
+		Locked:       *fl.locked,
+		BranchGlob:   *fl.branchGlob,
+		NoCache:      *fl.noCache,
+	}
+
+	if *fl.stale != "" {
+		d, err := ParseStaleDuration(*fl.stale)
+		if err != nil {
+			return nil, err
+		}
+		opts.Stale = d
+	}
+
+	if opts.BranchGlob != "" {
+		if _, err := path.Match(opts.BranchGlob, ""); err != nil {
+			return nil, fmt.Errorf("invalid --branch-glob %q: %w", opts.BranchGlob, err)
+		}
+	}
+
+	for _, name := range strings.Split(*fl.columns, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := listColumns[name]; !ok {
+			return nil, fmt.Errorf("unknown column %q: must be one of %s", name, strings.Join(listColumnNames(), ", "))
+		}
+		opts.Columns = append(opts.Columns, name)
+	}
+	if len(opts.Columns) == 0 {
+		return nil, fmt.Errorf("--columns must name at least one column")
+	}
+
+	switch *fl.format {
+	case "text", ListFormatTable:
+		opts.Format = ListFormatTable
+	case ListFormatTSV, ListFormatJSON, ListFormatNDJSON:
+		opts.Format = *fl.format
+	default:
+		return nil, fmt.Errorf("invalid value for --format: must be 'table', 'tsv', 'json' or 'ndjson'")
+	}
+
+	if fl.fs.NArg() > 0 {
+		opts.RepoPath = fl.fs.Arg(0)
+	}
+
+	return opts, nil
+}
+
+// hasColumn reports whether the listing shows the named column.
+func (o *ListOptions) hasColumn(name string) bool {
+	for _, c := range o.Columns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/report"
+	"github.com/abiswas97/sentei/internal/worktree"
+)
+
+func TestParseListFlags(t *testing.T) {
+	opts, err := ParseListFlags([]string{"--dirty", "--branch-glob", "feature/*", "--columns", "branch, path,size", "--format", "tsv", "--no-cache", "/repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !opts.Dirty || opts.BranchGlob != "feature/*" || opts.Format != ListFormatTSV || !opts.NoCache || opts.RepoPath != "/repo" {
+		t.Errorf("opts = %+v", opts)
+	}
+	if !slices.Equal(opts.Columns, []string{"branch", "path", "size"}) {
+		t.Errorf("Columns = %v", opts.Columns)
+	}
+
+	defaults, err := ParseListFlags(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(defaults.Columns, defaultListColumns) || defaults.Format != ListFormatTable {
+		t.Errorf("defaults = %+v", defaults)
+	}
+}
+
+func TestParseListFlags_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown column", []string{"--columns", "branch,colour"}},
+		{"empty columns", []string{"--columns", ","}},
+		{"bad glob", []string{"--branch-glob", "feature/["}},
+		{"bad format", []string{"--format", "csv"}},
+		{"bad stale", []string{"--stale", "abc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseListFlags(tt.args); err == nil {
+				t.Errorf("ParseListFlags(%v) expected an error", tt.args)
+			}
+		})
+	}
+}
+
+func TestRunList_TableMarksProtectedAndFilters(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+	mustGit(t, bareRepo, "worktree", "add", filepath.Join(bareRepo, "main"), "main")
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunList([]string{bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "main [P]") || !strings.Contains(out, "feature/merged-branch") {
+		t.Errorf("expected both worktrees with main protected, got:\n%s", out)
+	}
+
+	out = captureStdout(t, func() {
+		err = RunList([]string{"--branch-glob", "feature/*", "--columns", "branch,path", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out, "main [P]") {
+		t.Errorf("--branch-glob must exclude main, got:\n%s", out)
+	}
+	if !strings.Contains(out, "PATH") || !strings.Contains(out, filepath.Join(bareRepo, "feature-merged-branch")) {
+		t.Errorf("expected the path column, got:\n%s", out)
+	}
+}
+
+func TestRunList_FiltersCombineWithAnd(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+	mustGit(t, bareRepo, "worktree", "add", filepath.Join(bareRepo, "main"), "main")
+	mustWriteFile(t, filepath.Join(bareRepo, "main", "scratch.txt"), "dirty\n")
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunList([]string{"--dirty", "--branch-glob", "feature/*", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "No worktrees matched.") {
+		t.Errorf("the only dirty worktree is main; expected no match, got:\n%s", out)
+	}
+}
+
+func TestRunList_CachesStatusAndNoticesChanges(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+
+	var err error
+	captureStdout(t, func() { err = RunList([]string{bareRepo}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, statErr := os.Stat(worktree.CachePath(bareRepo)); statErr != nil {
+		t.Fatalf("expected the first run to write the enrichment cache: %v", statErr)
+	}
+
+	// A new top-level file invalidates the cached clean status.
+	mustWriteFile(t, filepath.Join(bareRepo, "feature-merged-branch", "scratch.txt"), "dirty\n")
+	out := captureStdout(t, func() { err = RunList([]string{"--dirty", "--columns", "branch", bareRepo}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "feature/merged-branch") {
+		t.Errorf("cached status should not hide the new file, got:\n%s", out)
+	}
+}
+
+func TestRunList_TSV(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunList([]string{"--format", "tsv", "--columns", "branch,ahead-behind,size", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header plus one row, got:\n%s", out)
+	}
+	if lines[0] != "branch\tahead\tbehind\tsize" {
+		t.Errorf("header = %q", lines[0])
+	}
+	fields := strings.Split(lines[1], "\t")
+	if len(fields) != 4 || fields[0] != "feature/merged-branch" {
+		t.Errorf("row = %q, want 4 fields for feature/merged-branch", lines[1])
+	}
+}
+
+func TestRunList_JSONIncludesRequestedFacts(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunList([]string{"--format", "json", "--columns", "branch,size", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var rec struct {
+		Kind string            `json:"kind"`
+		Data []report.Worktree `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &rec); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if rec.Kind != report.KindWorktrees || len(rec.Data) != 1 {
+		t.Fatalf("record = %+v", rec)
+	}
+	if rec.Data[0].DiskSize == nil || *rec.Data[0].DiskSize == 0 {
+		t.Errorf("disk_size must be set when the size column is requested: %+v", rec.Data[0])
+	}
+}
+
+func TestRunList_RequiresGitRepository(t *testing.T) {
+	if err := RunList([]string{t.TempDir()}); err == nil {
+		t.Error("expected an error outside a git repository")
+	}
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/report"
+	"github.com/abiswas97/sentei/internal/worktree"
+)
+
+// RunLock locks a worktree so git refuses to prune, move or remove it, and
+// remove's filters pass it over.
+func RunLock(args []string) error {
+	opts, err := ParseLockFlags(args)
+	if err != nil {
+		return err
+	}
+	return runLock(&git.GitRunner{}, ".", opts, true, os.Stdout)
+}
+
+// RunUnlock lifts a worktree's lock.
+func RunUnlock(args []string) error {
+	opts, err := ParseUnlockFlags(args)
+	if err != nil {
+		return err
+	}
+	return runLock(&git.GitRunner{}, ".", opts, false, os.Stdout)
+}
+
+// LockPrompt describes the lock about to be taken, for the confirmation
+// asked before it runs.
+func LockPrompt(args []string) (string, bool, error) {
+	opts, err := ParseLockFlags(args)
+	if err != nil {
+		return "", false, err
+	}
+	reason := ""
+	if opts.Reason != "" {
+		reason = fmt.Sprintf(" (%s)", opts.Reason)
+	}
+	return fmt.Sprintf("Lock %s%s so git refuses to prune, move or remove it and remove's filters pass it over.", opts.Worktree, reason), true, nil
+}
+
+// UnlockPrompt describes the lock about to be lifted, for the confirmation
+// asked before it runs.
+func UnlockPrompt(args []string) (string, bool, error) {
+	opts, err := ParseUnlockFlags(args)
+	if err != nil {
+		return "", false, err
+	}
+	return fmt.Sprintf("Unlock %s so git and remove's filters can remove it again.", opts.Worktree), true, nil
+}
+
+func runLock(runner git.CommandRunner, dir string, opts *LockOptions, lock bool, out io.Writer) error {
+	cwd := dir
+	if absPath, err := filepath.Abs(cwd); err == nil {
+		cwd = absPath
+	}
+	if repo.DetectContext(runner, cwd) == repo.ContextNoRepo {
+		return fmt.Errorf("%s requires a git repository: %s", lockVerb(lock), cwd)
+	}
+	root := repo.ResolveBareRoot(runner, cwd)
+
+	worktrees, err := git.ListWorktrees(runner, root)
+	if err != nil {
+		return err
+	}
+	wt, err := findWorktree(worktrees, cwd, opts.Worktree)
+	if err != nil {
+		return err
+	}
+
+	doc := report.Lock{Path: wt.Path, Branch: shortBranch(wt.Branch), Locked: wt.IsLocked, Reason: wt.LockReason}
+	switch {
+	case lock && wt.IsLocked:
+		reason := ""
+		if wt.LockReason != "" {
+			reason = fmt.Sprintf(" (%s)", wt.LockReason)
+		}
+		return fmt.Errorf("%s is already locked%s; unlock it first to change the reason", wt.Path, reason)
+	case lock:
+		if err := worktree.LockWorktree(runner, root, wt.Path, opts.Reason); err != nil {
+			return fmt.Errorf("locking %s: %w", wt.Path, err)
+		}
+		doc.Locked, doc.Reason, doc.Changed = true, opts.Reason, true
+	case wt.IsLocked:
+		if err := worktree.UnlockWorktree(runner, root, wt.Path); err != nil {
+			return fmt.Errorf("unlocking %s: %w", wt.Path, err)
+		}
+		doc.Locked, doc.Reason, doc.Changed = false, "", true
+	}
+
+	if opts.Format.Machine() {
+		return report.NewWriter(out, opts.Format).Write(report.KindLock, doc)
+	}
+	name := lockSubject(wt)
+	switch {
+	case !doc.Changed:
+		fmt.Fprintf(out, "%s%s is not locked%s\n", dim, name, nc)
+	case doc.Locked && doc.Reason != "":
+		fmt.Fprintf(out, "%s✓%s Locked %s %s(%s)%s\n", green, nc, name, dim, doc.Reason, nc)
+	case doc.Locked:
+		fmt.Fprintf(out, "%s✓%s Locked %s\n", green, nc, name)
+	default:
+		fmt.Fprintf(out, "%s✓%s Unlocked %s\n", green, nc, name)
+	}
+	return nil
+}
+
+func lockVerb(lock bool) string {
+	if lock {
+		return "lock"
+	}
+	return "unlock"
+}
+
+func lockSubject(wt git.Worktree) string {
+	if branch := shortBranch(wt.Branch); branch != "" {
+		return branch
+	}
+	return filepath.Base(wt.Path)
+}
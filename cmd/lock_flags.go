@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// LockOptions holds parsed flags for the lock and unlock commands.
+type LockOptions struct {
+	// Worktree names the worktree: its branch, directory name or path.
+	Worktree string
+	// Reason is recorded with the lock; unlock has no --reason.
+	Reason string
+	Format report.Format
+}
+
+// lockFlags is the lock or unlock command's flag set, shared by the parser
+// and the completion metadata.
+type lockFlags struct {
+	fs     *flag.FlagSet
+	reason *string
+	format *string
+}
+
+func newLockFlags(name string) *lockFlags {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fl := &lockFlags{fs: fs, format: formatFlag(fs)}
+	if name == "lock" {
+		fl.reason = fs.String("reason", "", "Why the worktree is locked, shown by list and the TUI")
+	}
+	return fl
+}
+
+// LockFlags describes the lock command's flags for usage and shell
+// completion.
+func LockFlags() []cli.Flag {
+	return cli.FlagsOf(newLockFlags("lock").fs)
+}
+
+// UnlockFlags describes the unlock command's flags for usage and shell
+// completion.
+func UnlockFlags() []cli.Flag {
+	return cli.FlagsOf(newLockFlags("unlock").fs)
+}
+
+// ParseLockFlags parses `lock <worktree> [--reason text]`.
+func ParseLockFlags(args []string) (*LockOptions, error) {
+	return parseLockFlags("lock", args)
+}
+
+// ParseUnlockFlags parses `unlock <worktree>`.
+func ParseUnlockFlags(args []string) (*LockOptions, error) {
+	return parseLockFlags("unlock", args)
+}
+
+// parseLockFlags lets flags follow the worktree as well as precede it, so
+// `sentei lock feature --reason "..."` reads the way it is typed.
+func parseLockFlags(name string, args []string) (*LockOptions, error) {
+	fl := newLockFlags(name)
+	if err := fl.fs.Parse(args); err != nil {
+		return nil, err
+	}
+	var positional []string
+	for fl.fs.NArg() > 0 {
+		positional = append(positional, fl.fs.Arg(0))
+		if err := fl.fs.Parse(fl.fs.Args()[1:]); err != nil {
+			return nil, err
+		}
+	}
+	format, err := report.ParseFormat(*fl.format)
+	if err != nil {
+		return nil, err
+	}
+	if len(positional) != 1 {
+		if name == "lock" {
+			return nil, fmt.Errorf("usage: sentei lock <worktree> [--reason text]")
+		}
+		return nil, fmt.Errorf("usage: sentei unlock <worktree>")
+	}
+	opts := &LockOptions{Worktree: positional[0], Format: format}
+	if fl.reason != nil {
+		opts.Reason = *fl.reason
+	}
+	return opts, nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/fileutil"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+func TestParseLockFlags_ReasonAfterWorktree(t *testing.T) {
+	opts, err := ParseLockFlags([]string{"feature", "--reason", "on a usb drive", "--format", "json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Worktree != "feature" || opts.Reason != "on a usb drive" || opts.Format != report.FormatJSON {
+		t.Errorf("opts = %+v", opts)
+	}
+	if _, err := ParseLockFlags([]string{"a", "b"}); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Errorf("err = %v, want usage", err)
+	}
+	if _, err := ParseUnlockFlags([]string{"feature", "--reason", "x"}); err == nil {
+		t.Error("unlock accepted --reason")
+	}
+}
+
+func TestRunLock_LocksAndUnlocks(t *testing.T) {
+	root := setupDoctorLayout(t)
+	wtPath := filepath.Join(root, "feature")
+	mustGit(t, filepath.Join(root, ".bare"), "worktree", "add", wtPath, "-b", "feature", "main")
+	runner := &git.GitRunner{}
+
+	lockState := func() git.Worktree {
+		t.Helper()
+		wts, err := git.ListWorktrees(runner, root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, wt := range wts {
+			if fileutil.SamePath(wt.Path, wtPath) {
+				return wt
+			}
+		}
+		t.Fatalf("%s not listed", wtPath)
+		return git.Worktree{}
+	}
+
+	var out bytes.Buffer
+	opts := &LockOptions{Worktree: "feature", Reason: "on a usb drive", Format: report.FormatJSON}
+	if err := runLock(runner, root, opts, true, &out); err != nil {
+		t.Fatalf("runLock: %v", err)
+	}
+	var rec struct {
+		Kind string      `json:"kind"`
+		Data report.Lock `json:"data"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &rec); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out.String())
+	}
+	if rec.Kind != report.KindLock || !rec.Data.Locked || !rec.Data.Changed || rec.Data.Reason != "on a usb drive" {
+		t.Fatalf("record = %+v", rec)
+	}
+	if wt := lockState(); !wt.IsLocked || wt.LockReason != "on a usb drive" {
+		t.Fatalf("worktree = %+v", wt)
+	}
+
+	if err := runLock(runner, root, &LockOptions{Worktree: "feature"}, true, &out); err == nil || !strings.Contains(err.Error(), "already locked (on a usb drive)") {
+		t.Fatalf("second lock err = %v", err)
+	}
+
+	out.Reset()
+	if err := runLock(runner, root, &LockOptions{Worktree: "feature"}, false, &out); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	if lockState().IsLocked || !strings.Contains(out.String(), "Unlocked feature") {
+		t.Fatalf("still locked; output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := runLock(runner, root, &LockOptions{Worktree: "feature"}, false, &out); err != nil || !strings.Contains(out.String(), "feature is not locked") {
+		t.Fatalf("unlock of an unlocked worktree: %v\n%s", err, out.String())
+	}
+}
+
+func TestLockPrompt_NamesTheChange(t *testing.T) {
+	prompt, needed, err := LockPrompt([]string{"--reason", "release", "feature"})
+	if err != nil || !needed || !strings.Contains(prompt, "Lock feature (release)") {
+		t.Errorf("LockPrompt = %q, %v, %v", prompt, needed, err)
+	}
+	prompt, needed, err = UnlockPrompt([]string{"feature"})
+	if err != nil || !needed || !strings.Contains(prompt, "Unlock feature") {
+		t.Errorf("UnlockPrompt = %q, %v, %v", prompt, needed, err)
+	}
+}
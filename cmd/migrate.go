@@ -8,6 +8,7 @@ import (
 	"github.com/abiswas97/sentei/internal/git"
 	"github.com/abiswas97/sentei/internal/progress"
 	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/report"
 )
 
 // RunMigrate executes the migrate command in non-interactive mode.
@@ -36,8 +37,17 @@ func RunMigrate(args []string) error {
 		return fmt.Errorf("not a git repository: %s", repoPath)
 	}
 
+	inventory, err := repo.InventoryMigration(runner, repoPath)
+	if err != nil {
+		return fmt.Errorf("inventorying repository: %w", err)
+	}
 	migrateOpts := repo.MigrateOptions{
-		RepoPath: repoPath,
+		RepoPath:  repoPath,
+		Inventory: inventory,
+	}
+
+	if out := newReport(opts.Format); out != nil {
+		return reportMigrate(out, runner, shell, migrateOpts, opts.DeleteBackup)
 	}
 
 	result := repo.Migrate(runner, shell, migrateOpts, printMigrateEvent)
@@ -67,6 +77,7 @@ func RunMigrate(args []string) error {
 	fmt.Printf("  Bare root:  %s\n", result.BareRoot)
 	fmt.Printf("  Worktree:   %s\n", result.WorktreePath)
 	fmt.Printf("  Backup:     %s (%s)\n", result.BackupPath, result.BackupSize)
+	printMigrateCarryOver(result.Inventory)
 
 	if opts.DeleteBackup && result.BackupPath != "" {
 		fmt.Printf("\n%s→%s Deleting backup...\n", blue, nc)
@@ -80,6 +91,50 @@ func RunMigrate(args []string) error {
 	return nil
 }
 
+// reportMigrate is RunMigrate for machine formats. The backup is only deleted
+// after a fully successful migration, as in text mode; a failed deletion is
+// reported on stderr and leaves backup_path in the document.
+func reportMigrate(out *report.Writer, runner git.CommandRunner, shell git.ShellRunner, migrateOpts repo.MigrateOptions, deleteBackup bool) error {
+	result := repo.Migrate(runner, shell, migrateOpts, out.Events())
+	failed := result.Err != nil || progress.PhasesHaveFailures(result.Phases)
+
+	if !failed && deleteBackup && result.BackupPath != "" {
+		if err := repo.DeleteBackup(result.BackupPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to delete backup: %v\n", err)
+		} else {
+			result.BackupPath, result.BackupSize = "", ""
+		}
+	}
+
+	if err := out.Write(report.KindMigrate, report.NewMigrateResult(result)); err != nil {
+		return err
+	}
+	if err := migrateResultError(result); err != nil {
+		return err
+	}
+	if name, _, ok := progress.FirstFailure(result.Phases); ok {
+		return fmt.Errorf("migration failed during %s phase", name)
+	}
+	return nil
+}
+
+// printMigrateCarryOver reports where the stashes went and what the
+// migration left behind.
+func printMigrateCarryOver(inventory repo.MigrateInventory) {
+	if n := len(inventory.Stashes); n > 0 {
+		fmt.Printf("  Stashes:    %d kept under refs/stashes (git stash apply %s)\n", n, inventory.Stashes[0].Ref)
+	}
+	if n := len(inventory.Worktrees); n > 0 {
+		fmt.Printf("  Worktrees:  %d re-registered in place\n", n)
+	}
+	if len(inventory.Unmovable) > 0 {
+		fmt.Printf("\n%s⚠%s  Not carried over:\n", yellow, nc)
+		for _, item := range inventory.Unmovable {
+			fmt.Printf("  %s\n", item)
+		}
+	}
+}
+
 func migrateResultError(result repo.MigrateResult) error {
 	if result.Err != nil {
 		return fmt.Errorf("migration failed: %w", result.Err)
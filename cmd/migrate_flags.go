@@ -3,30 +3,61 @@ package cmd
 import (
 	"flag"
 	"fmt"
+
+	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/report"
 )
 
 // MigrateOptions holds the parsed flags for the migrate command.
 type MigrateOptions struct {
 	DeleteBackup bool
 	RepoPath     string
+	Format       report.Format
+}
+
+// migrateFlags is the migrate command's flag set, shared by the parser and
+// the completion metadata.
+type migrateFlags struct {
+	fs           *flag.FlagSet
+	deleteBackup *bool
+	format       *string
+}
+
+func newMigrateFlags() *migrateFlags {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	return &migrateFlags{
+		fs:           fs,
+		deleteBackup: fs.Bool("delete-backup", false, "Delete the backup after successful migration"),
+		format:       formatFlag(fs),
+	}
+}
+
+// MigrateFlags describes the migrate command's flags for usage and shell
+// completion.
+func MigrateFlags() []cli.Flag {
+	return cli.FlagsOf(newMigrateFlags().fs)
 }
 
 // ParseMigrateFlags parses migrate-specific flags and returns MigrateOptions.
 // The repo path is taken as a positional argument.
 func ParseMigrateFlags(args []string) (*MigrateOptions, error) {
-	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
-	deleteBackup := fs.Bool("delete-backup", false, "Delete the backup after successful migration")
+	fl := newMigrateFlags()
+	if err := fl.fs.Parse(args); err != nil {
+		return nil, err
+	}
 
-	if err := fs.Parse(args); err != nil {
+	f, err := report.ParseFormat(*fl.format)
+	if err != nil {
 		return nil, err
 	}
 
 	opts := &MigrateOptions{
-		DeleteBackup: *deleteBackup,
+		DeleteBackup: *fl.deleteBackup,
+		Format:       f,
 	}
 
-	if fs.NArg() > 0 {
-		opts.RepoPath = fs.Arg(0)
+	if fl.fs.NArg() > 0 {
+		opts.RepoPath = fl.fs.Arg(0)
 	}
 
 	return opts, nil
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/fileutil"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/report"
+	"github.com/abiswas97/sentei/internal/worktree"
+)
+
+// RunMove renames a worktree's branch and moves its directory to match, or
+// moves it to a path, then sets up again the integrations that recorded the
+// old path.
+func RunMove(args []string) error {
+	opts, err := ParseMoveFlags(args)
+	if err != nil {
+		return err
+	}
+	return runMove(&git.GitRunner{}, &git.DefaultShellRunner{}, ".", opts, os.Stdout)
+}
+
+func runMove(runner git.CommandRunner, shell git.ShellRunner, dir string, opts *MoveOptions, out io.Writer) error {
+	cwd := dir
+	if absPath, err := filepath.Abs(cwd); err == nil {
+		cwd = absPath
+	}
+	if repo.DetectContext(runner, cwd) == repo.ContextNoRepo {
+		return fmt.Errorf("move requires a git repository: %s", cwd)
+	}
+	root := repo.ResolveBareRoot(runner, cwd)
+
+	worktrees, err := git.ListWorktrees(runner, root)
+	if err != nil {
+		return err
+	}
+	wt, err := findWorktree(worktrees, cwd, opts.Worktree)
+	if err != nil {
+		return err
+	}
+	commonDir, err := git.CommonDir(runner, root)
+	if err != nil {
+		return err
+	}
+	enabled, err := enabledIntegrations(runner, root, commonDir)
+	if err != nil {
+		return err
+	}
+
+	branch, path := worktree.SplitMoveTarget(opts.Target)
+	if path != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(cwd, path)
+	}
+	prepared, err := worktree.PrepareMove(runner, worktree.MoveOptions{
+		Worktree: wt, Branch: branch, Path: path,
+		RepoPath: root, Integrations: enabled,
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.Format.Machine() {
+		w := report.NewWriter(out, opts.Format)
+		result := prepared.Run(runner, shell, w.Events())
+		if err := w.Write(report.KindMove, report.NewMoveResult(result)); err != nil {
+			return err
+		}
+		return moveResultError(result)
+	}
+
+	result := prepared.Run(runner, shell, func(e progress.Event) { printMoveEvent(out, e) })
+	if err := moveResultError(result); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\n%s✓%s Moved %s\n", green, nc, moveSubject(result))
+	if result.NewBranch != result.OldBranch {
+		fmt.Fprintf(out, "  Branch: %s → %s\n", result.OldBranch, result.NewBranch)
+	}
+	if result.To != result.From {
+		fmt.Fprintf(out, "  Path:   %s\n", result.To)
+	}
+	return nil
+}
+
+// MovePrompt describes the move about to be made, for the confirmation
+// asked before it runs.
+func MovePrompt(args []string) (string, bool, error) {
+	opts, err := ParseMoveFlags(args)
+	if err != nil {
+		return "", false, err
+	}
+	if branch, _ := worktree.SplitMoveTarget(opts.Target); branch != "" {
+		return fmt.Sprintf("Rename %s's branch to %s and move its directory to match.", opts.Worktree, branch), true, nil
+	}
+	return fmt.Sprintf("Move %s to %s, keeping its branch.", opts.Worktree, opts.Target), true, nil
+}
+
+// findWorktree returns the worktree named by its branch, directory name or
+// path (relative to cwd). Moving is not undone by a second guess, so the
+// name must match exactly.
+func findWorktree(worktrees []git.Worktree, cwd, name string) (git.Worktree, error) {
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(cwd, path)
+	}
+	var matches []git.Worktree
+	for _, wt := range worktrees {
+		if wt.IsBare {
+			continue
+		}
+		if strings.TrimPrefix(wt.Branch, "refs/heads/") == name || filepath.Base(wt.Path) == name || fileutil.SamePath(wt.Path, path) {
+			matches = append(matches, wt)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return git.Worktree{}, fmt.Errorf("no worktree named %q", name)
+	case 1:
+		return matches[0], nil
+	}
+	paths := make([]string, len(matches))
+	for i, wt := range matches {
+		paths[i] = wt.Path
+	}
+	return git.Worktree{}, fmt.Errorf("%q names %d worktrees (%s); give its path", name, len(matches), strings.Join(paths, ", "))
+}
+
+func moveSubject(result worktree.MoveResult) string {
+	if result.NewBranch != "" {
+		return result.NewBranch
+	}
+	return filepath.Base(result.To)
+}
+
+func moveResultError(result worktree.MoveResult) error {
+	if result.Err != nil {
+		return fmt.Errorf("move failed: %w", result.Err)
+	}
+	if _, step, ok := progress.FirstFailure(result.Phases); ok {
+		return fmt.Errorf("move failed: %s: %v", step.Name, step.Error)
+	}
+	return nil
+}
+
+func printMoveEvent(out io.Writer, e progress.Event) {
+	switch e.Status {
+	case progress.StepRunning:
+		fmt.Fprintf(out, "%s→%s %s\n", blue, nc, e.StepLabel)
+	case progress.StepDone:
+		msg := ""
+		if e.Message != "" {
+			msg = " — " + e.Message
+		}
+		fmt.Fprintf(out, "%s✓%s %s%s\n", green, nc, e.StepLabel, msg)
+	case progress.StepFailed:
+		fmt.Fprintf(out, "%s✗%s %s: %v\n", yellow, nc, e.StepLabel, e.Error)
+	case progress.StepSkipped:
+		fmt.Fprintf(out, "  %s%s (%s)%s\n", dim, e.StepLabel, e.Message, nc)
+	}
+}
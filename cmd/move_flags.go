@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// MoveOptions holds parsed flags for the move command.
+type MoveOptions struct {
+	// Worktree names the worktree to move: its branch, directory name or
+	// path.
+	Worktree string
+	// Target is the new branch name, or a path when it is absolute or
+	// starts with ./ or ../.
+	Target string
+	Format report.Format
+}
+
+// moveFlags is the move command's flag set, shared by the parser and the
+// completion metadata.
+type moveFlags struct {
+	fs     *flag.FlagSet
+	format *string
+}
+
+func newMoveFlags() *moveFlags {
+	fs := flag.NewFlagSet("move", flag.ContinueOnError)
+	return &moveFlags{
+		fs:     fs,
+		format: formatFlag(fs),
+	}
+}
+
+// MoveFlags describes the move command's flags for usage and shell
+// completion.
+func MoveFlags() []cli.Flag {
+	return cli.FlagsOf(newMoveFlags().fs)
+}
+
+// ParseMoveFlags parses `move [flags] <worktree> <new-branch-or-path>`.
+func ParseMoveFlags(args []string) (*MoveOptions, error) {
+	fl := newMoveFlags()
+	if err := fl.fs.Parse(args); err != nil {
+		return nil, err
+	}
+	format, err := report.ParseFormat(*fl.format)
+	if err != nil {
+		return nil, err
+	}
+	if fl.fs.NArg() != 2 {
+		return nil, fmt.Errorf("usage: sentei move <worktree> <new-branch-or-path>")
+	}
+	return &MoveOptions{
+		Worktree: fl.fs.Arg(0),
+		Target:   fl.fs.Arg(1),
+		Format:   format,
+	}, nil
+}
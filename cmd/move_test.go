@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/fileutil"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+func TestParseMoveFlags_RequiresWorktreeAndTarget(t *testing.T) {
+	opts, err := ParseMoveFlags([]string{"--format", "json", "feature", "renamed"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Worktree != "feature" || opts.Target != "renamed" || opts.Format != report.FormatJSON {
+		t.Errorf("opts = %+v", opts)
+	}
+	if _, err := ParseMoveFlags([]string{"feature"}); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Errorf("err = %v, want usage", err)
+	}
+}
+
+func TestFindWorktree(t *testing.T) {
+	worktrees := []git.Worktree{
+		{Path: "/repo/.bare", IsBare: true},
+		{Path: "/repo/main", Branch: "refs/heads/main"},
+		{Path: "/repo/feature-x", Branch: "refs/heads/feature/x"},
+		{Path: "/other/feature-x", Branch: "refs/heads/other"},
+	}
+	tests := []struct{ name, want, wantErr string }{
+		{name: "main", want: "/repo/main"},
+		{name: "feature/x", want: "/repo/feature-x"},
+		{name: "/other/feature-x", want: "/other/feature-x"},
+		{name: "feature-x", wantErr: "names 2 worktrees"},
+		{name: "nope", wantErr: `no worktree named "nope"`},
+	}
+	for _, tt := range tests {
+		wt, err := findWorktree(worktrees, "/repo", tt.name)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("findWorktree(%q) err = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || wt.Path != tt.want {
+			t.Errorf("findWorktree(%q) = %q, %v; want %q", tt.name, wt.Path, err, tt.want)
+		}
+	}
+}
+
+func TestRunMove_RenamesBranchAndDirectory(t *testing.T) {
+	root := setupDoctorLayout(t)
+	mustGit(t, filepath.Join(root, ".bare"), "worktree", "add", filepath.Join(root, "feature"), "-b", "feature", "main")
+
+	var out bytes.Buffer
+	opts := &MoveOptions{Worktree: "feature", Target: "feature/renamed", Format: report.FormatJSON}
+	if err := runMove(&git.GitRunner{}, &git.DefaultShellRunner{}, root, opts, &out); err != nil {
+		t.Fatalf("runMove: %v\n%s", err, out.String())
+	}
+	var rec struct {
+		Kind string            `json:"kind"`
+		Data report.MoveResult `json:"data"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &rec); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out.String())
+	}
+	want := filepath.Join(root, "feature-renamed")
+	if rec.Kind != report.KindMove || rec.Data.Failed || !fileutil.SamePath(rec.Data.To, want) || rec.Data.NewBranch != "feature/renamed" {
+		t.Fatalf("record = %+v", rec)
+	}
+	branch, err := (&git.GitRunner{}).Run(want, "branch", "--show-current")
+	if err != nil || strings.TrimSpace(branch) != "feature/renamed" {
+		t.Fatalf("branch at %s = %q, %v", want, branch, err)
+	}
+}
+
+func TestMovePrompt_NamesTheChange(t *testing.T) {
+	prompt, needed, err := MovePrompt([]string{"feature", "feature/renamed"})
+	if err != nil || !needed || !strings.Contains(prompt, "branch to feature/renamed") {
+		t.Errorf("rename: prompt = %q, needed = %v, err = %v", prompt, needed, err)
+	}
+	prompt, _, _ = MovePrompt([]string{"feature", "../elsewhere"})
+	if !strings.Contains(prompt, "to ../elsewhere, keeping its branch") {
+		t.Errorf("path move: prompt = %q", prompt)
+	}
+}
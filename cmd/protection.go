@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/abiswas97/sentei/internal/config"
+	"github.com/abiswas97/sentei/internal/git"
+)
+
+// LoadProtectionPolicy builds the protection policy for repoPath from the
+// detected default branch and the merged config's protected_branches. A
+// config that fails to load degrades to the built-in set plus the default
+// branch with a warning: refusing to run would be worse than protecting the
+// conventional names.
+func LoadProtectionPolicy(runner git.CommandRunner, repoPath, defaultBranch string) *git.ProtectionPolicy {
+	cfg, err := config.LoadConfig(repoPath, config.WithRunner(runner))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load config, protecting built-in branches only: %v\n", err)
+		return git.NewProtectionPolicy(defaultBranch, nil)
+	}
+	return git.NewProtectionPolicy(defaultBranch, cfg.ProtectedBranches)
+}
+
+// loadArchiveConfig returns the merged config's archive settings. A config
+// that fails to load leaves archiving off and retention at the default; the
+// protection policy load already warns about the same failure.
+func loadArchiveConfig(runner git.CommandRunner, repoPath string) *config.Config {
+	cfg, err := config.LoadConfig(repoPath, config.WithRunner(runner))
+	if err != nil {
+		return &config.Config{}
+	}
+	return cfg
+}
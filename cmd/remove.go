@@ -8,8 +8,12 @@ import (
 	"time"
 
 	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/journal"
+	"github.com/abiswas97/sentei/internal/pr"
 	"github.com/abiswas97/sentei/internal/progress"
 	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/report"
+	"github.com/abiswas97/sentei/internal/trash"
 	"github.com/abiswas97/sentei/internal/worktree"
 )
 
@@ -54,74 +58,119 @@ func RunRemove(args []string) error {
 	// non-standard, e.g. "production"), and --merged needs it as the merge target.
 	defaultBranch := git.DetectDefaultBranch(runner, repoPath)
 
+	protection := LoadProtectionPolicy(runner, repoPath, defaultBranch)
+	archive := opts.Archive || loadArchiveConfig(runner, repoPath).ArchivesEnabled()
+
 	var isMerged MergedChecker
 	if opts.Merged {
 		isMerged = CheckMerged(runner, repoPath, defaultBranch)
 	}
+	var prChecker *pr.Checker
+	var prMerged MergedChecker
+	if opts.PRMerged {
+		prChecker = pr.NewChecker(&repo.DefaultGhRunner{}, repoPath)
+		prMerged = CheckPRMerged(prChecker, defaultBranch)
+	}
 
-	filtered := ResolveFilters(worktrees, opts, nil, defaultBranch, isMerged)
+	filtered := ResolveFilters(worktrees, opts, protection, isMerged, prMerged)
+	warnPRUnavailable(prChecker)
 
-	// Count a protected worktree as "skipped" only if the active filter would
-	// otherwise have selected it — otherwise the message implies protection saved
-	// a worktree the filter never wanted.
+	// Count a protected or locked worktree as "skipped" only if the active
+	// filter would otherwise have selected it — otherwise the message implies
+	// protection saved a worktree the filter never wanted.
 	now := time.Now()
-	var protectedCount int
+	withLocked := *opts
+	withLocked.IncludeLocked = true
+	var protectedCount, lockedCount int
 	for _, wt := range worktrees {
-		if wt.IsBare || !git.IsProtectedBranchWith(wt.Branch, defaultBranch) {
-			continue
-		}
-		if matchesFilters(wt, opts, now, isMerged, shortBranch(wt.Branch)) {
-			protectedCount++
+		switch {
+		case wt.IsBare:
+		case protection.IsProtected(wt.Branch):
+			if matchesFilters(wt, opts, now, isMerged, prMerged) {
+				protectedCount++
+			}
+		case wt.IsLocked && !opts.IncludeLocked:
+			if !matchesFilters(wt, opts, now, isMerged, prMerged) && matchesFilters(wt, &withLocked, now, isMerged, prMerged) {
+				lockedCount++
+			}
 		}
 	}
 
 	// At-risk gate: without --force, refuse deletions that would lose work
-	// existing nowhere else. Dry-run is exempt (it deletes nothing).
-	if !opts.Force && !opts.DryRun {
+	// existing nowhere else. Dry-run is exempt (it deletes nothing), and so is
+	// archiving, which keeps that work restorable.
+	if !opts.Force && !opts.DryRun && !archive {
 		var atRisk []string
 		for _, wt := range filtered {
-			if wt.HasUncommittedChanges || wt.HasUntrackedFiles || wt.HasUnpushedCommits {
+			if worktree.NeedsArchive(wt) {
 				atRisk = append(atRisk, shortBranch(wt.Branch))
 			}
 		}
 		if len(atRisk) > 0 {
-			return fmt.Errorf("%d worktree(s) have uncommitted, untracked, or unpushed work (%s); re-run with --force to delete them, or confirm interactively",
+			return fmt.Errorf("%d worktree(s) have uncommitted, untracked, or unpushed work (%s); re-run with --archive to keep them restorable, --force to delete them, or confirm interactively",
 				len(atRisk), strings.Join(atRisk, ", "))
 		}
 	}
 
-	// Unlock locked worktrees so removal + prune can clean them up
+	// Unlock locked worktrees so removal + prune can clean them up; a dry
+	// run leaves them locked.
 	for _, wt := range filtered {
-		if wt.IsLocked {
+		if wt.IsLocked && !opts.DryRun {
 			if err := worktree.UnlockWorktree(runner, repoPath, wt.Path); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to unlock %s: %v\n", wt.Path, err)
 			}
 		}
 	}
 
+	out := newReport(opts.Format)
+	doc := report.Remove{
+		DryRun:           opts.DryRun,
+		Worktrees:        report.NewWorktrees(filtered, protection),
+		ProtectedSkipped: protectedCount,
+		LockedSkipped:    lockedCount,
+	}
+
 	if len(filtered) == 0 {
+		if out != nil {
+			return out.Write(report.KindRemove, doc)
+		}
 		fmt.Println("No worktrees matched the specified filters.")
+		printLockedSkipped(lockedCount)
 		return nil
 	}
 
 	if opts.DryRun {
+		if out != nil {
+			return out.Write(report.KindRemove, doc)
+		}
 		fmt.Printf("%s(dry run)%s Would remove %d worktree(s):\n", dim, nc, len(filtered))
 		dirtyCount := 0
 		for _, wt := range filtered {
 			marker := ""
-			if wt.HasUncommittedChanges || wt.HasUntrackedFiles {
+			if archive && worktree.NeedsArchive(wt) {
+				marker = dim + "  (will be archived first)" + nc
+			} else if wt.HasUncommittedChanges || wt.HasUntrackedFiles {
 				marker = yellow + "  (uncommitted/untracked — will be LOST)" + nc
 				dirtyCount++
 			}
+			if wt.IsLocked {
+				marker += dim + "  (locked — will be unlocked)" + nc
+			}
 			fmt.Printf("  %s%s\n", shortBranch(wt.Branch), marker)
 		}
 		if dirtyCount > 0 {
 			fmt.Printf("\n%sWarning:%s %d worktree(s) have changes that removal will discard.\n", yellow, nc, dirtyCount)
 		}
+		printLockedSkipped(lockedCount)
 		return nil
 	}
 
-	fmt.Printf("Removing %d worktree(s)...\n", len(filtered))
+	var emit func(progress.Event)
+	if out != nil {
+		emit = out.Events()
+	} else {
+		fmt.Printf("Removing %d worktree(s)...\n", len(filtered))
+	}
 
 	remover := func(path string) error {
 		_, err := runner.Run(repoPath, "worktree", "remove", "--force", path)
@@ -130,17 +179,45 @@ func RunRemove(args []string) error {
 
 	targets := make([]worktree.RemovalTarget, len(filtered))
 	steps := make([]progress.PlannedStep, len(filtered))
+	var archiveSteps []progress.PlannedStep
 	for i, wt := range filtered {
 		stepID := progress.StepID(fmt.Sprintf("remove-%d", i))
 		targets[i] = worktree.RemovalTarget{Worktree: wt, StepID: stepID}
 		steps[i] = progress.PlannedStep{ID: stepID, Label: shortBranch(wt.Branch), Checkpoints: 2}
+		if archive && worktree.NeedsArchive(wt) {
+			targets[i].ArchiveStepID = progress.StepID(fmt.Sprintf("archive-%d", i))
+			archiveSteps = append(archiveSteps, progress.PlannedStep{ID: targets[i].ArchiveStepID, Label: shortBranch(wt.Branch)})
+		}
+	}
+	var phases []progress.PlannedPhase
+	if len(archiveSteps) > 0 {
+		phases = append(phases, progress.PlannedPhase{ID: worktree.ArchivePhaseID, Label: worktree.ArchivePhaseName, Steps: archiveSteps})
 	}
-	execution, err := progress.Start(progress.Plan{Phases: []progress.PlannedPhase{{
-		ID: worktree.RemovalPhaseID, Label: worktree.RemovalPhaseName, Steps: steps,
-	}}}, nil)
+	phases = append(phases, progress.PlannedPhase{ID: worktree.RemovalPhaseID, Label: worktree.RemovalPhaseName, Steps: steps})
+	execution, err := progress.Start(progress.Plan{Phases: phases}, emit)
 	if err != nil {
 		return fmt.Errorf("starting removal progress: %w", err)
 	}
+	commonDir, err := git.CommonDir(runner, repoPath)
+	if err != nil {
+		return err
+	}
+	started := time.Now()
+	var archives []trash.Archive
+	if len(archiveSteps) > 0 {
+		archiver := func(wt git.Worktree) (string, error) {
+			a, err := trash.Create(runner, commonDir, wt, time.Now())
+			if err != nil {
+				return "", err
+			}
+			archives = append(archives, a)
+			return "Archived as " + a.ID, nil
+		}
+		targets, err = worktree.ArchiveWorktrees(execution, worktree.ArchivePhaseID, worktree.RemovalPhaseID, archiver, targets)
+		if err != nil {
+			return fmt.Errorf("reporting archive progress: %w", err)
+		}
+	}
 	result := worktree.DeleteWorktrees(execution, worktree.RemovalPhaseID, remover, targets, 5)
 	if err := execution.Finish("removal command complete"); err != nil {
 		return fmt.Errorf("finishing removal progress: %w", err)
@@ -153,6 +230,15 @@ func RunRemove(args []string) error {
 	if err := worktree.PruneWorktrees(runner, repoPath); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to prune worktrees: %v\n", err)
 	}
+	if _, err := journal.RecordRemovals(commonDir, result.Removed(targets), started, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record removal in the undo journal: %v\n", err)
+	}
+
+	if out != nil {
+		doc.Result = report.NewDeletionResult(result)
+		doc.Archives = report.NewArchives(archives)
+		return out.Write(report.KindRemove, doc)
+	}
 
 	fmt.Printf("\n%sRemoved:%s %d worktree(s)\n", green, nc, result.SuccessCount)
 	if result.FailureCount > 0 {
@@ -166,6 +252,37 @@ func RunRemove(args []string) error {
 	if protectedCount > 0 {
 		fmt.Printf("%sSkipped (protected):%s %d worktree(s)\n", dim, nc, protectedCount)
 	}
+	printLockedSkipped(lockedCount)
+	if failed := len(archiveSteps) - len(archives); failed > 0 {
+		fmt.Printf("%sKept (archive failed):%s %d worktree(s)\n", yellow, nc, failed)
+		for _, phase := range result.Phases {
+			if phase.ID != worktree.ArchivePhaseID {
+				continue
+			}
+			for _, step := range phase.Steps {
+				if step.Error != nil {
+					fmt.Printf("  %s: %s\n", step.Name, step.Error)
+				}
+			}
+		}
+	}
+	if len(archives) > 0 {
+		fmt.Printf("%sArchived:%s %d worktree(s); restore with:\n", green, nc, len(archives))
+		for _, a := range archives {
+			fmt.Printf("  sentei restore --id %s\n", a.ID)
+			for _, path := range a.SkippedIgnored {
+				fmt.Printf("    %snot archived (ignored, over %d MiB):%s %s\n", dim, trash.IgnoredEntryLimit>>20, nc, path)
+			}
+		}
+	}
 
 	return nil
 }
+
+// printLockedSkipped notes the locked worktrees the filters passed over, so
+// a lock is not mistaken for a filter that missed.
+func printLockedSkipped(count int) {
+	if count > 0 {
+		fmt.Printf("%sSkipped (locked):%s %d worktree(s); --include-locked removes them too\n", dim, nc, count)
+	}
+}
@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path"
 	"strings"
 	"time"
 
 	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/pr"
 )
 
 // MergedChecker checks whether a branch is fully merged into the default branch.
@@ -14,14 +16,13 @@ type MergedChecker func(branch string) bool
 
 // ResolveFilters returns the worktrees that match the given filter options.
 // Filters combine with OR logic: a worktree matching any active filter is included.
-// Protected branches (built-in + custom) and bare worktrees are always excluded.
-// Locked worktrees are included so the caller can unlock them before deletion.
-func ResolveFilters(worktrees []git.Worktree, opts *RemoveOptions, protectedBranches []string, defaultBranch string, isMerged MergedChecker) []git.Worktree {
-	protectedSet := make(map[string]bool, len(protectedBranches))
-	for _, b := range protectedBranches {
-		protectedSet[b] = true
-	}
-
+// isMerged answers --merged and prMerged --pr-merged; either may be nil when
+// its filter is off.
+// Branches the protection policy covers (built-in, default, configured
+// patterns) and bare worktrees are always excluded. A lock keeps a worktree
+// out of every filter unless opts.IncludeLocked. The caller unlocks the
+// locked worktrees it gets back before deletion.
+func ResolveFilters(worktrees []git.Worktree, opts *RemoveOptions, protection *git.ProtectionPolicy, isMerged, prMerged MergedChecker) []git.Worktree {
 	now := time.Now()
 	var result []git.Worktree
 
@@ -30,12 +31,11 @@ func ResolveFilters(worktrees []git.Worktree, opts *RemoveOptions, protectedBran
 			continue
 		}
 
-		branch := shortBranch(wt.Branch)
-		if git.IsProtectedBranchWith(wt.Branch, defaultBranch) || protectedSet[branch] {
+		if protection.IsProtected(wt.Branch) {
 			continue
 		}
 
-		if matchesFilters(wt, opts, now, isMerged, branch) {
+		if matchesFilters(wt, opts, now, isMerged, prMerged) {
 			result = append(result, wt)
 		}
 	}
@@ -43,34 +43,94 @@ func ResolveFilters(worktrees []git.Worktree, opts *RemoveOptions, protectedBran
 	return result
 }
 
-func matchesFilters(wt git.Worktree, opts *RemoveOptions, now time.Time, isMerged MergedChecker, branch string) bool {
+func matchesFilters(wt git.Worktree, opts *RemoveOptions, now time.Time, isMerged, prMerged MergedChecker) bool {
+	if wt.IsLocked && !opts.IncludeLocked {
+		return false
+	}
 	if opts.All {
 		return true
 	}
+	if opts.Stale > 0 && staleFilter(opts.Stale, now)(wt) {
+		return true
+	}
+	if opts.Merged && mergedFilter(isMerged)(wt) {
+		return true
+	}
+	if opts.PRMerged && mergedFilter(prMerged)(wt) {
+		return true
+	}
+	if opts.Behind && behindFilter(wt) {
+		return true
+	}
+	if opts.UpstreamGone && upstreamGoneFilter(wt) {
+		return true
+	}
+	return false
+}
+
+// worktreePredicate reports whether a worktree matches one filter. remove
+// ORs its filters (any match selects a worktree for deletion); list ANDs
+// them (each filter narrows the listing).
+type worktreePredicate func(wt git.Worktree) bool
 
-	if opts.Stale > 0 {
+// staleFilter matches worktrees whose last commit is older than age. A
+// worktree without a commit date never matches, with a warning on stderr.
+func staleFilter(age time.Duration, now time.Time) worktreePredicate {
+	return func(wt git.Worktree) bool {
 		if wt.LastCommitDate.IsZero() {
 			fmt.Fprintf(os.Stderr, "Warning: skipping worktree %s (no commit date available)\n", wt.Path)
-		} else if now.Sub(wt.LastCommitDate) > opts.Stale {
-			return true
+			return false
 		}
+		return now.Sub(wt.LastCommitDate) > age
 	}
+}
 
-	if opts.Merged && isMerged != nil && branch != "" {
-		if isMerged(branch) {
-			return true
-		}
+// mergedFilter matches worktrees whose branch isMerged reports as merged.
+// Detached worktrees have no branch and never match.
+func mergedFilter(isMerged MergedChecker) worktreePredicate {
+	return func(wt git.Worktree) bool {
+		branch := shortBranch(wt.Branch)
+		return isMerged != nil && branch != "" && isMerged(branch)
 	}
+}
 
-	return false
+// dirtyFilter matches worktrees with uncommitted changes or untracked files.
+func dirtyFilter(wt git.Worktree) bool {
+	return wt.HasUncommittedChanges || wt.HasUntrackedFiles
+}
+
+// behindFilter matches worktrees whose branch is behind its upstream.
+func behindFilter(wt git.Worktree) bool {
+	return wt.HasUpstream() && wt.Behind > 0
+}
+
+// upstreamGoneFilter matches worktrees whose branch tracks a deleted upstream,
+// typically one removed after its pull request merged.
+func upstreamGoneFilter(wt git.Worktree) bool {
+	return wt.UpstreamGone
+}
+
+// lockedFilter matches locked worktrees.
+func lockedFilter(wt git.Worktree) bool {
+	return wt.IsLocked
+}
+
+// branchGlobFilter matches worktrees whose short branch name matches the
+// path.Match pattern. The pattern must already be validated.
+func branchGlobFilter(pattern string) worktreePredicate {
+	return func(wt git.Worktree) bool {
+		ok, _ := path.Match(pattern, shortBranch(wt.Branch))
+		return ok
+	}
 }
 
 func shortBranch(branch string) string {
 	return strings.TrimPrefix(branch, "refs/heads/")
 }
 
-// CheckMerged creates a MergedChecker that uses git merge-base --is-ancestor
-// to determine if a branch is fully merged into the default branch. The default
+// CheckMerged creates a MergedChecker that reports whether a branch's work is
+// on the default branch: by ancestry, or by a squash or rebase merge detected
+// locally through patch-ids (see git.MergedInto). The default
 // branch is never reported as merged into itself (a branch is its own ancestor),
 // so --merged can never select the default worktree.
 func CheckMerged(runner git.CommandRunner, repoPath string, defaultBranch string) MergedChecker {
@@ -78,7 +138,28 @@ func CheckMerged(runner git.CommandRunner, repoPath string, defaultBranch string
 		if strings.EqualFold(branch, defaultBranch) {
 			return false
 		}
-		_, err := runner.Run(repoPath, "merge-base", "--is-ancestor", branch, defaultBranch)
-		return err == nil
+		return git.MergedInto(runner, repoPath, branch, defaultBranch).Merged()
+	}
+}
+
+// CheckPRMerged creates a MergedChecker backed by the branch's pull request
+// state, which catches merges that --merged cannot see locally, such as a
+// squash merge edited on the way in. The
+// default branch never matches. When gh is unavailable nothing matches and
+// checker.Err reports why.
+func CheckPRMerged(checker *pr.Checker, defaultBranch string) MergedChecker {
+	return func(branch string) bool {
+		return !strings.EqualFold(branch, defaultBranch) && checker.Merged(branch)
+	}
+}
+
+// warnPRUnavailable tells the user when --pr-merged matched nothing because
+// gh could not answer. A nil checker (the filter is off) says nothing.
+func warnPRUnavailable(checker *pr.Checker) {
+	if checker == nil {
+		return
+	}
+	if err := checker.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: --pr-merged matched nothing: %v\n", err)
 	}
 }
@@ -8,16 +8,29 @@ import (
 	"time"
 
 	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/report"
 )
 
 // RemoveOptions holds parsed flags for the remove command.
 type RemoveOptions struct {
-	Stale    time.Duration
-	Merged   bool
-	All      bool
-	DryRun   bool
-	Force    bool
-	RepoPath string
+	Stale        time.Duration
+	Merged       bool
+	PRMerged     bool
+	Behind       bool
+	UpstreamGone bool
+	All          bool
+	DryRun       bool
+	Force        bool
+	Archive      bool
+	// IncludeLocked lets the filters select locked worktrees.
+	IncludeLocked bool
+	RepoPath      string
+	Format        report.Format
+}
+
+// HasFilter reports whether any worktree-selecting filter is set.
+func (o *RemoveOptions) HasFilter() bool {
+	return o.Merged || o.PRMerged || o.Behind || o.UpstreamGone || o.All || o.Stale > 0
 }
 
 // ParseStaleDuration parses human-friendly duration strings like "30d", "2w", "3m".
@@ -49,36 +62,82 @@ func ParseStaleDuration(s string) (time.Duration, error) {
 	return time.Duration(days) * 24 * time.Hour, nil
 }
 
-// ParseRemoveFlags parses remove-specific flags and returns RemoveOptions.
-func ParseRemoveFlags(args []string) (*RemoveOptions, error) {
+// removeFlags is the remove command's flag set, shared by the parser and
+// the completion metadata.
+type removeFlags struct {
+	fs            *flag.FlagSet
+	stale         *string
+	merged        *bool
+	prMerged      *bool
+	behind        *bool
+	upstreamGone  *bool
+	all           *bool
+	dryRun        *bool
+	force         *bool
+	archive       *bool
+	includeLocked *bool
+	format        *string
+}
+
+func newRemoveFlags() *removeFlags {
 	fs := flag.NewFlagSet("remove", flag.ContinueOnError)
-	stale := fs.String("stale", "", "Remove worktrees older than duration (e.g., 30d, 2w, 3m)")
-	merged := fs.Bool("merged", false, "Remove worktrees whose branches are fully merged")
-	all := fs.Bool("all", false, "Remove all non-protected worktrees")
-	dryRun := fs.Bool("dry-run", false, "Show what would be removed without deleting")
-	force := fs.Bool("force", false, "Remove at-risk worktrees (uncommitted, untracked, or unpushed work)")
+	return &removeFlags{
+		fs:            fs,
+		stale:         fs.String("stale", "", "Remove worktrees older than duration (e.g., 30d, 2w, 3m)"),
+		merged:        fs.Bool("merged", false, "Remove worktrees whose branches are fully merged"),
+		prMerged:      fs.Bool("pr-merged", false, "Remove worktrees whose pull request was merged (needs gh)"),
+		behind:        fs.Bool("behind", false, "Remove worktrees whose branches are behind their upstream"),
+		upstreamGone:  fs.Bool("upstream-gone", false, "Remove worktrees whose upstream branch was deleted"),
+		all:           fs.Bool("all", false, "Remove all non-protected worktrees"),
+		dryRun:        fs.Bool("dry-run", false, "Show what would be removed without deleting"),
+		force:         fs.Bool("force", false, "Remove at-risk worktrees (uncommitted, untracked, or unpushed work)"),
+		archive:       fs.Bool("archive", false, "Archive at-risk worktrees first so sentei restore can bring them back"),
+		includeLocked: fs.Bool("include-locked", false, "Let the filters select locked worktrees, unlocking them first"),
+		format:        formatFlag(fs),
+	}
+}
+
+// RemoveFlags describes the remove command's flags for usage and shell
+// completion.
+func RemoveFlags() []cli.Flag {
+	return cli.FlagsOf(newRemoveFlags().fs)
+}
 
-	if err := fs.Parse(args); err != nil {
+// ParseRemoveFlags parses remove-specific flags and returns RemoveOptions.
+func ParseRemoveFlags(args []string) (*RemoveOptions, error) {
+	fl := newRemoveFlags()
+	if err := fl.fs.Parse(args); err != nil {
 		return nil, err
 	}
 
-	opts := &RemoveOptions{
-		Merged: *merged,
-		All:    *all,
-		DryRun: *dryRun,
-		Force:  *force,
+	f, err := report.ParseFormat(*fl.format)
+	if err != nil {
+		return nil, err
 	}
 
-	if *stale != "" {
-		d, err := ParseStaleDuration(*stale)
+	opts := &RemoveOptions{
+		Merged:        *fl.merged,
+		PRMerged:      *fl.prMerged,
+		Behind:        *fl.behind,
+		UpstreamGone:  *fl.upstreamGone,
+		All:           *fl.all,
+		DryRun:        *fl.dryRun,
+		Force:         *fl.force,
+		Archive:       *fl.archive,
+		IncludeLocked: *fl.includeLocked,
+		Format:        f,
+	}
+
+	if *fl.stale != "" {
+		d, err := ParseStaleDuration(*fl.stale)
 		if err != nil {
 			return nil, err
 		}
 		opts.Stale = d
 	}
 
-	if fs.NArg() > 0 {
-		opts.RepoPath = fs.Arg(0)
+	if fl.fs.NArg() > 0 {
+		opts.RepoPath = fl.fs.Arg(0)
 	}
 
 	return opts, nil
@@ -87,8 +146,8 @@ func ParseRemoveFlags(args []string) (*RemoveOptions, error) {
 // ValidateRemoveForNonInteractive checks that at least one filter is specified
 // for non-interactive execution.
 func ValidateRemoveForNonInteractive(opts *RemoveOptions) error {
-	if !opts.Merged && !opts.All && opts.Stale == 0 {
-		return fmt.Errorf("at least one filter required: --stale, --merged, or --all")
+	if !opts.HasFilter() {
+		return fmt.Errorf("at least one filter required: --stale, --merged, --pr-merged, --behind, --upstream-gone, or --all")
 	}
 	return nil
 }
@@ -103,9 +162,24 @@ func RemoveCLICommand(opts *RemoveOptions) string {
 	if opts.Merged {
 		flags["merged"] = "true"
 	}
+	if opts.PRMerged {
+		flags["pr-merged"] = "true"
+	}
+	if opts.Behind {
+		flags["behind"] = "true"
+	}
+	if opts.UpstreamGone {
+		flags["upstream-gone"] = "true"
+	}
 	if opts.All {
 		flags["all"] = "true"
 	}
+	if opts.Archive {
+		flags["archive"] = "true"
+	}
+	if opts.IncludeLocked {
+		flags["include-locked"] = "true"
+	}
 	if opts.DryRun {
 		flags["dry-run"] = "true"
 	}
@@ -131,6 +205,15 @@ func FormatFilterLabel(opts *RemoveOptions) string {
 	if opts.Merged {
 		parts = append(parts, "merged")
 	}
+	if opts.PRMerged {
+		parts = append(parts, "PR merged")
+	}
+	if opts.Behind {
+		parts = append(parts, "behind upstream")
+	}
+	if opts.UpstreamGone {
+		parts = append(parts, "upstream gone")
+	}
 	if opts.Stale > 0 {
 		parts = append(parts, "stale > "+FormatStaleDuration(opts.Stale))
 	}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/pr"
+	"github.com/abiswas97/sentei/internal/testutil/mock"
 )
 
 func TestParseStaleDuration_Days(t *testing.T) {
@@ -202,7 +204,7 @@ func TestResolveFilters_StaleFilter(t *testing.T) {
 		{Path: "/new", Branch: "refs/heads/feature/new", LastCommitDate: now.Add(-5 * 24 * time.Hour)},
 	}
 	opts := &RemoveOptions{Stale: 30 * 24 * time.Hour}
-	result := ResolveFilters(worktrees, opts, nil, "", nil)
+	result := ResolveFilters(worktrees, opts, nil, nil, nil)
 	if len(result) != 1 {
 		t.Fatalf("expected 1 worktree, got %d", len(result))
 	}
@@ -221,7 +223,7 @@ func TestResolveFilters_MergedFilter(t *testing.T) {
 		return branch == "feature/merged"
 	}
 	opts := &RemoveOptions{Merged: true}
-	result := ResolveFilters(worktrees, opts, nil, "", isMerged)
+	result := ResolveFilters(worktrees, opts, nil, isMerged, nil)
 	if len(result) != 1 {
 		t.Fatalf("expected 1 worktree, got %d", len(result))
 	}
@@ -230,13 +232,44 @@ func TestResolveFilters_MergedFilter(t *testing.T) {
 	}
 }
 
+func TestResolveFilters_UpstreamFilters(t *testing.T) {
+	worktrees := []git.Worktree{
+		{Path: "/behind", Branch: "refs/heads/feature/behind", Upstream: "origin/feature/behind", Behind: 3},
+		{Path: "/ahead", Branch: "refs/heads/feature/ahead", Upstream: "origin/feature/ahead", Ahead: 2},
+		{Path: "/gone", Branch: "refs/heads/feature/gone", Upstream: "origin/feature/gone", UpstreamGone: true},
+		{Path: "/untracked", Branch: "refs/heads/feature/local"},
+	}
+
+	tests := []struct {
+		name string
+		opts RemoveOptions
+		want []string
+	}{
+		{"behind", RemoveOptions{Behind: true}, []string{"/behind"}},
+		{"upstream gone", RemoveOptions{UpstreamGone: true}, []string{"/gone"}},
+		{"either (OR)", RemoveOptions{Behind: true, UpstreamGone: true}, []string{"/behind", "/gone"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ResolveFilters(worktrees, &tt.opts, nil, nil, nil)
+			var got []string
+			for _, wt := range result {
+				got = append(got, wt.Path)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ResolveFilters(%+v) = %v, want %v", tt.opts, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestResolveFilters_AllFilter(t *testing.T) {
 	worktrees := []git.Worktree{
 		{Path: "/a", Branch: "refs/heads/feature/a"},
 		{Path: "/b", Branch: "refs/heads/feature/b"},
 	}
 	opts := &RemoveOptions{All: true}
-	result := ResolveFilters(worktrees, opts, nil, "", nil)
+	result := ResolveFilters(worktrees, opts, nil, nil, nil)
 	if len(result) != 2 {
 		t.Fatalf("expected 2 worktrees, got %d", len(result))
 	}
@@ -248,7 +281,7 @@ func TestResolveFilters_ProtectedExclusion(t *testing.T) {
 		{Path: "/feature", Branch: "refs/heads/feature/x"},
 	}
 	opts := &RemoveOptions{All: true}
-	result := ResolveFilters(worktrees, opts, nil, "", nil)
+	result := ResolveFilters(worktrees, opts, nil, nil, nil)
 	// main is protected and should be excluded
 	if len(result) != 1 {
 		t.Fatalf("expected 1 worktree, got %d", len(result))
@@ -265,7 +298,7 @@ func TestResolveFilters_ProtectsNonStandardDefaultBranch(t *testing.T) {
 	}
 	opts := &RemoveOptions{All: true}
 	// Default branch is "production" (non-standard) — it must be excluded.
-	result := ResolveFilters(worktrees, opts, nil, "production", nil)
+	result := ResolveFilters(worktrees, opts, git.NewProtectionPolicy("production", nil), nil, nil)
 	if len(result) != 1 {
 		t.Fatalf("expected 1 worktree, got %d", len(result))
 	}
@@ -283,13 +316,51 @@ func TestCheckMerged_DefaultBranchNeverMergedIntoItself(t *testing.T) {
 	}
 }
 
+func TestResolveFilters_PRMergedFilter(t *testing.T) {
+	worktrees := []git.Worktree{
+		{Path: "/main", Branch: "refs/heads/main"},
+		{Path: "/squashed", Branch: "refs/heads/feature/squashed"},
+		{Path: "/open", Branch: "refs/heads/feature/open"},
+	}
+	gh := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:gh[pr list --head feature/squashed --state all --json state,isCrossRepository]": {Output: `[{"state":"MERGED"}]`},
+		"/repo:gh[pr list --head feature/open --state all --json state,isCrossRepository]":     {Output: `[{"state":"OPEN"}]`},
+	}}
+	prMerged := CheckPRMerged(pr.NewChecker(gh, "/repo"), "main")
+
+	// Protection is off here: the checker itself must never select the
+	// default branch, and must not ask gh about it.
+	result := ResolveFilters(worktrees, &RemoveOptions{PRMerged: true}, git.NewProtectionPolicy("", nil), nil, prMerged)
+	if len(result) != 1 || result[0].Path != "/squashed" {
+		t.Errorf("expected only /squashed, got %v", result)
+	}
+	for _, call := range gh.Calls {
+		if strings.Contains(call, "--head main ") {
+			t.Errorf("gh asked about the default branch: %s", call)
+		}
+	}
+}
+
+func TestResolveFilters_PRMergedWithoutGhMatchesNothing(t *testing.T) {
+	worktrees := []git.Worktree{{Path: "/squashed", Branch: "refs/heads/feature/squashed"}}
+	checker := pr.NewChecker(&mock.Runner{}, "/repo")
+
+	result := ResolveFilters(worktrees, &RemoveOptions{PRMerged: true}, nil, nil, CheckPRMerged(checker, "main"))
+	if len(result) != 0 {
+		t.Errorf("an unavailable gh should match nothing, got %v", result)
+	}
+	if checker.Err() == nil {
+		t.Error("the checker should report why it matched nothing")
+	}
+}
+
 func TestResolveFilters_CustomProtectedBranches(t *testing.T) {
 	worktrees := []git.Worktree{
 		{Path: "/staging", Branch: "refs/heads/staging"},
 		{Path: "/feature", Branch: "refs/heads/feature/x"},
 	}
 	opts := &RemoveOptions{All: true}
-	result := ResolveFilters(worktrees, opts, []string{"staging"}, "", nil)
+	result := ResolveFilters(worktrees, opts, git.NewProtectionPolicy("", []string{"staging"}), nil, nil)
 	if len(result) != 1 {
 		t.Fatalf("expected 1 worktree, got %d", len(result))
 	}
@@ -309,7 +380,7 @@ func TestResolveFilters_CombinedORLogic(t *testing.T) {
 		return branch == "feature/merged"
 	}
 	opts := &RemoveOptions{Stale: 30 * 24 * time.Hour, Merged: true}
-	result := ResolveFilters(worktrees, opts, nil, "", isMerged)
+	result := ResolveFilters(worktrees, opts, nil, isMerged, nil)
 	if len(result) != 2 {
 		t.Fatalf("expected 2 worktrees, got %d", len(result))
 	}
@@ -328,7 +399,7 @@ func TestResolveFilters_ExcludesBareWorktrees(t *testing.T) {
 		{Path: "/feature", Branch: "refs/heads/feature/x"},
 	}
 	opts := &RemoveOptions{All: true}
-	result := ResolveFilters(worktrees, opts, nil, "", nil)
+	result := ResolveFilters(worktrees, opts, nil, nil, nil)
 	if len(result) != 1 {
 		t.Fatalf("expected 1 worktree, got %d", len(result))
 	}
@@ -337,27 +408,34 @@ func TestResolveFilters_ExcludesBareWorktrees(t *testing.T) {
 	}
 }
 
-func TestResolveFilters_IncludesLockedWorktrees(t *testing.T) {
+func TestResolveFilters_LockedWorktrees(t *testing.T) {
+	old := time.Now().Add(-60 * 24 * time.Hour)
 	worktrees := []git.Worktree{
 		{Path: "/bare", IsBare: true},
-		{Path: "/locked", Branch: "refs/heads/feature/locked", IsLocked: true},
-		{Path: "/clean", Branch: "refs/heads/feature/clean"},
-	}
-	opts := &RemoveOptions{All: true}
-	result := ResolveFilters(worktrees, opts, nil, "", nil)
-
-	paths := make(map[string]bool)
-	for _, wt := range result {
-		paths[wt.Path] = true
+		{Path: "/locked", Branch: "refs/heads/feature/locked", IsLocked: true, LastCommitDate: old},
+		{Path: "/clean", Branch: "refs/heads/feature/clean", LastCommitDate: old},
 	}
-
-	if !paths["/locked"] {
-		t.Error("locked worktree should be included in --all filter results")
-	}
-	if !paths["/clean"] {
-		t.Error("clean worktree should be included")
+	isMerged := func(string) bool { return true }
+	tests := []struct {
+		name string
+		opts RemoveOptions
+		want []string
+	}{
+		{"all skips locked", RemoveOptions{All: true}, []string{"/clean"}},
+		{"stale skips locked", RemoveOptions{Stale: 30 * 24 * time.Hour}, []string{"/clean"}},
+		{"include-locked", RemoveOptions{All: true, IncludeLocked: true}, []string{"/locked", "/clean"}},
+		{"merged skips locked", RemoveOptions{Merged: true}, []string{"/clean"}},
+		{"merged include-locked", RemoveOptions{Merged: true, IncludeLocked: true}, []string{"/locked", "/clean"}},
 	}
-	if paths["/bare"] {
-		t.Error("bare worktree should still be excluded")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, wt := range ResolveFilters(worktrees, &tt.opts, nil, isMerged, nil) {
+				got = append(got, wt.Path)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ResolveFilters(%+v) = %v, want %v", tt.opts, got, tt.want)
+			}
+		})
 	}
 }
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/abiswas97/sentei/internal/git"
 )
 
 func TestFormatStaleDuration(t *testing.T) {
@@ -37,6 +39,7 @@ func TestFormatFilterLabel(t *testing.T) {
 		{"merged only", RemoveOptions{Merged: true}, "merged"},
 		{"stale only", RemoveOptions{Stale: 48 * time.Hour}, "stale > 2d"},
 		{"merged and stale", RemoveOptions{Merged: true, Stale: 48 * time.Hour}, "merged, stale > 2d"},
+		{"upstream filters", RemoveOptions{Behind: true, UpstreamGone: true}, "behind upstream, upstream gone"},
 		{"no filters", RemoveOptions{}, ""},
 	}
 	for _, tt := range tests {
@@ -177,11 +180,64 @@ func TestRunRemove_UnlocksLockedWorktree(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if _, statErr := os.Stat(wtPath); statErr != nil {
+		t.Fatalf("--merged removed a locked worktree: %v", statErr)
+	}
+
+	captureStdout(t, func() {
+		err = RunRemove([]string{"--merged", "--include-locked", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	if _, statErr := os.Stat(wtPath); !os.IsNotExist(statErr) {
 		t.Errorf("expected locked worktree %s to be unlocked and removed", wtPath)
 	}
 }
 
+func TestRunRemove_AllSkipsLockedUnlessIncluded(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+	wtPath := filepath.Join(bareRepo, "feature-merged-branch")
+	mustGit(t, bareRepo, "worktree", "lock", "--reason", "keep", wtPath)
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunRemove([]string{"--all", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, statErr := os.Stat(wtPath); statErr != nil {
+		t.Fatalf("--all removed a locked worktree: %v", statErr)
+	}
+	if !strings.Contains(out, "Skipped (locked):") {
+		t.Errorf("expected the locked worktree to be reported, got:\n%s", out)
+	}
+
+	out = captureStdout(t, func() {
+		err = RunRemove([]string{"--all", "--include-locked", "--dry-run", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "will be unlocked") {
+		t.Errorf("dry run does not say which worktrees it would unlock:\n%s", out)
+	}
+	if out, _ := (&git.GitRunner{}).Run(bareRepo, "worktree", "list", "--porcelain"); !strings.Contains(out, "locked keep") {
+		t.Fatalf("dry run unlocked the worktree:\n%s", out)
+	}
+
+	captureStdout(t, func() {
+		err = RunRemove([]string{"--all", "--include-locked", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, statErr := os.Stat(wtPath); !os.IsNotExist(statErr) {
+		t.Errorf("expected --include-locked to remove %s", wtPath)
+	}
+}
+
 func TestRunRemove_WarnsWhenCommitDateUnavailable(t *testing.T) {
 	bareRepo := setupBareRepoWithMergedBranch(t)
 	wtPath := filepath.Join(bareRepo, "feature-merged-branch")
@@ -306,3 +362,29 @@ func TestRunRemove_DryRunExemptFromGate(t *testing.T) {
 		t.Errorf("expected dry-run output, got:\n%s", out)
 	}
 }
+
+func TestRunRemove_HonoursConfiguredProtectedBranches(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	bareRepo := setupBareRepoWithMergedBranch(t)
+	releasePath := filepath.Join(bareRepo, "release-1.0")
+	mustGit(t, bareRepo, "worktree", "add", "-b", "release/1.0", releasePath, "main")
+	// The repo config lives beside the git common dir, as for a sentei layout.
+	mustWriteFile(t, filepath.Join(filepath.Dir(bareRepo), ".sentei.yaml"), "protected_branches:\n  - release/*\n")
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunRemove([]string{"--all", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, statErr := os.Stat(releasePath); statErr != nil {
+		t.Errorf("a worktree matching protected_branches must survive --all: %v", statErr)
+	}
+	if _, statErr := os.Stat(filepath.Join(bareRepo, "feature-merged-branch")); !os.IsNotExist(statErr) {
+		t.Error("the unprotected feature worktree should be removed")
+	}
+	if !strings.Contains(out, "Skipped (protected):") {
+		t.Errorf("expected protected-skip summary, got:\n%s", out)
+	}
+}
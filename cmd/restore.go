@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/abiswas97/sentei/internal/dryrun"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/report"
+	"github.com/abiswas97/sentei/internal/trash"
+)
+
+// RunRestore lists the worktree archives remove --archive took, or, given
+// --id, recreates that worktree with its uncommitted and untracked files.
+func RunRestore(args []string) error {
+	opts, err := ParseRestoreFlags(args)
+	if err != nil {
+		return err
+	}
+
+	repoPath := "."
+	if opts.RepoPath != "" {
+		repoPath = opts.RepoPath
+	}
+	if absPath, err := filepath.Abs(repoPath); err == nil {
+		repoPath = absPath
+	}
+
+	runner := &git.GitRunner{}
+
+	switch repo.DetectContext(runner, repoPath) {
+	case repo.ContextBareRepo:
+		repoPath = repo.ResolveBareRoot(runner, repoPath)
+	case repo.ContextNonBareRepo:
+	default:
+		return fmt.Errorf("restore requires a git repository: %s", repoPath)
+	}
+
+	commonDir, err := git.CommonDir(runner, repoPath)
+	if err != nil {
+		return err
+	}
+	out := newReport(opts.Format)
+
+	if opts.ID == "" {
+		archives, err := trash.List(commonDir)
+		if err != nil {
+			return err
+		}
+		if out != nil {
+			return out.Write(report.KindArchives, report.NewArchives(archives))
+		}
+		return printArchives(archives)
+	}
+
+	archive, err := trash.Find(commonDir, opts.ID)
+	if err != nil {
+		return err
+	}
+	path := opts.Path
+	if path == "" {
+		path = archive.WorktreePath
+	}
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists; pass --path to restore somewhere else", path)
+	}
+
+	restored, err := trash.Restore(runner, repoPath, commonDir, archive, path)
+	if err != nil {
+		return fmt.Errorf("restoring %s: %w", archive.ID, err)
+	}
+
+	if out != nil {
+		return out.Write(report.KindRestore, report.Restore{
+			Archive:  report.NewArchive(archive),
+			Path:     path,
+			Detached: restored.Detached,
+			Kept:     restored.Kept,
+		})
+	}
+	fmt.Printf("%s✓%s Restored %s to %s\n", green, nc, archive.ID, path)
+	head := archive.Head
+	if len(head) > 7 {
+		head = head[:7]
+	}
+	if restored.Detached {
+		fmt.Printf("  %s%s has moved since it was archived; the worktree is detached at %s%s\n", yellow, archive.Branch, head, nc)
+	}
+	if restored.Kept {
+		fmt.Printf("  %sNo branch contains %s, so the archive is kept; drop it once those commits are on a branch%s\n", yellow, head, nc)
+	}
+	return nil
+}
+
+// RestorePrompt describes what a restore invocation is about to change, for
+// the confirmation asked before it runs; a listing needs none.
+func RestorePrompt(args []string) (string, bool, error) {
+	opts, err := ParseRestoreFlags(args)
+	if err != nil || opts.ID == "" {
+		return "", false, err
+	}
+	dest := "its original path"
+	if opts.Path != "" {
+		dest = opts.Path
+	}
+	return fmt.Sprintf("Restore archive %s into %s, recreating its worktree and branch, then delete the archive.", opts.ID, dest), true, nil
+}
+
+func printArchives(archives []trash.Archive) error {
+	if len(archives) == 0 {
+		fmt.Println("No archived worktrees.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tARCHIVED\tCHANGES\tUNTRACKED\tPATH")
+	for _, a := range archives {
+		changes := "no"
+		if a.HasChanges() {
+			changes = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", a.ID, dryrun.RelativeTime(a.CreatedAt), changes, a.Untracked, a.WorktreePath)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\nRestore one with: sentei restore --id ID [--path DIR]\n")
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"flag"
+
+	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// RestoreOptions holds parsed flags for the restore command. Without an ID
+// the command lists archives instead of restoring one.
+type RestoreOptions struct {
+	ID       string
+	Path     string
+	RepoPath string
+	Format   report.Format
+}
+
+// restoreFlags is the restore command's flag set, shared by the parser and
+// the completion metadata.
+type restoreFlags struct {
+	fs     *flag.FlagSet
+	id     *string
+	path   *string
+	format *string
+}
+
+func newRestoreFlags() *restoreFlags {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	return &restoreFlags{
+		fs:     fs,
+		id:     fs.String("id", "", "Archive to restore (as listed by sentei restore); lists archives when empty"),
+		path:   fs.String("path", "", "Directory to restore into (defaults to the worktree's original path)"),
+		format: formatFlag(fs),
+	}
+}
+
+// RestoreFlags describes the restore command's flags for usage and shell
+// completion.
+func RestoreFlags() []cli.Flag {
+	return cli.FlagsOf(newRestoreFlags().fs, cli.Flag{Name: "path", Kind: cli.FlagPath})
+}
+
+// ParseRestoreFlags parses restore-specific flags and returns RestoreOptions.
+func ParseRestoreFlags(args []string) (*RestoreOptions, error) {
+	fl := newRestoreFlags()
+	if err := fl.fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	f, err := report.ParseFormat(*fl.format)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &RestoreOptions{
+		ID:     *fl.id,
+		Path:   *fl.path,
+		Format: f,
+	}
+	if fl.fs.NArg() > 0 {
+		opts.RepoPath = fl.fs.Arg(0)
+	}
+	return opts, nil
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/cleanup"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/report"
+	"github.com/abiswas97/sentei/internal/trash"
+)
+
+func TestRunRemove_AtRiskRefusalSuggestsArchive(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+	mustWriteFile(t, filepath.Join(bareRepo, "feature-merged-branch", "scratch.txt"), "wip\n")
+
+	var err error
+	captureStdout(t, func() {
+		err = RunRemove([]string{"--merged", bareRepo})
+	})
+	if err == nil || !strings.Contains(err.Error(), "--archive") {
+		t.Fatalf("expected an at-risk refusal mentioning --archive, got %v", err)
+	}
+}
+
+func TestRunRemove_ArchiveThenRestore(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+	wtPath := filepath.Join(bareRepo, "feature-merged-branch")
+	mustWriteFile(t, filepath.Join(wtPath, "feature.txt"), "edited\n")
+	mustWriteFile(t, filepath.Join(wtPath, "scratch.txt"), "wip\n")
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunRemove([]string{"--merged", "--archive", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("RunRemove(--archive) error = %v", err)
+	}
+	if !strings.Contains(out, "Archived:") || !strings.Contains(out, "sentei restore --id feature/merged-branch/") {
+		t.Errorf("expected archive summary with restore hint, got:\n%s", out)
+	}
+	if _, statErr := os.Stat(wtPath); !os.IsNotExist(statErr) {
+		t.Fatalf("expected worktree %s to be removed", wtPath)
+	}
+
+	out = captureStdout(t, func() {
+		err = RunRestore([]string{"--format", "json", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("RunRestore(list) error = %v", err)
+	}
+	var rec struct {
+		Kind string           `json:"kind"`
+		Data []report.Archive `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &rec); err != nil {
+		t.Fatalf("decoding %q: %v", out, err)
+	}
+	if rec.Kind != report.KindArchives || len(rec.Data) != 1 {
+		t.Fatalf("got kind %q with %d archives, want 1 %s", rec.Kind, len(rec.Data), report.KindArchives)
+	}
+
+	captureStdout(t, func() {
+		err = RunRestore([]string{"--id", rec.Data[0].ID, bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("RunRestore(--id) error = %v", err)
+	}
+	for name, want := range map[string]string{"feature.txt": "edited\n", "scratch.txt": "wip\n"} {
+		got, readErr := os.ReadFile(filepath.Join(wtPath, name))
+		if readErr != nil || string(got) != want {
+			t.Errorf("restored %s = %q (%v), want %q", name, got, readErr, want)
+		}
+	}
+}
+
+func TestRunRestore_RefusesExistingPath(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+	wtPath := filepath.Join(bareRepo, "feature-merged-branch")
+	a, err := trash.Create(&git.GitRunner{}, bareRepo, git.Worktree{Path: wtPath, Branch: "refs/heads/feature/merged-branch"}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = RunRestore([]string{"--id", a.ID, bareRepo})
+	if err == nil || !strings.Contains(err.Error(), "--path") {
+		t.Fatalf("expected an error suggesting --path, got %v", err)
+	}
+}
+
+func TestRunCleanup_PurgesExpiredArchives(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+	wtPath := filepath.Join(bareRepo, "feature-merged-branch")
+	runner := &git.GitRunner{}
+	wt := git.Worktree{Path: wtPath, Branch: "refs/heads/feature/merged-branch"}
+	if _, err := trash.Create(runner, bareRepo, wt, time.Now().Add(-60*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	fresh, err := trash.Create(runner, bareRepo, wt, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	captureStdout(t, func() {
+		err = RunCleanupWithOpts(&cleanup.Options{Mode: cleanup.ModeSafe}, bareRepo)
+	})
+	if err != nil {
+		t.Fatalf("RunCleanupWithOpts() error = %v", err)
+	}
+	archives, err := trash.List(bareRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archives) != 1 || archives[0].ID != fresh.ID {
+		t.Fatalf("archives after cleanup = %+v, want only %s", archives, fresh.ID)
+	}
+}
+
+func TestRestorePrompt_OnlyConfirmsChanges(t *testing.T) {
+	if _, needed, err := RestorePrompt(nil); err != nil || needed {
+		t.Errorf("listing: needed = %v, err = %v; want no confirmation", needed, err)
+	}
+	prompt, needed, err := RestorePrompt([]string{"--id", "feature/20261018T120000Z", "--path", "../again"})
+	if err != nil || !needed || !strings.Contains(prompt, "feature/20261018T120000Z into ../again") {
+		t.Errorf("--id: prompt = %q, needed = %v, err = %v", prompt, needed, err)
+	}
+}
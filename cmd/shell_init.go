@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/cli"
+)
+
+// shellInitScripts wrap sentei in a shell function: `sentei switch` cds into
+// the printed path, every other command runs unchanged. A failed or
+// cancelled switch leaves the working directory alone.
+var shellInitScripts = map[string]string{
+	"bash": posixShellInit,
+	"zsh":  posixShellInit,
+	"fish": `# sentei shell integration for fish
+# Load with: sentei shell-init fish | source
+function sentei --wraps sentei --description 'sentei, with switch changing directory'
+    if test (count $argv) -gt 0; and test "$argv[1]" = switch; and not contains -- --list $argv
+        set -l dir (command sentei $argv); or return
+        test -n "$dir"; and cd -- $dir
+    else
+        command sentei $argv
+    end
+end
+`,
+}
+
+const posixShellInit = `# sentei shell integration for bash and zsh
+# Load with: eval "$(sentei shell-init bash)"  (or zsh)
+sentei() {
+    if [ "$1" = switch ]; then
+        case " $* " in
+            *" --list "*) command sentei "$@"; return ;;
+        esac
+        local dir
+        dir="$(command sentei "$@")" || return
+        [ -n "$dir" ] && cd -- "$dir"
+    else
+        command sentei "$@"
+    fi
+}
+`
+
+// RunShellInit prints the wrapper function for the shell named in args.
+func RunShellInit(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: sentei shell-init %s", strings.Join(cli.Shells, "|"))
+	}
+	script, ok := shellInitScripts[args[0]]
+	if !ok {
+		return fmt.Errorf("%w %q: must be one of %s", cli.ErrUnknownShell, args[0], strings.Join(cli.Shells, ", "))
+	}
+	fmt.Print(script)
+	return nil
+}
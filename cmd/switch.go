@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/state"
+	"github.com/abiswas97/sentei/internal/switcher"
+)
+
+// SwitchPicker lets the user choose among worktrees matching query. It
+// returns the chosen path, or "" when the user cancelled.
+type SwitchPicker func(worktrees []git.Worktree, query string, recent map[string]time.Time) (string, error)
+
+var errSwitchCancelled = errors.New("switch cancelled")
+
+// ambiguousListLimit caps how many matches an ambiguity error names.
+const ambiguousListLimit = 5
+
+// RunSwitch prints the path of the worktree matching the query, for the
+// shell-init wrapper to cd into. A single match, or a single exact match on
+// branch or directory name, is chosen directly; otherwise pick is asked,
+// and a nil pick (no terminal) makes the ambiguity an error.
+func RunSwitch(args []string, pick SwitchPicker) error {
+	opts, err := ParseSwitchFlags(args)
+	if err != nil {
+		return err
+	}
+	return runSwitch(&git.GitRunner{}, ".", opts, pick, os.Stdout)
+}
+
+func runSwitch(runner git.CommandRunner, dir string, opts *SwitchOptions, pick SwitchPicker, out io.Writer) error {
+	repoPath := dir
+	if absPath, err := filepath.Abs(repoPath); err == nil {
+		repoPath = absPath
+	}
+	switch repo.DetectContext(runner, repoPath) {
+	case repo.ContextBareRepo:
+		repoPath = repo.ResolveBareRoot(runner, repoPath)
+	case repo.ContextNonBareRepo:
+	default:
+		return fmt.Errorf("switch requires a git repository: %s", repoPath)
+	}
+
+	worktrees, err := git.ListWorktrees(runner, repoPath)
+	if err != nil {
+		return err
+	}
+
+	// Recency is a ranking hint: an unreadable state file ranks without it.
+	commonDir, err := git.CommonDir(runner, repoPath)
+	if err != nil {
+		return err
+	}
+	st, err := state.Load(commonDir)
+	if err != nil {
+		st = &state.State{}
+	}
+
+	matches := switcher.Rank(worktrees, opts.Query, st.RecentWorktrees)
+	if opts.List {
+		for _, c := range matches {
+			fmt.Fprintf(out, "%s\t%s\n", c.Name(), c.Worktree.Path)
+		}
+		return nil
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no worktree matches %q", opts.Query)
+	}
+
+	chosen := ""
+	if unambiguous(matches) {
+		chosen = matches[0].Worktree.Path
+	} else if pick != nil {
+		chosen, err = pick(worktrees, opts.Query, st.RecentWorktrees)
+		if err != nil {
+			return err
+		}
+		if chosen == "" {
+			return errSwitchCancelled
+		}
+	} else {
+		return ambiguousError(opts.Query, matches)
+	}
+
+	live := make([]string, 0, len(worktrees))
+	for _, wt := range worktrees {
+		live = append(live, wt.Path)
+	}
+	st.MarkUsed(chosen, time.Now())
+	st.PruneRecent(live)
+	if err := state.Save(commonDir, st); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record recent worktree: %v\n", err)
+	}
+
+	fmt.Fprintln(out, chosen)
+	return nil
+}
+
+// unambiguous reports whether the best match can be chosen without asking:
+// it is the only match, or the only exact one.
+func unambiguous(matches []switcher.Candidate) bool {
+	if len(matches) == 1 {
+		return true
+	}
+	return matches[0].Exact && !matches[1].Exact
+}
+
+func ambiguousError(query string, matches []switcher.Candidate) error {
+	names := make([]string, 0, ambiguousListLimit)
+	for _, c := range matches[:min(len(matches), ambiguousListLimit)] {
+		names = append(names, c.Name())
+	}
+	if len(matches) > ambiguousListLimit {
+		names = append(names, fmt.Sprintf("and %d more", len(matches)-ambiguousListLimit))
+	}
+	what := fmt.Sprintf("%q matches", query)
+	if query == "" {
+		what = "there are"
+	}
+	return fmt.Errorf("%s %d worktrees (%s); narrow the query or run in a terminal to pick",
+		what, len(matches), strings.Join(names, ", "))
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/cli"
+)
+
+// SwitchOptions holds parsed flags for the switch command.
+type SwitchOptions struct {
+	// Query is the positional arguments joined by spaces; empty matches
+	// every worktree.
+	Query string
+	// List prints every match, best first, instead of choosing one.
+	List bool
+}
+
+// switchFlags is the switch command's flag set, shared by the parser and
+// the completion metadata.
+type switchFlags struct {
+	fs   *flag.FlagSet
+	list *bool
+}
+
+func newSwitchFlags() *switchFlags {
+	fs := flag.NewFlagSet("switch", flag.ContinueOnError)
+	return &switchFlags{
+		fs:   fs,
+		list: fs.Bool("list", false, "Print every matching worktree, best first, instead of choosing one"),
+	}
+}
+
+// SwitchFlags describes the switch command's flags for usage and shell
+// completion.
+func SwitchFlags() []cli.Flag {
+	return cli.FlagsOf(newSwitchFlags().fs)
+}
+
+// ParseSwitchFlags parses switch-specific flags and returns SwitchOptions.
+func ParseSwitchFlags(args []string) (*SwitchOptions, error) {
+	fl := newSwitchFlags()
+	if err := fl.fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return &SwitchOptions{
+		Query: strings.Join(fl.fs.Args(), " "),
+		List:  *fl.list,
+	}, nil
+}
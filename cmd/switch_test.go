@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/state"
+)
+
+// setupSwitchRepo adds feature/login and feature/logout worktrees to a bare
+// repo.
+func setupSwitchRepo(t *testing.T) string {
+	t.Helper()
+	bareRepo := setupBareRepo(t)
+	mustGit(t, bareRepo, "worktree", "add", "-b", "feature/login", filepath.Join(bareRepo, "login"), "main")
+	mustGit(t, bareRepo, "worktree", "add", "-b", "feature/logout", filepath.Join(bareRepo, "logout"), "main")
+	return bareRepo
+}
+
+func TestRunSwitch_UniqueMatchPrintsPathAndRecordsUse(t *testing.T) {
+	bareRepo := setupSwitchRepo(t)
+	var out bytes.Buffer
+
+	err := runSwitch(&git.GitRunner{}, bareRepo, &SwitchOptions{Query: "logout"}, nil, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(bareRepo, "logout")
+	if got := strings.TrimSpace(out.String()); got != want {
+		t.Errorf("printed %q, want %q", got, want)
+	}
+
+	st, err := state.Load(bareRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.RecentWorktrees[want].IsZero() {
+		t.Errorf("switch should record the chosen worktree, got %v", st.RecentWorktrees)
+	}
+}
+
+func TestRunSwitch_AmbiguousAsksPicker(t *testing.T) {
+	bareRepo := setupSwitchRepo(t)
+	want := filepath.Join(bareRepo, "login")
+
+	var offered int
+	pick := func(wts []git.Worktree, query string, _ map[string]time.Time) (string, error) {
+		offered = len(wts)
+		if query != "log" {
+			t.Errorf("picker query = %q, want log", query)
+		}
+		return want, nil
+	}
+	var out bytes.Buffer
+	if err := runSwitch(&git.GitRunner{}, bareRepo, &SwitchOptions{Query: "log"}, pick, &out); err != nil {
+		t.Fatal(err)
+	}
+	if offered == 0 || strings.TrimSpace(out.String()) != want {
+		t.Errorf("picker should choose %s, printed %q", want, out.String())
+	}
+
+	cancel := func([]git.Worktree, string, map[string]time.Time) (string, error) { return "", nil }
+	if err := runSwitch(&git.GitRunner{}, bareRepo, &SwitchOptions{Query: "log"}, cancel, &out); err != errSwitchCancelled {
+		t.Errorf("a cancelled pick should be errSwitchCancelled, got %v", err)
+	}
+}
+
+func TestRunSwitch_AmbiguousWithoutTerminal(t *testing.T) {
+	bareRepo := setupSwitchRepo(t)
+	err := runSwitch(&git.GitRunner{}, bareRepo, &SwitchOptions{Query: "log"}, nil, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "feature/login") || !strings.Contains(err.Error(), "feature/logout") {
+		t.Errorf("expected an ambiguity error naming both matches, got %v", err)
+	}
+
+	err = runSwitch(&git.GitRunner{}, bareRepo, &SwitchOptions{Query: "nothing-like-this"}, nil, &bytes.Buffer{})
+	if err == nil {
+		t.Error("expected an error when nothing matches")
+	}
+}
+
+func TestRunSwitch_ListRanksRecentFirst(t *testing.T) {
+	bareRepo := setupSwitchRepo(t)
+	if err := runSwitch(&git.GitRunner{}, bareRepo, &SwitchOptions{Query: "feature/logout"}, nil, &bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runSwitch(&git.GitRunner{}, bareRepo, &SwitchOptions{Query: "log", List: true}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "feature/logout\t") {
+		t.Errorf("the recently used worktree should rank first:\n%s", out.String())
+	}
+}
+
+func TestRunShellInit(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		out := captureStdout(t, func() {
+			if err := RunShellInit([]string{shell}); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if !strings.Contains(out, "command sentei") || !strings.Contains(out, "cd -- ") {
+			t.Errorf("%s wrapper should call the binary and cd:\n%s", shell, out)
+		}
+	}
+	if err := RunShellInit([]string{"tcsh"}); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/dryrun"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/journal"
+	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// RunUndo lists the undo journal, or, given --last or --id, reverses that
+// entry's branch deletions, config purges, worktree removals and remote ref
+// prunes. The entry is only marked undone when every step succeeded, so a
+// partial undo can be retried.
+func RunUndo(args []string) error {
+	opts, err := ParseUndoFlags(args)
+	if err != nil {
+		return err
+	}
+
+	repoPath := "."
+	if opts.RepoPath != "" {
+		repoPath = opts.RepoPath
+	}
+	if absPath, err := filepath.Abs(repoPath); err == nil {
+		repoPath = absPath
+	}
+
+	runner := &git.GitRunner{}
+
+	switch repo.DetectContext(runner, repoPath) {
+	case repo.ContextBareRepo:
+		repoPath = repo.ResolveBareRoot(runner, repoPath)
+	case repo.ContextNonBareRepo:
+	default:
+		return fmt.Errorf("undo requires a git repository: %s", repoPath)
+	}
+
+	commonDir, err := git.CommonDir(runner, repoPath)
+	if err != nil {
+		return err
+	}
+	out := newReport(opts.Format)
+
+	if !opts.Last && opts.ID == "" {
+		entries, err := journal.List(commonDir)
+		if err != nil {
+			return err
+		}
+		if out != nil {
+			return out.Write(report.KindJournal, report.NewJournal(entries))
+		}
+		return printJournal(entries)
+	}
+
+	var entry journal.Entry
+	if opts.Last {
+		entry, err = journal.Last(commonDir)
+	} else {
+		entry, err = journal.Find(commonDir, opts.ID)
+	}
+	if err != nil {
+		return err
+	}
+	if entry.Undone() {
+		return fmt.Errorf("%s was already undone at %s", entry.ID, entry.UndoneAt.Local().Format(time.DateTime))
+	}
+
+	outcomes, undoErr := journal.Undo(runner, repoPath, commonDir, entry)
+	if undoErr == nil {
+		if err := journal.MarkUndone(commonDir, entry.ID, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if out != nil {
+		if err := out.Write(report.KindUndo, report.NewUndo(entry, outcomes)); err != nil {
+			return err
+		}
+	} else {
+		printUndo(entry, outcomes)
+	}
+	if undoErr != nil {
+		return fmt.Errorf("undo of %s incomplete; fix the failures and run sentei undo --id %s again: %w", entry.ID, entry.ID, undoErr)
+	}
+	return nil
+}
+
+// UndoPrompt describes what an undo invocation is about to change, for the
+// confirmation asked before it runs; a listing needs none.
+func UndoPrompt(args []string) (string, bool, error) {
+	opts, err := ParseUndoFlags(args)
+	if err != nil {
+		return "", false, err
+	}
+	target := "the newest remove or cleanup not yet undone"
+	switch {
+	case opts.ID != "":
+		target = "journal entry " + opts.ID
+	case !opts.Last:
+		return "", false, nil
+	}
+	return fmt.Sprintf("Undo %s, recreating the branches, worktrees, config and remote refs it deleted.", target), true, nil
+}
+
+func printJournal(entries []journal.Entry) error {
+	if len(entries) == 0 {
+		fmt.Println("Nothing recorded in the undo journal.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tCOMMAND\tWHEN\tBRANCHES\tWORKTREES\tCONFIG\tREMOTE REFS\tSTATE")
+	// Newest first: the entry undo --last would pick is at the top.
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		state := "undoable"
+		if e.Undone() {
+			state = "undone"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%s\n", e.ID, e.Command, dryrun.RelativeTime(e.CreatedAt),
+			len(e.Branches), len(e.Worktrees), len(e.ConfigSections), len(e.RemoteRefs), state)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\nUndo one with: sentei undo --last | --id ID\n")
+	return nil
+}
+
+func printUndo(entry journal.Entry, outcomes []journal.Outcome) {
+	fmt.Printf("Undoing %s (%s):\n", entry.ID, entry.Command)
+	for _, o := range outcomes {
+		switch o.Status {
+		case journal.OutcomeRestored:
+			fmt.Printf("  %s✓%s %s %s\n", green, nc, o.Kind, o.Target)
+		case journal.OutcomeSkipped:
+			fmt.Printf("  %s- %s %s (already present)%s\n", dim, o.Kind, o.Target, nc)
+		case journal.OutcomeFailed:
+			fmt.Printf("  %s✗%s %s %s: %v\n", yellow, nc, o.Kind, o.Target, o.Err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+
+	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// UndoOptions holds parsed flags for the undo command. Without --last or
+// --id the command lists the journal instead of undoing an entry.
+type UndoOptions struct {
+	Last     bool
+	ID       string
+	RepoPath string
+	Format   report.Format
+}
+
+// undoFlags is the undo command's flag set, shared by the parser and the
+// completion metadata.
+type undoFlags struct {
+	fs     *flag.FlagSet
+	last   *bool
+	id     *string
+	format *string
+}
+
+func newUndoFlags() *undoFlags {
+	fs := flag.NewFlagSet("undo", flag.ContinueOnError)
+	return &undoFlags{
+		fs:     fs,
+		last:   fs.Bool("last", false, "Undo the most recent remove or cleanup that has not been undone"),
+		id:     fs.String("id", "", "Journal entry to undo (as listed by sentei undo)"),
+		format: formatFlag(fs),
+	}
+}
+
+// UndoFlags describes the undo command's flags for usage and shell
+// completion.
+func UndoFlags() []cli.Flag {
+	return cli.FlagsOf(newUndoFlags().fs)
+}
+
+// ParseUndoFlags parses undo-specific flags and returns UndoOptions.
+func ParseUndoFlags(args []string) (*UndoOptions, error) {
+	fl := newUndoFlags()
+	if err := fl.fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *fl.last && *fl.id != "" {
+		return nil, errors.New("--last and --id are mutually exclusive")
+	}
+
+	f, err := report.ParseFormat(*fl.format)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &UndoOptions{
+		Last:   *fl.last,
+		ID:     *fl.id,
+		Format: f,
+	}
+	if fl.fs.NArg() > 0 {
+		opts.RepoPath = fl.fs.Arg(0)
+	}
+	return opts, nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/report"
+	"github.com/abiswas97/sentei/internal/testtmp"
+)
+
+func branchExists(t *testing.T, repo, branch string) bool {
+	t.Helper()
+	cmd := exec.Command("git", "-C", repo, "show-ref", "--verify", "--quiet", "refs/heads/"+branch)
+	cmd.Env = testtmp.HermeticGitEnv()
+	return cmd.Run() == nil
+}
+
+func TestRunUndo_ReversesCleanupThenRemove(t *testing.T) {
+	bareRepo := setupBareRepoWithMergedBranch(t)
+	wtPath := filepath.Join(bareRepo, "feature-merged-branch")
+
+	var err error
+	captureStdout(t, func() {
+		err = RunRemove([]string{"--merged", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("RunRemove() error = %v", err)
+	}
+	captureStdout(t, func() {
+		err = RunCleanup([]string{"--mode", "aggressive", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("RunCleanup() error = %v", err)
+	}
+	if branchExists(t, bareRepo, "feature/merged-branch") {
+		t.Fatal("aggressive cleanup should have deleted feature/merged-branch")
+	}
+
+	out := captureStdout(t, func() {
+		err = RunUndo([]string{"--format", "json", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("RunUndo(list) error = %v", err)
+	}
+	var listed struct {
+		Kind string                `json:"kind"`
+		Data []report.JournalEntry `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &listed); err != nil {
+		t.Fatalf("decoding %q: %v", out, err)
+	}
+	if listed.Kind != report.KindJournal || len(listed.Data) != 2 ||
+		listed.Data[0].Command != "remove" || listed.Data[1].Command != "cleanup" {
+		t.Fatalf("journal = %+v, want a remove entry then a cleanup entry", listed.Data)
+	}
+
+	// The newest entry is the cleanup: undoing it brings the branch back.
+	captureStdout(t, func() {
+		err = RunUndo([]string{"--last", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("RunUndo(--last) error = %v", err)
+	}
+	if !branchExists(t, bareRepo, "feature/merged-branch") {
+		t.Fatal("undoing the cleanup should recreate feature/merged-branch")
+	}
+
+	// The next --last skips the undone cleanup and restores the worktree.
+	out = captureStdout(t, func() {
+		err = RunUndo([]string{"--last", "--format", "json", bareRepo})
+	})
+	if err != nil {
+		t.Fatalf("second RunUndo(--last) error = %v", err)
+	}
+	var undone struct {
+		Kind string      `json:"kind"`
+		Data report.Undo `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &undone); err != nil {
+		t.Fatalf("decoding %q: %v", out, err)
+	}
+	if undone.Kind != report.KindUndo || !undone.Data.Complete || undone.Data.Entry.Command != "remove" {
+		t.Errorf("undo = %+v, want a complete undo of the remove", undone.Data)
+	}
+	if _, err := os.Stat(filepath.Join(wtPath, "feature.txt")); err != nil {
+		t.Errorf("worktree should be back at %s: %v", wtPath, err)
+	}
+
+	err = RunUndo([]string{"--last", bareRepo})
+	if err == nil || !strings.Contains(err.Error(), "nothing to undo") {
+		t.Errorf("third RunUndo(--last) error = %v, want nothing to undo", err)
+	}
+}
+
+func TestParseUndoFlags_LastAndIDConflict(t *testing.T) {
+	if _, err := ParseUndoFlags([]string{"--last", "--id", "x"}); err == nil {
+		t.Error("expected --last with --id to be rejected")
+	}
+}
+
+func TestUndoPrompt_OnlyConfirmsChanges(t *testing.T) {
+	if _, needed, err := UndoPrompt(nil); err != nil || needed {
+		t.Errorf("listing: needed = %v, err = %v; want no confirmation", needed, err)
+	}
+	prompt, needed, err := UndoPrompt([]string{"--id", "20261018T120000Z"})
+	if err != nil || !needed || !strings.Contains(prompt, "journal entry 20261018T120000Z") {
+		t.Errorf("--id: prompt = %q, needed = %v, err = %v", prompt, needed, err)
+	}
+	if _, _, err := UndoPrompt([]string{"--last", "--id", "x"}); err == nil {
+		t.Error("flag errors must surface before the prompt")
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// RunUnmigrate executes the unmigrate command in non-interactive mode.
+func RunUnmigrate(args []string) error {
+	opts, err := ParseUnmigrateFlags(args)
+	if err != nil {
+		return err
+	}
+	if err := ValidateUnmigrateForNonInteractive(opts); err != nil {
+		return err
+	}
+
+	repoPath := opts.RepoPath
+	if absPath, err := filepath.Abs(repoPath); err == nil {
+		repoPath = absPath
+	}
+
+	runner := &git.GitRunner{}
+
+	context := repo.DetectContext(runner, repoPath)
+	if context == repo.ContextNonBareRepo {
+		return fmt.Errorf("repository is not in sentei's bare layout: %s", repoPath)
+	}
+	if context != repo.ContextBareRepo {
+		return fmt.Errorf("not a git repository: %s", repoPath)
+	}
+	root := repo.ResolveBareRoot(runner, repoPath)
+
+	worktree, err := unmigrateWorktree(runner, root, opts.Worktree)
+	if err != nil {
+		return err
+	}
+	unmigrateOpts := repo.UnmigrateOptions{BareRoot: root, Worktree: worktree}
+
+	if out := newReport(opts.Format); out != nil {
+		return reportUnmigrate(out, runner, unmigrateOpts, opts.DeleteBackup)
+	}
+
+	result := repo.Unmigrate(runner, unmigrateOpts, printMigrateEvent)
+	if err := unmigrateResultError(result); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if name, step, ok := progress.FirstFailure(result.Phases); ok {
+		fmt.Fprintf(os.Stderr, "%s✗%s %s: %v\n", yellow, nc, step.Name, step.Error)
+		if result.RolledBack {
+			fmt.Fprintf(os.Stderr, "\nYour bare layout was restored from the backup.\n")
+		} else if result.BackupPath != "" {
+			fmt.Fprintf(os.Stderr, "\nYour bare layout is backed up at:\n  %s\n", result.BackupPath)
+			fmt.Fprintf(os.Stderr, "To restore:\n  %s\n", result.RestoreCommand())
+		}
+		return fmt.Errorf("unmigrate failed during %s phase", name)
+	}
+
+	fmt.Printf("%s✓%s Converted to a regular repository\n", green, nc)
+	fmt.Printf("  Repository: %s\n", result.RepoPath)
+	fmt.Printf("  Branch:     %s\n", result.Branch)
+	fmt.Printf("  Backup:     %s (%s)\n", result.BackupPath, result.BackupSize)
+	if n := len(result.Converted); n > 0 {
+		fmt.Printf("  Worktrees:  %d kept, now linked to .git\n", n)
+	}
+	if len(result.Dropped) > 0 {
+		fmt.Printf("\n%s⚠%s  Pruned missing worktrees:\n", yellow, nc)
+		for _, path := range result.Dropped {
+			fmt.Printf("  %s\n", path)
+		}
+	}
+
+	if opts.DeleteBackup && result.BackupPath != "" {
+		fmt.Printf("\n%s→%s Deleting backup...\n", blue, nc)
+		if err := repo.DeleteBackup(result.BackupPath); err != nil {
+			fmt.Fprintf(os.Stderr, "%s⚠%s  Failed to delete backup: %v\n", yellow, nc, err)
+		} else {
+			fmt.Printf("%s✓%s Backup deleted\n", green, nc)
+		}
+	}
+
+	return nil
+}
+
+// unmigrateWorktree picks the worktree to become the checkout: the one
+// asked for, else the default branch's.
+func unmigrateWorktree(runner git.CommandRunner, root, want string) (string, error) {
+	if want != "" {
+		return want, nil
+	}
+	candidates, err := repo.UnmigrateCandidates(runner, root)
+	if err != nil {
+		return "", err
+	}
+	defaultBranch := git.DetectDefaultBranch(runner, root)
+	var branches []string
+	for _, wt := range candidates {
+		branch := strings.TrimPrefix(wt.Branch, "refs/heads/")
+		if branch == defaultBranch {
+			return wt.Path, nil
+		}
+		if branch != "" {
+			branches = append(branches, branch)
+		}
+	}
+	return "", fmt.Errorf("no worktree for the default branch %q; choose one with --worktree (%s)", defaultBranch, strings.Join(branches, ", "))
+}
+
+// reportUnmigrate is RunUnmigrate for machine formats, deleting the backup
+// only after a fully successful conversion.
+func reportUnmigrate(out *report.Writer, runner git.CommandRunner, unmigrateOpts repo.UnmigrateOptions, deleteBackup bool) error {
+	result := repo.Unmigrate(runner, unmigrateOpts, out.Events())
+
+	if !result.HasFailures() && deleteBackup && result.BackupPath != "" {
+		if err := repo.DeleteBackup(result.BackupPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to delete backup: %v\n", err)
+		} else {
+			result.BackupPath, result.BackupSize = "", ""
+		}
+	}
+
+	if err := out.Write(report.KindUnmigrate, report.NewUnmigrateResult(result)); err != nil {
+		return err
+	}
+	if err := unmigrateResultError(result); err != nil {
+		return err
+	}
+	if name, _, ok := progress.FirstFailure(result.Phases); ok {
+		return fmt.Errorf("unmigrate failed during %s phase", name)
+	}
+	return nil
+}
+
+func unmigrateResultError(result repo.UnmigrateResult) error {
+	if result.Err != nil {
+		return fmt.Errorf("unmigrate failed: %w", result.Err)
+	}
+	return nil
+}
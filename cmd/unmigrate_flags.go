@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/abiswas97/sentei/internal/cli"
+	"github.com/abiswas97/sentei/internal/report"
+)
+
+// UnmigrateOptions holds the parsed flags for the unmigrate command.
+type UnmigrateOptions struct {
+	Worktree     string
+	DeleteBackup bool
+	RepoPath     string
+	Format       report.Format
+}
+
+// unmigrateFlags is the unmigrate command's flag set, shared by the parser
+// and the completion metadata.
+type unmigrateFlags struct {
+	fs           *flag.FlagSet
+	worktree     *string
+	deleteBackup *bool
+	format       *string
+}
+
+func newUnmigrateFlags() *unmigrateFlags {
+	fs := flag.NewFlagSet("unmigrate", flag.ContinueOnError)
+	return &unmigrateFlags{
+		fs:           fs,
+		worktree:     fs.String("worktree", "", "Worktree (branch or path) whose files become the checkout; defaults to the default branch"),
+		deleteBackup: fs.Bool("delete-backup", false, "Delete the backup after successful conversion"),
+		format:       formatFlag(fs),
+	}
+}
+
+// UnmigrateFlags describes the unmigrate command's flags for usage and shell
+// completion.
+func UnmigrateFlags() []cli.Flag {
+	return cli.FlagsOf(newUnmigrateFlags().fs)
+}
+
+// ParseUnmigrateFlags parses unmigrate-specific flags and returns
+// UnmigrateOptions. The repo path is taken as a positional argument.
+func ParseUnmigrateFlags(args []string) (*UnmigrateOptions, error) {
+	fl := newUnmigrateFlags()
+	if err := fl.fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	f, err := report.ParseFormat(*fl.format)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &UnmigrateOptions{
+		Worktree:     *fl.worktree,
+		DeleteBackup: *fl.deleteBackup,
+		Format:       f,
+	}
+
+	if fl.fs.NArg() > 0 {
+		opts.RepoPath = fl.fs.Arg(0)
+	}
+
+	return opts, nil
+}
+
+// ValidateUnmigrateForNonInteractive checks that all required arguments are
+// present for non-interactive execution.
+func ValidateUnmigrateForNonInteractive(opts *UnmigrateOptions) error {
+	if opts.RepoPath == "" {
+		return fmt.Errorf("repo path required (positional argument)")
+	}
+	return nil
+}
+
+// UnmigrateCLICommand generates the equivalent CLI command string from options.
+func UnmigrateCLICommand(opts *UnmigrateOptions) string {
+	cmd := "sentei unmigrate"
+	if opts.Worktree != "" {
+		cmd += " --worktree " + opts.Worktree
+	}
+	if opts.DeleteBackup {
+		cmd += " --delete-backup"
+	}
+	if opts.RepoPath != "" {
+		cmd += " " + opts.RepoPath
+	}
+	return cmd
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseUnmigrateFlags(t *testing.T) {
+	opts, err := ParseUnmigrateFlags([]string{"--worktree", "feature", "--delete-backup", "/some/repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Worktree != "feature" || !opts.DeleteBackup || opts.RepoPath != "/some/repo" {
+		t.Errorf("opts = %+v", opts)
+	}
+}
+
+func TestValidateUnmigrateForNonInteractive_MissingPath(t *testing.T) {
+	err := ValidateUnmigrateForNonInteractive(&UnmigrateOptions{})
+	if err == nil || !strings.Contains(err.Error(), "repo path required") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestUnmigrateCLICommand(t *testing.T) {
+	got := UnmigrateCLICommand(&UnmigrateOptions{Worktree: "main", DeleteBackup: true, RepoPath: "/repo"})
+	if want := "sentei unmigrate --worktree main --delete-backup /repo"; got != want {
+		t.Errorf("UnmigrateCLICommand() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/git"
+)
+
+func TestRunUnmigrate_RegularRepoRejected(t *testing.T) {
+	repoDir := setupNonBareRepo(t)
+
+	err := RunUnmigrate([]string{repoDir})
+	if err == nil || !strings.Contains(err.Error(), "not in sentei's bare layout") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestRunUnmigrate_UnknownWorktreeChangesNothing(t *testing.T) {
+	repoDir := setupNonBareRepo(t)
+	captureStdout(t, func() {
+		if err := RunMigrate([]string{"--delete-backup", repoDir}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	err := RunUnmigrate([]string{"--worktree", "nope", repoDir})
+	if err == nil || !strings.Contains(err.Error(), `no worktree for "nope"`) {
+		t.Fatalf("err = %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(repoDir, ".bare")); statErr != nil {
+		t.Errorf("bare layout touched: %v", statErr)
+	}
+}
+
+func TestRunUnmigrate_RoundTripWithDeleteBackup(t *testing.T) {
+	repoDir := setupNonBareRepo(t)
+	captureStdout(t, func() {
+		if err := RunMigrate([]string{"--delete-backup", repoDir}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	var err error
+	out := captureStdout(t, func() {
+		err = RunUnmigrate([]string{"--worktree", "main", "--delete-backup", filepath.Join(repoDir, "main")})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Converted to a regular repository") || !strings.Contains(out, "Backup deleted") {
+		t.Errorf("output:\n%s", out)
+	}
+	if info, statErr := os.Stat(filepath.Join(repoDir, ".git")); statErr != nil || !info.IsDir() {
+		t.Fatalf(".git is not a directory: %v", statErr)
+	}
+	if _, statErr := os.Stat(filepath.Join(repoDir, "README.md")); statErr != nil {
+		t.Errorf("README.md not at the root: %v", statErr)
+	}
+	if status, gitErr := (&git.GitRunner{}).Run(repoDir, "status", "--porcelain"); gitErr != nil || status != "" {
+		t.Errorf("status after round trip = %q, %v", status, gitErr)
+	}
+}
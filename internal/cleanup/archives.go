@@ -0,0 +1,50 @@
+package cleanup
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/trash"
+)
+
+// PurgeArchives drops worktree archives (see package trash) older than
+// opts.ArchiveRetention. commonDir is the git common dir the archives live
+// in. A zero retention disables purging.
+func PurgeArchives(runner git.CommandRunner, commonDir string, opts Options, emit func(Event)) (int, error) {
+	if opts.ArchiveRetention <= 0 {
+		return 0, nil
+	}
+	emit(Event{Step: "purge-archives", Message: "Purging expired worktree archives", Level: LevelStep})
+
+	expired, err := trash.Expired(commonDir, opts.ArchiveRetention, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if len(expired) == 0 {
+		emit(Event{Step: "purge-archives", Message: "No expired archives", Level: LevelInfo})
+		return 0, nil
+	}
+	if opts.DryRun {
+		emit(Event{Step: "purge-archives", Message: fmt.Sprintf("Would purge %d expired archive(s)", len(expired)), Level: LevelInfo})
+		return len(expired), nil
+	}
+
+	purged := 0
+	for _, a := range expired {
+		if err := trash.Drop(runner, commonDir, a); err != nil {
+			return purged, err
+		}
+		purged++
+		emit(Event{Step: "purge-archives", Message: a.ID, Level: LevelDetail})
+	}
+	emit(Event{Step: "purge-archives", Message: fmt.Sprintf("Purged %d expired archive(s)", purged), Level: LevelInfo})
+	return purged, nil
+}
+
+// commonDirOf returns the git common dir for the config path
+// resolveConfigPath produced.
+func commonDirOf(configPath string) string {
+	return filepath.Dir(configPath)
+}
@@ -1,6 +1,7 @@
 package cleanup
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -22,15 +23,19 @@ func DeleteGoneBranches(runner git.CommandRunner, repoPath string, opts Options,
 	}
 
 	gone, worktreeGone := parseGoneBranches(output)
+	gone, protected := partitionProtected(gone, opts.Protection)
 
 	var result BranchCleanResult
 
 	for _, b := range worktreeGone {
 		result.Skipped = append(result.Skipped, SkippedBranch{Name: b, Reason: SkipInWorktree})
 	}
+	for _, b := range protected {
+		result.Skipped = append(result.Skipped, SkippedBranch{Name: b, Reason: SkipProtected})
+	}
 
 	if len(gone) == 0 {
-		if len(worktreeGone) == 0 {
+		if len(worktreeGone) == 0 && len(protected) == 0 {
 			emit(Event{Step: "gone-branches", Message: "No branches with gone upstream", Level: LevelInfo})
 		}
 		return result, nil
@@ -51,10 +56,16 @@ func DeleteGoneBranches(runner git.CommandRunner, repoPath string, opts Options,
 		deleteFlag = "-D"
 	}
 
+	squashMerged := 0
 	for _, b := range gone {
-		if _, err := runner.Run(repoPath, "branch", deleteFlag, b); err != nil {
+		err := deleteBranch(runner, repoPath, b, deleteFlag, opts.Mode == ModeAggressive)
+		switch {
+		case errors.Is(err, errSquashMerged):
+			result.Skipped = append(result.Skipped, SkippedBranch{Name: b, Reason: SkipSquashMerged})
+			squashMerged++
+		case err != nil:
 			result.Skipped = append(result.Skipped, SkippedBranch{Name: b, Reason: SkipUnmerged})
-		} else {
+		default:
 			result.Deleted++
 		}
 	}
@@ -62,17 +73,48 @@ func DeleteGoneBranches(runner git.CommandRunner, repoPath string, opts Options,
 	if result.Deleted > 0 {
 		emit(Event{Step: "gone-branches", Message: fmt.Sprintf("Deleted %d branch(es) with gone upstream", result.Deleted), Level: LevelInfo})
 	}
-	if skipped := len(result.Skipped) - len(worktreeGone); skipped > 0 {
+	if len(protected) > 0 {
+		emit(Event{Step: "gone-branches", Message: fmt.Sprintf("%d protected branch(es) with gone upstream kept", len(protected)), Level: LevelDetail})
+	}
+	if squashMerged > 0 {
+		emit(Event{Step: "gone-branches", Message: fmt.Sprintf("%d squash-merged branch(es) kept; delete them with aggressive mode", squashMerged), Level: LevelWarn})
+	}
+	if skipped := len(result.Skipped) - len(worktreeGone) - len(protected) - squashMerged; skipped > 0 {
 		emit(Event{Step: "gone-branches", Message: fmt.Sprintf("%d branch(es) skipped (not fully merged)", skipped), Level: LevelWarn})
 	}
 
 	return result, nil
 }
 
+// errSquashMerged marks a branch git branch -d refused although its work
+// reached HEAD through a squash or rebase merge.
+var errSquashMerged = errors.New("squash-merged")
+
+// deleteBranch runs `git branch <flag> branch`. When a plain -d refuses
+// because git sees the branch as unmerged, but its work reached HEAD through a
+// squash or rebase merge, escalate deletes it with -D after all: nothing on
+// it is lost, and the aggressive preview already counted it as merged.
+// Without escalate the refusal is returned wrapped in errSquashMerged, since
+// the merge is a heuristic safe mode does not act on.
+func deleteBranch(runner git.CommandRunner, repoPath, branch, flag string, escalate bool) error {
+	_, err := runner.Run(repoPath, "branch", flag, branch)
+	if err == nil || flag != "-d" {
+		return err
+	}
+	if !git.MergedInto(runner, repoPath, branch, "HEAD").Merged() {
+		return err
+	}
+	if !escalate {
+		return fmt.Errorf("%w: %w", errSquashMerged, err)
+	}
+	_, err = runner.Run(repoPath, "branch", "-D", branch)
+	return err
+}
+
 func CleanNonWorktreeBranches(runner git.CommandRunner, repoPath string, opts Options, emit func(Event)) (BranchCleanResult, error) {
 	emit(Event{Step: "non-wt-branches", Message: "Checking non-worktree branches...", Level: LevelStep})
 
-	candidates, err := listNonWorktreeCandidates(runner, repoPath)
+	candidates, err := listNonWorktreeCandidates(runner, repoPath, opts.Protection)
 	if err != nil {
 		return BranchCleanResult{}, err
 	}
@@ -101,7 +143,7 @@ func CleanNonWorktreeBranches(runner git.CommandRunner, repoPath string, opts Op
 	}
 
 	for _, b := range candidates {
-		if _, err := runner.Run(repoPath, "branch", deleteFlag, b); err != nil {
+		if err := deleteBranch(runner, repoPath, b, deleteFlag, true); err != nil {
 			result.Skipped = append(result.Skipped, SkippedBranch{Name: b, Reason: SkipUnmerged})
 			result.Remaining++
 		} else {
@@ -121,7 +163,7 @@ func CleanNonWorktreeBranches(runner git.CommandRunner, repoPath string, opts Op
 
 // listNonWorktreeCandidates returns local branches that are neither checked
 // out in any worktree nor protected — the set aggressive cleanup deletes.
-func listNonWorktreeCandidates(runner git.CommandRunner, repoPath string) ([]string, error) {
+func listNonWorktreeCandidates(runner git.CommandRunner, repoPath string, protection *git.ProtectionPolicy) ([]string, error) {
 	wtOutput, err := runner.Run(repoPath, "worktree", "list", "--porcelain")
 	if err != nil {
 		return nil, fmt.Errorf("listing worktrees: %w", err)
@@ -139,7 +181,7 @@ func listNonWorktreeCandidates(runner git.CommandRunner, repoPath string) ([]str
 		if b == "" {
 			continue
 		}
-		if wtBranches[b] || git.IsProtectedBranch(b) {
+		if wtBranches[b] || protection.IsProtected(b) {
 			continue
 		}
 		candidates = append(candidates, b)
@@ -147,6 +189,19 @@ func listNonWorktreeCandidates(runner git.CommandRunner, repoPath string) ([]str
 	return candidates, nil
 }
 
+// partitionProtected splits branches into those the policy allows deleting
+// and those it protects, preserving order.
+func partitionProtected(branches []string, protection *git.ProtectionPolicy) (deletable, protected []string) {
+	for _, b := range branches {
+		if protection.IsProtected(b) {
+			protected = append(protected, b)
+		} else {
+			deletable = append(deletable, b)
+		}
+	}
+	return deletable, protected
+}
+
 func parseGoneBranches(output string) (gone []string, worktreeGone []string) {
 	for _, line := range strings.Split(output, "\n") {
 		if !strings.Contains(line, ": gone]") {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/abiswas97/sentei/internal/git"
 	"github.com/abiswas97/sentei/internal/testutil/mock"
 )
 
@@ -12,9 +13,11 @@ func TestDeleteGoneBranches(t *testing.T) {
 		name           string
 		branchVV       string
 		extraResponses map[string]mock.Response
+		mode           Mode
 		dryRun         bool
 		wantDeleted    int
 		wantSkipped    int
+		wantReason     SkipReason
 	}{
 		{
 			name:        "no gone branches",
@@ -47,6 +50,22 @@ func TestDeleteGoneBranches(t *testing.T) {
 			wantDeleted: 0,
 			wantSkipped: 1,
 		},
+		{
+			name:     "aggressive mode force-deletes a squash-merged branch that -d refuses",
+			branchVV: "  feature/squashed abc123 [origin/feature/squashed: gone] commit",
+			mode:     ModeAggressive,
+			extraResponses: withSquashProbe(map[string]mock.Response{
+				"/repo:[branch -D feature/squashed]": {Output: "Deleted branch feature/squashed"},
+			}),
+			wantDeleted: 1,
+		},
+		{
+			name:           "safe mode keeps a squash-merged branch that -d refuses",
+			branchVV:       "  feature/squashed abc123 [origin/feature/squashed: gone] commit",
+			extraResponses: withSquashProbe(nil),
+			wantSkipped:    1,
+			wantReason:     SkipSquashMerged,
+		},
 		{
 			name:        "dry run counts without deleting",
 			branchVV:    "  feature/gone abc123 [origin/feature/gone: gone] commit",
@@ -66,7 +85,7 @@ func TestDeleteGoneBranches(t *testing.T) {
 			}
 
 			runner := &mock.Runner{Responses: responses}
-			opts := Options{DryRun: tt.dryRun}
+			opts := Options{Mode: tt.mode, DryRun: tt.dryRun}
 			events := collectEvents(t)
 
 			result, err := DeleteGoneBranches(runner, "/repo", opts, events.Emit)
@@ -79,10 +98,74 @@ func TestDeleteGoneBranches(t *testing.T) {
 			if len(result.Skipped) != tt.wantSkipped {
 				t.Errorf("Skipped = %d, want %d", len(result.Skipped), tt.wantSkipped)
 			}
+			if tt.wantReason != "" && (len(result.Skipped) == 0 || result.Skipped[0].Reason != tt.wantReason) {
+				t.Errorf("Skipped = %+v, want reason %q", result.Skipped, tt.wantReason)
+			}
 		})
 	}
 }
 
+// withSquashProbe adds the calls through which git.MergedInto finds
+// feature/squashed squash-merged into HEAD after -d refuses it.
+func withSquashProbe(responses map[string]mock.Response) map[string]mock.Response {
+	out := map[string]mock.Response{
+		"/repo:[branch -d feature/squashed]":                                             {Err: fmt.Errorf("error: branch not fully merged")},
+		"/repo:[merge-base --is-ancestor feature/squashed HEAD]":                         {Err: fmt.Errorf("exit 1")},
+		"/repo:[cherry HEAD feature/squashed]":                                           {Output: "+ abc123"},
+		"/repo:[merge-base HEAD feature/squashed]":                                       {Output: "base000\n"},
+		"/repo:[diff --name-only --no-renames base000 feature/squashed]":                 {Output: "a.go\n"},
+		"/repo:[log --no-merges --no-renames --name-only --format=%x00%H base000..HEAD]": {Output: "\x00c2\n\nb.go\n\x00c1\n\na.go\n"},
+		"/repo:[diff --no-color --no-ext-diff --no-renames base000 feature/squashed]":    {Output: "squash diff"},
+		"/repo:[patch-id --stable]<squash diff":                                          {Output: "pid1 0000000"},
+		"/repo:[show --format=commit %H --no-color --no-ext-diff --no-renames c1]":       {Output: "target patches"},
+		"/repo:[patch-id --stable]<target patches":                                       {Output: "pid1 c1"},
+	}
+	for k, v := range responses {
+		out[k] = v
+	}
+	return out
+}
+
+func TestDeleteGoneBranches_SkipsProtected(t *testing.T) {
+	// Neither delete is mocked: a protected branch reaching `git branch -d`
+	// would fail the mock and surface as SkipUnmerged instead.
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[branch -vv]":            {Output: "  release/1.0 abc123 [origin/release/1.0: gone] commit\n  feature/old def456 [origin/feature/old: gone] commit"},
+		"/repo:[branch -d feature/old]": {Output: "Deleted branch feature/old"},
+	}}
+	opts := Options{Mode: ModeSafe, Protection: git.NewProtectionPolicy("main", []string{"release/*"})}
+	events := collectEvents(t)
+
+	result, err := DeleteGoneBranches(runner, "/repo", opts, events.Emit)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Deleted != 1 {
+		t.Errorf("Deleted = %d, want 1", result.Deleted)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != (SkippedBranch{Name: "release/1.0", Reason: SkipProtected}) {
+		t.Errorf("Skipped = %+v, want release/1.0 as protected", result.Skipped)
+	}
+}
+
+func TestCleanNonWorktreeBranches_SkipsProtectedPatterns(t *testing.T) {
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[worktree list --porcelain]":        {Output: "worktree /repo\nbare\n"},
+		"/repo:[branch --format=%(refname:short)]": {Output: "main\nproduction\nrelease/2.0\nfeature/x"},
+		"/repo:[branch -d feature/x]":              {Output: ""},
+	}}
+	opts := Options{Mode: ModeAggressive, Protection: git.NewProtectionPolicy("main", []string{"release/*", "production"})}
+	events := collectEvents(t)
+
+	result, err := CleanNonWorktreeBranches(runner, "/repo", opts, events.Emit)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Deleted != 1 || len(result.Skipped) != 0 {
+		t.Errorf("only feature/x is deletable: deleted=%d skipped=%+v", result.Deleted, result.Skipped)
+	}
+}
+
 func TestParseGoneBranches(t *testing.T) {
 	tests := []struct {
 		name             string
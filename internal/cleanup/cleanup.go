@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/journal"
 )
 
 type Mode string
@@ -19,6 +21,14 @@ type Options struct {
 	Mode   Mode
 	Force  bool
 	DryRun bool
+
+	// Protection decides which branches branch deletion must skip. Nil
+	// protects the built-in convention set only.
+	Protection *git.ProtectionPolicy
+
+	// ArchiveRetention is how long worktree archives are kept before cleanup
+	// purges them. Zero leaves archives alone.
+	ArchiveRetention time.Duration
 }
 
 type Result struct {
@@ -29,6 +39,7 @@ type Result struct {
 	NonWtBranchesDeleted   int
 	NonWtBranchesRemaining int
 	WorktreesPruned        int
+	ArchivesPurged         int
 	BranchesSkipped        []SkippedBranch
 	Errors                 []OperationError
 }
@@ -48,6 +59,9 @@ type ConfigResult struct {
 	Before  int
 	After   int
 	Removed int
+	// Sections holds the removed sections verbatim, for the undo journal.
+	// Only PurgeOrphanedBranchConfigs fills it.
+	Sections []journal.ConfigSection
 }
 
 type OperationError struct {
@@ -61,6 +75,10 @@ const (
 	SkipUnmerged   SkipReason = "not fully merged"
 	SkipInWorktree SkipReason = "checked out in worktree"
 	SkipProtected  SkipReason = "protected branch"
+	// SkipSquashMerged: git branch -d refused, but the branch's work is on
+	// HEAD through a squash or rebase merge. Safe mode leaves the -D to
+	// aggressive mode.
+	SkipSquashMerged SkipReason = "squash-merged; delete with aggressive mode"
 )
 
 type SkippedBranch struct {
@@ -102,6 +120,14 @@ func Run(runner git.CommandRunner, repoPath string, opts Options, emit func(Even
 
 	var result Result
 
+	// Snapshot ref tips so the journal can record what the steps below delete.
+	var tips map[string]string
+	if !opts.DryRun {
+		if tips, err = journal.Tips(runner, repoPath); err != nil {
+			result.Errors = append(result.Errors, OperationError{Step: "journal", Err: err})
+		}
+	}
+
 	if r, err := PruneRemoteRefs(runner, repoPath, opts, emit); err != nil {
 		result.Errors = append(result.Errors, OperationError{Step: "prune-refs", Err: err})
 	} else {
@@ -142,6 +168,18 @@ func Run(runner git.CommandRunner, repoPath string, opts Options, emit func(Even
 		result.WorktreesPruned = pruned
 	}
 
+	purged, err := PurgeArchives(runner, commonDirOf(configPath), opts, emit)
+	if err != nil {
+		result.Errors = append(result.Errors, OperationError{Step: "purge-archives", Err: err})
+	}
+	result.ArchivesPurged = purged
+
+	if tips != nil {
+		if err := recordJournal(runner, repoPath, commonDirOf(configPath), tips, result); err != nil {
+			result.Errors = append(result.Errors, OperationError{Step: "journal", Err: err})
+		}
+	}
+
 	return result
 }
 
@@ -68,6 +68,7 @@ func setupOrchestratorTest(t *testing.T) (*mock.Runner, string) {
 		tmpDir + ":[branch -vv]":                       {Output: "  main abc123 [origin/main] latest"},
 		tmpDir + ":[worktree list --porcelain]":        {Output: "worktree " + tmpDir + "\nbare\n\nworktree " + tmpDir + "/main\nHEAD abc\nbranch refs/heads/main"},
 		tmpDir + ":[branch --format=%(refname:short)]": {Output: "main\nfeature/old"},
+		tmpDir + ":[for-each-ref --format=%(objectname) %(refname) refs/heads refs/remotes]": {Output: "abc123 refs/heads/main\ndef456 refs/heads/feature/old"},
 	}}
 
 	return runner, tmpDir
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/journal"
 )
 
 func DedupConfig(configPath string, opts Options, emit func(Event)) (ConfigResult, error) {
@@ -89,6 +90,7 @@ func PurgeOrphanedBranchConfigs(runner git.CommandRunner, repoPath string, confi
 	lines := strings.Split(string(data), "\n")
 	before := len(lines)
 	var out []string
+	var sections []journal.ConfigSection
 	skip := false
 	orphanCount := 0
 
@@ -100,6 +102,7 @@ func PurgeOrphanedBranchConfigs(runner git.CommandRunner, repoPath string, confi
 			if !existing[branchName] {
 				skip = true
 				orphanCount++
+				sections = append(sections, journal.ConfigSection{Header: line, Lines: []string{line}})
 				continue
 			}
 			skip = false
@@ -115,11 +118,14 @@ func PurgeOrphanedBranchConfigs(runner git.CommandRunner, repoPath string, confi
 
 		if !skip {
 			out = append(out, line)
+		} else if strings.TrimSpace(line) != "" {
+			last := &sections[len(sections)-1]
+			last.Lines = append(last.Lines, line)
 		}
 	}
 
 	after := len(out)
-	result := ConfigResult{Before: before, After: after, Removed: orphanCount}
+	result := ConfigResult{Before: before, After: after, Removed: orphanCount, Sections: sections}
 
 	if orphanCount == 0 {
 		emit(Event{Step: "orphaned-configs", Message: "No orphaned branch config sections", Level: LevelInfo})
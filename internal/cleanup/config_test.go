@@ -168,3 +168,25 @@ func TestPurgeOrphanedBranchConfigs(t *testing.T) {
 		})
 	}
 }
+
+func TestPurgeOrphanedBranchConfigs_RecordsSections(t *testing.T) {
+	path := copyFixture(t, "bloated.gitconfig")
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[branch --format=%(refname:short)]": {Output: "main\nfeature/old-work"},
+	}}
+
+	result, err := PurgeOrphanedBranchConfigs(runner, "/repo", path, Options{}, collectEvents(t).Emit)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Sections) != 1 {
+		t.Fatalf("Sections = %+v, want one", result.Sections)
+	}
+	section := result.Sections[0]
+	if section.Header != `[branch "fix/stale-branch"]` {
+		t.Errorf("Header = %q", section.Header)
+	}
+	if len(section.Lines) < 2 || section.Lines[0] != section.Header {
+		t.Errorf("Lines = %q, want the header followed by the section's keys", section.Lines)
+	}
+}
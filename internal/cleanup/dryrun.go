@@ -17,6 +17,8 @@ type DryRunResult struct {
 	GoneBranches      []string // safe mode deletes these (gone upstream, not in a worktree)
 	OrphanedConfigs   int
 	PrunableWorktrees int
+	// ExpiredArchives are worktree archives past the retention period.
+	ExpiredArchives int
 
 	// AggressiveBranches are local branches in no worktree and not protected:
 	// the additional set only aggressive mode deletes.
@@ -30,16 +32,17 @@ type BranchInfo struct {
 	Name              string
 	LastCommitDate    time.Time
 	LastCommitSubject string
-	// Merged reports whether the branch is fully merged into HEAD: without
-	// --force, aggressive cleanup only deletes merged branches, and the
-	// preview must not promise more than that.
+	// Merged reports whether the branch is fully merged into HEAD, by
+	// ancestry or by a squash or rebase merge: without --force, aggressive
+	// cleanup only deletes merged branches, and the preview must not promise
+	// more than that.
 	Merged bool
 }
 
 // SafeHasWork reports whether a safe-mode cleanup would change anything.
 func (r DryRunResult) SafeHasWork() bool {
 	return r.StaleRefs > 0 || r.ConfigDuplicates > 0 || len(r.GoneBranches) > 0 ||
-		r.OrphanedConfigs > 0 || r.PrunableWorktrees > 0
+		r.OrphanedConfigs > 0 || r.PrunableWorktrees > 0 || r.ExpiredArchives > 0
 }
 
 // AggressiveHasWork reports whether aggressive mode would delete branches
@@ -61,16 +64,18 @@ func (r DryRunResult) UnmergedAggressiveCount() int {
 }
 
 // DryRun inspects the repository without mutating it and returns what both
-// cleanup modes would do. Individual probe failures are collected in
-// Errors; only an unresolvable repository aborts the scan.
-func DryRun(runner git.CommandRunner, repoPath string) (DryRunResult, error) {
+// cleanup modes would do. Branches the protection policy covers are left out
+// of every deletion list, as Run would skip them. Individual probe failures
+// are collected in Errors; only an unresolvable repository aborts the scan.
+// archiveRetention is Options.ArchiveRetention; zero skips the archive count.
+func DryRun(runner git.CommandRunner, repoPath string, protection *git.ProtectionPolicy, archiveRetention time.Duration) (DryRunResult, error) {
 	configPath, err := resolveConfigPath(runner, repoPath)
 	if err != nil {
 		return DryRunResult{}, err
 	}
 
 	noop := func(Event) {}
-	probe := Options{Mode: ModeSafe, DryRun: true}
+	probe := Options{Mode: ModeSafe, DryRun: true, Protection: protection, ArchiveRetention: archiveRetention}
 	var result DryRunResult
 
 	if n, err := PruneRemoteRefs(runner, repoPath, probe, noop); err != nil {
@@ -89,7 +94,7 @@ func DryRun(runner git.CommandRunner, repoPath string) (DryRunResult, error) {
 		result.Errors = append(result.Errors, OperationError{Step: "gone-branches", Err: err})
 	} else {
 		gone, _ := parseGoneBranches(output)
-		result.GoneBranches = gone
+		result.GoneBranches, _ = partitionProtected(gone, protection)
 	}
 
 	if r, err := PurgeOrphanedBranchConfigs(runner, repoPath, configPath, probe, noop); err != nil {
@@ -104,7 +109,13 @@ func DryRun(runner git.CommandRunner, repoPath string) (DryRunResult, error) {
 		result.PrunableWorktrees = countPrunable(output)
 	}
 
-	if candidates, err := listNonWorktreeCandidates(runner, repoPath); err != nil {
+	if n, err := PurgeArchives(runner, commonDirOf(configPath), probe, noop); err != nil {
+		result.Errors = append(result.Errors, OperationError{Step: "purge-archives", Err: err})
+	} else {
+		result.ExpiredArchives = n
+	}
+
+	if candidates, err := listNonWorktreeCandidates(runner, repoPath, protection); err != nil {
 		result.Errors = append(result.Errors, OperationError{Step: "non-wt-branches", Err: err})
 	} else if len(candidates) > 0 {
 		meta, err := branchMetadata(runner, repoPath)
@@ -117,7 +128,7 @@ func DryRun(runner git.CommandRunner, repoPath string) (DryRunResult, error) {
 				Name:              name,
 				LastCommitDate:    meta[name].LastCommitDate,
 				LastCommitSubject: meta[name].LastCommitSubject,
-				Merged:            branchDeletableByGit(runner, repoPath, name),
+				Merged:            branchDeletable(runner, repoPath, name),
 			})
 		}
 	}
@@ -125,6 +136,14 @@ func DryRun(runner git.CommandRunner, repoPath string) (DryRunResult, error) {
 	return result, nil
 }
 
+// branchDeletable predicts whether aggressive cleanup without --force deletes
+// branch: either `git branch -d` accepts it, or deleteBranch falls back to -D
+// because its work reached HEAD through a squash or rebase merge.
+func branchDeletable(runner git.CommandRunner, repoPath, branch string) bool {
+	return branchDeletableByGit(runner, repoPath, branch) ||
+		git.MergedInto(runner, repoPath, branch, "HEAD").Merged()
+}
+
 // branchDeletableByGit predicts whether `git branch -d` would delete branch.
 // git refuses unless the branch is fully merged into its upstream (when one is
 // configured) or into HEAD (when it is not) — notably it checks the upstream,
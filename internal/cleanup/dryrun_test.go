@@ -44,7 +44,7 @@ func dryRunMock(t *testing.T) (*mock.Runner, string) {
 func TestDryRun_CollectsBothModes(t *testing.T) {
 	runner, _ := dryRunMock(t)
 
-	result, err := DryRun(runner, "/repo")
+	result, err := DryRun(runner, "/repo", nil, 0)
 	if err != nil {
 		t.Fatalf("DryRun() error: %v", err)
 	}
@@ -105,7 +105,7 @@ func TestBranchDeletableByGit(t *testing.T) {
 func TestDryRun_MutatesNothing(t *testing.T) {
 	runner, _ := dryRunMock(t)
 
-	if _, err := DryRun(runner, "/repo"); err != nil {
+	if _, err := DryRun(runner, "/repo", nil, 0); err != nil {
 		t.Fatalf("DryRun() error: %v", err)
 	}
 
@@ -148,7 +148,7 @@ func TestDryRunResult_HasWork(t *testing.T) {
 
 func TestDryRun_ErrorWhenConfigUnresolvable(t *testing.T) {
 	runner := &mock.Runner{Responses: map[string]mock.Response{}}
-	if _, err := DryRun(runner, "/repo"); err == nil {
+	if _, err := DryRun(runner, "/repo", nil, 0); err == nil {
 		t.Error("expected an error when the repo config cannot be resolved")
 	}
 }
@@ -0,0 +1,41 @@
+package cleanup
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/journal"
+)
+
+// recordJournal appends what Run deleted to the undo journal: every branch
+// and remote-tracking ref present in before but gone now, with its old tip,
+// and the orphaned config sections that were purged.
+func recordJournal(runner git.CommandRunner, repoPath, commonDir string, before map[string]string, result Result) error {
+	after, err := journal.Tips(runner, repoPath)
+	if err != nil {
+		return err
+	}
+
+	entry := journal.Entry{Command: "cleanup", ConfigSections: result.ConfigOrphanResult.Sections}
+	names := make([]string, 0, len(before))
+	for name := range before {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := after[name]; ok {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(name, "refs/heads/"):
+			entry.Branches = append(entry.Branches, journal.Branch{Name: strings.TrimPrefix(name, "refs/heads/"), SHA: before[name]})
+		case strings.HasPrefix(name, "refs/remotes/"):
+			entry.RemoteRefs = append(entry.RemoteRefs, journal.Ref{Name: name, SHA: before[name]})
+		}
+	}
+
+	_, err = journal.Append(commonDir, entry, time.Now())
+	return err
+}
@@ -0,0 +1,324 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Shells CompletionScript can generate for.
+var Shells = []string{"bash", "zsh", "fish"}
+
+var ErrUnknownShell = errors.New("unknown shell")
+
+// ValuesCommand is the hidden completion subcommand the scripts call for
+// dynamic candidates: `sentei completion __values branches|ecosystems|profiles`.
+const ValuesCommand = "__values"
+
+// CompletionScript returns a completion script for shell covering every
+// registered command, its flags and the global flags. Branch, ecosystem and
+// profile values are looked up when completing, through ValuesCommand.
+func (r *Registry) CompletionScript(shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return r.bashCompletion(), nil
+	case "zsh":
+		return r.zshCompletion(), nil
+	case "fish":
+		return r.fishCompletion(), nil
+	}
+	return "", fmt.Errorf("%w %q: must be one of %s", ErrUnknownShell, shell, strings.Join(Shells, ", "))
+}
+
+// completionCommands returns the commands in name order.
+func (r *Registry) completionCommands() []*Command {
+	names := r.CommandNames()
+	sort.Strings(names)
+	cmds := make([]*Command, len(names))
+	for i, name := range names {
+		cmds[i] = r.commands[name]
+	}
+	return cmds
+}
+
+// commandFlags merges a command's flags with the global ones, the command's
+// own declaration winning on a shared name.
+func commandFlags(cmd *Command) []Flag {
+	seen := make(map[string]bool, len(cmd.Flags))
+	flags := make([]Flag, 0, len(cmd.Flags)+len(GlobalFlags))
+	for _, f := range cmd.Flags {
+		seen[f.Name] = true
+		flags = append(flags, f)
+	}
+	for _, f := range GlobalFlags {
+		if !seen[f.Name] {
+			flags = append(flags, f)
+		}
+	}
+	return flags
+}
+
+func flagWords(flags []Flag) string {
+	words := make([]string, 0, len(flags))
+	for _, f := range flags {
+		words = append(words, "--"+f.Name)
+		if f.Short != "" {
+			words = append(words, "-"+f.Short)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func (r *Registry) bashCompletion() string {
+	cmds := r.completionCommands()
+	var b strings.Builder
+	b.WriteString("# bash completion for sentei\n")
+	b.WriteString("# Load with: source <(sentei completion bash)\n\n")
+	b.WriteString(`_sentei() {
+    local cur prev cmd i
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    cmd=""
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        case "${COMP_WORDS[i]}" in
+            -*) ;;
+            *) cmd="${COMP_WORDS[i]}"; break ;;
+        esac
+    done
+
+    local values=""
+    case "$cmd:$prev" in
+`)
+	valueCase := func(cmd string, f Flag) {
+		pattern := fmt.Sprintf("%s:--%s", cmd, f.Name)
+		switch f.Kind {
+		case FlagEnum:
+			fmt.Fprintf(&b, "        %s) COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;\n", pattern, strings.Join(f.Values, " "))
+		case FlagBranch:
+			fmt.Fprintf(&b, "        %s) COMPREPLY=($(compgen -W \"$(sentei completion %s branches 2>/dev/null)\" -- \"$cur\")); return ;;\n", pattern, ValuesCommand)
+		case FlagEcosystems:
+			fmt.Fprintf(&b, "        %s) values=\"$(sentei completion %s ecosystems 2>/dev/null)\" ;;\n", pattern, ValuesCommand)
+		case FlagPath:
+			fmt.Fprintf(&b, "        %s) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n", pattern)
+		case FlagProfile:
+			fmt.Fprintf(&b, "        %s) COMPREPLY=($(compgen -W \"$(sentei completion %s profiles 2>/dev/null)\" -- \"$cur\")); return ;;\n", pattern, ValuesCommand)
+		case FlagString:
+			fmt.Fprintf(&b, "        %s) return ;;\n", pattern)
+		}
+	}
+	for _, f := range r.rootFlags {
+		valueCase("", f)
+	}
+	for _, cmd := range cmds {
+		for _, f := range commandFlags(cmd) {
+			valueCase(cmd.Name, f)
+		}
+	}
+	b.WriteString(`    esac
+    if [[ -n "$values" ]]; then
+        # A comma-separated list: complete the element after the last comma.
+        local pre=""
+        [[ "$cur" == *,* ]] && pre="${cur%,*},"
+        COMPREPLY=($(compgen -P "$pre" -W "$values" -- "${cur##*,}"))
+        return
+    fi
+
+    local flags="" args=""
+    case "$cmd" in
+`)
+	fmt.Fprintf(&b, "        \"\") flags=%q ;;\n", flagWords(r.rootFlags))
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "        %s) flags=%q", cmd.Name, flagWords(commandFlags(cmd)))
+		if len(cmd.Args) > 0 {
+			fmt.Fprintf(&b, "; args=%q", strings.Join(cmd.Args, " "))
+		}
+		b.WriteString(" ;;\n")
+	}
+	names := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		names[i] = cmd.Name
+	}
+	fmt.Fprintf(&b, `    esac
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+    elif [[ -z "$cmd" ]]; then
+        COMPREPLY=($(compgen -W %q -- "$cur"))
+    elif [[ -n "$args" ]]; then
+        COMPREPLY=($(compgen -W "$args" -- "$cur"))
+    else
+        COMPREPLY=($(compgen -d -- "$cur"))
+    fi
+}
+
+complete -F _sentei sentei
+`, strings.Join(names, " "))
+	return b.String()
+}
+
+// zshQuote escapes s for the description of a single-quoted _arguments spec,
+// where brackets are also special.
+func zshQuote(s string) string {
+	s = strings.ReplaceAll(s, `'`, `'\''`)
+	s = strings.NewReplacer(`[`, `\[`, `]`, `\]`).Replace(s)
+	return s
+}
+
+func zshSpecs(flags []Flag) []string {
+	specs := make([]string, 0, len(flags))
+	for _, f := range flags {
+		var action string
+		switch f.Kind {
+		case FlagEnum:
+			action = fmt.Sprintf(":%s:(%s)", f.Name, strings.Join(f.Values, " "))
+		case FlagBranch:
+			action = ":branch:_sentei_branches"
+		case FlagEcosystems:
+			action = ":ecosystems:_sentei_ecosystems"
+		case FlagPath:
+			action = ":path:_files"
+		case FlagProfile:
+			action = ":profile:_sentei_profiles"
+		case FlagString:
+			action = ":" + f.Name + ": "
+		}
+		names := "--" + f.Name
+		if f.Short != "" {
+			names = fmt.Sprintf("(--%s -%s)'{--%s,-%s}'", f.Name, f.Short, f.Name, f.Short)
+		}
+		specs = append(specs, fmt.Sprintf("'%s[%s]%s'", names, zshQuote(f.Usage), action))
+	}
+	return specs
+}
+
+func (r *Registry) zshCompletion() string {
+	cmds := r.completionCommands()
+	var b strings.Builder
+	b.WriteString("#compdef sentei\n")
+	b.WriteString("# zsh completion for sentei\n")
+	b.WriteString("# Load with: source <(sentei completion zsh)\n\n")
+	fmt.Fprintf(&b, `_sentei_branches() {
+    local -a branches
+    branches=(${(f)"$(sentei completion %[1]s branches 2>/dev/null)"})
+    _describe 'branch' branches
+}
+
+_sentei_ecosystems() {
+    local -a ecosystems
+    ecosystems=(${(f)"$(sentei completion %[1]s ecosystems 2>/dev/null)"})
+    _values -s , 'ecosystem' $ecosystems
+}
+
+_sentei_profiles() {
+    local -a profiles
+    profiles=(${(f)"$(sentei completion %[1]s profiles 2>/dev/null)"})
+    _describe 'profile' profiles
+}
+
+_sentei() {
+    local curcontext="$curcontext" state line
+    local -a commands
+    commands=(`, ValuesCommand)
+	for i, cmd := range cmds {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(cmd.Name)
+	}
+	b.WriteString(")\n\n    _arguments -C \\\n")
+	for _, spec := range zshSpecs(r.rootFlags) {
+		fmt.Fprintf(&b, "        %s \\\n", spec)
+	}
+	b.WriteString(`        '1: :->command' \
+        '*:: :->args'
+
+    case $state in
+        command)
+            _describe 'command' commands
+            ;;
+        args)
+            case $words[1] in
+`)
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "                %s)\n                    _arguments \\\n", cmd.Name)
+		for _, spec := range zshSpecs(commandFlags(cmd)) {
+			fmt.Fprintf(&b, "                        %s \\\n", spec)
+		}
+		if len(cmd.Args) > 0 {
+			fmt.Fprintf(&b, "                        '1:%s:(%s)'\n                    ;;\n", cmd.Name, strings.Join(cmd.Args, " "))
+		} else {
+			b.WriteString("                        '*:repository:_files -/'\n                    ;;\n")
+		}
+	}
+	b.WriteString(`            esac
+            ;;
+    esac
+}
+
+if [[ $zsh_eval_context[-1] == loadautofunc ]]; then
+    _sentei "$@"
+else
+    compdef _sentei sentei
+fi
+`)
+	return b.String()
+}
+
+// fishQuote single-quotes s for fish.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func fishFlagLine(b *strings.Builder, condition string, f Flag) {
+	fmt.Fprintf(b, "complete -c sentei -n %s -l %s", fishQuote(condition), f.Name)
+	if f.Short != "" {
+		fmt.Fprintf(b, " -s %s", f.Short)
+	}
+	switch f.Kind {
+	case FlagEnum:
+		fmt.Fprintf(b, " -x -a %s", fishQuote(strings.Join(f.Values, " ")))
+	case FlagBranch:
+		b.WriteString(" -x -a '(__sentei_values branches)'")
+	case FlagEcosystems:
+		b.WriteString(` -x -a '(__fish_complete_list , "__sentei_values ecosystems")'`)
+	case FlagPath:
+		b.WriteString(" -r -F")
+	case FlagProfile:
+		b.WriteString(" -x -a '(__sentei_values profiles)'")
+	case FlagString:
+		b.WriteString(" -x")
+	}
+	fmt.Fprintf(b, " -d %s\n", fishQuote(f.Usage))
+}
+
+func (r *Registry) fishCompletion() string {
+	cmds := r.completionCommands()
+	var b strings.Builder
+	b.WriteString("# fish completion for sentei\n")
+	b.WriteString("# Load with: sentei completion fish | source\n\n")
+	fmt.Fprintf(&b, "function __sentei_values\n    sentei completion %s $argv 2>/dev/null\nend\n\n", ValuesCommand)
+	b.WriteString("complete -c sentei -f\n")
+	for _, f := range r.rootFlags {
+		fishFlagLine(&b, "__fish_use_subcommand", f)
+	}
+	for _, cmd := range cmds {
+		label := "output"
+		if cmd.Type == Decision {
+			label = "interactive"
+		}
+		fmt.Fprintf(&b, "complete -c sentei -n __fish_use_subcommand -a %s -d %s\n", cmd.Name, label)
+	}
+	for _, cmd := range cmds {
+		b.WriteString("\n")
+		condition := "__fish_seen_subcommand_from " + cmd.Name
+		for _, f := range commandFlags(cmd) {
+			fishFlagLine(&b, condition, f)
+		}
+		args := "(__fish_complete_directories)"
+		if len(cmd.Args) > 0 {
+			args = strings.Join(cmd.Args, " ")
+		}
+		fmt.Fprintf(&b, "complete -c sentei -n %s -a %s\n", fishQuote(condition), fishQuote(args))
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newCompletionRegistry() *Registry {
+	r := newTestRegistry()
+	fs := flag.NewFlagSet("cleanup", flag.ContinueOnError)
+	fs.String("mode", "", "Cleanup mode: safe or aggressive")
+	fs.Bool("dry-run", false, "Show what would be done")
+	fs.String("format", "text", "Output format")
+	r.Lookup("cleanup").Flags = FlagsOf(fs, Flag{Name: "mode", Kind: FlagEnum, Values: []string{"safe", "aggressive"}})
+
+	fs = flag.NewFlagSet("create", flag.ContinueOnError)
+	fs.String("base", "", "Base branch to create from [required]")
+	fs.String("ecosystems", "", "Comma-separated ecosystems")
+	fs.String("profile", "", "Profile from the config")
+	r.Lookup("create").Flags = FlagsOf(fs,
+		Flag{Name: "base", Kind: FlagBranch},
+		Flag{Name: "ecosystems", Kind: FlagEcosystems},
+		Flag{Name: "profile", Kind: FlagProfile})
+	return r
+}
+
+func TestFlagsOf(t *testing.T) {
+	flags := newCompletionRegistry().Lookup("cleanup").Flags
+	byName := make(map[string]Flag)
+	for _, f := range flags {
+		byName[f.Name] = f
+	}
+	if len(flags) != 3 || flags[0].Name != "dry-run" {
+		t.Fatalf("want three flags in name order, got %+v", flags)
+	}
+	if byName["dry-run"].Kind != FlagBool {
+		t.Error("dry-run should be recognized as a boolean flag")
+	}
+	if mode := byName["mode"]; mode.Kind != FlagEnum || mode.Label() != "--mode safe|aggressive" || mode.Usage == "" {
+		t.Errorf("mode should take its kind from the hint and usage from the FlagSet, got %+v", mode)
+	}
+	if byName["format"].Label() != "--format text|json|ndjson" {
+		t.Errorf("format should default to the global values, got %q", byName["format"].Label())
+	}
+}
+
+func TestUsageString_ListsCommandFlags(t *testing.T) {
+	usage := newCompletionRegistry().UsageString()
+	for _, want := range []string{"--mode safe|aggressive", "--base BRANCH", "--ecosystems NAMES", "--profile NAME", "--yes, -y"} {
+		if !strings.Contains(usage, want) {
+			t.Errorf("usage should list %q:\n%s", want, usage)
+		}
+	}
+	if strings.Count(usage, "--format") != 1 {
+		t.Errorf("the shared --format flag belongs under global flags only:\n%s", usage)
+	}
+}
+
+func TestCompletionScript(t *testing.T) {
+	r := newCompletionRegistry()
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{"complete -F _sentei sentei", `cleanup:--mode) COMPREPLY=($(compgen -W "safe aggressive"`, "__values branches", "__values ecosystems", "__values profiles"}},
+		{"zsh", []string{"#compdef sentei", "'--mode[Cleanup mode: safe or aggressive]:mode:(safe aggressive)'", `\[required\]`, "_sentei_branches", ":profile:_sentei_profiles"}},
+		{"fish", []string{"complete -c sentei -n '__fish_seen_subcommand_from cleanup' -l mode -x -a 'safe aggressive'", "-l yes -s y", "__sentei_values ecosystems", "-l profile -x -a '(__sentei_values profiles)'"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			script, err := r.CompletionScript(tt.shell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(script, want) {
+					t.Errorf("script missing %q:\n%s", want, script)
+				}
+			}
+			if _, err := exec.LookPath(tt.shell); err == nil && tt.shell != "fish" {
+				path := filepath.Join(t.TempDir(), "sentei."+tt.shell)
+				if err := os.WriteFile(path, []byte(script), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				if out, err := exec.Command(tt.shell, "-n", path).CombinedOutput(); err != nil {
+					t.Errorf("%s rejects the script: %v\n%s", tt.shell, err, out)
+				}
+			}
+		})
+	}
+
+	if _, err := r.CompletionScript("tcsh"); !errors.Is(err, ErrUnknownShell) {
+		t.Errorf("want ErrUnknownShell, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// FlagKind says what a flag's value is, so usage can show a placeholder and
+// completion can offer candidates.
+type FlagKind int
+
+const (
+	FlagBool       FlagKind = iota // No value.
+	FlagString                     // Free-form value; nothing to complete.
+	FlagEnum                       // One of Values.
+	FlagBranch                     // A local branch name.
+	FlagEcosystems                 // Comma-separated ecosystem names.
+	FlagPath                       // A file or directory.
+	FlagProfile                    // A create profile from the config.
+)
+
+// Flag describes one command-line flag for usage and shell completion.
+type Flag struct {
+	Name   string
+	Short  string // single-letter alias, without the dash; optional
+	Usage  string
+	Kind   FlagKind
+	Values []string // candidates for FlagEnum
+}
+
+// formatValues are the --format values every command accepts. A command
+// whose --format takes other values lists its own.
+var formatValues = []string{"text", "json", "ndjson"}
+
+var formatFlag = Flag{Name: "format", Usage: "text (default), json or ndjson; decision commands also need --yes", Kind: FlagEnum, Values: formatValues}
+
+// GlobalFlags are the flags Dispatch understands for every command.
+var GlobalFlags = []Flag{
+	{Name: "non-interactive", Usage: "run without the TUI (destructive commands also need --force)", Kind: FlagBool},
+	{Name: "yes", Short: "y", Usage: "skip the confirmation prompt; command safeties stay active", Kind: FlagBool},
+	{Name: "force", Usage: "pass destructive gates / force-delete where the command supports it", Kind: FlagBool},
+	formatFlag,
+}
+
+// FlagsOf describes every flag declared on fs, in name order, so a command's
+// metadata comes from the same FlagSet that parses it. Boolean flags are
+// recognized and --format defaults to the global format values; other flags
+// are FlagString unless a hint with the same name supplies the kind and
+// values.
+func FlagsOf(fs *flag.FlagSet, hints ...Flag) []Flag {
+	byName := map[string]Flag{"format": formatFlag}
+	for _, h := range hints {
+		byName[h.Name] = h
+	}
+	var flags []Flag
+	fs.VisitAll(func(f *flag.Flag) {
+		d := Flag{Name: f.Name, Usage: f.Usage, Kind: FlagString}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			d.Kind = FlagBool
+		}
+		if h, ok := byName[f.Name]; ok {
+			d.Kind = h.Kind
+			d.Values = h.Values
+			d.Short = h.Short
+		}
+		flags = append(flags, d)
+	})
+	return flags
+}
+
+// Label renders the flag as usage shows it: "--name", "--name a|b" for an
+// enum, or "--name PLACEHOLDER" for other values.
+func (f Flag) Label() string {
+	label := "--" + f.Name
+	if f.Short != "" {
+		label += ", -" + f.Short
+	}
+	switch f.Kind {
+	case FlagBool:
+		return label
+	case FlagEnum:
+		return label + " " + strings.Join(f.Values, "|")
+	case FlagBranch:
+		return label + " BRANCH"
+	case FlagEcosystems:
+		return label + " NAMES"
+	case FlagPath:
+		return label + " PATH"
+	case FlagProfile:
+		return label + " NAME"
+	}
+	return label + " VALUE"
+}
+
+// writeFlags writes one aligned usage line per flag.
+func writeFlags(b *strings.Builder, indent string, flags []Flag) {
+	width := 0
+	for _, f := range flags {
+		width = max(width, len(f.Label()))
+	}
+	for _, f := range flags {
+		fmt.Fprintf(b, "%s%-*s  %s\n", indent, width, f.Label(), f.Usage)
+	}
+}
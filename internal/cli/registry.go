@@ -3,6 +3,7 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -22,15 +23,29 @@ type Command struct {
 	Type        CommandType
 	Destructive bool // When true, --non-interactive requires --force.
 
+	// Flags describes the command's own flags for usage and shell
+	// completion; see FlagsOf.
+	Flags []Flag
+	// Args lists the values completion offers for positional arguments.
+	// Empty means a repository path.
+	Args []string
+
 	// RunCLI executes the command in non-interactive mode.
 	// For output commands, this is the only execution path.
 	// For decision commands, this runs when --non-interactive is provided.
 	RunCLI func(args []string) error
+
+	// Confirm is set on decision commands with no TUI view. It describes
+	// what args are about to change, and the caller asks for a yes on the
+	// terminal before RunCLI instead of opening the TUI. needed is false
+	// when args only read, as a listing does.
+	Confirm func(args []string) (prompt string, needed bool, err error)
 }
 
 // Registry holds registered commands and dispatches based on os.Args.
 type Registry struct {
-	commands map[string]*Command
+	commands  map[string]*Command
+	rootFlags []Flag
 }
 
 // NewRegistry creates an empty command registry.
@@ -43,6 +58,17 @@ func (r *Registry) Register(cmd *Command) {
 	r.commands[cmd.Name] = cmd
 }
 
+// SetRootFlags records the flags sentei accepts without a command, for
+// shell completion.
+func (r *Registry) SetRootFlags(flags []Flag) {
+	r.rootFlags = flags
+}
+
+// RootFlags returns the flags recorded by SetRootFlags.
+func (r *Registry) RootFlags() []Flag {
+	return r.rootFlags
+}
+
 // DispatchResult tells the caller what action to take after dispatch.
 type DispatchResult struct {
 	// IsRoot is true when no command was provided (launch TUI menu).
@@ -63,11 +89,16 @@ type DispatchResult struct {
 	// confirmation and run the CLI path. Unlike --non-interactive it does
 	// not require --force; each command's own safeties stay in effect.
 	Yes bool
+
+	// Format is the --format value, if any. It stays in Args for the
+	// command's own flag parser; dispatch only reads it to gate the TUI.
+	Format string
 }
 
 var (
 	ErrUnknownCommand = errors.New("unknown command")
 	ErrMissingForce   = errors.New("destructive operation requires --force with --non-interactive")
+	ErrFormatNeedsCLI = errors.New("--format json/ndjson requires --yes or --non-interactive")
 )
 
 // IsUnknownCommand returns true if the error wraps ErrUnknownCommand.
@@ -103,12 +134,19 @@ func (r *Registry) Dispatch(args []string) (*DispatchResult, error) {
 		NonInteractive: nonInteractive,
 		Force:          force,
 		Yes:            yes,
+		Format:         peekFormat(remaining),
 	}
 
 	// Validate flag combinations.
 	if nonInteractive && cmd.Destructive && !force {
 		return nil, ErrMissingForce
 	}
+	// Machine output owns stdout, so it cannot share it with the TUI; a
+	// decision command must be told to take the CLI path explicitly. A
+	// terminal confirmation asks on stderr and leaves stdout alone.
+	if cmd.Type == Decision && cmd.Confirm == nil && isMachineFormat(result.Format) && !nonInteractive && !yes {
+		return nil, ErrFormatNeedsCLI
+	}
 
 	return result, nil
 }
@@ -127,7 +165,8 @@ func (r *Registry) CommandNames() []string {
 	return names
 }
 
-// UsageString returns a formatted help string listing all commands.
+// UsageString returns a formatted help string listing all commands and
+// their flags.
 func (r *Registry) UsageString() string {
 	var b strings.Builder
 	b.WriteString("Usage: sentei [command] [options]\n\n")
@@ -142,18 +181,25 @@ func (r *Registry) UsageString() string {
 			label = "interactive"
 		}
 		fmt.Fprintf(&b, "  %-14s (%s)\n", cmd.Name, label)
+		// --format is listed once, under global flags.
+		var flags []Flag
+		for _, f := range cmd.Flags {
+			if f.Name != "format" || !slices.Equal(f.Values, formatValues) {
+				flags = append(flags, f)
+			}
+		}
+		writeFlags(&b, "      ", flags)
 	}
 
 	b.WriteString("\nGlobal flags:\n")
-	b.WriteString("  --non-interactive  run without the TUI (destructive commands also need --force)\n")
-	b.WriteString("  --yes, -y          skip the confirmation prompt; command safeties stay active\n")
-	b.WriteString("  --force            pass destructive gates / force-delete where the command supports it\n")
+	writeFlags(&b, "  ", GlobalFlags)
 	b.WriteString("\nRun 'sentei <command> --help' for command-specific options.\n")
 	return b.String()
 }
 
 // BuildFlagString constructs a CLI command string from a base command and flags.
-// Boolean flags (value == "true") are rendered as --flag (no value).
+// Boolean flags (value == "true") are rendered as --flag (no value), and
+// "false" as --flag=false, which the flag package requires for booleans.
 // Flags are sorted by key for deterministic output.
 func BuildFlagString(base string, flags map[string]string) string {
 	flagKeys := make([]string, 0, len(flags))
@@ -165,9 +211,12 @@ func BuildFlagString(base string, flags map[string]string) string {
 	result := base
 	for _, k := range flagKeys {
 		v := flags[k]
-		if v == "true" {
+		switch v {
+		case "true":
 			result += " --" + k
-		} else {
+		case "false":
+			result += " --" + k + "=false"
+		default:
 			result += " --" + k + " " + v
 		}
 	}
@@ -192,3 +241,28 @@ func extractGlobalFlags(args []string) (nonInteractive bool, force bool, yes boo
 	}
 	return
 }
+
+// peekFormat returns the value of --format (or --format=) without consuming
+// it. Scanning stops at "--", matching the flag package.
+func peekFormat(args []string) string {
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return ""
+		case arg == "--format" || arg == "-format":
+			if i+1 < len(args) {
+				return args[i+1]
+			}
+			return ""
+		case strings.HasPrefix(arg, "--format="):
+			return strings.TrimPrefix(arg, "--format=")
+		case strings.HasPrefix(arg, "-format="):
+			return strings.TrimPrefix(arg, "-format=")
+		}
+	}
+	return ""
+}
+
+func isMachineFormat(format string) bool {
+	return format == "json" || format == "ndjson"
+}
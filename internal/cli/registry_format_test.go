@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDispatch_MachineFormatNeedsCLIPathForDecisionCommands(t *testing.T) {
+	r := yesTestRegistry()
+	r.Register(&Command{Name: "ecosystems", Type: Output})
+
+	if _, err := r.Dispatch([]string{"remove", "--all", "--format", "json"}); !errors.Is(err, ErrFormatNeedsCLI) {
+		t.Errorf("decision command with --format json and no --yes: err = %v, want ErrFormatNeedsCLI", err)
+	}
+
+	result, err := r.Dispatch([]string{"remove", "--yes", "--format=ndjson", "--all"})
+	if err != nil {
+		t.Fatalf("--yes --format=ndjson must dispatch: %v", err)
+	}
+	if result.Format != "ndjson" {
+		t.Errorf("Format = %q, want ndjson", result.Format)
+	}
+	if len(result.Args) != 2 || result.Args[0] != "--format=ndjson" {
+		t.Errorf("--format must stay in Args for the command's parser, got %v", result.Args)
+	}
+
+	if _, err := r.Dispatch([]string{"remove", "--all", "--format", "text"}); err != nil {
+		t.Errorf("--format text keeps the TUI path: %v", err)
+	}
+	if _, err := r.Dispatch([]string{"ecosystems", "--format", "json"}); err != nil {
+		t.Errorf("output commands need no --yes: %v", err)
+	}
+
+	r.Register(&Command{Name: "undo", Type: Decision, Confirm: func([]string) (string, bool, error) { return "", false, nil }})
+	if _, err := r.Dispatch([]string{"undo", "--format", "json"}); err != nil {
+		t.Errorf("a terminal confirmation leaves stdout to --format: %v", err)
+	}
+}
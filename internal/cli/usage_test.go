@@ -63,6 +63,7 @@ func TestBuildFlagString(t *testing.T) {
 		{"no flags", "sentei remove", nil, "sentei remove"},
 		{"bool flag rendered without value", "sentei remove", map[string]string{"dry-run": "true"}, "sentei remove --dry-run"},
 		{"value flag", "sentei cleanup", map[string]string{"mode": "safe"}, "sentei cleanup --mode safe"},
+		{"false bool flag rendered with =false", "sentei create", map[string]string{"copy-env": "false"}, "sentei create --copy-env=false"},
 		{
 			"flags sorted by key",
 			"sentei remove",
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// unknownFieldRe matches yaml.v3's unknown-field error, which names the Go
+// type rather than the key's place in the file.
+var unknownFieldRe = regexp.MustCompile(`^(line \d+: )field (\S+) not found in type \S+$`)
+
+// Problem is one finding of Check.
+type Problem struct {
+	Path    string // the file it was found in; empty for the merged config
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Path == "" {
+		return p.Message
+	}
+	return p.Path + ": " + p.Message
+}
+
+// Check validates the global and repo config files strictly: on top of what
+// LoadConfig rejects, it reports unknown keys, malformed detect globs,
+// enabled or profile integrations that are neither in the
+// WithKnownIntegrations names nor declared, and declared integrations that
+// cannot be detected. It returns every problem found; the error is reserved
+// for files it could not read.
+func Check(repoPath string, opts ...LoadOption) ([]Problem, error) {
+	var lo loadOptions
+	for _, opt := range opts {
+		opt(&lo)
+	}
+
+	defaults, err := loadEmbeddedDefaults()
+	if err != nil {
+		return nil, err
+	}
+	layers := []Layer{{Source: "embedded", Found: true, config: defaults}}
+
+	var problems []Problem
+	for _, l := range []Layer{
+		{Source: "global", Path: globalConfigPath()},
+		{Source: "per-repo", Path: filepath.Join(resolveRepoRoot(repoPath, lo.runner), RepoConfigFile)},
+	} {
+		cfg, found, err := decodeStrict(l.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range found {
+			problems = append(problems, Problem{Path: l.Path, Message: msg})
+		}
+		if cfg != nil {
+			l.Found, l.config = true, cfg
+			layers = append(layers, l)
+		}
+	}
+
+	merged := mergeLayers(layers)
+	// An integration declared in one layer may be enabled in another.
+	known := slices.Clone(lo.knownIntegrationNames)
+	for _, integ := range merged.Integrations {
+		known = append(known, integ.Name)
+	}
+	for _, l := range layers[1:] {
+		for _, msg := range layerProblems(l.config, known, len(lo.knownIntegrationNames) > 0) {
+			problems = append(problems, Problem{Path: l.Path, Message: msg})
+		}
+	}
+
+	for _, err := range requiredFieldErrors(merged) {
+		problems = append(problems, Problem{Message: err.Error()})
+	}
+	for _, msg := range unknownProfileIntegrations(merged, lo.knownIntegrationNames) {
+		problems = append(problems, Problem{Message: msg})
+	}
+	// Without the built-in names an entry may be changing a built-in
+	// integration, which already has a detection.
+	if len(lo.knownIntegrationNames) > 0 {
+		for _, integ := range merged.Integrations {
+			if !slices.Contains(lo.knownIntegrationNames, integ.Name) && integ.Detect.Command == "" && integ.Detect.Binary == "" {
+				problems = append(problems, Problem{Message: fmt.Sprintf("integration %q: detect.command or detect.binary is required", integ.Name)})
+			}
+		}
+	}
+	return problems, nil
+}
+
+// decodeStrict decodes one config file, reporting unknown keys. It returns
+// a nil config when the file is absent or not valid YAML.
+func decodeStrict(path string) (*Config, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("reading config file %s: %w", path, err)
+	}
+
+	var cfg Config
+	var problems []string
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	// An empty file decodes to io.EOF; a TypeError still decodes everything
+	// it can. Anything else leaves nothing to check.
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, []string{err.Error()}, nil
+		}
+		for _, msg := range typeErr.Errors {
+			problems = append(problems, unknownFieldRe.ReplaceAllString(msg, `${1}unknown key "${2}"`))
+		}
+	}
+	return &cfg, problems, nil
+}
+
+// layerProblems lists the problems in one file's settings. Enabled
+// integrations are checked against known only when checkEnabled is set.
+func layerProblems(cfg *Config, known []string, checkEnabled bool) []string {
+	var problems []string
+	for _, err := range valueErrors(cfg) {
+		problems = append(problems, err.Error())
+	}
+	if checkEnabled {
+		for _, name := range unknownIntegrations(cfg, known) {
+			problems = append(problems, fmt.Sprintf("integrations_enabled: unknown integration %q", name))
+		}
+	}
+	return problems
+}
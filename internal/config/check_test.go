@@ -0,0 +1,154 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+var testIntegrations = []string{"code-review-graph", "cocoindex-code"}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		repo  string
+		wants []string // substrings, one per expected problem
+	}{
+		{
+			name: "valid",
+			repo: `
+protected_branches: ["release/*"]
+integrations_enabled: [code-review-graph]
+ecosystems:
+  - name: go
+    enabled: false
+`,
+		},
+		{
+			name:  "unknown keys",
+			repo:  "protected_branch: [main]\necosystems:\n  - name: go\n    instal: {}\n",
+			wants: []string{`line 1: unknown key "protected_branch"`, `line 4: unknown key "instal"`},
+		},
+		{
+			name:  "bad globs",
+			repo:  "protected_branches: [\"release/[\"]\necosystems:\n  - name: go\n    detect:\n      files: [\"go.[mod\"]\n",
+			wants: []string{`invalid pattern "release/["`, `detect.files: invalid pattern "go.[mod"`},
+		},
+		{
+			name:  "unknown integration",
+			repo:  "integrations_enabled: [future-tool]\n",
+			wants: []string{`unknown integration "future-tool"`},
+		},
+		{
+			name:  "new ecosystem missing detect files",
+			repo:  "ecosystems:\n  - name: custom\n    install:\n      command: custom install\n",
+			wants: []string{`ecosystem "custom": detect.files must not be empty`},
+		},
+		{
+			name: "integration declared and enabled",
+			repo: `
+integrations_enabled: [ctags]
+integrations:
+  - name: ctags
+    detect:
+      binary: ctags
+  - name: code-review-graph
+    install:
+      command: pip install code-review-graph
+`,
+		},
+		{
+			name:  "declared integration without detection",
+			repo:  "integrations:\n  - name: ctags\n    setup:\n      command: ctags -R\n",
+			wants: []string{`integration "ctags": detect.command or detect.binary is required`},
+		},
+		{
+			name: "profiles",
+			repo: `
+integrations:
+  - name: ctags
+    detect:
+      binary: ctags
+profiles:
+  review:
+    base: main
+    ecosystems: [pnpm]
+    integrations: [ctags, code-review-graph]
+  broken:
+    ecosystems: [cobol]
+    integrations: [future-tool]
+`,
+			wants: []string{`profile "broken": unknown ecosystem "cobol"`, `profile "broken": unknown integration "future-tool"`},
+		},
+		{
+			name:  "malformed YAML",
+			repo:  "ecosystems: [\nunclosed bracket",
+			wants: []string{"yaml:"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repoDir := writeLayers(t, "", tc.repo)
+			problems, err := Check(repoDir, WithKnownIntegrations(testIntegrations))
+			if err != nil {
+				t.Fatalf("Check() error: %v", err)
+			}
+			if len(problems) != len(tc.wants) {
+				t.Fatalf("got %d problems, want %d: %v", len(problems), len(tc.wants), problems)
+			}
+			for i, want := range tc.wants {
+				if !strings.Contains(problems[i].String(), want) {
+					t.Errorf("problem %d = %q, want it to contain %q", i, problems[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestScaffold_PassesCheck(t *testing.T) {
+	defaults, err := loadEmbeddedDefaults()
+	if err != nil {
+		t.Fatalf("loadEmbeddedDefaults() error: %v", err)
+	}
+	for _, detected := range [][]EcosystemConfig{nil, defaults.Ecosystems[:3]} {
+		scaffold := string(Scaffold(detected))
+		repoDir := writeLayers(t, "", scaffold)
+		problems, err := Check(repoDir, WithKnownIntegrations(testIntegrations))
+		if err != nil {
+			t.Fatalf("Check() error: %v", err)
+		}
+		if len(problems) > 0 {
+			t.Errorf("scaffold has problems %v:\n%s", problems, scaffold)
+		}
+		for _, e := range detected {
+			if !strings.Contains(scaffold, "- name: "+e.Name+"\n") {
+				t.Errorf("scaffold should list %s:\n%s", e.Name, scaffold)
+			}
+		}
+
+		// Everything but the ecosystem names is commented out, so the
+		// scaffold must not set any field.
+		exp, err := Explain(repoDir)
+		if err != nil {
+			t.Fatalf("Explain() error: %v", err)
+		}
+		for field, source := range exp.Sources {
+			if source == "per-repo" {
+				t.Errorf("scaffold sets %s", field)
+			}
+		}
+	}
+}
+
+func TestCheck_IntegrationDeclaredInAnotherLayer(t *testing.T) {
+	repoDir := writeLayers(t,
+		"integrations:\n  - name: ctags\n    detect:\n      binary: ctags\n",
+		"integrations_enabled: [ctags]\n")
+	problems, err := Check(repoDir, WithKnownIntegrations(testIntegrations))
+	if err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+	if len(problems) > 0 {
+		t.Errorf("an integration declared globally may be enabled per repo, got %v", problems)
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/abiswas97/sentei/internal/git"
 	"gopkg.in/yaml.v3"
 )
 
@@ -95,6 +98,9 @@ func mergeEcosystems(base, overlay []EcosystemConfig, overlaySource string) []Ec
 		if len(over.EnvFiles) > 0 {
 			e.EnvFiles = over.EnvFiles
 		}
+		if over.EnvMode != "" {
+			e.EnvMode = over.EnvMode
+		}
 		if len(over.PostInstall) > 0 {
 			e.PostInstall = over.PostInstall
 		}
@@ -105,6 +111,86 @@ func mergeEcosystems(base, overlay []EcosystemConfig, overlaySource string) []Ec
 	return result
 }
 
+// mergeIntegrations performs a keyed merge of overlay into base like
+// mergeEcosystems: fields set in the overlay replace the base entry's, lists
+// wholesale, and new names are appended.
+func mergeIntegrations(base, overlay []IntegrationConfig, overlaySource string) []IntegrationConfig {
+	if len(overlay) == 0 {
+		return base
+	}
+
+	result := make([]IntegrationConfig, len(base))
+	copy(result, base)
+
+	index := make(map[string]int, len(result))
+	for i, e := range result {
+		index[e.Name] = i
+	}
+
+	for _, over := range overlay {
+		over.Source = overlaySource
+		i, exists := index[over.Name]
+		if !exists {
+			result = append(result, over)
+			index[over.Name] = len(result) - 1
+			continue
+		}
+		result[i] = result[i].Overlay(over)
+	}
+
+	return result
+}
+
+// Overlay returns e with every field over sets replaced, lists wholesale,
+// and Source taken from over.
+func (e IntegrationConfig) Overlay(over IntegrationConfig) IntegrationConfig {
+	str := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	str(&e.ShortDescription, over.ShortDescription)
+	str(&e.Description, over.Description)
+	str(&e.URL, over.URL)
+	if len(over.Dependencies) > 0 {
+		e.Dependencies = over.Dependencies
+	}
+	str(&e.Detect.Command, over.Detect.Command)
+	str(&e.Detect.Binary, over.Detect.Binary)
+	str(&e.Install.Command, over.Install.Command)
+	str(&e.Install.FirstRunNote, over.Install.FirstRunNote)
+	str(&e.Setup.Command, over.Setup.Command)
+	str(&e.Setup.WorkingDir, over.Setup.WorkingDir)
+	str(&e.Refresh.Command, over.Refresh.Command)
+	str(&e.Teardown.Command, over.Teardown.Command)
+	if len(over.Teardown.Dirs) > 0 {
+		e.Teardown.Dirs = over.Teardown.Dirs
+	}
+	if len(over.Gitignore) > 0 {
+		e.Gitignore = over.Gitignore
+	}
+	str(&e.IndexCopyDir, over.IndexCopyDir)
+	e.Source = over.Source
+	return e
+}
+
+// mergeProfiles adds overlay's profiles to base's, replacing any of the
+// same name.
+func mergeProfiles(base, overlay map[string]Profile, overlaySource string) map[string]Profile {
+	if len(overlay) == 0 {
+		return base
+	}
+	result := make(map[string]Profile, len(base)+len(overlay))
+	for name, p := range base {
+		result[name] = p
+	}
+	for name, p := range overlay {
+		p.Source = overlaySource
+		result[name] = p
+	}
+	return result
+}
+
 // mergeConfigs merges overlay on top of base, returning a new Config. Scalar
 // lists (ProtectedBranches, IntegrationsEnabled) are replaced entirely when
 // the overlay provides them. Note: an empty list (e.g. `protected_branches: []`)
@@ -113,9 +199,13 @@ func mergeEcosystems(base, overlay []EcosystemConfig, overlaySource string) []Ec
 // indistinguishable from an absent field.
 func mergeConfigs(base, overlay *Config, overlaySource string) *Config {
 	result := &Config{
-		Ecosystems:          mergeEcosystems(base.Ecosystems, overlay.Ecosystems, overlaySource),
-		ProtectedBranches:   base.ProtectedBranches,
-		IntegrationsEnabled: base.IntegrationsEnabled,
+		Ecosystems:           mergeEcosystems(base.Ecosystems, overlay.Ecosystems, overlaySource),
+		Integrations:         mergeIntegrations(base.Integrations, overlay.Integrations, overlaySource),
+		ProtectedBranches:    base.ProtectedBranches,
+		IntegrationsEnabled:  base.IntegrationsEnabled,
+		ArchiveBeforeRemove:  base.ArchiveBeforeRemove,
+		ArchiveRetentionDays: base.ArchiveRetentionDays,
+		Profiles:             mergeProfiles(base.Profiles, overlay.Profiles, overlaySource),
 	}
 	if len(overlay.ProtectedBranches) > 0 {
 		result.ProtectedBranches = overlay.ProtectedBranches
@@ -123,33 +213,142 @@ func mergeConfigs(base, overlay *Config, overlaySource string) *Config {
 	if len(overlay.IntegrationsEnabled) > 0 {
 		result.IntegrationsEnabled = overlay.IntegrationsEnabled
 	}
+	if overlay.ArchiveBeforeRemove != nil {
+		result.ArchiveBeforeRemove = overlay.ArchiveBeforeRemove
+	}
+	if overlay.ArchiveRetentionDays != 0 {
+		result.ArchiveRetentionDays = overlay.ArchiveRetentionDays
+	}
 	return result
 }
 
 // validate checks the config for structural errors and warns about unknown
 // integration names. knownIntegrationNames is the set of recognised names.
 func validate(cfg *Config, knownIntegrationNames []string) error {
+	if errs := append(requiredFieldErrors(cfg), valueErrors(cfg)...); len(errs) > 0 {
+		return errs[0]
+	}
+	for _, name := range unknownIntegrations(cfg, knownIntegrationNames) {
+		fmt.Fprintf(os.Stderr, "warning: unknown integration %q in integrations_enabled\n", name)
+	}
+	for _, problem := range unknownProfileIntegrations(cfg, knownIntegrationNames) {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", problem)
+	}
+	return nil
+}
+
+// requiredFieldErrors reports enabled ecosystems missing a name or detect
+// files, and profiles naming an ecosystem no layer declares. It only makes
+// sense on the merged config: an overlay entry may set just the fields it
+// changes, and a profile may name an ecosystem declared in another layer.
+func requiredFieldErrors(cfg *Config) []error {
+	var errs []error
+	for _, name := range cfg.ProfileNames() {
+		for _, eco := range cfg.Profiles[name].Ecosystems {
+			if !slices.ContainsFunc(cfg.Ecosystems, func(e EcosystemConfig) bool { return e.Name == eco }) {
+				errs = append(errs, fmt.Errorf("profile %q: unknown ecosystem %q", name, eco))
+			}
+		}
+	}
 	for i, e := range cfg.Ecosystems {
 		if !e.IsEnabled() {
 			continue
 		}
 		if e.Name == "" {
-			return fmt.Errorf("ecosystems[%d]: name is required", i)
+			errs = append(errs, fmt.Errorf("ecosystems[%d]: name is required", i))
+		} else if len(e.Detect.Files) == 0 {
+			errs = append(errs, fmt.Errorf("ecosystem %q: detect.files must not be empty", e.Name))
 		}
-		if len(e.Detect.Files) == 0 {
-			return fmt.Errorf("ecosystem %q: detect.files must not be empty", e.Name)
+	}
+	return errs
+}
+
+// validateDetectPattern reports a malformed glob in a detect.files entry.
+// Detection expands these with filepath.Glob, which skips a malformed pattern
+// silently, so the ecosystem would never be detected.
+func validateDetectPattern(pattern string) error {
+	_, err := filepath.Match(pattern, "")
+	return err
+}
+
+// valueErrors reports values that are wrong wherever they are set.
+func valueErrors(cfg *Config) []error {
+	var errs []error
+	for _, pattern := range cfg.ProtectedBranches {
+		if err := git.ValidateProtectedPattern(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("protected_branches: invalid pattern %q: %w", pattern, err))
 		}
 	}
-	known := make(map[string]struct{}, len(knownIntegrationNames))
-	for _, n := range knownIntegrationNames {
-		known[n] = struct{}{}
+	for _, e := range cfg.Ecosystems {
+		for _, pattern := range e.Detect.Files {
+			if err := validateDetectPattern(pattern); err != nil {
+				errs = append(errs, fmt.Errorf("ecosystem %q: detect.files: invalid pattern %q: %w", e.Name, pattern, err))
+			}
+		}
+		switch e.EnvMode {
+		case "", EnvModeCopy, EnvModeSymlink, EnvModeTemplate:
+		default:
+			errs = append(errs, fmt.Errorf("ecosystem %q: env_mode must be copy, symlink or template, got %q", e.Name, e.EnvMode))
+		}
 	}
+	if cfg.ArchiveRetentionDays < 0 {
+		errs = append(errs, fmt.Errorf("archive_retention_days must not be negative, got %d", cfg.ArchiveRetentionDays))
+	}
+	for i, integ := range cfg.Integrations {
+		if integ.Name == "" {
+			errs = append(errs, fmt.Errorf("integrations[%d]: name is required", i))
+			continue
+		}
+		switch integ.Setup.WorkingDir {
+		case "", "repo", "worktree":
+		default:
+			errs = append(errs, fmt.Errorf("integration %q: setup.working_dir must be repo or worktree, got %q", integ.Name, integ.Setup.WorkingDir))
+		}
+		for _, dep := range integ.Dependencies {
+			if dep.Name == "" || dep.Detect == "" {
+				errs = append(errs, fmt.Errorf("integration %q: every dependency needs a name and a detect command", integ.Name))
+				break
+			}
+		}
+		// These are removed or copied inside each worktree, so they must
+		// stay inside it.
+		for _, dir := range append([]string{integ.IndexCopyDir}, integ.Teardown.Dirs...) {
+			if dir != "" && !filepath.IsLocal(dir) {
+				errs = append(errs, fmt.Errorf("integration %q: %q must be a relative path inside the worktree", integ.Name, dir))
+			}
+		}
+	}
+	return errs
+}
+
+// unknownIntegrations returns the integrations_enabled names that are
+// neither in known nor declared in cfg.
+func unknownIntegrations(cfg *Config, known []string) []string {
+	var unknown []string
 	for _, name := range cfg.IntegrationsEnabled {
-		if _, ok := known[name]; !ok {
-			fmt.Fprintf(os.Stderr, "warning: unknown integration %q in integrations_enabled\n", name)
+		if !slices.Contains(known, name) && !slices.ContainsFunc(cfg.Integrations, func(i IntegrationConfig) bool { return i.Name == name }) {
+			unknown = append(unknown, name)
 		}
 	}
-	return nil
+	return unknown
+}
+
+// unknownProfileIntegrations describes each profile integration that is
+// neither in known nor declared in cfg. Without known names it reports
+// nothing.
+func unknownProfileIntegrations(cfg *Config, known []string) []string {
+	if len(known) == 0 {
+		return nil
+	}
+	var problems []string
+	for _, name := range cfg.ProfileNames() {
+		for _, integ := range cfg.Profiles[name].Integrations {
+			if !slices.Contains(known, integ) && !slices.ContainsFunc(cfg.Integrations, func(i IntegrationConfig) bool { return i.Name == integ }) {
+				problems = append(problems, fmt.Sprintf("profile %q: unknown integration %q", name, integ))
+			}
+		}
+	}
+	return problems
 }
 
 // globalConfigPath returns the path to the global sentei config file, honouring
@@ -207,43 +406,82 @@ func WithKnownIntegrations(names []string) LoadOption {
 	return func(o *loadOptions) { o.knownIntegrationNames = names }
 }
 
-// LoadConfig is the public API for loading sentei configuration. It:
-//  1. Loads the embedded defaults (Source="embedded").
-//  2. Merges the global config (~/.config/sentei/config.yaml).
-//  3. Resolves the repo root and merges .sentei.yaml from it.
-//  4. Validates the result.
-func LoadConfig(repoPath string, opts ...LoadOption) (*Config, error) {
-	var lo loadOptions
-	for _, opt := range opts {
-		opt(&lo)
-	}
+// Layer is one of the config sources LoadConfig merges.
+type Layer struct {
+	Source string // "embedded", "global" or "per-repo"
+	Path   string // empty for the embedded defaults
+	Found  bool   // false when the file does not exist
 
-	cfg, err := loadEmbeddedDefaults()
+	config *Config
+}
+
+// loadLayers reads the embedded defaults, the global config and the repo's
+// .sentei.yaml, in merge order.
+func loadLayers(repoPath string, lo loadOptions) ([]Layer, error) {
+	defaults, err := loadEmbeddedDefaults()
 	if err != nil {
 		return nil, err
 	}
-	for i := range cfg.Ecosystems {
-		cfg.Ecosystems[i].Source = "embedded"
-	}
+	layers := []Layer{{Source: "embedded", Found: true, config: defaults}}
 
 	globalPath := globalConfigPath()
 	globalCfg, err := loadFile(globalPath)
 	if err != nil {
 		return nil, fmt.Errorf("loading global config: %w", err)
 	}
-	if globalCfg != nil {
-		cfg = mergeConfigs(cfg, globalCfg, "global")
-	}
+	layers = append(layers, Layer{Source: "global", Path: globalPath, Found: globalCfg != nil, config: globalCfg})
 
-	repoRoot := resolveRepoRoot(repoPath, lo.runner)
-	repoCfg, err := loadFile(filepath.Join(repoRoot, ".sentei.yaml"))
+	repoConfigPath := filepath.Join(resolveRepoRoot(repoPath, lo.runner), RepoConfigFile)
+	repoCfg, err := loadFile(repoConfigPath)
 	if err != nil {
 		return nil, fmt.Errorf("loading repo config: %w", err)
 	}
-	if repoCfg != nil {
-		cfg = mergeConfigs(cfg, repoCfg, "per-repo")
+	layers = append(layers, Layer{Source: "per-repo", Path: repoConfigPath, Found: repoCfg != nil, config: repoCfg})
+	return layers, nil
+}
+
+// mergeLayers merges the layers that were found into one config.
+func mergeLayers(layers []Layer) *Config {
+	cfg := layers[0].config
+	for i := range cfg.Ecosystems {
+		cfg.Ecosystems[i].Source = layers[0].Source
 	}
+	for _, l := range layers[1:] {
+		if l.config != nil {
+			cfg = mergeConfigs(cfg, l.config, l.Source)
+		}
+	}
+	return cfg
+}
+
+// RepoConfigFile is the per-repo config's name, at the repository root.
+const RepoConfigFile = ".sentei.yaml"
 
+// RepoConfigPath returns where LoadConfig looks for the repo's .sentei.yaml.
+func RepoConfigPath(repoPath string, opts ...LoadOption) string {
+	var lo loadOptions
+	for _, opt := range opts {
+		opt(&lo)
+	}
+	return filepath.Join(resolveRepoRoot(repoPath, lo.runner), RepoConfigFile)
+}
+
+// LoadConfig is the public API for loading sentei configuration. It:
+//  1. Loads the embedded defaults (Source="embedded").
+//  2. Merges the global config (~/.config/sentei/config.yaml).
+//  3. Resolves the repo root and merges .sentei.yaml from it.
+//  4. Validates the result.
+func LoadConfig(repoPath string, opts ...LoadOption) (*Config, error) {
+	var lo loadOptions
+	for _, opt := range opts {
+		opt(&lo)
+	}
+
+	layers, err := loadLayers(repoPath, lo)
+	if err != nil {
+		return nil, err
+	}
+	cfg := mergeLayers(layers)
 	if err := validate(cfg, lo.knownIntegrationNames); err != nil {
 		return nil, err
 	}
@@ -255,17 +493,104 @@ type Config struct {
 	Ecosystems          []EcosystemConfig `yaml:"ecosystems"`
 	ProtectedBranches   []string          `yaml:"protected_branches"`
 	IntegrationsEnabled []string          `yaml:"integrations_enabled"`
+	// Integrations declares per-worktree tools beyond the built-in ones, or
+	// changes a built-in one by name.
+	Integrations []IntegrationConfig `yaml:"integrations,omitempty"`
+	// ArchiveBeforeRemove makes remove archive at-risk worktrees (uncommitted,
+	// untracked or unpushed work) so `sentei restore` can bring them back.
+	ArchiveBeforeRemove *bool `yaml:"archive_before_remove,omitempty"`
+	// ArchiveRetentionDays is how long cleanup keeps archives; 0 means the
+	// default of DefaultArchiveRetentionDays.
+	ArchiveRetentionDays int `yaml:"archive_retention_days,omitempty"`
+	// Profiles are named sets of create options, chosen with
+	// `create --profile`. A profile replaces one of the same name from an
+	// earlier layer as a whole.
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+}
+
+// Profile is a named set of create options. Unset fields leave the
+// corresponding option to the command line or the TUI.
+type Profile struct {
+	Base       string   `yaml:"base,omitempty"`
+	Ecosystems []string `yaml:"ecosystems,omitempty"`
+	// Integrations to set up in the new worktree. An empty list sets up
+	// none; when unset, create keeps its usual choice.
+	Integrations []string `yaml:"integrations,omitempty"`
+	MergeBase    *bool    `yaml:"merge_base,omitempty"`
+	CopyEnv      *bool    `yaml:"copy_env,omitempty"`
+	Source       string   `yaml:"-"` // "global" or "per-repo"
+}
+
+// ProfileNames returns the configured profile names, sorted.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Profile returns the named profile, or an error listing the configured
+// ones.
+func (c *Config) Profile(name string) (Profile, error) {
+	if p, ok := c.Profiles[name]; ok {
+		return p, nil
+	}
+	if len(c.Profiles) == 0 {
+		return Profile{}, fmt.Errorf("unknown profile %q: no profiles are configured", name)
+	}
+	return Profile{}, fmt.Errorf("unknown profile %q (profiles: %s)", name, strings.Join(c.ProfileNames(), ", "))
+}
+
+// DefaultArchiveRetentionDays is how long archives are kept when
+// archive_retention_days is not set.
+const DefaultArchiveRetentionDays = 30
+
+// ArchivesEnabled reports whether remove archives at-risk worktrees. An
+// absent ArchiveBeforeRemove field is treated as false.
+func (c *Config) ArchivesEnabled() bool {
+	return c.ArchiveBeforeRemove != nil && *c.ArchiveBeforeRemove
+}
+
+// ArchiveRetention returns how long cleanup keeps archives.
+func (c *Config) ArchiveRetention() time.Duration {
+	days := c.ArchiveRetentionDays
+	if days == 0 {
+		days = DefaultArchiveRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
 }
 
 // EcosystemConfig describes how to detect and install a language/tool ecosystem.
 type EcosystemConfig struct {
-	Name        string        `yaml:"name"`
-	Enabled     *bool         `yaml:"enabled,omitempty"`
-	Detect      DetectConfig  `yaml:"detect"`
-	Install     InstallConfig `yaml:"install"`
-	EnvFiles    []string      `yaml:"env_files"`
-	PostInstall []string      `yaml:"post_install"`
-	Source      string        `yaml:"-"` // "embedded", "global", or "per-repo"
+	Name     string        `yaml:"name"`
+	Enabled  *bool         `yaml:"enabled,omitempty"`
+	Detect   DetectConfig  `yaml:"detect"`
+	Install  InstallConfig `yaml:"install"`
+	EnvFiles []string      `yaml:"env_files"`
+	// EnvMode is how env_files reach a new worktree: EnvModeCopy (the
+	// default), EnvModeSymlink or EnvModeTemplate.
+	EnvMode     string   `yaml:"env_mode,omitempty"`
+	PostInstall []string `yaml:"post_install"`
+	Source      string   `yaml:"-"` // "embedded", "global", or "per-repo"
+}
+
+// Env modes: copy the source worktree's file, link to it, or render
+// "<file>.template" with the new worktree's branch, path and port.
+const (
+	EnvModeCopy     = "copy"
+	EnvModeSymlink  = "symlink"
+	EnvModeTemplate = "template"
+)
+
+// EnvFileMode returns the ecosystem's env mode. An absent EnvMode field is
+// treated as EnvModeCopy.
+func (e *EcosystemConfig) EnvFileMode() string {
+	if e.EnvMode == "" {
+		return EnvModeCopy
+	}
+	return e.EnvMode
 }
 
 // IsEnabled reports whether the ecosystem is active. An absent Enabled field
@@ -292,3 +617,64 @@ type InstallConfig struct {
 func (i *InstallConfig) IsParallel() bool {
 	return i.Parallel != nil && *i.Parallel
 }
+
+// IntegrationConfig declares a per-worktree tool; the fields mirror
+// integration.Integration. Entries are merged by name across layers and
+// then onto the built-in integrations, only the fields an entry sets
+// replacing earlier ones.
+type IntegrationConfig struct {
+	Name             string                    `yaml:"name"`
+	ShortDescription string                    `yaml:"short_description,omitempty"`
+	Description      string                    `yaml:"description,omitempty"`
+	URL              string                    `yaml:"url,omitempty"`
+	Dependencies     []DependencyConfig        `yaml:"dependencies,omitempty"`
+	Detect           IntegrationDetectConfig   `yaml:"detect,omitempty"`
+	Install          IntegrationInstallConfig  `yaml:"install,omitempty"`
+	Setup            IntegrationSetupConfig    `yaml:"setup,omitempty"`
+	Refresh          IntegrationRefreshConfig  `yaml:"refresh,omitempty"`
+	Teardown         IntegrationTeardownConfig `yaml:"teardown,omitempty"`
+	Gitignore        []string                  `yaml:"gitignore,omitempty"`
+	// IndexCopyDir is copied from the main worktree to seed a new
+	// worktree's index; see integration.Integration.
+	IndexCopyDir string `yaml:"index_copy_dir,omitempty"`
+	Source       string `yaml:"-"` // "global" or "per-repo"
+}
+
+// DependencyConfig is a tool an integration needs before it can install.
+type DependencyConfig struct {
+	Name    string `yaml:"name"`
+	Detect  string `yaml:"detect"`
+	Install string `yaml:"install,omitempty"`
+}
+
+// IntegrationDetectConfig says how to tell the tool is installed: a shell
+// command that succeeds, or a binary on PATH.
+type IntegrationDetectConfig struct {
+	Command string `yaml:"command,omitempty"`
+	Binary  string `yaml:"binary,omitempty"`
+}
+
+// IntegrationInstallConfig says how to install the tool.
+type IntegrationInstallConfig struct {
+	Command      string `yaml:"command,omitempty"`
+	FirstRunNote string `yaml:"first_run_note,omitempty"`
+}
+
+// IntegrationSetupConfig initialises the tool for a worktree. {path} in the
+// command is replaced with the worktree path.
+type IntegrationSetupConfig struct {
+	Command    string `yaml:"command,omitempty"`
+	WorkingDir string `yaml:"working_dir,omitempty"` // "repo" or "worktree" (default)
+}
+
+// IntegrationRefreshConfig brings the tool's index up to date in a worktree
+// that already has it. It runs where setup runs, with the same {path}.
+type IntegrationRefreshConfig struct {
+	Command string `yaml:"command,omitempty"`
+}
+
+// IntegrationTeardownConfig removes the tool's artefacts from a worktree.
+type IntegrationTeardownConfig struct {
+	Command string   `yaml:"command,omitempty"`
+	Dirs    []string `yaml:"dirs,omitempty"`
+}
@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -487,6 +489,78 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "malformed detect glob",
+			cfg: Config{Ecosystems: []EcosystemConfig{
+				{Name: "go", Detect: DetectConfig{Files: []string{"go.[mod"}}},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "protected branch glob",
+			cfg:     Config{ProtectedBranches: []string{"release/*", "production"}},
+			wantErr: false,
+		},
+		{
+			name:    "malformed protected branch glob",
+			cfg:     Config{ProtectedBranches: []string{"release/["}},
+			wantErr: true,
+		},
+		{
+			name: "env modes",
+			cfg: Config{Ecosystems: []EcosystemConfig{
+				{Name: "node", Detect: DetectConfig{Files: []string{"package.json"}}, EnvMode: EnvModeTemplate},
+				{Name: "go", Detect: DetectConfig{Files: []string{"go.mod"}}, EnvMode: EnvModeSymlink},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "unknown env mode",
+			cfg: Config{Ecosystems: []EcosystemConfig{
+				{Name: "node", Detect: DetectConfig{Files: []string{"package.json"}}, EnvMode: "hardlink"},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "negative archive retention",
+			cfg:     Config{ArchiveRetentionDays: -1},
+			wantErr: true,
+		},
+		{
+			name: "declared integration",
+			cfg: Config{Integrations: []IntegrationConfig{{
+				Name:     "ctags",
+				Detect:   IntegrationDetectConfig{Binary: "ctags"},
+				Setup:    IntegrationSetupConfig{Command: "ctags -R", WorkingDir: "worktree"},
+				Teardown: IntegrationTeardownConfig{Dirs: []string{".tags/"}},
+			}}},
+			wantErr: false,
+		},
+		{
+			name:    "integration without name",
+			cfg:     Config{Integrations: []IntegrationConfig{{Detect: IntegrationDetectConfig{Binary: "ctags"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "integration with bad working dir",
+			cfg:     Config{Integrations: []IntegrationConfig{{Name: "ctags", Setup: IntegrationSetupConfig{WorkingDir: "home"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "integration dependency without detect",
+			cfg:     Config{Integrations: []IntegrationConfig{{Name: "ctags", Dependencies: []DependencyConfig{{Name: "brew"}}}}},
+			wantErr: true,
+		},
+		{
+			name:    "integration teardown dir outside the worktree",
+			cfg:     Config{Integrations: []IntegrationConfig{{Name: "ctags", Teardown: IntegrationTeardownConfig{Dirs: []string{"../other"}}}}},
+			wantErr: true,
+		},
+		{
+			name:    "integration index dir is absolute",
+			cfg:     Config{Integrations: []IntegrationConfig{{Name: "ctags", IndexCopyDir: "/tmp/tags"}}},
+			wantErr: true,
+		},
 		{
 			name: "unknown integration name is warning not error",
 			cfg: Config{
@@ -585,3 +659,89 @@ func TestLoadConfig_EmbeddedOnly(t *testing.T) {
 		t.Fatalf("expected 16 ecosystems from defaults, got %d", len(cfg.Ecosystems))
 	}
 }
+
+func TestMergeConfigs_ArchiveSettings(t *testing.T) {
+	on := true
+	base := &Config{ArchiveRetentionDays: 14}
+	merged := mergeConfigs(base, &Config{ArchiveBeforeRemove: &on}, "per-repo")
+	if !merged.ArchivesEnabled() {
+		t.Error("overlay archive_before_remove should enable archives")
+	}
+	if got, want := merged.ArchiveRetention(), 14*24*time.Hour; got != want {
+		t.Errorf("ArchiveRetention() = %v, want %v (kept from base)", got, want)
+	}
+
+	var empty Config
+	if empty.ArchivesEnabled() {
+		t.Error("archives should be off by default")
+	}
+	if got, want := empty.ArchiveRetention(), DefaultArchiveRetentionDays*24*time.Hour; got != want {
+		t.Errorf("default ArchiveRetention() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeIntegrations(t *testing.T) {
+	base := []IntegrationConfig{{
+		Name:     "ctags",
+		Detect:   IntegrationDetectConfig{Binary: "ctags"},
+		Install:  IntegrationInstallConfig{Command: "brew install universal-ctags"},
+		Setup:    IntegrationSetupConfig{Command: "ctags -R"},
+		Teardown: IntegrationTeardownConfig{Dirs: []string{".tags/"}},
+		Source:   "global",
+	}}
+	overlay := []IntegrationConfig{
+		{Name: "ctags", Install: IntegrationInstallConfig{Command: "apt-get install universal-ctags"}},
+		{Name: "lsp-cache", Detect: IntegrationDetectConfig{Command: "true"}},
+	}
+
+	result := mergeIntegrations(base, overlay, "per-repo")
+	if len(result) != 2 {
+		t.Fatalf("expected 2 integrations, got %d", len(result))
+	}
+	ctags := result[0]
+	if ctags.Install.Command != "apt-get install universal-ctags" {
+		t.Errorf("install command should be overridden, got %q", ctags.Install.Command)
+	}
+	if ctags.Detect.Binary != "ctags" || ctags.Setup.Command != "ctags -R" || len(ctags.Teardown.Dirs) != 1 {
+		t.Errorf("fields the overlay leaves empty should be kept, got %+v", ctags)
+	}
+	if ctags.Source != "per-repo" || result[1].Source != "per-repo" {
+		t.Errorf("sources = %q, %q, want per-repo", ctags.Source, result[1].Source)
+	}
+	if base[0].Install.Command != "brew install universal-ctags" {
+		t.Error("merge must not modify base")
+	}
+}
+
+func TestMergeConfigs_ProfilesReplaceByName(t *testing.T) {
+	on := true
+	base := &Config{Profiles: map[string]Profile{
+		"review": {Base: "main", CopyEnv: &on, Source: "global"},
+		"spike":  {Ecosystems: []string{"go"}, Source: "global"},
+	}}
+	overlay := &Config{Profiles: map[string]Profile{
+		"review": {Base: "develop", Integrations: []string{}},
+	}}
+
+	merged := mergeConfigs(base, overlay, "per-repo")
+	review, err := merged.Profile("review")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if review.Base != "develop" || review.CopyEnv != nil || review.Integrations == nil || review.Source != "per-repo" {
+		t.Errorf("review = %+v, want the per-repo profile as a whole", review)
+	}
+	if spike, _ := merged.Profile("spike"); spike.Source != "global" {
+		t.Errorf("spike = %+v, want it kept from the global layer", spike)
+	}
+	if _, ok := base.Profiles["review"]; !ok || base.Profiles["review"].Base != "main" {
+		t.Error("merge must not modify base")
+	}
+
+	if _, err := merged.Profile("nope"); err == nil || !strings.Contains(err.Error(), "profiles: review, spike") {
+		t.Errorf("unknown profile error = %v, want it to list the profiles", err)
+	}
+	if _, err := (&Config{}).Profile("nope"); err == nil || !strings.Contains(err.Error(), "no profiles are configured") {
+		t.Errorf("unknown profile error = %v", err)
+	}
+}
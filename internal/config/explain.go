@@ -0,0 +1,208 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SourceDefault is the provenance of a field no layer set: its value is the
+// built-in default.
+const SourceDefault = "default"
+
+// Explanation is the merged config together with the layer each field came
+// from.
+type Explanation struct {
+	Config *Config
+	Layers []Layer
+	// Sources maps a field path to the layer that last set it. Paths are
+	// the YAML keys joined by dots, with ecosystems and integrations keyed
+	// by name:
+	// "protected_branches", "ecosystems.pnpm", "ecosystems.pnpm.install.command".
+	Sources map[string]string
+}
+
+// Explain loads the config like LoadConfig, without validating it, and
+// records which layer each field came from.
+func Explain(repoPath string, opts ...LoadOption) (*Explanation, error) {
+	var lo loadOptions
+	for _, opt := range opts {
+		opt(&lo)
+	}
+
+	layers, err := loadLayers(repoPath, lo)
+	if err != nil {
+		return nil, err
+	}
+	sources := make(map[string]string)
+	for _, l := range layers {
+		if l.config != nil {
+			recordSources(sources, l.config, l.Source)
+		}
+	}
+	return &Explanation{Config: mergeLayers(layers), Layers: layers, Sources: sources}, nil
+}
+
+// Source returns the layer that set field, or SourceDefault.
+func (e *Explanation) Source(field string) string {
+	if s, ok := e.Sources[field]; ok {
+		return s
+	}
+	return SourceDefault
+}
+
+// recordSources marks the fields cfg sets as coming from source. It follows
+// mergeConfigs: only non-empty values override.
+func recordSources(sources map[string]string, cfg *Config, source string) {
+	set := func(field string, ok bool) {
+		if ok {
+			sources[field] = source
+		}
+	}
+	set("protected_branches", len(cfg.ProtectedBranches) > 0)
+	set("integrations_enabled", len(cfg.IntegrationsEnabled) > 0)
+	set("archive_before_remove", cfg.ArchiveBeforeRemove != nil)
+	set("archive_retention_days", cfg.ArchiveRetentionDays != 0)
+
+	for _, e := range cfg.Ecosystems {
+		prefix := "ecosystems." + e.Name
+		if _, ok := sources[prefix]; !ok {
+			sources[prefix] = source
+		}
+		set(prefix+".enabled", e.Enabled != nil)
+		set(prefix+".detect.files", len(e.Detect.Files) > 0)
+		set(prefix+".install.command", e.Install.Command != "")
+		set(prefix+".install.workspace_detect", e.Install.WorkspaceDetect != "")
+		set(prefix+".install.workspace_install", e.Install.WorkspaceInstall != "")
+		set(prefix+".install.parallel", e.Install.Parallel != nil)
+		set(prefix+".env_files", len(e.EnvFiles) > 0)
+		set(prefix+".env_mode", e.EnvMode != "")
+		set(prefix+".post_install", len(e.PostInstall) > 0)
+	}
+
+	for name, p := range cfg.Profiles {
+		prefix := "profiles." + name
+		set(prefix+".base", p.Base != "")
+		set(prefix+".ecosystems", p.Ecosystems != nil)
+		set(prefix+".integrations", p.Integrations != nil)
+		set(prefix+".merge_base", p.MergeBase != nil)
+		set(prefix+".copy_env", p.CopyEnv != nil)
+	}
+
+	for _, integ := range cfg.Integrations {
+		prefix := "integrations." + integ.Name
+		if _, ok := sources[prefix]; !ok {
+			sources[prefix] = source
+		}
+		set(prefix+".short_description", integ.ShortDescription != "")
+		set(prefix+".description", integ.Description != "")
+		set(prefix+".url", integ.URL != "")
+		set(prefix+".dependencies", len(integ.Dependencies) > 0)
+		set(prefix+".detect.command", integ.Detect.Command != "")
+		set(prefix+".detect.binary", integ.Detect.Binary != "")
+		set(prefix+".install.command", integ.Install.Command != "")
+		set(prefix+".install.first_run_note", integ.Install.FirstRunNote != "")
+		set(prefix+".setup.command", integ.Setup.Command != "")
+		set(prefix+".setup.working_dir", integ.Setup.WorkingDir != "")
+		set(prefix+".refresh.command", integ.Refresh.Command != "")
+		set(prefix+".teardown.command", integ.Teardown.Command != "")
+		set(prefix+".teardown.dirs", len(integ.Teardown.Dirs) > 0)
+		set(prefix+".gitignore", len(integ.Gitignore) > 0)
+		set(prefix+".index_copy_dir", integ.IndexCopyDir != "")
+	}
+}
+
+// effective returns a copy of the merged config with defaulted fields
+// spelled out, so show prints the values sentei acts on.
+func (e *Explanation) effective() *Config {
+	cfg := *e.Config
+	archive := cfg.ArchivesEnabled()
+	cfg.ArchiveBeforeRemove = &archive
+	if cfg.ArchiveRetentionDays == 0 {
+		cfg.ArchiveRetentionDays = DefaultArchiveRetentionDays
+	}
+	return &cfg
+}
+
+// YAML renders the effective config as YAML, each field annotated with the
+// layer it came from.
+func (e *Explanation) YAML() ([]byte, error) {
+	var root yaml.Node
+	if err := root.Encode(e.effective()); err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+	e.annotate(&root, "")
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Values returns the effective config as generic YAML-keyed values, for the
+// JSON document.
+func (e *Explanation) Values() (map[string]any, error) {
+	data, err := yaml.Marshal(e.effective())
+	if err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+	return values, nil
+}
+
+// annotate walks a mapping node and sets a line comment naming the source of
+// every field under prefix.
+func (e *Explanation) annotate(node *yaml.Node, prefix string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		field := key.Value
+		if prefix != "" {
+			field = prefix + "." + key.Value
+		}
+
+		switch {
+		case (field == "ecosystems" || field == "integrations") && value.Kind == yaml.SequenceNode:
+			for _, item := range value.Content {
+				e.annotateNamed(item, field)
+			}
+		case value.Kind == yaml.MappingNode:
+			e.annotate(value, field)
+		default:
+			if value.Kind == yaml.SequenceNode {
+				value.Style = yaml.FlowStyle
+			}
+			value.LineComment = e.Source(field)
+		}
+	}
+}
+
+// annotateNamed labels an ecosystem or integration entry's name with the
+// layer that defined it and its fields with the layer that last set each one.
+func (e *Explanation) annotateNamed(item *yaml.Node, list string) {
+	var name string
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		if item.Content[i].Value == "name" {
+			name = item.Content[i+1].Value
+		}
+	}
+	prefix := list + "." + name
+	e.annotate(item, prefix)
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		if item.Content[i].Value == "name" {
+			item.Content[i+1].LineComment = e.Source(prefix)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeLayers points XDG_CONFIG_HOME at a temp dir holding global, writes
+// repo as .sentei.yaml in a fresh repo dir (either may be empty to skip it)
+// and returns the repo dir.
+func writeLayers(t *testing.T, global, repo string) string {
+	t.Helper()
+	xdgDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdgDir)
+	if global != "" {
+		if err := os.MkdirAll(filepath.Join(xdgDir, "sentei"), 0o755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(xdgDir, "sentei", "config.yaml"), []byte(global), 0o644); err != nil {
+			t.Fatalf("WriteFile global config: %v", err)
+		}
+	}
+	repoDir := t.TempDir()
+	if repo != "" {
+		if err := os.WriteFile(filepath.Join(repoDir, RepoConfigFile), []byte(repo), 0o644); err != nil {
+			t.Fatalf("WriteFile repo config: %v", err)
+		}
+	}
+	return repoDir
+}
+
+func TestExplain_Sources(t *testing.T) {
+	repoDir := writeLayers(t, `
+protected_branches: ["release/*"]
+ecosystems:
+  - name: go
+    install:
+      command: go mod download -x
+`, `
+protected_branches: [staging]
+ecosystems:
+  - name: custom
+    detect:
+      files: [custom.lock]
+    install:
+      command: custom install
+`)
+
+	exp, err := Explain(repoDir)
+	if err != nil {
+		t.Fatalf("Explain() error: %v", err)
+	}
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"protected_branches", "per-repo"},
+		{"archive_retention_days", SourceDefault},
+		{"ecosystems.go", "embedded"},
+		{"ecosystems.go.install.command", "global"},
+		{"ecosystems.go.detect.files", "embedded"},
+		{"ecosystems.custom", "per-repo"},
+		{"ecosystems.custom.install.command", "per-repo"},
+		{"ecosystems.custom.env_files", SourceDefault},
+	}
+	for _, tc := range tests {
+		if got := exp.Source(tc.field); got != tc.want {
+			t.Errorf("Source(%q) = %q, want %q", tc.field, got, tc.want)
+		}
+	}
+
+	if len(exp.Layers) != 3 || !exp.Layers[1].Found || !exp.Layers[2].Found {
+		t.Errorf("expected three found layers, got %+v", exp.Layers)
+	}
+}
+
+func TestExplain_YAMLAnnotatesFields(t *testing.T) {
+	repoDir := writeLayers(t, "", "archive_before_remove: true\n")
+
+	exp, err := Explain(repoDir)
+	if err != nil {
+		t.Fatalf("Explain() error: %v", err)
+	}
+	if exp.Layers[1].Found {
+		t.Error("missing global config should not be found")
+	}
+	data, err := exp.YAML()
+	if err != nil {
+		t.Fatalf("YAML() error: %v", err)
+	}
+	out := string(data)
+
+	for _, want := range []string{
+		"archive_before_remove: true # per-repo",
+		"archive_retention_days: 30 # default",
+		"- name: pnpm # embedded",
+		"command: pnpm install # embedded",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("YAML output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scaffold returns a commented .sentei.yaml listing the given ecosystems.
+// Every setting is commented out at its current value, so the file changes
+// nothing until edited and later changes to the defaults still apply.
+func Scaffold(ecosystems []EcosystemConfig) []byte {
+	var b strings.Builder
+	b.WriteString(`# sentei per-repo configuration.
+#
+# Settings here override ~/.config/sentei/config.yaml and the built-in
+# defaults. Run 'sentei config show' to see the merged result and
+# 'sentei config validate' to check this file.
+
+`)
+	if len(ecosystems) == 0 {
+		b.WriteString(`# No ecosystems were detected in this worktree. Entries are merged with the
+# built-in ones by name; a new name needs detect.files and install.command.
+# ecosystems:
+#   - name: my-tool
+#     detect:
+#       files: ["my-tool.lock"]
+#     install:
+#       command: "my-tool install"
+`)
+	} else {
+		b.WriteString(`# Ecosystems detected in this worktree. Entries are merged with the built-in
+# ones by name: uncomment only the fields you want to change.
+ecosystems:
+`)
+		for _, e := range ecosystems {
+			fmt.Fprintf(&b, "  - name: %s\n", e.Name)
+			b.WriteString("    # enabled: false\n")
+			fmt.Fprintf(&b, "    # detect:\n    #   files: %s\n", flowList(e.Detect.Files))
+			fmt.Fprintf(&b, "    # install:\n    #   command: %q\n", e.Install.Command)
+			if len(e.EnvFiles) > 0 {
+				fmt.Fprintf(&b, "    # env_files: %s\n", flowList(e.EnvFiles))
+				fmt.Fprintf(&b, "    # env_mode: %s  # or symlink, or template to render <file>.template\n", e.EnvFileMode())
+			}
+			if len(e.PostInstall) > 0 {
+				fmt.Fprintf(&b, "    # post_install: %s\n", flowList(e.PostInstall))
+			}
+		}
+	}
+
+	fmt.Fprintf(&b, `
+# Branches sentei never removes, as exact names or globs, in addition to the
+# default branch and the built-in set.
+# protected_branches: ["release/*"]
+
+# Integrations to set up in new worktrees; see 'sentei integrations'.
+# integrations_enabled: []
+
+# Per-worktree tools beyond the built-in integrations. An entry named after
+# a built-in one changes only the fields it sets.
+# integrations:
+#   - name: ctags
+#     short_description: "Tag index for editors"
+#     detect:
+#       binary: ctags
+#     install:
+#       command: "brew install universal-ctags"
+#     setup:
+#       command: "mkdir -p .tags && ctags -R -f .tags/tags ."
+#     refresh:
+#       command: "ctags -R -f .tags/tags ."
+#     teardown:
+#       dirs: [".tags/"]
+#     gitignore: [".tags/"]
+#     index_copy_dir: ".tags"
+
+# Named sets of create options, chosen with 'sentei create --profile review'.
+# integrations: [] sets up none; leaving a field out keeps create's default.
+# profiles:
+#   review:
+#     base: main
+#     ecosystems: [pnpm]
+#     integrations: []
+#     copy_env: true
+
+# Archive worktrees with unsaved work before remove deletes them, so
+# 'sentei restore' can bring them back; cleanup drops older archives.
+# archive_before_remove: true
+# archive_retention_days: %d
+`, DefaultArchiveRetentionDays)
+	return []byte(b.String())
+}
+
+// flowList renders values as a YAML flow sequence of quoted strings.
+func flowList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
@@ -0,0 +1,235 @@
+package creator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/progress"
+)
+
+// BatchConcurrency bounds how many worktrees a batch creates at once; each
+// may run several dependency installs of its own.
+const BatchConcurrency = 3
+
+// BatchWorktree is one entry's outcome in a batch.
+type BatchWorktree struct {
+	Branch       string
+	WorktreePath string           // empty when the worktree was not created
+	Phases       []progress.Phase // this entry's phases only
+}
+
+func (w BatchWorktree) HasFailures() bool {
+	return w.WorktreePath == "" || progress.PhasesHaveFailures(w.Phases)
+}
+
+type BatchResult struct {
+	Worktrees []BatchWorktree  // in entry order
+	Phases    []progress.Phase // every entry's phases, in entry order
+	Err       error
+}
+
+func (r *BatchResult) HasFailures() bool {
+	if r.Err != nil {
+		return true
+	}
+	for _, wt := range r.Worktrees {
+		if wt.HasFailures() {
+			return true
+		}
+	}
+	return false
+}
+
+type batchEntry struct {
+	branch   string
+	prepared preparedCreation
+	after    int // entry that creates this one's base branch, or -1
+}
+
+// RunBatch creates every entry's worktree under one progress plan, with
+// each entry's phases prefixed by its branch. Everything is prepared before
+// the first worktree is added; a preparation failure in any entry creates
+// none. Up to BatchConcurrency worktrees run at once. An entry whose base
+// is a branch another entry creates waits for that worktree and is skipped
+// when it could not be created.
+func RunBatch(runner git.CommandRunner, shell git.ShellRunner, entries []Options, emit func(progress.Event)) BatchResult {
+	result := BatchResult{}
+	batch, plan, err := prepareBatch(runner, shell, entries)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	execution, err := progress.Start(plan, emit)
+	if err != nil {
+		result.Err = fmt.Errorf("starting batch creation: %w", err)
+		return result
+	}
+
+	results := make([]Result, len(batch))
+	created := make([]chan struct{}, len(batch))
+	for i := range created {
+		created[i] = make(chan struct{})
+	}
+	sem := make(chan struct{}, BatchConcurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var runErr error
+	for i := range batch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := runBatchEntry(execution, runner, shell, batch, i, results, created, sem)
+			if err != nil {
+				mu.Lock()
+				runErr = errors.Join(runErr, fmt.Errorf("%s: %w", batch[i].branch, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	finishErr := execution.Finish("batch creation finished")
+	result.Phases = execution.Phases()
+	result.Err = errors.Join(runErr, finishErr)
+	for i, entry := range batch {
+		planned := make(map[progress.PhaseID]bool, len(entry.prepared.plan.Phases))
+		for _, phase := range entry.prepared.plan.Phases {
+			planned[phase.ID] = true
+		}
+		wt := BatchWorktree{Branch: entry.branch, WorktreePath: results[i].WorktreePath}
+		for _, phase := range result.Phases {
+			if planned[phase.ID] {
+				wt.Phases = append(wt.Phases, phase)
+			}
+		}
+		result.Worktrees = append(result.Worktrees, wt)
+	}
+	return result
+}
+
+// runBatchEntry runs entry i once the worktree its base depends on exists.
+// It waits before taking a slot, so waiting entries never starve the ones
+// they wait for. created[i] is closed once entry i's worktree is added or
+// known to be missing.
+func runBatchEntry(execution *progress.Execution, runner git.CommandRunner, shell git.ShellRunner, batch []batchEntry, i int, results []Result, created []chan struct{}, sem chan struct{}) error {
+	entry := batch[i]
+	if entry.after >= 0 {
+		<-created[entry.after]
+		if results[entry.after].WorktreePath == "" {
+			close(created[i])
+			return entry.prepared.skipBlocked(execution, "blocked by "+batch[entry.after].branch)
+		}
+	}
+	sem <- struct{}{}
+	defer func() { <-sem }()
+
+	ok, err := entry.prepared.runCreate(execution, runner, &results[i])
+	close(created[i])
+	if err != nil || !ok {
+		return err
+	}
+	return entry.prepared.runAfterCreate(execution, runner, shell, &results[i])
+}
+
+// prepareBatch prepares every entry in dependency order, so an entry based
+// on a branch the batch has yet to create reads its workspace manifests
+// from the tree that branch will start from.
+func prepareBatch(runner git.CommandRunner, shell git.ShellRunner, entries []Options) ([]batchEntry, progress.Plan, error) {
+	if len(entries) == 0 {
+		return nil, progress.Plan{}, errors.New("preparing batch creation: no worktrees")
+	}
+	batch := make([]batchEntry, len(entries))
+	byBranch := make(map[string]int, len(entries))
+	byPath := make(map[string]int, len(entries))
+	for i, opts := range entries {
+		branch := strings.TrimSpace(opts.BranchName)
+		if branch == "" && opts.Source != nil {
+			branch = opts.Source.Branch
+		}
+		if branch == "" {
+			return nil, progress.Plan{}, fmt.Errorf("preparing batch creation: entry %d has no branch", i+1)
+		}
+		if _, dup := byBranch[branch]; dup {
+			return nil, progress.Plan{}, fmt.Errorf("preparing batch creation: branch %q is listed twice", branch)
+		}
+		path := git.WorktreePath(opts.RepoPath, branch)
+		if j, dup := byPath[path]; dup {
+			return nil, progress.Plan{}, fmt.Errorf("preparing batch creation: branches %q and %q would share %s", batch[j].branch, branch, path)
+		}
+		byBranch[branch], byPath[path] = i, i
+		batch[i] = batchEntry{branch: branch, after: -1}
+	}
+	for i, opts := range entries {
+		if j, ok := byBranch[opts.BaseBranch]; ok && opts.Source == nil && j != i && !git.BranchExists(runner, opts.RepoPath, opts.BaseBranch) {
+			batch[i].after = j
+		}
+	}
+	order, err := batchOrder(batch)
+	if err != nil {
+		return nil, progress.Plan{}, fmt.Errorf("preparing batch creation: %w", err)
+	}
+
+	treeRefs := make([]string, len(batch))
+	for _, i := range order {
+		opts := entries[i]
+		opts.BranchName = batch[i].branch
+		treeRef := ""
+		if j := batch[i].after; j >= 0 {
+			treeRef = treeRefs[j]
+		}
+		prepared, err := prepareCreationIn(runner, shell, opts, creationPhases{prefix: batch[i].branch}, treeRef)
+		if err != nil {
+			return nil, progress.Plan{}, fmt.Errorf("%s: %w", batch[i].branch, err)
+		}
+		treeRefs[i] = prepared.opts.BaseBranch
+		if treeRef != "" {
+			treeRefs[i] = treeRef
+		}
+		batch[i].prepared = prepared
+	}
+
+	var plan progress.Plan
+	for _, entry := range batch {
+		plan.Phases = append(plan.Phases, entry.prepared.plan.Phases...)
+	}
+	return batch, plan, nil
+}
+
+// batchOrder orders entries so each comes after the entry creating its
+// base, keeping the listed order otherwise.
+func batchOrder(batch []batchEntry) ([]int, error) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	marks := make([]int, len(batch))
+	var order []int
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch marks[i] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("branch %q is based on itself through the batch", batch[i].branch)
+		}
+		marks[i] = visiting
+		if j := batch[i].after; j >= 0 {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		marks[i] = done
+		order = append(order, i)
+		return nil
+	}
+	for i := range batch {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
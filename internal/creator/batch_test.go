@@ -0,0 +1,162 @@
+package creator
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/testutil/mock"
+)
+
+func TestRunBatch_StackedEntryWaitsForItsBase(t *testing.T) {
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[worktree add /repo/stack-2 -b stack/2 stack/1]": {},
+		"/repo:[worktree add /repo/stack-1 -b stack/1 main]":    {},
+		"/repo:[worktree add /repo/other -b other main]":        {},
+	}}
+	entries := []Options{
+		{BranchName: "stack/2", BaseBranch: "stack/1", RepoPath: "/repo"},
+		{BranchName: "stack/1", BaseBranch: "main", RepoPath: "/repo"},
+		{BranchName: "other", BaseBranch: "main", RepoPath: "/repo"},
+	}
+	ec := &mock.EventCollector[progress.Event]{}
+
+	result := RunBatch(runner, runner, entries, ec.Emit)
+
+	if result.HasFailures() {
+		t.Fatalf("batch failed: %v %#v", result.Err, result.Phases)
+	}
+	if err := progress.ValidateCompletedStream(ec.Events); err != nil {
+		t.Fatalf("event stream: %v", err)
+	}
+	added := map[string]int{}
+	for i, call := range runner.Calls {
+		if strings.Contains(call, "[worktree add") {
+			added[call] = i
+		}
+	}
+	if added["/repo:[worktree add /repo/stack-1 -b stack/1 main]"] > added["/repo:[worktree add /repo/stack-2 -b stack/2 stack/1]"] {
+		t.Errorf("stack/2 was added before its base: %v", runner.Calls)
+	}
+
+	var branches []string
+	for _, wt := range result.Worktrees {
+		branches = append(branches, wt.Branch)
+		if len(wt.Phases) != 1 || wt.Phases[0].Name != wt.Branch+": Setup" {
+			t.Errorf("%s phases = %#v, want its own Setup phase", wt.Branch, wt.Phases)
+		}
+	}
+	if strings.Join(branches, ",") != "stack/2,stack/1,other" {
+		t.Errorf("worktrees = %v, want entry order", branches)
+	}
+	if len(result.Phases) != 3 {
+		t.Errorf("combined phases = %d, want 3", len(result.Phases))
+	}
+}
+
+func TestRunBatch_FailedBaseSkipsOnlyItsDependents(t *testing.T) {
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[worktree add /repo/stack-1 -b stack/1 main]": {Err: errors.New("invalid reference: main")},
+		"/repo:[worktree add /repo/other -b other develop]":  {},
+	}}
+	entries := []Options{
+		{BranchName: "stack/1", BaseBranch: "main", RepoPath: "/repo"},
+		{BranchName: "stack/2", BaseBranch: "stack/1", RepoPath: "/repo"},
+		{BranchName: "other", BaseBranch: "develop", RepoPath: "/repo"},
+	}
+
+	result := RunBatch(runner, runner, entries, nil)
+
+	if result.Err != nil {
+		t.Fatalf("unexpected contract error: %v", result.Err)
+	}
+	failed := map[string]bool{}
+	for _, wt := range result.Worktrees {
+		failed[wt.Branch] = wt.HasFailures()
+	}
+	if !failed["stack/1"] || !failed["stack/2"] || failed["other"] {
+		t.Errorf("failures = %v, want stack/1 and stack/2 only", failed)
+	}
+	step := result.Worktrees[1].Phases[0].Steps[0]
+	if step.Status != progress.StepSkipped || step.Message != "blocked by stack/1" {
+		t.Errorf("stack/2 create = %v %q, want skipped as blocked by stack/1", step.Status, step.Message)
+	}
+}
+
+func TestRunBatch_RejectsBadBatchesBeforeCreating(t *testing.T) {
+	cases := []struct {
+		name    string
+		entries []Options
+		want    string
+	}{
+		{"empty", nil, "no worktrees"},
+		{"duplicate branch", []Options{
+			{BranchName: "a", BaseBranch: "main", RepoPath: "/repo"},
+			{BranchName: "a", BaseBranch: "main", RepoPath: "/repo"},
+		}, `"a" is listed twice`},
+		{"same directory", []Options{
+			{BranchName: "feat/a", BaseBranch: "main", RepoPath: "/repo"},
+			{BranchName: "feat-a", BaseBranch: "main", RepoPath: "/repo"},
+		}, "would share"},
+		{"cycle", []Options{
+			{BranchName: "a", BaseBranch: "b", RepoPath: "/repo"},
+			{BranchName: "b", BaseBranch: "a", RepoPath: "/repo"},
+		}, "based on itself"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			runner := &mock.Runner{}
+			result := RunBatch(runner, runner, tc.entries, nil)
+			if result.Err == nil || !strings.Contains(result.Err.Error(), tc.want) {
+				t.Errorf("error = %v, want %q", result.Err, tc.want)
+			}
+			for _, call := range runner.Calls {
+				if strings.Contains(call, "worktree add") {
+					t.Errorf("created a worktree despite the error: %s", call)
+				}
+			}
+		})
+	}
+}
+
+func TestRunBatch_SerializesWorktreeAddAndUpstreamConfig(t *testing.T) {
+	var inside, overlapped atomic.Int32
+	runner := &mock.Runner{Responses: map[string]mock.Response{}}
+	var entries []Options
+	for _, branch := range []string{"a", "b", "c"} {
+		section := "branch." + branch
+		runner.Responses["/repo:[fetch origin +refs/heads/"+branch+":refs/remotes/origin/"+branch+"]"] = mock.Response{}
+		runner.Responses["/repo:[worktree add /repo/"+branch+" --no-track -b "+branch+" refs/remotes/origin/"+branch+"]"] = mock.Response{}
+		runner.Responses["/repo:[config "+section+".remote origin]"] = mock.Response{}
+		runner.Responses["/repo:[config "+section+".merge refs/heads/"+branch+"]"] = mock.Response{}
+		entries = append(entries, Options{
+			BranchName: branch, BaseBranch: "main", RepoPath: "/repo",
+			Source: &RemoteSource{Remote: "origin", Ref: "refs/heads/" + branch, Track: true},
+		})
+	}
+	// Each add holds the step until its .merge write; a second add while
+	// one is held means two could race on config.lock.
+	runner.OnRun = func(dir string, args []string) {
+		switch {
+		case len(args) > 1 && args[0] == "worktree" && args[1] == "add":
+			if inside.Add(1) > 1 {
+				overlapped.Store(1)
+			}
+			time.Sleep(10 * time.Millisecond)
+		case len(args) > 1 && args[0] == "config" && strings.HasSuffix(args[1], ".merge"):
+			inside.Add(-1)
+		}
+	}
+
+	result := RunBatch(runner, runner, entries, nil)
+
+	if result.HasFailures() {
+		t.Fatalf("batch failed: %v %v", result.Err, runner.Calls)
+	}
+	if overlapped.Load() != 0 {
+		t.Errorf("worktree add and upstream config of two entries overlapped: %v", runner.Calls)
+	}
+}
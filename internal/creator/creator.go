@@ -11,8 +11,12 @@ import (
 )
 
 type Options struct {
-	BranchName     string
-	BaseBranch     string
+	BranchName string
+	BaseBranch string
+	// Source, when set, replaces BaseBranch: its head is fetched before
+	// preparation and a new branch starts from there. An existing local
+	// branch is checked out as it is. MergeBase is ignored.
+	Source         *RemoteSource
 	RepoPath       string
 	SourceWorktree string
 	MergeBase      bool
@@ -23,6 +27,7 @@ type Options struct {
 
 type Result struct {
 	WorktreePath string
+	EnvFiles     []EnvFile
 	Phases       []progress.Phase
 	Err          error
 }
@@ -7,8 +7,10 @@ import (
 	"testing"
 
 	"github.com/abiswas97/sentei/internal/config"
+	"github.com/abiswas97/sentei/internal/fileutil"
 	"github.com/abiswas97/sentei/internal/integration"
 	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/state"
 	"github.com/abiswas97/sentei/internal/testutil/mock"
 )
 
@@ -183,6 +185,110 @@ func TestRun_CopyEnvFiles(t *testing.T) {
 	_ = result
 }
 
+func TestRun_SymlinkEnvFiles(t *testing.T) {
+	srcDir := t.TempDir()
+	os.WriteFile(filepath.Join(srcDir, ".env"), []byte("KEY=val"), 0644)
+
+	repoDir := t.TempDir()
+	wtPath := filepath.Join(repoDir, "feature-env")
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		fmt.Sprintf("%s:[show-ref --verify refs/heads/feature/env]", repoDir):    {Err: fmt.Errorf("not found")},
+		fmt.Sprintf("%s:[worktree add %s -b feature/env main]", repoDir, wtPath): {Output: ""},
+	}}
+	os.MkdirAll(wtPath, 0755)
+
+	result := Run(runner, runner, Options{
+		BranchName: "feature/env", BaseBranch: "main", RepoPath: repoDir,
+		SourceWorktree: srcDir, CopyEnvFiles: true,
+		Ecosystems: []config.EcosystemConfig{
+			{Name: "node", EnvFiles: []string{".env"}, EnvMode: config.EnvModeSymlink},
+		},
+	}, (&mock.EventCollector[progress.Event]{}).Emit)
+
+	target, err := os.Readlink(filepath.Join(wtPath, ".env"))
+	if err != nil {
+		t.Fatalf(".env is not a symlink: %v", err)
+	}
+	if target != filepath.Join(srcDir, ".env") {
+		t.Errorf("link target = %q, want %q", target, filepath.Join(srcDir, ".env"))
+	}
+	want := []EnvFile{{Name: ".env", Mode: config.EnvModeSymlink}}
+	if len(result.EnvFiles) != 1 || result.EnvFiles[0] != want[0] {
+		t.Errorf("EnvFiles = %+v, want %+v", result.EnvFiles, want)
+	}
+	if got := result.Phases[0].Steps[1].Name; got != "Link env files" {
+		t.Errorf("env step label = %q, want %q", got, "Link env files")
+	}
+}
+
+func TestRun_TemplateEnvFiles(t *testing.T) {
+	repoDir := t.TempDir()
+	bareDir := filepath.Join(repoDir, ".bare")
+	wtPath := filepath.Join(repoDir, "feature-env")
+	os.MkdirAll(bareDir, 0755)
+	os.MkdirAll(wtPath, 0755)
+	// The template is committed, so it arrives with the checkout.
+	os.WriteFile(filepath.Join(wtPath, ".env.template"),
+		[]byte("BRANCH={{.Branch}}\nDIR={{.WorktreePath}}\nPORT={{.Port}}\n"), 0600)
+
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		fmt.Sprintf("%s:[show-ref --verify refs/heads/feature/env]", repoDir):    {Err: fmt.Errorf("not found")},
+		fmt.Sprintf("%s:[worktree add %s -b feature/env main]", repoDir, wtPath): {Output: ""},
+		fmt.Sprintf("%s:[rev-parse --git-common-dir]", repoDir):                  {Output: bareDir},
+		fmt.Sprintf("%s:[rev-parse --git-dir]", repoDir):                         {Output: bareDir},
+		fmt.Sprintf("%s:[worktree list --porcelain]", repoDir): {
+			Output: fmt.Sprintf("worktree %s\nbare\n\nworktree %s\nHEAD abc\nbranch refs/heads/feature/env\n", bareDir, wtPath),
+		},
+	}}
+
+	result := Run(runner, runner, Options{
+		BranchName: "feature/env", BaseBranch: "main", RepoPath: repoDir, CopyEnvFiles: true,
+		Ecosystems: []config.EcosystemConfig{
+			{Name: "node", EnvFiles: []string{".env"}, EnvMode: config.EnvModeTemplate},
+		},
+	}, (&mock.EventCollector[progress.Event]{}).Emit)
+	if result.HasFailures() {
+		t.Fatalf("creation failed: %+v", result)
+	}
+
+	if len(result.EnvFiles) != 1 || result.EnvFiles[0].Port == 0 {
+		t.Fatalf("EnvFiles = %+v, want one rendered file with a port", result.EnvFiles)
+	}
+	port := result.EnvFiles[0].Port
+	data, err := os.ReadFile(filepath.Join(wtPath, ".env"))
+	if err != nil {
+		t.Fatalf("reading rendered env file: %v", err)
+	}
+	want := fmt.Sprintf("BRANCH=feature/env\nDIR=%s\nPORT=%d\n", wtPath, port)
+	if string(data) != want {
+		t.Errorf("rendered env file = %q, want %q", data, want)
+	}
+	if info, _ := os.Stat(filepath.Join(wtPath, ".env")); info.Mode().Perm() != 0600 {
+		t.Errorf("rendered mode = %v, want the template's 0600", info.Mode().Perm())
+	}
+
+	st, err := state.Load(bareDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Ports[fileutil.ResolvePath(wtPath)] != port {
+		t.Errorf("state ports = %v, want %s recorded at %d", st.Ports, wtPath, port)
+	}
+}
+
+func TestRun_EnvFileWithConflictingModes(t *testing.T) {
+	result := Run(&mock.Runner{}, &mock.Runner{}, Options{
+		BranchName: "feature/env", BaseBranch: "main", RepoPath: "/repo", CopyEnvFiles: true,
+		Ecosystems: []config.EcosystemConfig{
+			{Name: "node", EnvFiles: []string{".env"}},
+			{Name: "python", EnvFiles: []string{".env"}, EnvMode: config.EnvModeTemplate},
+		},
+	}, (&mock.EventCollector[progress.Event]{}).Emit)
+	if result.Err == nil {
+		t.Fatal("expected an error for .env set up as both copy and template")
+	}
+}
+
 func TestResult_HasFailures(t *testing.T) {
 	tests := []struct {
 		name   string
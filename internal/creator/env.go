@@ -0,0 +1,288 @@
+package creator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/abiswas97/sentei/internal/config"
+	"github.com/abiswas97/sentei/internal/fileutil"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/state"
+)
+
+// templateSuffix names the file an EnvModeTemplate env file is rendered
+// from: ".env.template" renders ".env".
+const templateSuffix = ".template"
+
+// EnvFile is one env file set up in a new worktree.
+type EnvFile struct {
+	Name string // relative to the worktree
+	Mode string // config.EnvModeCopy, EnvModeSymlink or EnvModeTemplate
+	Port int    // the port a template rendered, or 0
+}
+
+func (f EnvFile) String() string {
+	switch {
+	case f.Port != 0:
+		return fmt.Sprintf("%s (%s, port %d)", f.Name, f.Mode, f.Port)
+	case f.Mode != config.EnvModeCopy:
+		return fmt.Sprintf("%s (%s)", f.Name, f.Mode)
+	}
+	return f.Name
+}
+
+type preparedEnvFile struct {
+	name string
+	mode string
+}
+
+// uniqueEnvFiles collects the ecosystems' env files, sorted by name. A file
+// two ecosystems list with different modes is an error.
+func uniqueEnvFiles(opts Options) ([]preparedEnvFile, error) {
+	modes := map[string]string{}
+	var files []preparedEnvFile
+	for _, ecosystem := range opts.Ecosystems {
+		mode := ecosystem.EnvFileMode()
+		for _, name := range ecosystem.EnvFiles {
+			name = filepath.Clean(strings.TrimSpace(name))
+			if name == "." || filepath.IsAbs(name) || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+				continue
+			}
+			if seen, ok := modes[name]; ok {
+				if seen != mode {
+					return nil, fmt.Errorf("env file %s is set up as both %s and %s", name, seen, mode)
+				}
+				continue
+			}
+			modes[name] = mode
+			files = append(files, preparedEnvFile{name: name, mode: mode})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+	return files, nil
+}
+
+// envStepLabel names the env step after what it does to every file.
+func envStepLabel(files []preparedEnvFile) string {
+	mode := files[0].mode
+	for _, f := range files[1:] {
+		if f.mode != mode {
+			return "Set up env files"
+		}
+	}
+	switch mode {
+	case config.EnvModeSymlink:
+		return "Link env files"
+	case config.EnvModeTemplate:
+		return "Render env files"
+	}
+	return "Copy env files"
+}
+
+// envTemplateData is what an env file template sees.
+type envTemplateData struct {
+	Branch       string
+	WorktreePath string
+	WorktreeName string // the worktree's directory name
+
+	allocate func() (int, error)
+	port     int
+}
+
+// Port allocates the worktree's port on first use, so templates that do
+// not use it reserve nothing.
+func (d *envTemplateData) Port() (int, error) {
+	if d.port == 0 {
+		port, err := d.allocate()
+		if err != nil {
+			return 0, err
+		}
+		d.port = port
+	}
+	return d.port, nil
+}
+
+// setUpEnvFiles brings each env file into the new worktree by its mode.
+// Copies and links come from the source worktree; a template is read from
+// the new worktree first, where it is committed, then from the source. A
+// file with nothing to read is left out.
+func (p preparedCreation) setUpEnvFiles(runner git.CommandRunner) ([]EnvFile, error) {
+	source := p.opts.SourceWorktree
+	data := &envTemplateData{
+		Branch:       p.opts.BranchName,
+		WorktreePath: p.worktreePath,
+		WorktreeName: filepath.Base(p.worktreePath),
+		allocate: func() (int, error) {
+			return allocateWorktreePort(runner, p.opts.RepoPath, p.worktreePath)
+		},
+	}
+
+	var done []EnvFile
+	for _, f := range p.envFiles {
+		destination := filepath.Join(p.worktreePath, f.name)
+		switch f.mode {
+		case config.EnvModeTemplate:
+			from := firstExisting(filepath.Join(p.worktreePath, f.name+templateSuffix), joinSource(source, f.name+templateSuffix))
+			if from == "" {
+				continue
+			}
+			if err := renderEnvTemplate(from, destination, data); err != nil {
+				return done, fmt.Errorf("rendering %s: %w", f.name, err)
+			}
+			port := 0
+			if templateUsesPort(from) {
+				port = data.port
+			}
+			done = append(done, EnvFile{Name: f.name, Mode: f.mode, Port: port})
+
+		case config.EnvModeSymlink:
+			from := firstExisting(joinSource(source, f.name))
+			if from == "" {
+				continue
+			}
+			target, err := filepath.Abs(from)
+			if err != nil {
+				return done, fmt.Errorf("linking %s: %w", f.name, err)
+			}
+			if err := os.Remove(destination); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return done, fmt.Errorf("linking %s: %w", f.name, err)
+			}
+			if err := os.Symlink(target, destination); err != nil {
+				return done, fmt.Errorf("linking %s: %w", f.name, err)
+			}
+			done = append(done, EnvFile{Name: f.name, Mode: f.mode})
+
+		default:
+			from := joinSource(source, f.name)
+			if from == "" {
+				continue
+			}
+			if _, err := os.Stat(from); errors.Is(err, os.ErrNotExist) {
+				continue
+			} else if err != nil {
+				return done, fmt.Errorf("inspecting %s: %w", f.name, err)
+			}
+			if err := fileutil.CopyFile(from, destination); err != nil {
+				return done, fmt.Errorf("copying %s: %w", f.name, err)
+			}
+			done = append(done, EnvFile{Name: f.name, Mode: f.mode})
+		}
+	}
+	return done, nil
+}
+
+// envFilesMessage is the env step's message: each file with its mode.
+func envFilesMessage(files []EnvFile) string {
+	if len(files) == 0 {
+		return "no source files found"
+	}
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+func joinSource(source, name string) string {
+	if source == "" {
+		return ""
+	}
+	return filepath.Join(source, name)
+}
+
+func firstExisting(paths ...string) string {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// renderEnvTemplate renders the template at from to destination with the
+// template file's permissions. A field the template names but data lacks
+// is an error rather than an empty value.
+func renderEnvTemplate(from, destination string, data *envTemplateData) error {
+	info, err := os.Stat(from)
+	if err != nil {
+		return err
+	}
+	text, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New(filepath.Base(from)).Option("missingkey=error").Parse(string(text))
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return err
+	}
+	return os.WriteFile(destination, out.Bytes(), info.Mode().Perm())
+}
+
+// templateUsesPort reports whether a template mentions .Port, for a port
+// allocated by an earlier template.
+func templateUsesPort(path string) bool {
+	text, err := os.ReadFile(path)
+	return err == nil && bytes.Contains(text, []byte(".Port"))
+}
+
+// portMu serializes allocations within a process, such as a batch's
+// concurrent creations sharing sentei.json.
+var portMu sync.Mutex
+
+// allocateWorktreePort returns the port recorded for the worktree in the
+// repository's state, or allocates one no other worktree holds and nothing
+// is listening on. Ports of worktrees that no longer exist are released.
+func allocateWorktreePort(runner git.CommandRunner, repoPath, worktreePath string) (int, error) {
+	portMu.Lock()
+	defer portMu.Unlock()
+
+	commonDir, err := git.CommonDir(runner, repoPath)
+	if err != nil {
+		return 0, err
+	}
+	st, err := state.Load(commonDir)
+	if err != nil {
+		return 0, err
+	}
+	worktrees, err := git.ListWorktrees(runner, repoPath)
+	if err != nil {
+		return 0, fmt.Errorf("listing worktrees: %w", err)
+	}
+	live := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		live[i] = fileutil.ResolvePath(wt.Path)
+	}
+	st.PrunePorts(live)
+	port, err := st.AllocatePort(fileutil.ResolvePath(worktreePath), portFree)
+	if err != nil {
+		return 0, err
+	}
+	if err := state.Save(commonDir, st); err != nil {
+		return 0, err
+	}
+	return port, nil
+}
+
+func portFree(port int) bool {
+	l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return false
+	}
+	_ = l.Close()
+	return true
+}
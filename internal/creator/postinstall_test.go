@@ -0,0 +1,152 @@
+package creator
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/config"
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/testutil/mock"
+)
+
+func postInstallPhase(t *testing.T, phases []progress.Phase) progress.Phase {
+	t.Helper()
+	for _, phase := range phases {
+		if phase.ID == postInstallPhaseID {
+			return phase
+		}
+	}
+	t.Fatalf("no Post-install phase in %v", phases)
+	return progress.Phase{}
+}
+
+func TestRun_PostInstallRunsAfterDependenciesInDeclaredOrder(t *testing.T) {
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[show-ref --verify refs/heads/feature/hooks]":             {Err: errors.New("not found")},
+		"/repo:[worktree add /repo/feature-hooks -b feature/hooks main]": {},
+		"/repo/feature-hooks:shell[pnpm install]":                        {},
+		"/repo/feature-hooks:shell[prisma generate]":                     {},
+		"/repo/feature-hooks:shell[pre-commit install]":                  {},
+	}}
+	opts := Options{
+		BranchName: "feature/hooks", BaseBranch: "main", RepoPath: "/repo",
+		Ecosystems: []config.EcosystemConfig{{
+			Name:        "pnpm",
+			Install:     config.InstallConfig{Command: "pnpm install"},
+			PostInstall: []string{"prisma generate", "pre-commit install"},
+		}},
+	}
+
+	result := Run(runner, runner, opts, func(progress.Event) {})
+
+	if result.HasFailures() {
+		t.Fatalf("unexpected failures: %v %v", result.Err, result.Phases)
+	}
+	phase := postInstallPhase(t, result.Phases)
+	if phase.Name != "Post-install" || len(phase.Steps) != 2 {
+		t.Fatalf("post-install phase = %+v, want two declared steps", phase)
+	}
+	install := slices.Index(runner.Calls, "/repo/feature-hooks:shell[pnpm install]")
+	first := slices.Index(runner.Calls, "/repo/feature-hooks:shell[prisma generate]")
+	second := slices.Index(runner.Calls, "/repo/feature-hooks:shell[pre-commit install]")
+	if install < 0 || first < install || second < first {
+		t.Fatalf("hooks must run after the install, in order: %v", runner.Calls)
+	}
+}
+
+func TestRun_PostInstallExpandsDirPlaceholderPerWorkspace(t *testing.T) {
+	opts := workspaceOptions()
+	opts.Ecosystems[0].PostInstall = []string{"npm --prefix {dir} run codegen", "make hooks"}
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[ls-tree -r --name-only main]":                                  {Output: "package.json\npackages/api/package.json\npackages/web/package.json"},
+		"/repo:[show main:package.json]":                                       {Output: `{"workspaces":["packages/*"]}`},
+		"/repo:[show-ref --verify refs/heads/feature/manifest]":                {Err: errors.New("not found")},
+		"/repo:[worktree add /repo/feature-manifest -b feature/manifest main]": {},
+		"/repo/feature-manifest:shell[npm --prefix packages/api install]":      {},
+		"/repo/feature-manifest:shell[npm --prefix packages/web install]":      {},
+		"/repo/feature-manifest:shell[npm --prefix packages/api run codegen]":  {},
+		"/repo/feature-manifest:shell[npm --prefix packages/web run codegen]":  {},
+		"/repo/feature-manifest:shell[make hooks]":                             {},
+	}}
+
+	var declared []string
+	result := Run(runner, runner, opts, func(event progress.Event) {
+		if event.Phase == postInstallPhaseID && event.Status == progress.StepPending && !event.Close {
+			declared = append(declared, event.StepLabel)
+		}
+	})
+
+	if result.HasFailures() {
+		t.Fatalf("unexpected failures: %v %v", result.Err, result.Phases)
+	}
+	want := []string{
+		"node (packages/api): npm --prefix packages/api run codegen",
+		"node (packages/web): npm --prefix packages/web run codegen",
+		"node: make hooks",
+	}
+	if !slices.Equal(declared, want) {
+		t.Fatalf("declared post-install steps = %v, want %v", declared, want)
+	}
+}
+
+func TestRun_PostInstallFailureIsSoft(t *testing.T) {
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[show-ref --verify refs/heads/feature/hooks]":             {Err: errors.New("not found")},
+		"/repo:[worktree add /repo/feature-hooks -b feature/hooks main]": {},
+		"/repo/feature-hooks:shell[go mod download]":                     {},
+		"/repo/feature-hooks:shell[make codegen]":                        {Err: errors.New("no rule to make target")},
+		"/repo/feature-hooks:shell[pre-commit install]":                  {},
+	}}
+	opts := Options{
+		BranchName: "feature/hooks", BaseBranch: "main", RepoPath: "/repo",
+		Ecosystems: []config.EcosystemConfig{{
+			Name:        "go",
+			Install:     config.InstallConfig{Command: "go mod download"},
+			PostInstall: []string{"make codegen", "pre-commit install"},
+		}},
+	}
+
+	result := Run(runner, runner, opts, func(progress.Event) {})
+
+	if result.Err != nil {
+		t.Fatalf("a failed hook must not be a contract error: %v", result.Err)
+	}
+	if result.WorktreePath == "" {
+		t.Error("the worktree must still be reported after a failed hook")
+	}
+	phase := postInstallPhase(t, result.Phases)
+	if phase.Steps[0].Status != progress.StepFailed || phase.Steps[1].Status != progress.StepDone {
+		t.Fatalf("want failed then done, got %+v", phase.Steps)
+	}
+}
+
+func TestRun_PostInstallSkippedWhenEcosystemInstallFails(t *testing.T) {
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[show-ref --verify refs/heads/feature/hooks]":             {Err: errors.New("not found")},
+		"/repo:[worktree add /repo/feature-hooks -b feature/hooks main]": {},
+		"/repo/feature-hooks:shell[pnpm install]":                        {Err: errors.New("registry unreachable")},
+		"/repo/feature-hooks:shell[go mod download]":                     {},
+		"/repo/feature-hooks:shell[go generate ./...]":                   {},
+	}}
+	opts := Options{
+		BranchName: "feature/hooks", BaseBranch: "main", RepoPath: "/repo",
+		Ecosystems: []config.EcosystemConfig{
+			{Name: "pnpm", Install: config.InstallConfig{Command: "pnpm install"}, PostInstall: []string{"prisma generate"}},
+			{Name: "go", Install: config.InstallConfig{Command: "go mod download"}, PostInstall: []string{"go generate ./..."}},
+		},
+	}
+
+	result := Run(runner, runner, opts, func(progress.Event) {})
+
+	if slices.Contains(runner.Calls, "/repo/feature-hooks:shell[prisma generate]") {
+		t.Fatal("a hook must not run against a failed install")
+	}
+	phase := postInstallPhase(t, result.Phases)
+	if phase.Steps[0].Status != progress.StepSkipped {
+		t.Errorf("pnpm hook status = %v, want skipped", phase.Steps[0].Status)
+	}
+	if phase.Steps[1].Status != progress.StepDone {
+		t.Errorf("go hook status = %v, want done", phase.Steps[1].Status)
+	}
+}
@@ -4,13 +4,10 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"sort"
 	"strings"
 	"sync"
 
-	"github.com/abiswas97/sentei/internal/fileutil"
+	"github.com/abiswas97/sentei/internal/config"
 	"github.com/abiswas97/sentei/internal/git"
 	"github.com/abiswas97/sentei/internal/integration"
 	"github.com/abiswas97/sentei/internal/progress"
@@ -19,6 +16,7 @@ import (
 const (
 	setupPhaseID        progress.PhaseID = "setup"
 	dependenciesPhaseID progress.PhaseID = "dependencies"
+	postInstallPhaseID  progress.PhaseID = "post-install"
 	integrationsPhaseID progress.PhaseID = "integrations"
 	maxDepsConcurrency                   = 5
 )
@@ -31,53 +29,117 @@ type preparedDependency struct {
 	ecosystem string
 }
 
+// preparedPostInstall is one post_install command frozen for one target: the
+// worktree root, or a workspace when the command uses the {dir} placeholder.
+type preparedPostInstall struct {
+	stepID    progress.StepID
+	label     string
+	command   string
+	ecosystem string
+}
+
+// creationPhases names one creation's phases. A single creation uses the
+// bare IDs and labels; a batch prefixes them with the entry's branch so each
+// worktree's phases stay distinct in the combined plan.
+type creationPhases struct {
+	prefix string
+}
+
+func (c creationPhases) id(phase progress.PhaseID) progress.PhaseID {
+	if c.prefix == "" {
+		return phase
+	}
+	return progress.PhaseID(c.prefix + "/" + string(phase))
+}
+
+func (c creationPhases) label(label string) string {
+	if c.prefix == "" {
+		return label
+	}
+	return c.prefix + ": " + label
+}
+
 type preparedCreation struct {
 	opts            Options
+	phases          creationPhases
 	plan            progress.Plan
 	worktreePath    string
 	createStepID    progress.StepID
 	mergeStepID     progress.StepID
 	envStepID       progress.StepID
-	envFiles        []string
+	envFiles        []preparedEnvFile
 	dependencies    []preparedDependency
+	postInstalls    []preparedPostInstall
 	integrations    integration.PreparedApply
 	hasDependencies bool
+	hasPostInstalls bool
 	hasIntegrations bool
 }
 
 func prepareCreation(runner git.CommandRunner, shell git.ShellRunner, opts Options) (preparedCreation, error) {
+	return prepareCreationIn(runner, shell, opts, creationPhases{}, "")
+}
+
+// prepareCreationIn prepares one creation under phases. treeRef, when set,
+// is read for workspace manifests instead of the base branch: a batch
+// passes it for a base branch that an earlier entry has yet to create.
+func prepareCreationIn(runner git.CommandRunner, shell git.ShellRunner, opts Options, phases creationPhases, treeRef string) (preparedCreation, error) {
+	if opts.Source != nil {
+		if strings.TrimSpace(opts.BranchName) == "" {
+			opts.BranchName = opts.Source.Branch
+		}
+		if strings.TrimSpace(opts.RepoPath) != "" {
+			// The fetch is the one change made before progress starts: the
+			// workspace manifests are read from the fetched tree below.
+			if err := fetchSource(runner, opts.RepoPath, *opts.Source); err != nil {
+				return preparedCreation{}, fmt.Errorf("preparing worktree creation: %w", err)
+			}
+			opts.BaseBranch = opts.Source.TrackingRef()
+			opts.MergeBase = false
+		}
+	}
 	if strings.TrimSpace(opts.BranchName) == "" || strings.TrimSpace(opts.BaseBranch) == "" || strings.TrimSpace(opts.RepoPath) == "" {
 		return preparedCreation{}, errors.New("preparing worktree creation: branch, base branch, and repository path are required")
 	}
 	if err := validateEcosystemIdentities(opts); err != nil {
 		return preparedCreation{}, fmt.Errorf("preparing worktree creation: %w", err)
 	}
-	targets, err := prepareDependencyTargets(runner, opts)
+	treeOpts := opts
+	if treeRef != "" {
+		treeOpts.BaseBranch = treeRef
+	}
+	targets, err := prepareDependencyTargets(runner, treeOpts)
 	if err != nil {
 		return preparedCreation{}, err
 	}
 
 	prepared := preparedCreation{
-		opts: opts, worktreePath: git.WorktreePath(opts.RepoPath, opts.BranchName),
+		opts: opts, phases: phases, worktreePath: git.WorktreePath(opts.RepoPath, opts.BranchName),
 		createStepID: semanticStepID("create-worktree", opts.BranchName),
 	}
-	setup := progress.PlannedPhase{ID: setupPhaseID, Label: "Setup"}
+	setup := progress.PlannedPhase{ID: phases.id(setupPhaseID), Label: phases.label("Setup")}
 	setup.Steps = append(setup.Steps, progress.PlannedStep{ID: prepared.createStepID, Label: "Create worktree"})
 	if opts.MergeBase {
 		prepared.mergeStepID = semanticStepID("merge-base", opts.BaseBranch)
 		setup.Steps = append(setup.Steps, progress.PlannedStep{ID: prepared.mergeStepID, Label: "Merge base branch"})
 	}
 	if opts.CopyEnvFiles {
-		prepared.envFiles = uniqueEnvFiles(opts)
+		if prepared.envFiles, err = uniqueEnvFiles(opts); err != nil {
+			return preparedCreation{}, fmt.Errorf("preparing worktree creation: %w", err)
+		}
 		if len(prepared.envFiles) > 0 {
-			prepared.envStepID = semanticStepID("copy-env", strings.Join(prepared.envFiles, "\x00"))
-			setup.Steps = append(setup.Steps, progress.PlannedStep{ID: prepared.envStepID, Label: "Copy env files"})
+			names := make([]string, len(prepared.envFiles))
+			for i, f := range prepared.envFiles {
+				names[i] = f.name
+			}
+			prepared.envStepID = semanticStepID("copy-env", strings.Join(names, "\x00"))
+			setup.Steps = append(setup.Steps, progress.PlannedStep{ID: prepared.envStepID, Label: envStepLabel(prepared.envFiles)})
 		}
 	}
 	prepared.plan.Phases = append(prepared.plan.Phases, setup)
 
 	seenDependencies := map[string]bool{}
-	dependencyPhase := progress.PlannedPhase{ID: dependenciesPhaseID, Label: "Dependencies"}
+	dependencyPhase := progress.PlannedPhase{ID: phases.id(dependenciesPhaseID), Label: phases.label("Dependencies")}
 	for _, target := range targets {
 		command := strings.TrimSpace(target.ecosystem.Install.Command)
 		label := target.ecosystem.Name
@@ -105,6 +167,16 @@ func prepareCreation(runner git.CommandRunner, shell git.ShellRunner, opts Optio
 		prepared.plan.Phases = append(prepared.plan.Phases, dependencyPhase)
 	}
 
+	postInstallPhase := progress.PlannedPhase{ID: phases.id(postInstallPhaseID), Label: phases.label("Post-install")}
+	for _, operation := range preparePostInstalls(opts.Ecosystems, targets) {
+		prepared.postInstalls = append(prepared.postInstalls, operation)
+		postInstallPhase.Steps = append(postInstallPhase.Steps, progress.PlannedStep{ID: operation.stepID, Label: operation.label})
+	}
+	if len(postInstallPhase.Steps) > 0 {
+		prepared.hasPostInstalls = true
+		prepared.plan.Phases = append(prepared.plan.Phases, postInstallPhase)
+	}
+
 	if len(opts.Integrations) > 0 {
 		probeDir := opts.SourceWorktree
 		if probeDir == "" {
@@ -114,7 +186,7 @@ func prepareCreation(runner git.CommandRunner, shell git.ShellRunner, opts Optio
 		if err != nil {
 			return preparedCreation{}, err
 		}
-		apply, err = apply.BindPhase(integrationsPhaseID, "Integrations")
+		apply, err = apply.BindPhase(phases.id(integrationsPhaseID), phases.label("Integrations"))
 		if err != nil {
 			return preparedCreation{}, err
 		}
@@ -127,6 +199,49 @@ func prepareCreation(runner git.CommandRunner, shell git.ShellRunner, opts Optio
 	return prepared, nil
 }
 
+// preparePostInstalls expands each ecosystem's post_install commands into
+// steps, in ecosystem then declaration order. A command containing {dir}
+// runs once per workspace install target with {dir} substituted (like
+// workspace_install); any other command runs once from the worktree root.
+func preparePostInstalls(ecosystems []config.EcosystemConfig, targets []dependencyTarget) []preparedPostInstall {
+	workspaces := map[string][]string{}
+	for _, target := range targets {
+		if target.workspace != "" {
+			workspaces[target.ecosystem.Name] = append(workspaces[target.ecosystem.Name], target.workspace)
+		}
+	}
+	var operations []preparedPostInstall
+	for _, ecosystem := range ecosystems {
+		for i, raw := range ecosystem.PostInstall {
+			command := strings.TrimSpace(raw)
+			if command == "" {
+				continue
+			}
+			dirs := []string{""}
+			if strings.Contains(command, "{dir}") {
+				dirs = workspaces[ecosystem.Name]
+				if len(dirs) == 0 {
+					dirs = []string{"."}
+				}
+			}
+			for _, dir := range dirs {
+				resolved := command
+				label := fmt.Sprintf("%s: %s", ecosystem.Name, command)
+				if dir != "" {
+					resolved = strings.ReplaceAll(command, "{dir}", dir)
+					label = fmt.Sprintf("%s (%s): %s", ecosystem.Name, dir, resolved)
+				}
+				identity := fmt.Sprintf("%s\x00%d\x00%s", ecosystem.Name, i, dir)
+				operations = append(operations, preparedPostInstall{
+					stepID: semanticStepID("post-install", identity), label: label,
+					command: resolved, ecosystem: ecosystem.Name,
+				})
+			}
+		}
+	}
+	return operations
+}
+
 func validateEcosystemIdentities(opts Options) error {
 	seen := make(map[string]bool, len(opts.Ecosystems))
 	for _, ecosystem := range opts.Ecosystems {
@@ -142,46 +257,66 @@ func validateEcosystemIdentities(opts Options) error {
 	return nil
 }
 
-func uniqueEnvFiles(opts Options) []string {
-	seen := map[string]bool{}
-	var files []string
-	for _, ecosystem := range opts.Ecosystems {
-		for _, name := range ecosystem.EnvFiles {
-			name = filepath.Clean(strings.TrimSpace(name))
-			if name == "." || filepath.IsAbs(name) || strings.HasPrefix(name, ".."+string(filepath.Separator)) || seen[name] {
-				continue
-			}
-			seen[name] = true
-			files = append(files, name)
-		}
+func (p preparedCreation) run(execution *progress.Execution, runner git.CommandRunner, shell git.ShellRunner, result *Result) error {
+	created, err := p.runCreate(execution, runner, result)
+	if err != nil || !created {
+		return err
 	}
-	sort.Strings(files)
-	return files
+	return p.runAfterCreate(execution, runner, shell, result)
 }
 
-func (p preparedCreation) run(execution *progress.Execution, runner git.CommandRunner, shell git.ShellRunner, result *Result) error {
-	createResult, err := execution.Run(setupPhaseID, p.createStepID, func() (string, error) {
+// addMu serializes worktree additions within a process. A batch's
+// concurrent creations otherwise race on the repository's config.lock,
+// which worktree add and the upstream config writes both take.
+var addMu sync.Mutex
+
+// runCreate adds the worktree. When that fails, every other step is skipped
+// and created is false.
+func (p preparedCreation) runCreate(execution *progress.Execution, runner git.CommandRunner, result *Result) (created bool, err error) {
+	createResult, err := execution.Run(p.phases.id(setupPhaseID), p.createStepID, func() (string, error) {
+		addMu.Lock()
+		defer addMu.Unlock()
+
 		args := []string{"worktree", "add", p.worktreePath}
-		if git.BranchExists(runner, p.opts.RepoPath, p.opts.BranchName) {
+		exists := git.BranchExists(runner, p.opts.RepoPath, p.opts.BranchName)
+		switch {
+		case exists:
 			args = append(args, p.opts.BranchName)
-		} else {
+		case p.opts.Source != nil:
+			// Any upstream is set explicitly below; git's automatic setup
+			// would miss refs outside the remote's fetch refspec.
+			args = append(args, "--no-track", "-b", p.opts.BranchName, p.opts.BaseBranch)
+		default:
 			args = append(args, "-b", p.opts.BranchName, p.opts.BaseBranch)
 		}
 		if _, err := runner.Run(p.opts.RepoPath, args...); err != nil {
 			return "", fmt.Errorf("creating worktree: %w", err)
 		}
+		if p.opts.Source != nil && p.opts.Source.Track {
+			section := "branch." + p.opts.BranchName
+			if _, err := runner.Run(p.opts.RepoPath, "config", section+".remote", p.opts.Source.Remote); err != nil {
+				return "", fmt.Errorf("setting upstream: %w", err)
+			}
+			if _, err := runner.Run(p.opts.RepoPath, "config", section+".merge", p.opts.Source.Ref); err != nil {
+				return "", fmt.Errorf("setting upstream: %w", err)
+			}
+		}
 		return p.worktreePath, nil
 	})
 	if err != nil {
-		return fmt.Errorf("executing worktree creation: %w", err)
+		return false, fmt.Errorf("executing worktree creation: %w", err)
 	}
 	if createResult.Status == progress.StepFailed {
-		return p.skipBlocked(execution, "blocked by Create worktree")
+		return false, p.skipBlocked(execution, "blocked by Create worktree")
 	}
 	result.WorktreePath = p.worktreePath
+	return true, nil
+}
 
+// runAfterCreate runs everything that needs the new worktree.
+func (p preparedCreation) runAfterCreate(execution *progress.Execution, runner git.CommandRunner, shell git.ShellRunner, result *Result) error {
 	if p.mergeStepID != "" {
-		_, err := execution.Run(setupPhaseID, p.mergeStepID, func() (string, error) {
+		_, err := execution.Run(p.phases.id(setupPhaseID), p.mergeStepID, func() (string, error) {
 			_, err := runner.Run(p.worktreePath, "merge", p.opts.BaseBranch, "--no-edit")
 			return "", err
 		})
@@ -190,17 +325,25 @@ func (p preparedCreation) run(execution *progress.Execution, runner git.CommandR
 		}
 	}
 	if p.envStepID != "" {
-		_, err := execution.Run(setupPhaseID, p.envStepID, func() (string, error) {
-			return copyPreparedEnvFiles(p.opts.SourceWorktree, p.worktreePath, p.envFiles)
+		_, err := execution.Run(p.phases.id(setupPhaseID), p.envStepID, func() (string, error) {
+			files, err := p.setUpEnvFiles(runner)
+			result.EnvFiles = files
+			if err != nil {
+				return "", err
+			}
+			return envFilesMessage(files), nil
 		})
 		if err != nil {
-			return fmt.Errorf("executing env copy: %w", err)
+			return fmt.Errorf("executing env setup: %w", err)
 		}
 	}
 	var runErr error
 	if p.hasDependencies {
 		runErr = errors.Join(runErr, p.runDependencies(execution, shell))
 	}
+	if p.hasPostInstalls {
+		runErr = errors.Join(runErr, p.runPostInstalls(execution, shell))
+	}
 	if p.hasIntegrations {
 		runErr = errors.Join(runErr, p.integrations.RunIn(execution, shell))
 	}
@@ -209,12 +352,15 @@ func (p preparedCreation) run(execution *progress.Execution, runner git.CommandR
 
 func (p preparedCreation) skipBlocked(execution *progress.Execution, reason string) error {
 	var err error
-	err = errors.Join(err, execution.SkipPending(setupPhaseID, reason))
+	err = errors.Join(err, execution.SkipPending(p.phases.id(setupPhaseID), reason))
 	if p.hasDependencies {
-		err = errors.Join(err, execution.SkipPending(dependenciesPhaseID, reason))
+		err = errors.Join(err, execution.SkipPending(p.phases.id(dependenciesPhaseID), reason))
+	}
+	if p.hasPostInstalls {
+		err = errors.Join(err, execution.SkipPending(p.phases.id(postInstallPhaseID), reason))
 	}
 	if p.hasIntegrations {
-		err = errors.Join(err, execution.SkipPending(integrationsPhaseID, reason))
+		err = errors.Join(err, execution.SkipPending(p.phases.id(integrationsPhaseID), reason))
 	}
 	return err
 }
@@ -232,7 +378,7 @@ func (p preparedCreation) runDependencies(execution *progress.Execution, shell g
 	for _, group := range groups {
 		if len(group) == 1 || !group[0].parallel {
 			for _, dependency := range group {
-				runErr = errors.Join(runErr, runPreparedDependency(execution, shell, p.worktreePath, dependency))
+				runErr = errors.Join(runErr, runPreparedDependency(execution, shell, p.phases.id(dependenciesPhaseID), p.worktreePath, dependency))
 			}
 			continue
 		}
@@ -246,7 +392,7 @@ func (p preparedCreation) runDependencies(execution *progress.Execution, shell g
 				defer wg.Done()
 				sem <- struct{}{}
 				defer func() { <-sem }()
-				err := runPreparedDependency(execution, shell, p.worktreePath, dependency)
+				err := runPreparedDependency(execution, shell, p.phases.id(dependenciesPhaseID), p.worktreePath, dependency)
 				if err != nil {
 					mu.Lock()
 					runErr = errors.Join(runErr, err)
@@ -259,8 +405,8 @@ func (p preparedCreation) runDependencies(execution *progress.Execution, shell g
 	return runErr
 }
 
-func runPreparedDependency(execution *progress.Execution, shell git.ShellRunner, worktreePath string, dependency preparedDependency) error {
-	_, err := execution.Run(dependenciesPhaseID, dependency.stepID, func() (string, error) {
+func runPreparedDependency(execution *progress.Execution, shell git.ShellRunner, phaseID progress.PhaseID, worktreePath string, dependency preparedDependency) error {
+	_, err := execution.Run(phaseID, dependency.stepID, func() (string, error) {
 		_, err := shell.RunShell(worktreePath, dependency.command)
 		if err != nil {
 			return "", fmt.Errorf("installing %s: %w", dependency.label, err)
@@ -273,23 +419,48 @@ func runPreparedDependency(execution *progress.Execution, shell git.ShellRunner,
 	return nil
 }
 
-func copyPreparedEnvFiles(source, destination string, files []string) (string, error) {
-	var copied []string
-	for _, name := range files {
-		if _, err := os.Stat(filepath.Join(source, name)); errors.Is(err, os.ErrNotExist) {
+// runPostInstalls runs hooks sequentially in declaration order: codegen
+// commonly depends on an earlier hook's output. A hook whose ecosystem had a
+// failed install is skipped rather than run against missing dependencies;
+// hook failures are recorded on their step and never block later steps.
+func (p preparedCreation) runPostInstalls(execution *progress.Execution, shell git.ShellRunner) error {
+	failedInstalls := map[string]bool{}
+	failedSteps := map[progress.StepID]bool{}
+	for _, phase := range execution.Phases() {
+		if phase.ID != p.phases.id(dependenciesPhaseID) {
 			continue
-		} else if err != nil {
-			return "", fmt.Errorf("inspecting %s: %w", name, err)
 		}
-		if err := fileutil.CopyFile(filepath.Join(source, name), filepath.Join(destination, name)); err != nil {
-			return "", fmt.Errorf("copying %s: %w", name, err)
+		for _, step := range phase.Steps {
+			if step.Status == progress.StepFailed {
+				failedSteps[step.ID] = true
+			}
 		}
-		copied = append(copied, name)
 	}
-	if len(copied) == 0 {
-		return "no source files found", nil
+	for _, dependency := range p.dependencies {
+		if failedSteps[dependency.stepID] {
+			failedInstalls[dependency.ecosystem] = true
+		}
 	}
-	return strings.Join(copied, ", "), nil
+
+	var runErr error
+	for _, hook := range p.postInstalls {
+		if failedInstalls[hook.ecosystem] {
+			if _, err := execution.Skip(p.phases.id(postInstallPhaseID), hook.stepID, "blocked by failed "+hook.ecosystem+" install"); err != nil {
+				runErr = errors.Join(runErr, fmt.Errorf("skipping post-install %s: %w", hook.label, err))
+			}
+			continue
+		}
+		_, err := execution.Run(p.phases.id(postInstallPhaseID), hook.stepID, func() (string, error) {
+			if _, err := shell.RunShell(p.worktreePath, hook.command); err != nil {
+				return "", fmt.Errorf("running %s: %w", hook.label, err)
+			}
+			return "", nil
+		})
+		if err != nil {
+			runErr = errors.Join(runErr, fmt.Errorf("executing post-install %s: %w", hook.label, err))
+		}
+	}
+	return runErr
 }
 
 func semanticStepID(kind, identity string) progress.StepID {
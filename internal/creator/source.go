@@ -0,0 +1,131 @@
+package creator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/pr"
+	"github.com/abiswas97/sentei/internal/repo"
+)
+
+// RemoteSource is a ref on a remote that a new worktree's branch starts
+// from instead of Options.BaseBranch: a remote branch or a pull request head.
+type RemoteSource struct {
+	Remote string // remote name, e.g. "origin"
+	Ref    string // ref on Remote: "refs/heads/feature-x" or "refs/pull/42/head"
+	// Track makes Ref the new branch's upstream. It is false for a fork's
+	// pull request, whose branch cannot be pushed to as-is.
+	Track  bool
+	Branch string // local branch name used when Options.BranchName is empty
+	Label  string // how the source is shown: "origin/feature-x", "PR #42"
+}
+
+// TrackingRef is the local ref the fetched head is stored under:
+// refs/remotes/origin/feature-x for a branch, refs/sentei/pull/42 for a pull
+// request head. A pull request head is not a branch of the remote, so under
+// refs/remotes it would show in git branch -r and the next fetch --prune
+// would delete it.
+func (s RemoteSource) TrackingRef() string {
+	if branch, ok := strings.CutPrefix(s.Ref, "refs/heads/"); ok {
+		return "refs/remotes/" + s.Remote + "/" + branch
+	}
+	return "refs/sentei/" + strings.TrimSuffix(strings.TrimPrefix(s.Ref, "refs/"), "/head")
+}
+
+// TrackSource resolves "remote/branch", as given to create --track, against
+// the repository's remotes. The longest matching remote name wins, since
+// remote names may contain slashes.
+func TrackSource(runner git.CommandRunner, repoPath, spec string) (RemoteSource, error) {
+	remotes, err := listRemotes(runner, repoPath)
+	if err != nil {
+		return RemoteSource{}, err
+	}
+	remote := ""
+	for _, name := range remotes {
+		if strings.HasPrefix(spec, name+"/") && len(name) > len(remote) {
+			remote = name
+		}
+	}
+	if remote == "" {
+		return RemoteSource{}, fmt.Errorf("%q does not name a remote branch (remotes: %s)", spec, strings.Join(remotes, ", "))
+	}
+	branch := strings.TrimPrefix(spec, remote+"/")
+	if branch == "" {
+		return RemoteSource{}, fmt.Errorf("%q does not name a remote branch", spec)
+	}
+	return RemoteSource{Remote: remote, Ref: "refs/heads/" + branch, Track: true, Branch: branch, Label: spec}, nil
+}
+
+// PullRequestSource resolves pull request number through gh. A pull request
+// from a branch of this repository tracks that branch; one from a fork is
+// fetched from refs/pull/<number>/head onto a local pr-<number> branch with
+// no upstream.
+func PullRequestSource(runner git.CommandRunner, gh repo.GhRunner, repoPath string, number int) (RemoteSource, error) {
+	if number <= 0 {
+		return RemoteSource{}, fmt.Errorf("invalid pull request number %d", number)
+	}
+	remotes, err := listRemotes(runner, repoPath)
+	if err != nil {
+		return RemoteSource{}, err
+	}
+	remote := ""
+	for _, name := range remotes {
+		if name == "origin" || remote == "" {
+			remote = name
+		}
+	}
+	if remote == "" {
+		return RemoteSource{}, fmt.Errorf("pull request #%d: repository has no remote", number)
+	}
+	head, err := pr.ResolveHead(gh, repoPath, number)
+	if err != nil {
+		return RemoteSource{}, fmt.Errorf("resolving pull request #%d: %w", number, err)
+	}
+	if head.CrossRepository {
+		return RemoteSource{
+			Remote: remote, Ref: fmt.Sprintf("refs/pull/%d/head", number),
+			Branch: fmt.Sprintf("pr-%d", number), Label: fmt.Sprintf("PR #%d", number),
+		}, nil
+	}
+	return RemoteSource{
+		Remote: remote, Ref: "refs/heads/" + head.Branch, Track: true,
+		Branch: head.Branch, Label: fmt.Sprintf("PR #%d (%s/%s)", number, remote, head.Branch),
+	}, nil
+}
+
+// ResolveSource resolves a remote branch spec or a pull request number,
+// whichever is set, and returns nil when neither is.
+func ResolveSource(runner git.CommandRunner, gh repo.GhRunner, repoPath, track string, prNumber int) (*RemoteSource, error) {
+	var source RemoteSource
+	var err error
+	switch {
+	case track != "":
+		source, err = TrackSource(runner, repoPath, track)
+	case prNumber != 0:
+		source, err = PullRequestSource(runner, gh, repoPath, prNumber)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+func listRemotes(runner git.CommandRunner, repoPath string) ([]string, error) {
+	out, err := runner.Run(repoPath, "remote")
+	if err != nil {
+		return nil, fmt.Errorf("listing remotes: %w", err)
+	}
+	return strings.Fields(out), nil
+}
+
+// fetchSource fetches the source's head into its tracking ref, forcing the
+// update so a rebased branch or force-pushed pull request is picked up.
+func fetchSource(runner git.CommandRunner, repoPath string, source RemoteSource) error {
+	if _, err := runner.Run(repoPath, "fetch", source.Remote, "+"+source.Ref+":"+source.TrackingRef()); err != nil {
+		return fmt.Errorf("fetching %s: %w", source.Label, err)
+	}
+	return nil
+}
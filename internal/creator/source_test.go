@@ -0,0 +1,119 @@
+package creator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/pr"
+	"github.com/abiswas97/sentei/internal/testutil/mock"
+)
+
+func TestTrackSource(t *testing.T) {
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[remote]": {Output: "origin\nteam/fork"},
+	}}
+	cases := []struct {
+		spec    string
+		want    RemoteSource
+		wantErr bool
+	}{
+		{spec: "origin/feature-x", want: RemoteSource{Remote: "origin", Ref: "refs/heads/feature-x", Track: true, Branch: "feature-x", Label: "origin/feature-x"}},
+		{spec: "origin/feature/x", want: RemoteSource{Remote: "origin", Ref: "refs/heads/feature/x", Track: true, Branch: "feature/x", Label: "origin/feature/x"}},
+		{spec: "team/fork/fix", want: RemoteSource{Remote: "team/fork", Ref: "refs/heads/fix", Track: true, Branch: "fix", Label: "team/fork/fix"}},
+		{spec: "feature-x", wantErr: true},
+		{spec: "upstream/main", wantErr: true},
+		{spec: "origin/", wantErr: true},
+	}
+	for _, tc := range cases {
+		got, err := TrackSource(runner, "/repo", tc.spec)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("TrackSource(%q) = %+v, want an error", tc.spec, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("TrackSource(%q) = %+v, %v; want %+v", tc.spec, got, err, tc.want)
+		}
+	}
+}
+
+func TestPullRequestSource(t *testing.T) {
+	view := func(n string) string { return "/repo:gh[pr view " + n + " --json headRefName,isCrossRepository]" }
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[remote]": {Output: "mirror\norigin"},
+		view("42"):       {Output: `{"headRefName":"feature/x","isCrossRepository":false}`},
+		view("7"):        {Output: `{"headRefName":"main","isCrossRepository":true}`},
+		view("9"):        {Err: errors.New("not found")},
+	}}
+
+	src, err := PullRequestSource(runner, runner, "/repo", 42)
+	want := RemoteSource{Remote: "origin", Ref: "refs/heads/feature/x", Track: true, Branch: "feature/x", Label: "PR #42 (origin/feature/x)"}
+	if err != nil || src != want {
+		t.Errorf("same-repo PR = %+v, %v; want %+v", src, err, want)
+	}
+
+	src, err = PullRequestSource(runner, runner, "/repo", 7)
+	want = RemoteSource{Remote: "origin", Ref: "refs/pull/7/head", Branch: "pr-7", Label: "PR #7"}
+	if err != nil || src != want {
+		t.Errorf("fork PR = %+v, %v; want %+v", src, err, want)
+	}
+	if got := src.TrackingRef(); got != "refs/sentei/pull/7" {
+		t.Errorf("fork TrackingRef = %q", got)
+	}
+
+	if _, err := PullRequestSource(runner, runner, "/repo", 9); !errors.Is(err, pr.ErrUnavailable) {
+		t.Errorf("unresolvable PR error = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestRun_RemoteSourceFetchesAndSetsUpstream(t *testing.T) {
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[fetch origin +refs/heads/feature-x:refs/remotes/origin/feature-x]":                   {},
+		"/repo:[show-ref --verify refs/heads/feature-x]":                                             {Err: fmt.Errorf("not found")},
+		"/repo:[worktree add /repo/feature-x --no-track -b feature-x refs/remotes/origin/feature-x]": {},
+		"/repo:[config branch.feature-x.remote origin]":                                              {},
+		"/repo:[config branch.feature-x.merge refs/heads/feature-x]":                                 {},
+	}}
+	source := RemoteSource{Remote: "origin", Ref: "refs/heads/feature-x", Track: true, Branch: "feature-x", Label: "origin/feature-x"}
+
+	result := Run(runner, runner, Options{RepoPath: "/repo", Source: &source, MergeBase: true}, nil)
+
+	if result.HasFailures() {
+		t.Fatalf("Run failed: %v %#v", result.Err, result.Phases)
+	}
+	if result.WorktreePath != "/repo/feature-x" {
+		t.Errorf("WorktreePath = %q", result.WorktreePath)
+	}
+	if steps := result.Phases[0].Steps; len(steps) != 1 {
+		t.Errorf("setup steps = %#v, want only Create worktree (merge base is ignored)", steps)
+	}
+}
+
+func TestRun_ForkSourceHasNoUpstreamAndFetchFailureStopsEarly(t *testing.T) {
+	source := RemoteSource{Remote: "origin", Ref: "refs/pull/7/head", Branch: "pr-7", Label: "PR #7"}
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[fetch origin +refs/pull/7/head:refs/sentei/pull/7]":                 {},
+		"/repo:[show-ref --verify refs/heads/review]":                               {Err: fmt.Errorf("not found")},
+		"/repo:[worktree add /repo/review --no-track -b review refs/sentei/pull/7]": {},
+	}}
+	result := Run(runner, runner, Options{BranchName: "review", RepoPath: "/repo", Source: &source}, nil)
+	if result.HasFailures() {
+		t.Fatalf("Run failed: %v %#v", result.Err, result.Phases)
+	}
+	for _, call := range runner.Calls {
+		if strings.Contains(call, "[config ") {
+			t.Errorf("fork PR set an upstream: %s", call)
+		}
+	}
+
+	failing := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[fetch origin +refs/pull/7/head:refs/sentei/pull/7]": {Err: errors.New("couldn't find remote ref")},
+	}}
+	result = Run(failing, failing, Options{RepoPath: "/repo", Source: &source}, nil)
+	if result.Err == nil || len(result.Phases) != 0 {
+		t.Errorf("fetch failure = %v with phases %#v, want an error before any phase", result.Err, result.Phases)
+	}
+}
@@ -0,0 +1,327 @@
+// Package doctor diagnoses the ways sentei's bare layout breaks — a stale
+// .git pointer, a missing fetch refspec, worktrees that lost their links —
+// and repairs the ones it can.
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/fileutil"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/integration"
+)
+
+// Status grades one check.
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// OriginRefspec is the fetch refspec clone and migrate give origin, so
+// remote branches land in refs/remotes/origin.
+const OriginRefspec = "+refs/heads/*:refs/remotes/origin/*"
+
+// Check is the outcome of one diagnosis. Fix describes the repair Repair
+// would run and is empty when there is none; Hint is what to do by hand.
+type Check struct {
+	ID       string
+	Name     string
+	Status   Status
+	Detail   string
+	Problems []string
+	Fix      string
+	Hint     string
+	repair   func(git.CommandRunner) (string, error)
+}
+
+// Fixable reports whether Repair can act on the check.
+func (c Check) Fixable() bool { return c.repair != nil }
+
+// Report is every check run against one repository.
+type Report struct {
+	Root   string
+	Checks []Check
+}
+
+// Worst returns the most severe status among the checks.
+func (r Report) Worst() Status {
+	worst := StatusOK
+	for _, c := range r.Checks {
+		switch {
+		case c.Status == StatusFail:
+			return StatusFail
+		case c.Status == StatusWarn:
+			worst = StatusWarn
+		}
+	}
+	return worst
+}
+
+// Fixable returns the checks Repair can act on, in report order.
+func (r Report) Fixable() []Check {
+	var fixable []Check
+	for _, c := range r.Checks {
+		if c.Fixable() {
+			fixable = append(fixable, c)
+		}
+	}
+	return fixable
+}
+
+// FindRoot returns the directory holding .bare for path: path itself, or
+// the root git resolves from inside a worktree.
+func FindRoot(runner git.CommandRunner, path string) (string, error) {
+	candidates := []string{path}
+	if commonDir, err := git.CommonDir(runner, path); err == nil {
+		candidates = append(candidates, filepath.Dir(commonDir))
+	}
+	for _, root := range candidates {
+		if info, err := os.Stat(filepath.Join(root, ".bare")); err == nil && info.IsDir() {
+			return root, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not in sentei's bare layout (no .bare directory)", path)
+}
+
+// Diagnose runs every check against the layout at root. Git runs inside
+// .bare, so a broken pointer does not hide the other problems. enabled are
+// the integrations whose dependencies should be present.
+func Diagnose(runner git.CommandRunner, shell integration.DepDetector, root string, enabled []integration.Integration) (Report, error) {
+	barePath := filepath.Join(root, ".bare")
+	report := Report{Root: root}
+	report.Checks = append(report.Checks, checkPointer(root), checkRefspec(runner, barePath))
+
+	wts, err := git.ListWorktrees(runner, barePath)
+	if err != nil {
+		return report, fmt.Errorf("listing worktrees: %w", err)
+	}
+	links, stale := checkWorktrees(root, barePath, wts)
+	report.Checks = append(report.Checks, links, stale, checkDependencies(shell, enabled))
+	return report, nil
+}
+
+func checkPointer(root string) Check {
+	c := Check{ID: "pointer", Name: ".git pointer"}
+	pointer := filepath.Join(root, ".git")
+	rewrite := func(git.CommandRunner) (string, error) {
+		if err := os.RemoveAll(pointer); err != nil {
+			return "", err
+		}
+		return "gitdir: .bare", os.WriteFile(pointer, []byte("gitdir: .bare\n"), 0644)
+	}
+
+	info, err := os.Lstat(pointer)
+	switch {
+	case os.IsNotExist(err):
+		c.Status, c.Detail = StatusFail, "missing"
+	case err != nil:
+		c.Status, c.Detail = StatusFail, err.Error()
+		return c
+	case info.IsDir():
+		// A .git directory holds a repository of its own; never replace it.
+		c.Status, c.Detail = StatusFail, ".git is a directory, not a pointer to .bare"
+		c.Hint = "move .git aside, then run sentei doctor --fix"
+		return c
+	default:
+		data, err := os.ReadFile(pointer)
+		if err != nil {
+			c.Status, c.Detail = StatusFail, err.Error()
+			return c
+		}
+		target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+		target = strings.TrimSpace(target)
+		if !ok {
+			c.Status, c.Detail = StatusFail, "has no gitdir line"
+			break
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(root, target)
+		}
+		if fileutil.SamePath(target, filepath.Join(root, ".bare")) {
+			c.Status, c.Detail = StatusOK, "points to .bare"
+			return c
+		}
+		c.Status, c.Detail = StatusFail, "points to "+target
+	}
+	c.Fix, c.repair = "write gitdir: .bare", rewrite
+	return c
+}
+
+func checkRefspec(runner git.CommandRunner, barePath string) Check {
+	c := Check{ID: "refspec", Name: "Origin fetch refspec"}
+	if url, err := runner.Run(barePath, "config", "--get", "remote.origin.url"); err != nil || strings.TrimSpace(url) == "" {
+		c.Status, c.Detail = StatusOK, "no origin remote"
+		return c
+	}
+	refspecs, _ := runner.Run(barePath, "config", "--get-all", "remote.origin.fetch")
+	if strings.TrimSpace(refspecs) != "" {
+		c.Status, c.Detail = StatusOK, strings.Join(strings.Fields(refspecs), ", ")
+		return c
+	}
+	c.Status, c.Detail = StatusWarn, "missing; git fetch does not update origin's branches"
+	c.Fix = "add " + OriginRefspec
+	c.repair = func(runner git.CommandRunner) (string, error) {
+		_, err := runner.Run(barePath, "config", "--add", "remote.origin.fetch", OriginRefspec)
+		return "", err
+	}
+	return c
+}
+
+// checkWorktrees finds worktrees whose links are broken, and registrations
+// whose directory is gone. A worktree moved by hand shows up as both: its
+// old registration looks missing and its new directory links to it. The
+// repair reconnects it, so that registration is not offered for pruning.
+func checkWorktrees(root, barePath string, wts []git.Worktree) (links, stale Check) {
+	links = Check{ID: "worktree-links", Name: "Worktree links"}
+	stale = Check{ID: "stale-worktrees", Name: "Missing worktrees"}
+
+	adminsDir := filepath.Join(barePath, "worktrees")
+	listed := make(map[string]bool)
+	var broken []string
+	var missing []string
+	for _, wt := range wts {
+		if wt.IsBare {
+			continue
+		}
+		if wt.IsPrunable {
+			missing = append(missing, wt.Path)
+			continue
+		}
+		listed[fileutil.ResolvePath(wt.Path)] = true
+		if reason := linkProblem(wt.Path, adminsDir); reason != "" {
+			broken = append(broken, wt.Path)
+			links.Problems = append(links.Problems, wt.Path+": "+reason)
+		}
+	}
+
+	// Worktrees moved under the root are no longer listed at their new path.
+	moved := make(map[string]bool) // registered paths the moved worktrees answer for
+	entries, _ := os.ReadDir(root)
+	for _, entry := range entries {
+		path := filepath.Join(root, entry.Name())
+		if !entry.IsDir() || entry.Name() == ".bare" || listed[fileutil.ResolvePath(path)] {
+			continue
+		}
+		admin, _, ok := adminFor(path, adminsDir)
+		if !ok {
+			continue
+		}
+		recorded, _ := os.ReadFile(filepath.Join(admin, "gitdir"))
+		moved[fileutil.ResolvePath(filepath.Dir(strings.TrimSpace(string(recorded))))] = true
+		broken = append(broken, path)
+		links.Problems = append(links.Problems, path+": moved from "+filepath.Dir(strings.TrimSpace(string(recorded))))
+	}
+	missing = slices.DeleteFunc(missing, func(path string) bool { return moved[fileutil.ResolvePath(path)] })
+
+	if len(broken) == 0 {
+		links.Status, links.Detail = StatusOK, "all linked"
+	} else {
+		links.Status, links.Detail = StatusFail, fmt.Sprintf("%d broken", len(broken))
+		links.Fix = "git worktree repair"
+		links.repair = func(runner git.CommandRunner) (string, error) {
+			_, err := runner.Run(barePath, append([]string{"worktree", "repair"}, broken...)...)
+			return fmt.Sprintf("%d repaired", len(broken)), err
+		}
+	}
+
+	if len(missing) == 0 {
+		stale.Status, stale.Detail = StatusOK, "none"
+	} else {
+		stale.Status, stale.Detail = StatusWarn, fmt.Sprintf("%d registered but gone", len(missing))
+		stale.Problems = missing
+		stale.Fix = "git worktree prune"
+		stale.repair = func(runner git.CommandRunner) (string, error) {
+			_, err := runner.Run(barePath, "worktree", "prune")
+			return fmt.Sprintf("%d pruned", len(missing)), err
+		}
+	}
+	return links, stale
+}
+
+// linkProblem describes what is wrong with the links between a worktree and
+// its administrative directory, or returns "" when both point at each other.
+func linkProblem(path, adminsDir string) string {
+	admin, target, ok := adminFor(path, adminsDir)
+	if !ok {
+		return "its .git file does not point into .bare/worktrees"
+	}
+	if target != admin {
+		return "its .git file points to " + target
+	}
+	recorded, err := os.ReadFile(filepath.Join(admin, "gitdir"))
+	if err != nil {
+		return "its administrative directory has no gitdir"
+	}
+	if !fileutil.SamePath(strings.TrimSpace(string(recorded)), filepath.Join(path, ".git")) {
+		return "registered at " + filepath.Dir(strings.TrimSpace(string(recorded)))
+	}
+	return ""
+}
+
+// adminFor reads the worktree's .git file and returns the administrative
+// directory in adminsDir it belongs to, along with the gitdir the file
+// names. The two differ when the whole repository moved: the file still
+// names the old location's worktrees/<name>, which is matched by name.
+func adminFor(path, adminsDir string) (admin, target string, ok bool) {
+	data, err := os.ReadFile(filepath.Join(path, ".git"))
+	if err != nil {
+		return "", "", false
+	}
+	target, found := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !found {
+		return "", "", false
+	}
+	target = strings.TrimSpace(target)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(path, target)
+	}
+	if fileutil.SamePath(filepath.Dir(target), adminsDir) {
+		if _, err := os.Stat(target); err != nil {
+			return "", "", false
+		}
+		return target, target, true
+	}
+	if filepath.Base(filepath.Dir(target)) != "worktrees" {
+		return "", "", false
+	}
+	admin = filepath.Join(adminsDir, filepath.Base(target))
+	if _, err := os.Stat(admin); err != nil {
+		return "", "", false
+	}
+	return admin, target, true
+}
+
+func checkDependencies(shell integration.DepDetector, enabled []integration.Integration) Check {
+	c := Check{ID: "integration-deps", Name: "Integration dependencies"}
+	if len(enabled) == 0 {
+		c.Status, c.Detail = StatusOK, "no integrations enabled"
+		return c
+	}
+	present := integration.DetectDeps(shell, enabled)
+	var hints []string
+	for _, integ := range enabled {
+		for _, dep := range integ.Dependencies {
+			if present[dep.Name] || slices.Contains(c.Problems, dep.Name) {
+				continue
+			}
+			c.Problems = append(c.Problems, dep.Name)
+			if dep.Install != "" {
+				hints = append(hints, dep.Install)
+			}
+		}
+	}
+	if len(c.Problems) == 0 {
+		c.Status, c.Detail = StatusOK, "all present"
+		return c
+	}
+	c.Status, c.Detail = StatusWarn, fmt.Sprintf("%d missing", len(c.Problems))
+	c.Hint = strings.Join(hints, "; ")
+	return c
+}
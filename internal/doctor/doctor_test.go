@@ -0,0 +1,261 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/integration"
+	"github.com/abiswas97/sentei/internal/progress"
+)
+
+func mustGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// bareLayout builds sentei's bare layout with an origin remote and a
+// worktree per branch under the root.
+func bareLayout(t *testing.T, branches ...string) string {
+	t.Helper()
+	base := t.TempDir()
+	origin := filepath.Join(base, "origin")
+	mustGit(t, base, "init", "--initial-branch=main", origin)
+	mustGit(t, origin, "-c", "user.name=t", "-c", "user.email=t@t", "commit", "--allow-empty", "-m", "init")
+
+	root := filepath.Join(base, "repo")
+	bare := filepath.Join(root, ".bare")
+	mustGit(t, base, "clone", "--bare", origin, bare)
+	mustGit(t, bare, "config", "remote.origin.fetch", OriginRefspec)
+	if err := os.WriteFile(filepath.Join(root, ".git"), []byte("gitdir: .bare\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, branch := range branches {
+		args := []string{"worktree", "add", filepath.Join(root, branch)}
+		if branch != "main" {
+			args = append(args, "-b", branch)
+		} else {
+			args = append(args, "main")
+		}
+		mustGit(t, bare, args...)
+	}
+	return root
+}
+
+func diagnose(t *testing.T, root string) Report {
+	t.Helper()
+	report, err := Diagnose(&git.GitRunner{}, nil, root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func check(t *testing.T, report Report, id string) Check {
+	t.Helper()
+	for _, c := range report.Checks {
+		if c.ID == id {
+			return c
+		}
+	}
+	t.Fatalf("no %s check in %+v", id, report.Checks)
+	return Check{}
+}
+
+func TestDiagnose_HealthyLayout(t *testing.T) {
+	report := diagnose(t, bareLayout(t, "main", "feature"))
+	for _, c := range report.Checks {
+		if c.Status != StatusOK {
+			t.Errorf("%s = %s (%s) %v", c.ID, c.Status, c.Detail, c.Problems)
+		}
+	}
+	if len(report.Fixable()) != 0 {
+		t.Fatalf("healthy layout offers fixes: %+v", report.Fixable())
+	}
+}
+
+func TestDiagnoseAndRepair(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(t *testing.T, root string)
+		id     string
+		status Status
+	}{
+		{
+			name: "pointer to an old location",
+			damage: func(t *testing.T, root string) {
+				os.WriteFile(filepath.Join(root, ".git"), []byte("gitdir: /moved/away/.bare\n"), 0644)
+			},
+			id:     "pointer",
+			status: StatusFail,
+		},
+		{
+			name:   "missing pointer",
+			damage: func(t *testing.T, root string) { os.Remove(filepath.Join(root, ".git")) },
+			id:     "pointer",
+			status: StatusFail,
+		},
+		{
+			name: "missing refspec",
+			damage: func(t *testing.T, root string) {
+				mustGit(t, filepath.Join(root, ".bare"), "config", "--unset-all", "remote.origin.fetch")
+			},
+			id:     "refspec",
+			status: StatusWarn,
+		},
+		{
+			name: "worktree moved by hand",
+			damage: func(t *testing.T, root string) {
+				if err := os.Rename(filepath.Join(root, "feature"), filepath.Join(root, "renamed")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			id:     "worktree-links",
+			status: StatusFail,
+		},
+		{
+			name: "worktree deleted",
+			damage: func(t *testing.T, root string) {
+				os.RemoveAll(filepath.Join(root, "feature"))
+			},
+			id:     "stale-worktrees",
+			status: StatusWarn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := bareLayout(t, "main", "feature")
+			tt.damage(t, root)
+
+			report := diagnose(t, root)
+			broken := check(t, report, tt.id)
+			if broken.Status != tt.status || !broken.Fixable() {
+				t.Fatalf("%s = %s fixable=%v (%s)", tt.id, broken.Status, broken.Fixable(), broken.Detail)
+			}
+
+			phases, err := Repair(&git.GitRunner{}, report, func(progress.Event) {})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if progress.PhasesHaveFailures(phases) {
+				t.Fatalf("repair failed: %+v", phases)
+			}
+			for _, c := range diagnose(t, root).Checks {
+				if c.Status != StatusOK {
+					t.Errorf("after repair %s = %s (%s) %v", c.ID, c.Status, c.Detail, c.Problems)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagnose_MovedWorktreeIsNotOfferedForPruning(t *testing.T) {
+	root := bareLayout(t, "main", "feature")
+	if err := os.Rename(filepath.Join(root, "feature"), filepath.Join(root, "renamed")); err != nil {
+		t.Fatal(err)
+	}
+	report := diagnose(t, root)
+	if stale := check(t, report, "stale-worktrees"); stale.Status != StatusOK {
+		t.Fatalf("moved worktree offered for pruning: %v", stale.Problems)
+	}
+	links := check(t, report, "worktree-links")
+	if len(links.Problems) != 1 || !strings.Contains(links.Problems[0], "renamed") {
+		t.Fatalf("links problems = %v", links.Problems)
+	}
+}
+
+func TestDiagnose_RepositoryMoved(t *testing.T) {
+	root := bareLayout(t, "main", "feature")
+	moved := root + "-moved"
+	if err := os.Rename(root, moved); err != nil {
+		t.Fatal(err)
+	}
+
+	report := diagnose(t, moved)
+	if links := check(t, report, "worktree-links"); links.Status != StatusFail || len(links.Problems) != 2 {
+		t.Fatalf("links = %s %v", links.Status, links.Problems)
+	}
+	if stale := check(t, report, "stale-worktrees"); stale.Status != StatusOK {
+		t.Fatalf("moved worktrees offered for pruning: %v", stale.Problems)
+	}
+	if _, err := Repair(&git.GitRunner{}, report, func(progress.Event) {}); err != nil {
+		t.Fatal(err)
+	}
+	if after := diagnose(t, moved); after.Worst() != StatusOK {
+		t.Fatalf("after repair: %+v", after.Checks)
+	}
+	mustGit(t, filepath.Join(moved, "feature"), "status")
+}
+
+func TestCheckPointer_NeverReplacesGitDirectory(t *testing.T) {
+	root := bareLayout(t, "main")
+	os.Remove(filepath.Join(root, ".git"))
+	os.MkdirAll(filepath.Join(root, ".git"), 0755)
+
+	c := checkPointer(root)
+	if c.Status != StatusFail || c.Fixable() || c.Hint == "" {
+		t.Fatalf("pointer = %+v", c)
+	}
+}
+
+type shellFunc func(dir, command string) (string, error)
+
+func (fn shellFunc) RunShell(dir, command string) (string, error) { return fn(dir, command) }
+
+func TestCheckDependencies_HintsInstallCommands(t *testing.T) {
+	enabled := []integration.Integration{{
+		Name: "indexer",
+		Dependencies: []integration.Dependency{
+			{Name: "present", Detect: "present --version"},
+			{Name: "absent", Detect: "absent --version", Install: "brew install absent"},
+		},
+	}}
+	shell := shellFunc(func(_, command string) (string, error) {
+		if strings.HasPrefix(command, "absent") {
+			return "", errors.New("not found")
+		}
+		return "", nil
+	})
+
+	c := checkDependencies(shell, enabled)
+	if c.Status != StatusWarn || c.Fixable() {
+		t.Fatalf("deps = %+v", c)
+	}
+	if len(c.Problems) != 1 || c.Problems[0] != "absent" || c.Hint != "brew install absent" {
+		t.Fatalf("problems = %v hint = %q", c.Problems, c.Hint)
+	}
+}
+
+func TestRepair_FailureBlocksLaterFixes(t *testing.T) {
+	failing := func(git.CommandRunner) (string, error) { return "", errors.New("injected") }
+	ran := false
+	later := func(git.CommandRunner) (string, error) { ran = true; return "", nil }
+	report := Report{Checks: []Check{
+		{ID: "pointer", Name: ".git pointer", Status: StatusFail, Fix: "rewrite", repair: failing},
+		{ID: "ok", Name: "Fine", Status: StatusOK},
+		{ID: "refspec", Name: "Refspec", Status: StatusWarn, Fix: "add", repair: later},
+	}}
+
+	phases, err := Repair(nil, report, func(progress.Event) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ran {
+		t.Fatal("later repair ran after a failure")
+	}
+	steps := phases[0].Steps
+	if len(steps) != 2 || steps[0].Status != progress.StepFailed || steps[1].Status != progress.StepSkipped {
+		t.Fatalf("steps = %+v", steps)
+	}
+	if steps[1].Message != "blocked by .git pointer" {
+		t.Fatalf("skip message = %q", steps[1].Message)
+	}
+}
@@ -0,0 +1,55 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/progress"
+)
+
+const repairPhase progress.PhaseID = "doctor:repair"
+
+// RepairPlan declares one step per fixable check, in report order, so the
+// pointer is rewritten before git runs through it and moved worktrees are
+// repaired before missing ones are pruned.
+func RepairPlan(report Report) progress.Plan {
+	phase := progress.PlannedPhase{ID: repairPhase, Label: "Repair"}
+	for _, c := range report.Fixable() {
+		phase.Steps = append(phase.Steps, progress.PlannedStep{ID: c.ID, Label: c.Fix})
+	}
+	return progress.Plan{Phases: []progress.PlannedPhase{phase}}
+}
+
+// Repair runs every fixable check's repair. A failed repair blocks the
+// ones after it; the returned error is for the execution itself, while step
+// failures are reported in the phases.
+func Repair(runner git.CommandRunner, report Report, emit func(progress.Event)) ([]progress.Phase, error) {
+	fixable := report.Fixable()
+	if len(fixable) == 0 {
+		return nil, nil
+	}
+	execution, err := progress.Start(RepairPlan(report), emit)
+	if err != nil {
+		return nil, fmt.Errorf("starting repair: %w", err)
+	}
+	var runErr error
+	failedBy := ""
+	for _, c := range fixable {
+		var step progress.StepResult
+		if failedBy != "" {
+			_, err = execution.Skip(repairPhase, c.ID, "blocked by "+failedBy)
+		} else {
+			step, err = execution.Run(repairPhase, c.ID, func() (string, error) { return c.repair(runner) })
+			if err == nil && step.Status == progress.StepFailed {
+				failedBy = c.Name
+			}
+		}
+		if err != nil {
+			runErr = errors.Join(runErr, fmt.Errorf("executing %s: %w", c.Fix, err))
+			failedBy = c.Name
+		}
+	}
+	finishErr := execution.Finish("repair finished")
+	return execution.Phases(), errors.Join(runErr, finishErr)
+}
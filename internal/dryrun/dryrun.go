@@ -11,7 +11,37 @@ import (
 	"github.com/abiswas97/sentei/internal/git"
 )
 
-func Print(worktrees []git.Worktree, w io.Writer) error {
+// Print writes the worktree summary table to w, oldest commit first. Branches
+// the protection policy covers are marked [P]; a nil policy marks the
+// built-in set only.
+func Print(worktrees []git.Worktree, protection *git.ProtectionPolicy, w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "STATUS\tBRANCH\tAGE\tAHEAD/BEHIND\tSUBJECT"); err != nil {
+		return err
+	}
+	for _, wt := range SortByAge(worktrees) {
+		branch := BranchLabel(wt)
+		age := RelativeTime(wt.LastCommitDate)
+		subject := wt.LastCommitSubject
+		if wt.EnrichmentError != "" {
+			age = "error"
+			subject = wt.EnrichmentError
+		}
+
+		if protection.IsProtected(wt.Branch) {
+			branch += " [P]"
+		}
+
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", StatusIndicator(wt), branch, age, AheadBehind(wt), subject); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// SortByAge returns a copy of worktrees ordered oldest commit first, with
+// worktrees whose commit date is unknown last.
+func SortByAge(worktrees []git.Worktree) []git.Worktree {
 	sorted := make([]git.Worktree, len(worktrees))
 	copy(sorted, worktrees)
 	sort.SliceStable(sorted, func(a, b int) bool {
@@ -25,44 +55,42 @@ func Print(worktrees []git.Worktree, w io.Writer) error {
 		}
 		return sorted[a].LastCommitDate.Before(sorted[b].LastCommitDate)
 	})
+	return sorted
+}
 
-	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-	if _, err := fmt.Fprintln(tw, "STATUS\tBRANCH\tAGE\tSUBJECT"); err != nil {
-		return err
-	}
-	for _, wt := range sorted {
-		branch := stripBranchPrefix(wt.Branch)
-		if branch == "" {
-			switch {
-			case wt.IsDetached:
-				branch = wt.HEAD
-				if len(branch) >= 7 {
-					branch = branch[:7]
-				}
-			case wt.IsPrunable:
-				branch = "(prunable)"
+// BranchLabel is the short branch name, the abbreviated HEAD for a detached
+// worktree, or "(prunable)" for a prunable one.
+func BranchLabel(wt git.Worktree) string {
+	branch := stripBranchPrefix(wt.Branch)
+	if branch == "" {
+		switch {
+		case wt.IsDetached:
+			branch = wt.HEAD
+			if len(branch) >= 7 {
+				branch = branch[:7]
 			}
+		case wt.IsPrunable:
+			branch = "(prunable)"
 		}
+	}
+	return branch
+}
 
-		age := relativeTime(wt.LastCommitDate)
-		subject := wt.LastCommitSubject
-		if wt.EnrichmentError != "" {
-			age = "error"
-			subject = wt.EnrichmentError
-		}
-
-		if git.IsProtectedBranch(wt.Branch) {
-			branch += " [P]"
-		}
-
-		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", statusIndicator(wt), branch, age, subject); err != nil {
-			return err
-		}
+// AheadBehind renders the branch's divergence from its upstream as
+// "+ahead/-behind", "gone" when the upstream was deleted, or "-" when the
+// branch tracks nothing.
+func AheadBehind(wt git.Worktree) string {
+	switch {
+	case wt.UpstreamGone:
+		return "gone"
+	case !wt.HasUpstream():
+		return "-"
 	}
-	return tw.Flush()
+	return fmt.Sprintf("+%d/-%d", wt.Ahead, wt.Behind)
 }
 
-func statusIndicator(wt git.Worktree) string {
+// StatusIndicator is the bracketed status marker shown in the STATUS column.
+func StatusIndicator(wt git.Worktree) string {
 	switch {
 	case wt.IsLocked:
 		return "[L]"
@@ -79,7 +107,9 @@ func stripBranchPrefix(ref string) string {
 	return strings.TrimPrefix(ref, "refs/heads/")
 }
 
-func relativeTime(t time.Time) string {
+// RelativeTime renders t as an age such as "3 days ago"; the zero time is
+// "unknown".
+func RelativeTime(t time.Time) string {
 	if t.IsZero() {
 		return "unknown"
 	}
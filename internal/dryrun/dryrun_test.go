@@ -123,6 +123,27 @@ func TestPrint(t *testing.T) {
 			},
 			wantLines: []string{"abc123d"},
 		},
+		{
+			name: "upstream divergence",
+			worktrees: []git.Worktree{
+				{
+					Branch:            "refs/heads/feature/sync",
+					LastCommitDate:    now.Add(-1 * time.Hour),
+					LastCommitSubject: "Sync",
+					Upstream:          "origin/feature/sync",
+					Ahead:             2,
+					Behind:            5,
+				},
+				{
+					Branch:            "refs/heads/feature/merged",
+					LastCommitDate:    now.Add(-2 * time.Hour),
+					LastCommitSubject: "Merged",
+					Upstream:          "origin/feature/merged",
+					UpstreamGone:      true,
+				},
+			},
+			wantLines: []string{"AHEAD/BEHIND", "+2/-5", "gone"},
+		},
 		{
 			name: "prunable worktree",
 			worktrees: []git.Worktree{
@@ -137,7 +158,7 @@ func TestPrint(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var buf bytes.Buffer
-			if err := Print(tt.worktrees, &buf); err != nil {
+			if err := Print(tt.worktrees, nil, &buf); err != nil {
 				t.Fatalf("Print() error: %v", err)
 			}
 			output := buf.String()
@@ -165,7 +186,7 @@ func TestPrintSortOrder(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	if err := Print(worktrees, &buf); err != nil {
+	if err := Print(worktrees, nil, &buf); err != nil {
 		t.Fatalf("Print() error: %v", err)
 	}
 	output := buf.String()
@@ -195,7 +216,7 @@ func TestPrintNoAnsi(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	if err := Print(worktrees, &buf); err != nil {
+	if err := Print(worktrees, nil, &buf); err != nil {
 		t.Fatalf("Print() error: %v", err)
 	}
 	output := buf.String()
@@ -204,3 +225,27 @@ func TestPrintNoAnsi(t *testing.T) {
 		t.Errorf("output contains ANSI escape codes:\n%s", output)
 	}
 }
+
+func TestPrintMarksPolicyProtectedBranches(t *testing.T) {
+	worktrees := []git.Worktree{
+		{Branch: "refs/heads/release/1.0", LastCommitDate: time.Now().Add(-2 * time.Hour)},
+		{Branch: "refs/heads/production", LastCommitDate: time.Now().Add(-1 * time.Hour)},
+		{Branch: "refs/heads/feature/x", LastCommitDate: time.Now()},
+	}
+
+	var buf bytes.Buffer
+	policy := git.NewProtectionPolicy("production", []string{"release/*"})
+	if err := Print(worktrees, policy, &buf); err != nil {
+		t.Fatalf("Print() error: %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{"release/1.0 [P]", "production [P]"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q\ngot:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "feature/x [P]") {
+		t.Errorf("feature/x must not be marked protected\ngot:\n%s", output)
+	}
+}
@@ -0,0 +1,23 @@
+package fileutil
+
+import "path/filepath"
+
+// ResolvePath returns path absolute and with symlinks resolved, so paths
+// git reports and paths sentei builds compare equal on systems where the
+// temp or home directory is a link, such as macOS's /var -> /private/var. A
+// path that cannot be resolved, such as one that no longer exists, is only
+// made absolute and cleaned.
+func ResolvePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
+
+// SamePath reports whether a and b name the same path once resolved.
+func SamePath(a, b string) bool {
+	return ResolvePath(a) == ResolvePath(b)
+}
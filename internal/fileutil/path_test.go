@@ -0,0 +1,30 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSamePath_LooksThroughSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	real := filepath.Join(dir, "real")
+	if err := os.Mkdir(real, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(real, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if !SamePath(link, real+string(filepath.Separator)) {
+		t.Errorf("SamePath(%s, %s) = false, want true", link, real)
+	}
+	if SamePath(real, filepath.Join(dir, "other")) {
+		t.Error("distinct paths must not compare equal")
+	}
+	gone := filepath.Join(link, "gone")
+	if got, want := ResolvePath(gone), filepath.Clean(gone); got != want {
+		t.Errorf("ResolvePath(missing) = %q, want %q", got, want)
+	}
+}
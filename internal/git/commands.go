@@ -3,6 +3,7 @@ package git
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os/exec"
 	"strings"
 )
@@ -11,11 +12,57 @@ type CommandRunner interface {
 	Run(dir string, args ...string) (string, error)
 }
 
+// RawRunner is implemented by runners that can return output as git wrote
+// it. NUL-separated listings need it: Run trims, and a path may begin with
+// a space.
+type RawRunner interface {
+	RunRaw(dir string, args ...string) (string, error)
+}
+
+// RunRaw runs git through runner without trimming its output when runner
+// supports it, and falls back to Run otherwise.
+func RunRaw(runner CommandRunner, dir string, args ...string) (string, error) {
+	if raw, ok := runner.(RawRunner); ok {
+		return raw.RunRaw(dir, args...)
+	}
+	return runner.Run(dir, args...)
+}
+
+// InputRunner is implemented by runners that can feed git standard input,
+// as patch-id needs.
+type InputRunner interface {
+	RunInput(dir, input string, args ...string) (string, error)
+}
+
+// RunInput runs git through runner with input on its standard input. A
+// runner that cannot feed input fails the call.
+func RunInput(runner CommandRunner, dir, input string, args ...string) (string, error) {
+	if in, ok := runner.(InputRunner); ok {
+		return in.RunInput(dir, input, args...)
+	}
+	return "", fmt.Errorf("git %s: runner cannot feed standard input", strings.Join(args, " "))
+}
+
 type GitRunner struct{}
 
 func (r *GitRunner) Run(dir string, args ...string) (string, error) {
+	out, err := r.RunRaw(dir, args...)
+	return strings.TrimSpace(out), err
+}
+
+func (r *GitRunner) RunRaw(dir string, args ...string) (string, error) {
+	return r.run(dir, nil, args)
+}
+
+func (r *GitRunner) RunInput(dir, input string, args ...string) (string, error) {
+	out, err := r.run(dir, strings.NewReader(input), args)
+	return strings.TrimSpace(out), err
+}
+
+func (r *GitRunner) run(dir string, stdin io.Reader, args []string) (string, error) {
 	fullArgs := append([]string{"-C", dir}, args...)
 	cmd := exec.Command("git", fullArgs...)
+	cmd.Stdin = stdin
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -31,7 +78,7 @@ func (r *GitRunner) Run(dir string, args ...string) (string, error) {
 		return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
 	}
 
-	return strings.TrimSpace(stdout.String()), nil
+	return stdout.String(), nil
 }
 
 // ShellQuote single-quotes a value so shell metacharacters in it are inert when
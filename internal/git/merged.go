@@ -0,0 +1,167 @@
+package git
+
+import (
+	"slices"
+	"strings"
+)
+
+// MergeKind says how a branch's work reached a target branch, if it did.
+type MergeKind int
+
+const (
+	NotMerged MergeKind = iota
+	// MergedAncestor: the branch tip is reachable from the target (a merge
+	// commit or fast-forward).
+	MergedAncestor
+	// MergedRebase: every branch commit has a patch-equivalent commit on the
+	// target, as after a rebase merge or a cherry-pick.
+	MergedRebase
+	// MergedSquash: the branch's combined change since its merge-base is on
+	// the target as a single commit, as after a squash merge.
+	MergedSquash
+)
+
+// Merged reports whether the branch's work is on the target by any route.
+func (k MergeKind) Merged() bool {
+	return k != NotMerged
+}
+
+func (k MergeKind) String() string {
+	switch k {
+	case MergedAncestor:
+		return "merged"
+	case MergedRebase:
+		return "rebase-merged"
+	case MergedSquash:
+		return "squash-merged"
+	}
+	return "not merged"
+}
+
+// MergedInto reports how branch was merged into target, catching the squash
+// and rebase merges that ancestry alone misses. Checks run cheapest first:
+//
+//  1. merge-base --is-ancestor: the branch tip is in target's history.
+//  2. git cherry target branch: every branch commit's patch-id appears on
+//     target ("-" lines only).
+//  3. The branch's cumulative change: the patch-id of its diff from the
+//     merge-base is looked for among the patch-ids of target's commits
+//     since the merge-base that touch the same files. Nothing is written
+//     to the repository.
+//
+// Any git failure answers NotMerged, so callers deleting on a "merged"
+// answer stay conservative. A squash merge whose change was later edited on
+// target, or which combined several branches, is not recognized.
+func MergedInto(runner CommandRunner, repoPath, branch, target string) MergeKind {
+	if _, err := runner.Run(repoPath, "merge-base", "--is-ancestor", branch, target); err == nil {
+		return MergedAncestor
+	}
+
+	out, err := runner.Run(repoPath, "cherry", target, branch)
+	if err != nil {
+		return NotMerged
+	}
+	if allCherryPicked(out) {
+		return MergedRebase
+	}
+
+	base, err := runner.Run(repoPath, "merge-base", target, branch)
+	if err != nil {
+		return NotMerged
+	}
+	if squashMerged(runner, repoPath, strings.TrimSpace(base), branch, target) {
+		return MergedSquash
+	}
+	return NotMerged
+}
+
+// squashDiffOptions keep the branch's diff and target's log comparable
+// whatever the user's diff config says.
+var squashDiffOptions = []string{"--no-color", "--no-ext-diff", "--no-renames"}
+
+// squashMerged reports whether a commit on target since base has the same
+// patch-id as branch's whole change since base. Only commits touching
+// exactly the files the branch changed can match, so target's history is
+// listed by file name first and patches are read for those commits alone.
+func squashMerged(runner CommandRunner, repoPath, base, branch, target string) bool {
+	names, err := RunRaw(runner, repoPath, "diff", "--name-only", "--no-renames", base, branch)
+	if err != nil {
+		return false
+	}
+	files := splitLines(names)
+	if len(files) == 0 {
+		return false
+	}
+
+	// A NUL starts each commit, which no file name can contain.
+	log, err := RunRaw(runner, repoPath, "log", "--no-merges", "--no-renames", "--name-only", "--format=%x00%H", base+".."+target)
+	if err != nil {
+		return false
+	}
+	var candidates []string
+	for _, record := range strings.Split(log, "\x00")[1:] {
+		commit, touched, _ := strings.Cut(record, "\n")
+		if slices.Equal(splitLines(touched), files) {
+			candidates = append(candidates, commit)
+		}
+	}
+	if len(candidates) == 0 {
+		return false
+	}
+
+	diff, err := RunRaw(runner, repoPath, append(append([]string{"diff"}, squashDiffOptions...), base, branch)...)
+	if err != nil || diff == "" {
+		return false
+	}
+	want, err := RunInput(runner, repoPath, diff, "patch-id", "--stable")
+	if err != nil || want == "" {
+		return false
+	}
+	want = strings.Fields(want)[0]
+
+	patches, err := RunRaw(runner, repoPath, append(append([]string{"show", "--format=commit %H"}, squashDiffOptions...), candidates...)...)
+	if err != nil || patches == "" {
+		return false
+	}
+	ids, err := RunInput(runner, repoPath, patches, "patch-id", "--stable")
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(ids, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 && fields[0] == want {
+			return true
+		}
+	}
+	return false
+}
+
+// splitLines returns the non-empty lines of s, sorted. File names are kept
+// byte for byte; git quotes any with unusual characters the same way in
+// every listing.
+func splitLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+// allCherryPicked reports whether git cherry output lists at least one commit
+// and marks every one "-" (an equivalent change exists upstream).
+func allCherryPicked(output string) bool {
+	found := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "- ") {
+			return false
+		}
+		found = true
+	}
+	return found
+}
@@ -0,0 +1,109 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// mergeFixture builds a repository whose main branch absorbed one branch by
+// merge commit, one by rebase and one by squash, and left one unmerged.
+func mergeFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@test.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@test.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(file, content, msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", file)
+		run("commit", "-q", "-m", msg)
+	}
+
+	run("init", "-q", "-b", "main")
+	commit("base.txt", "base\n", "base")
+
+	for _, b := range []string{"merged", "rebased", "squashed", "open"} {
+		run("checkout", "-q", "-b", b, "main")
+		commit(b+"-1.txt", b+" one\n", b+" one")
+		commit(b+"-2.txt", b+" two\n", b+" two")
+	}
+
+	run("checkout", "-q", "main")
+	commit("main.txt", "main moves on\n", "main moves on")
+	run("merge", "-q", "--no-edit", "merged")
+	run("cherry-pick", "main..rebased")
+	run("merge", "-q", "--squash", "squashed")
+	run("commit", "-q", "-m", "squashed (#1)")
+	return dir
+}
+
+func TestMergedInto(t *testing.T) {
+	dir := mergeFixture(t)
+	runner := &GitRunner{}
+
+	cases := map[string]MergeKind{
+		"merged":   MergedAncestor,
+		"rebased":  MergedRebase,
+		"squashed": MergedSquash,
+		"open":     NotMerged,
+	}
+	for branch, want := range cases {
+		if got := MergedInto(runner, dir, branch, "main"); got != want {
+			t.Errorf("MergedInto(%s) = %v, want %v", branch, got, want)
+		}
+	}
+	if MergedInto(runner, dir, "no-such-branch", "main").Merged() {
+		t.Error("a missing branch must not report merged")
+	}
+}
+
+func TestMergedInto_SquashProbeWritesNothing(t *testing.T) {
+	dir := mergeFixture(t)
+	runner := &GitRunner{}
+	objects := func() string {
+		t.Helper()
+		out, err := runner.Run(dir, "count-objects")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	before := objects()
+	if got := MergedInto(runner, dir, "squashed", "main"); got != MergedSquash {
+		t.Fatalf("MergedInto(squashed) = %v, want %v", got, MergedSquash)
+	}
+	if after := objects(); after != before {
+		t.Errorf("objects before = %q, after = %q; the probe must not write", before, after)
+	}
+}
+
+func TestAllCherryPicked(t *testing.T) {
+	cases := []struct {
+		output string
+		want   bool
+	}{
+		{"", false},
+		{"- abc\n- def", true},
+		{"- abc\n+ def", false},
+		{"+ abc", false},
+	}
+	for _, tc := range cases {
+		if got := allCherryPicked(tc.output); got != tc.want {
+			t.Errorf("allCherryPicked(%q) = %v, want %v", tc.output, got, tc.want)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package git
 
-import "strings"
+import (
+	"path"
+	"strings"
+)
 
 var protectedBranches = map[string]bool{
 	"main":    true,
@@ -52,3 +55,78 @@ func DetectDefaultBranch(runner CommandRunner, repoPath string) string {
 
 	return "main" // last resort
 }
+
+// ProtectionPolicy is the single answer to "may sentei delete this branch?"
+// shared by remove, cleanup, the TUI list and dry-run. It protects the
+// built-in convention set, the repo's default branch, and the configured
+// protected_branches patterns.
+//
+// A nil policy is valid and protects the built-in set only, so code paths
+// that run before config or default-branch detection still fail safe.
+type ProtectionPolicy struct {
+	defaultBranch string
+	patterns      []string
+}
+
+// NewProtectionPolicy builds a policy from the detected default branch (bare
+// name, may be empty) and configured patterns. Patterns are exact names or
+// path.Match globs ("release/*", "hotfix-*"); like the built-in set they
+// match case-insensitively, since protection should err toward keeping a
+// branch.
+func NewProtectionPolicy(defaultBranch string, patterns []string) *ProtectionPolicy {
+	p := &ProtectionPolicy{defaultBranch: defaultBranch}
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			p.patterns = append(p.patterns, strings.ToLower(strings.TrimPrefix(pattern, "refs/heads/")))
+		}
+	}
+	return p
+}
+
+// DetectProtectionPolicy builds a policy for repoPath, detecting its default
+// branch. It runs git, so TUI callers must invoke it inside a tea.Cmd.
+func DetectProtectionPolicy(runner CommandRunner, repoPath string, patterns []string) *ProtectionPolicy {
+	return NewProtectionPolicy(DetectDefaultBranch(runner, repoPath), patterns)
+}
+
+// ValidateProtectedPattern reports a malformed glob in a protected_branches
+// entry. Config validation rejects these up front; at match time a malformed
+// pattern degrades to an exact-name comparison.
+func ValidateProtectedPattern(pattern string) error {
+	_, err := path.Match(pattern, "")
+	return err
+}
+
+// DefaultBranch returns the default branch the policy protects, or "" when
+// none was detected.
+func (p *ProtectionPolicy) DefaultBranch() string {
+	if p == nil {
+		return ""
+	}
+	return p.defaultBranch
+}
+
+// IsProtected reports whether branch (short name or refs/heads/ ref) must not
+// be deleted. An empty branch (detached HEAD) is never protected.
+func (p *ProtectionPolicy) IsProtected(branch string) bool {
+	if p == nil {
+		return IsProtectedBranch(branch)
+	}
+	if IsProtectedBranchWith(branch, p.defaultBranch) {
+		return true
+	}
+	name := strings.ToLower(strings.TrimPrefix(branch, "refs/heads/"))
+	if name == "" {
+		return false
+	}
+	for _, pattern := range p.patterns {
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			matched = pattern == name
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
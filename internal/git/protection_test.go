@@ -58,3 +58,57 @@ func TestIsProtectedBranchWith_ProtectsNonStandardDefault(t *testing.T) {
 		t.Error("with no default supplied, 'production' is not in the static set")
 	}
 }
+
+func TestProtectionPolicy_IsProtected(t *testing.T) {
+	p := NewProtectionPolicy("trunk", []string{"release/*", "production", "hotfix-*"})
+
+	tests := []struct {
+		branch string
+		want   bool
+	}{
+		{"refs/heads/main", true},  // built-in set
+		{"refs/heads/trunk", true}, // detected default
+		{"production", true},       // exact configured name
+		{"refs/heads/Production", true},
+		{"refs/heads/release/1.0", true},
+		{"release/2026-q3", true},
+		{"refs/heads/hotfix-login", true},
+		{"refs/heads/release/1.0/rc", false}, // * does not cross a slash
+		{"refs/heads/feature/release", false},
+		{"refs/heads/productionize", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			if got := p.IsProtected(tt.branch); got != tt.want {
+				t.Errorf("IsProtected(%q) = %v, want %v", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtectionPolicy_NilProtectsBuiltInOnly(t *testing.T) {
+	var p *ProtectionPolicy
+	if !p.IsProtected("refs/heads/main") {
+		t.Error("a nil policy must still protect the built-in set")
+	}
+	if p.IsProtected("refs/heads/production") {
+		t.Error("a nil policy knows no default branch or patterns")
+	}
+	if p.DefaultBranch() != "" {
+		t.Errorf("DefaultBranch() = %q, want empty", p.DefaultBranch())
+	}
+}
+
+func TestProtectionPolicy_MalformedPatternMatchesLiterally(t *testing.T) {
+	if err := ValidateProtectedPattern("release/["); err == nil {
+		t.Fatal("expected a malformed pattern to fail validation")
+	}
+	p := NewProtectionPolicy("", []string{"release/["})
+	if !p.IsProtected("release/[") {
+		t.Error("a malformed pattern should still protect its literal name")
+	}
+	if p.IsProtected("release/x") {
+		t.Error("a malformed pattern must not act as a wildcard")
+	}
+}
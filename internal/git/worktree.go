@@ -20,4 +20,24 @@ type Worktree struct {
 	HasUnpushedCommits    bool
 	IsEnriched            bool
 	EnrichmentError       string
+
+	// Upstream is the branch's upstream as a short name (origin/feature),
+	// empty when it tracks nothing. UpstreamGone means the upstream is still
+	// configured but its remote-tracking ref no longer exists. Ahead and
+	// Behind count commits relative to a live upstream.
+	Upstream     string
+	UpstreamGone bool
+	Ahead        int
+	Behind       int
+
+	// DefaultAhead and DefaultBehind count commits relative to the repo's
+	// default branch; both are zero for the default branch itself.
+	DefaultAhead  int
+	DefaultBehind int
+}
+
+// HasUpstream reports whether the branch tracks an upstream that still
+// exists, i.e. whether Ahead and Behind mean anything.
+func (wt Worktree) HasUpstream() bool {
+	return wt.Upstream != "" && !wt.UpstreamGone
 }
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/abiswas97/sentei/internal/fileutil"
 	"github.com/abiswas97/sentei/internal/git"
@@ -31,6 +32,7 @@ const (
 	applyRemove
 	applyCopy
 	applyGitignore
+	applySkip
 )
 
 type applyOperation struct {
@@ -43,6 +45,8 @@ type applyOperation struct {
 	command   string
 	failure   error
 	dependsOn []string
+	// skipReason is the reason an applySkip operation reports.
+	skipReason string
 
 	seedSource     string
 	seedDest       string
@@ -64,6 +68,9 @@ type PreparedApply struct {
 	plan       progress.Plan
 	operations []applyOperation
 	files      applyFileOperations
+	// phaseConcurrency, when above one, runs up to that many phases at
+	// once. Their operations may then depend only on their own phase.
+	phaseConcurrency int
 }
 
 type applyFileOperations interface {
@@ -502,82 +509,142 @@ func (p PreparedApply) RunIn(execution *progress.Execution, shell git.ShellRunne
 	if err := validateOperationGraph(p.operations); err != nil {
 		return fmt.Errorf("validating integration apply: %w", err)
 	}
-	results := make(map[string]progress.StepResult, len(p.operations))
 	files := p.files
 	if files == nil {
 		files = realApplyFileOperations{}
 	}
+	if p.phaseConcurrency > 1 {
+		return p.runPhasesConcurrently(execution, shell, files)
+	}
+	results := make(map[string]progress.StepResult, len(p.operations))
+	for _, op := range p.operations {
+		if err := runOperation(execution, shell, files, op, results); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPhasesConcurrently runs each phase's operations in order, up to
+// phaseConcurrency phases at a time.
+func (p PreparedApply) runPhasesConcurrently(execution *progress.Execution, shell git.ShellRunner, files applyFileOperations) error {
+	var order []progress.PhaseID
+	byPhase := map[progress.PhaseID][]applyOperation{}
+	phaseOf := make(map[string]progress.PhaseID, len(p.operations))
 	for _, op := range p.operations {
-		blockedBy := ""
 		for _, dependency := range op.dependsOn {
-			result := results[dependency]
-			if result.Status == progress.StepFailed || result.Status == progress.StepSkipped {
-				blockedBy = result.Name
-				break
+			if phaseOf[dependency] != op.phaseID {
+				return fmt.Errorf("validating integration apply: operation %q depends on %q in another phase", op.key(), dependency)
 			}
 		}
-		if blockedBy != "" {
-			result, err := execution.Skip(op.phaseID, op.stepID, "blocked by "+blockedBy)
-			if err != nil {
-				return fmt.Errorf("skipping %s: %w", op.label, err)
-			}
-			results[op.key()] = result
-			continue
+		phaseOf[op.key()] = op.phaseID
+		if _, ok := byPhase[op.phaseID]; !ok {
+			order = append(order, op.phaseID)
 		}
-		var result progress.StepResult
-		var transitionErr error
-		switch op.kind {
-		case applyFailure:
-			result, transitionErr = execution.Fail(op.phaseID, op.stepID, op.failure)
-		case applyRemove:
-			result, transitionErr = execution.Run(op.phaseID, op.stepID, func() (string, error) {
-				path := op.dir
-				if op.managedPath != "" {
-					resolved, err := ResolveManagedPath(op.managedRoot, op.managedPath)
-					if err != nil {
-						return "", fmt.Errorf("revalidating removal path: %w", err)
-					}
-					path = resolved
-				}
-				return "", files.removeAll(path)
-			})
-		case applyCopy:
-			result, transitionErr = execution.Run(op.phaseID, op.stepID, func() (string, error) {
-				source, destination := op.seedSource, op.seedDest
-				if op.seedSourcePath != "" {
-					resolved, err := ResolveManagedPath(op.seedSourceRoot, op.seedSourcePath)
-					if err != nil {
-						return "", fmt.Errorf("revalidating index source: %w", err)
-					}
-					source = resolved
-				}
-				if op.seedDestPath != "" {
-					resolved, err := ResolveManagedPath(op.seedDestRoot, op.seedDestPath)
-					if err != nil {
-						return "", fmt.Errorf("revalidating index destination: %w", err)
-					}
-					destination = resolved
-				}
-				if err := files.removeAll(destination); err != nil {
-					return "", fmt.Errorf("removing existing index: %w", err)
+		byPhase[op.phaseID] = append(byPhase[op.phaseID], op)
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	sem := make(chan struct{}, p.phaseConcurrency)
+	for _, phaseID := range order {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(operations []applyOperation) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results := make(map[string]progress.StepResult, len(operations))
+			for _, op := range operations {
+				if err := runOperation(execution, shell, files, op, results); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+					return
 				}
-				if err := files.copyDir(source, destination); err != nil {
-					return "", fmt.Errorf("copying index: %w", err)
-				}
-				return "", nil
-			})
-		case applyGitignore:
-			result, transitionErr = execution.Run(op.phaseID, op.stepID, func() (string, error) {
-				return "", files.appendGitignore(op.gitignoreDir, op.gitignore)
-			})
-		default:
-			result, transitionErr = execution.Run(op.phaseID, op.stepID, func() (string, error) { return shell.RunShell(op.dir, op.command) })
+			}
+		}(byPhase[phaseID])
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// runOperation performs one frozen operation, or skips it when a dependency
+// in results failed or was skipped, and records its result.
+func runOperation(execution *progress.Execution, shell git.ShellRunner, files applyFileOperations, op applyOperation, results map[string]progress.StepResult) error {
+	blockedBy := ""
+	for _, dependency := range op.dependsOn {
+		result := results[dependency]
+		if result.Status == progress.StepFailed || result.Status == progress.StepSkipped {
+			blockedBy = result.Name
+			break
 		}
-		if transitionErr != nil {
-			return fmt.Errorf("executing %s: %w", op.label, transitionErr)
+	}
+	if blockedBy != "" {
+		result, err := execution.Skip(op.phaseID, op.stepID, "blocked by "+blockedBy)
+		if err != nil {
+			return fmt.Errorf("skipping %s: %w", op.label, err)
 		}
 		results[op.key()] = result
-	}
+		return nil
+	}
+	var result progress.StepResult
+	var transitionErr error
+	switch op.kind {
+	case applyFailure:
+		result, transitionErr = execution.Fail(op.phaseID, op.stepID, op.failure)
+	case applySkip:
+		result, transitionErr = execution.Skip(op.phaseID, op.stepID, op.skipReason)
+	case applyRemove:
+		result, transitionErr = execution.Run(op.phaseID, op.stepID, func() (string, error) {
+			path := op.dir
+			if op.managedPath != "" {
+				resolved, err := ResolveManagedPath(op.managedRoot, op.managedPath)
+				if err != nil {
+					return "", fmt.Errorf("revalidating removal path: %w", err)
+				}
+				path = resolved
+			}
+			return "", files.removeAll(path)
+		})
+	case applyCopy:
+		result, transitionErr = execution.Run(op.phaseID, op.stepID, func() (string, error) {
+			source, destination := op.seedSource, op.seedDest
+			if op.seedSourcePath != "" {
+				resolved, err := ResolveManagedPath(op.seedSourceRoot, op.seedSourcePath)
+				if err != nil {
+					return "", fmt.Errorf("revalidating index source: %w", err)
+				}
+				source = resolved
+			}
+			if op.seedDestPath != "" {
+				resolved, err := ResolveManagedPath(op.seedDestRoot, op.seedDestPath)
+				if err != nil {
+					return "", fmt.Errorf("revalidating index destination: %w", err)
+				}
+				destination = resolved
+			}
+			if err := files.removeAll(destination); err != nil {
+				return "", fmt.Errorf("removing existing index: %w", err)
+			}
+			if err := files.copyDir(source, destination); err != nil {
+				return "", fmt.Errorf("copying index: %w", err)
+			}
+			return "", nil
+		})
+	case applyGitignore:
+		result, transitionErr = execution.Run(op.phaseID, op.stepID, func() (string, error) {
+			return "", files.appendGitignore(op.gitignoreDir, op.gitignore)
+		})
+	default:
+		result, transitionErr = execution.Run(op.phaseID, op.stepID, func() (string, error) { return shell.RunShell(op.dir, op.command) })
+	}
+	if transitionErr != nil {
+		return fmt.Errorf("executing %s: %w", op.label, transitionErr)
+	}
+	results[op.key()] = result
 	return nil
 }
 
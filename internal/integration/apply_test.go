@@ -10,6 +10,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/abiswas97/sentei/internal/config"
 	"github.com/abiswas97/sentei/internal/progress"
 )
 
@@ -927,3 +928,31 @@ func TestPreparedApplyPrerequisiteFailureBlocksOnlyDependentOperations(t *testin
 		t.Fatalf("dependent setup = %#v", got)
 	}
 }
+
+func TestPrepareApply_DeclaredIntegrationPlansLikeBuiltIn(t *testing.T) {
+	declared := Resolve([]config.IntegrationConfig{{
+		Name:      "ctags",
+		Detect:    config.IntegrationDetectConfig{Binary: "ctags"},
+		Install:   config.IntegrationInstallConfig{Command: "brew install universal-ctags"},
+		Setup:     config.IntegrationSetupConfig{Command: "ctags -R -f .tags/tags {path}"},
+		Gitignore: []string{".tags/"},
+		Source:    "per-repo",
+	}})[2]
+	shell := &applyShell{responses: map[string]mockShellResponse{
+		"/wt/a:shell[command -v ctags]":               {err: errors.New("missing")},
+		"/wt/a:shell[brew install universal-ctags]":   {output: "ok"},
+		"/wt/a:shell[ctags -R -f .tags/tags '/wt/a']": {output: "ok"},
+	}}
+	prepared, err := PrepareApply(shell, "/repo", "/wt/a", []Integration{declared}, nil, []string{"/wt/a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Prerequisites/Install ctags",
+		"/wt/a/Setup ctags",
+		"/wt/a/Update .gitignore for ctags",
+	}
+	if got := plannedLabels(prepared.Plan()); !reflect.DeepEqual(got, want) {
+		t.Fatalf("plan = %v, want %v", got, want)
+	}
+}
@@ -34,6 +34,10 @@ func cocoindexCode() Integration {
 			Command:    "ccc init && ccc index",
 			WorkingDir: "worktree",
 		},
+		Refresh: RefreshSpec{
+			// ccc index is incremental once ccc init has run.
+			Command: "ccc index",
+		},
 		Teardown: TeardownSpec{
 			Command: "ccc reset --all --force",
 			Dirs:    []string{".cocoindex_code/"},
@@ -27,6 +27,9 @@ func codeReviewGraph() Integration {
 			Command:    "code-review-graph build --repo {path}",
 			WorkingDir: "repo",
 		},
+		Refresh: RefreshSpec{
+			Command: "code-review-graph update --repo {path}",
+		},
 		Teardown: TeardownSpec{
 			Dirs: []string{".code-review-graph/"},
 		},
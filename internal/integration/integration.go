@@ -1,5 +1,7 @@
 package integration
 
+import "github.com/abiswas97/sentei/internal/config"
+
 // Integration describes a dev tool that can be installed and managed per-worktree.
 type Integration struct {
 	Name             string
@@ -10,14 +12,21 @@ type Integration struct {
 	Detect           DetectSpec
 	Install          InstallSpec
 	Setup            SetupSpec
+	Refresh          RefreshSpec
 	Teardown         TeardownSpec
 	GitignoreEntries []string
 	// IndexCopyDir is the directory name (relative to worktree root) that can be
 	// copied from one worktree to another to seed an incremental index. Empty means
 	// the integration's index cannot be shared across worktrees (e.g., absolute paths).
 	IndexCopyDir string
+	// Source is SourceBuiltIn, or the config layer ("global" or "per-repo")
+	// that declared or last changed the integration.
+	Source string
 }
 
+// SourceBuiltIn is the Source of an integration sentei ships.
+const SourceBuiltIn = "built-in"
+
 // Dependency is a prerequisite tool needed before an integration can be installed.
 type Dependency struct {
 	Name    string
@@ -43,21 +52,99 @@ type SetupSpec struct {
 	WorkingDir string // "repo" or "worktree"
 }
 
+// RefreshSpec describes how to bring an integration's index up to date in a
+// worktree that is already set up. It runs in Setup.WorkingDir, with {path}
+// replaced like the setup command.
+type RefreshSpec struct {
+	Command string
+}
+
 // TeardownSpec describes how to remove an integration's artefacts.
 type TeardownSpec struct {
 	Command string
 	Dirs    []string
 }
 
-// All returns every known integration.
+// All returns every built-in integration.
 func All() []Integration {
-	return []Integration{
+	all := []Integration{
 		codeReviewGraph(),
 		cocoindexCode(),
 	}
+	for i := range all {
+		all[i].Source = SourceBuiltIn
+	}
+	return all
+}
+
+// Resolve returns the built-in integrations with the configured
+// declarations merged in by name: a declaration naming a built-in changes
+// only the fields it sets, any other name adds an integration.
+func Resolve(declared []config.IntegrationConfig) []Integration {
+	all := All()
+	index := make(map[string]int, len(all))
+	for i, integ := range all {
+		index[integ.Name] = i
+	}
+	for _, decl := range declared {
+		if i, ok := index[decl.Name]; ok {
+			all[i] = fromConfig(toConfig(all[i]).Overlay(decl))
+			continue
+		}
+		index[decl.Name] = len(all)
+		all = append(all, fromConfig(decl))
+	}
+	return all
+}
+
+// toConfig and fromConfig convert between the two mirrored shapes so
+// Resolve merges with the same rules as the config layers.
+func toConfig(integ Integration) config.IntegrationConfig {
+	deps := make([]config.DependencyConfig, len(integ.Dependencies))
+	for i, d := range integ.Dependencies {
+		deps[i] = config.DependencyConfig{Name: d.Name, Detect: d.Detect, Install: d.Install}
+	}
+	return config.IntegrationConfig{
+		Name:             integ.Name,
+		ShortDescription: integ.ShortDescription,
+		Description:      integ.Description,
+		URL:              integ.URL,
+		Dependencies:     deps,
+		Detect:           config.IntegrationDetectConfig{Command: integ.Detect.Command, Binary: integ.Detect.BinaryName},
+		Install:          config.IntegrationInstallConfig{Command: integ.Install.Command, FirstRunNote: integ.Install.FirstRunNote},
+		Setup:            config.IntegrationSetupConfig{Command: integ.Setup.Command, WorkingDir: integ.Setup.WorkingDir},
+		Refresh:          config.IntegrationRefreshConfig{Command: integ.Refresh.Command},
+		Teardown:         config.IntegrationTeardownConfig{Command: integ.Teardown.Command, Dirs: integ.Teardown.Dirs},
+		Gitignore:        integ.GitignoreEntries,
+		IndexCopyDir:     integ.IndexCopyDir,
+		Source:           integ.Source,
+	}
+}
+
+func fromConfig(c config.IntegrationConfig) Integration {
+	deps := make([]Dependency, len(c.Dependencies))
+	for i, d := range c.Dependencies {
+		deps[i] = Dependency{Name: d.Name, Detect: d.Detect, Install: d.Install}
+	}
+	return Integration{
+		Name:             c.Name,
+		ShortDescription: c.ShortDescription,
+		Description:      c.Description,
+		URL:              c.URL,
+		Dependencies:     deps,
+		Detect:           DetectSpec{Command: c.Detect.Command, BinaryName: c.Detect.Binary},
+		Install:          InstallSpec{Command: c.Install.Command, FirstRunNote: c.Install.FirstRunNote},
+		Setup:            SetupSpec{Command: c.Setup.Command, WorkingDir: c.Setup.WorkingDir},
+		Refresh:          RefreshSpec{Command: c.Refresh.Command},
+		Teardown:         TeardownSpec{Command: c.Teardown.Command, Dirs: c.Teardown.Dirs},
+		GitignoreEntries: c.Gitignore,
+		IndexCopyDir:     c.IndexCopyDir,
+		Source:           c.Source,
+	}
 }
 
-// Get returns the integration with the given name, or nil if not found.
+// Get returns the built-in integration with the given name, or nil if not
+// found.
 func Get(name string) *Integration {
 	all := All()
 	for i := range all {
@@ -68,7 +155,7 @@ func Get(name string) *Integration {
 	return nil
 }
 
-// Names returns the names of all known integrations.
+// Names returns the names of the built-in integrations.
 func Names() []string {
 	all := All()
 	names := make([]string, len(all))
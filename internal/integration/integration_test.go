@@ -3,6 +3,8 @@ package integration
 import (
 	"strings"
 	"testing"
+
+	"github.com/abiswas97/sentei/internal/config"
 )
 
 func TestAll(t *testing.T) {
@@ -106,3 +108,37 @@ func TestCocoindexCode_InstallCarriesEmbeddingsExtra(t *testing.T) {
 		t.Errorf("ccc install must pin its python: ambient .python-version files break the >=3.11 resolution, got %q", cmd)
 	}
 }
+
+func TestResolve(t *testing.T) {
+	all := Resolve([]config.IntegrationConfig{
+		{Name: "code-review-graph", Install: config.IntegrationInstallConfig{Command: "uv tool install code-review-graph"}, Source: "global"},
+		{
+			Name:      "ctags",
+			Detect:    config.IntegrationDetectConfig{Binary: "ctags"},
+			Setup:     config.IntegrationSetupConfig{Command: "ctags -R"},
+			Teardown:  config.IntegrationTeardownConfig{Dirs: []string{".tags/"}},
+			Gitignore: []string{".tags/"},
+			Source:    "per-repo",
+		},
+	})
+	if len(all) != 3 {
+		t.Fatalf("want 2 built-in + 1 declared integrations, got %d", len(all))
+	}
+
+	crg := all[0]
+	if crg.Install.Command != "uv tool install code-review-graph" || crg.Source != "global" {
+		t.Errorf("declaration should override the built-in install command, got %q from %q", crg.Install.Command, crg.Source)
+	}
+	if crg.Detect.Command != codeReviewGraph().Detect.Command || len(crg.Dependencies) != 2 {
+		t.Errorf("fields the declaration leaves empty should keep their built-in values, got %+v", crg)
+	}
+	if all[1].Source != SourceBuiltIn {
+		t.Errorf("untouched built-in source = %q, want %q", all[1].Source, SourceBuiltIn)
+	}
+
+	ctags := all[2]
+	if ctags.Name != "ctags" || ctags.Detect.BinaryName != "ctags" || ctags.Setup.Command != "ctags -R" ||
+		len(ctags.Teardown.Dirs) != 1 || len(ctags.GitignoreEntries) != 1 || ctags.Source != "per-repo" {
+		t.Errorf("declared integration not converted: %+v", ctags)
+	}
+}
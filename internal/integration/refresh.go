@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/progress"
+)
+
+// RefreshConcurrency bounds how many worktrees refresh at once; an index
+// update can use a whole core and a fair amount of memory.
+const RefreshConcurrency = 4
+
+func RefreshStepName(integ Integration) string { return "Refresh " + integ.Name }
+
+// PrepareRefresh freezes one phase per worktree that re-runs each
+// integration's refresh command there. Integrations without one are left
+// out; a worktree without the integration's artefacts gets a skipped step,
+// since there is no index to update. The phases run concurrently, up to
+// RefreshConcurrency at a time.
+func PrepareRefresh(repoPath string, integrations []Integration, wtPaths []string) (PreparedApply, error) {
+	var refreshable []Integration
+	seen := make(map[string]bool, len(integrations))
+	for _, integ := range integrations {
+		name := strings.TrimSpace(integ.Name)
+		if name == "" {
+			return PreparedApply{}, errors.New("preparing refresh: integration has empty name")
+		}
+		if seen[name] {
+			return PreparedApply{}, fmt.Errorf("preparing refresh: duplicate integration identity %q", name)
+		}
+		seen[name] = true
+		if strings.TrimSpace(integ.Refresh.Command) != "" {
+			refreshable = append(refreshable, integ)
+		}
+	}
+	if len(refreshable) > 0 && len(wtPaths) == 0 {
+		return PreparedApply{}, errors.New("preparing refresh: no target worktree")
+	}
+
+	var operations []applyOperation
+	for _, wtPath := range wtPaths {
+		phaseID := progress.PhaseID("worktree:" + stableToken(normalizeWorkspaceIdentity(wtPath)))
+		for _, integ := range refreshable {
+			op := applyOperation{
+				phaseID: phaseID, phaseName: wtPath,
+				stepID: stableStepID("refresh", integ.Name), label: RefreshStepName(integ),
+			}
+			// Without gitignore entries there is nothing to look for, so
+			// the refresh is attempted.
+			if len(integ.GitignoreEntries) > 0 && !DetectPresent(wtPath, integ) {
+				op.kind = applySkip
+				op.skipReason = "not set up in this worktree"
+				operations = append(operations, op)
+				continue
+			}
+			op.kind = applyShellCommand
+			op.dir = wtPath
+			if integ.Setup.WorkingDir == "repo" {
+				op.dir = repoPath
+			}
+			op.command = strings.ReplaceAll(integ.Refresh.Command, "{path}", git.ShellQuote(wtPath))
+			operations = append(operations, op)
+		}
+	}
+
+	if err := validateOperationGraph(operations); err != nil {
+		return PreparedApply{}, fmt.Errorf("preparing refresh: %w", err)
+	}
+	return PreparedApply{
+		plan: planForOperations(operations), operations: operations, files: realApplyFileOperations{},
+		phaseConcurrency: RefreshConcurrency,
+	}, nil
+}
+
+// PrepareResetup freezes the setup that has to run again after a worktree
+// moves from fromPath to toPath: integrations whose setup command embeds
+// {path} recorded the old location in their index. Only integrations set
+// up at fromPath are planned, so the decision is made before the move.
+func PrepareResetup(repoPath string, integrations []Integration, fromPath, toPath string) (PreparedApply, error) {
+	phaseID := progress.PhaseID("worktree:" + stableToken(normalizeWorkspaceIdentity(toPath)))
+	var operations []applyOperation
+	for _, integ := range integrations {
+		name := strings.TrimSpace(integ.Name)
+		if name == "" {
+			return PreparedApply{}, errors.New("preparing setup: integration has empty name")
+		}
+		if !strings.Contains(integ.Setup.Command, "{path}") || !DetectPresent(fromPath, integ) {
+			continue
+		}
+		op := applyOperation{
+			phaseID: phaseID, phaseName: toPath,
+			stepID: stableStepID("setup", name), label: SetupStepName(integ),
+			kind: applyShellCommand, dir: toPath,
+			command: strings.ReplaceAll(integ.Setup.Command, "{path}", git.ShellQuote(toPath)),
+		}
+		if integ.Setup.WorkingDir == "repo" {
+			op.dir = repoPath
+		}
+		operations = append(operations, op)
+	}
+
+	if err := validateOperationGraph(operations); err != nil {
+		return PreparedApply{}, fmt.Errorf("preparing setup: %w", err)
+	}
+	return PreparedApply{plan: planForOperations(operations), operations: operations, files: realApplyFileOperations{}}, nil
+}
@@ -0,0 +1,160 @@
+package integration
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/progress"
+)
+
+func refreshIntegration(name, workingDir string) Integration {
+	integ := testIntegration(name)
+	integ.Setup.WorkingDir = workingDir
+	integ.Refresh = RefreshSpec{Command: name + " refresh {path}"}
+	integ.GitignoreEntries = []string{"." + name + "/"}
+	return integ
+}
+
+func TestPrepareRefresh_PlansOneStepPerWorktreeAndIntegration(t *testing.T) {
+	repo := t.TempDir()
+	wtA, wtB := filepath.Join(repo, "a"), filepath.Join(repo, "b")
+	for _, dir := range []string{filepath.Join(wtA, ".graph"), filepath.Join(wtA, ".index"), filepath.Join(wtB, ".index")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	noRefresh := testIntegration("static")
+
+	prepared, err := PrepareRefresh(repo, []Integration{refreshIntegration("graph", "repo"), refreshIntegration("index", "worktree"), noRefresh}, []string{wtA, wtB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantLabels := []string{
+		wtA + "/Refresh graph", wtA + "/Refresh index",
+		wtB + "/Refresh graph", wtB + "/Refresh index",
+	}
+	if got := plannedLabels(prepared.Plan()); !reflect.DeepEqual(got, wantLabels) {
+		t.Fatalf("planned labels = %v, want %v", got, wantLabels)
+	}
+
+	shell := &applyShell{responses: map[string]mockShellResponse{
+		repo + ":shell[graph refresh '" + wtA + "']": {},
+		wtA + ":shell[index refresh '" + wtA + "']":  {},
+		wtB + ":shell[index refresh '" + wtB + "']":  {},
+	}}
+	events, _ := collectPreparedEvents(prepared, shell)
+	assertSettledStream(t, events)
+	if len(shell.calls) != 3 {
+		t.Fatalf("shell calls = %v, want 3", shell.calls)
+	}
+	skipped := false
+	for _, phase := range progress.Snapshot(events) {
+		for _, step := range phase.Steps {
+			if phase.Name == wtB && step.Name == "Refresh graph" {
+				skipped = step.Status == progress.StepSkipped && step.Message == "not set up in this worktree"
+			}
+		}
+	}
+	if !skipped {
+		t.Fatal("graph refresh in a worktree without its index was not skipped")
+	}
+}
+
+func TestPrepareRefresh_FailureInOneWorktreeLeavesOthersRunning(t *testing.T) {
+	integ := refreshIntegration("index", "worktree")
+	integ.GitignoreEntries = nil
+	wtPaths := []string{"/wt/a", "/wt/b", "/wt/c"}
+
+	prepared, err := PrepareRefresh("/repo", []Integration{integ}, wtPaths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shell := &applyShell{responses: map[string]mockShellResponse{
+		"/wt/a:shell[index refresh '/wt/a']": {},
+		"/wt/b:shell[index refresh '/wt/b']": {err: errors.New("index locked")},
+		"/wt/c:shell[index refresh '/wt/c']": {},
+	}}
+	events, phases := collectPreparedEvents(prepared, shell)
+	assertSettledStream(t, events)
+
+	var failed []string
+	for _, phase := range phases {
+		if phase.HasFailures() {
+			failed = append(failed, phase.Name)
+		}
+	}
+	if !reflect.DeepEqual(failed, []string{"/wt/b"}) {
+		t.Fatalf("failed phases = %v, want [/wt/b]", failed)
+	}
+}
+
+func TestPrepareRefresh_RunsWorktreesConcurrently(t *testing.T) {
+	integ := refreshIntegration("index", "worktree")
+	integ.GitignoreEntries = nil
+	prepared, err := PrepareRefresh("/repo", []Integration{integ}, []string{"/wt/a", "/wt/b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each refresh waits for the other to start, so a sequential run would
+	// time out.
+	var started sync.WaitGroup
+	started.Add(2)
+	shell := applyShellFunc(func(dir, command string) (string, error) {
+		started.Done()
+		done := make(chan struct{})
+		go func() { started.Wait(); close(done) }()
+		select {
+		case <-done:
+			return "", nil
+		case <-time.After(5 * time.Second):
+			return "", errors.New("refreshes did not overlap")
+		}
+	})
+	phases, err := prepared.Run(shell, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress.PhasesHaveFailures(phases) {
+		t.Fatalf("phases failed: %#v", phases)
+	}
+}
+
+func TestPrepareRefresh_NothingRefreshableIsEmpty(t *testing.T) {
+	prepared, err := PrepareRefresh("/repo", []Integration{testIntegration("static")}, []string{"/wt/a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !prepared.Empty() {
+		t.Fatalf("plan = %v, want empty", plannedLabels(prepared.Plan()))
+	}
+}
+
+func TestPrepareResetup_PlansPathSetupsPresentAtTheOldPath(t *testing.T) {
+	repo := t.TempDir()
+	from, to := filepath.Join(repo, "old"), filepath.Join(repo, "new")
+	for _, dir := range []string{filepath.Join(from, ".graph"), filepath.Join(from, ".index")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	graph := refreshIntegration("graph", "repo")
+	graph.Setup.Command = "graph build {path}"
+	index := refreshIntegration("index", "worktree")
+	index.Setup.Command = "index init" // does not embed {path}
+	absent := refreshIntegration("absent", "repo")
+	absent.Setup.Command = "absent build {path}"
+
+	prepared, err := PrepareResetup(repo, []Integration{graph, index, absent}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := plannedLabels(prepared.Plan()), []string{to + "/Setup graph"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("planned labels = %v, want %v", got, want)
+	}
+}
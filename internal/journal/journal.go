@@ -0,0 +1,273 @@
+// Package journal records what sentei's destructive commands did, so
+// `sentei undo` can put it back.
+//
+// The journal is <git common dir>/sentei-journal.jsonl, next to sentei.json:
+// one Entry per line, appended after each remove or cleanup run that changed
+// something. An entry holds enough to invert every step — branch tips,
+// removed config sections verbatim, worktree paths and heads, pruned remote
+// ref tips. Duplicate config lines dropped by deduplication are not recorded:
+// removing a repeated line changes nothing git reads.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/trash"
+)
+
+const (
+	fileName    = "sentei-journal.jsonl"
+	stampLayout = "20060102T150405Z"
+)
+
+// ErrNotFound is returned when no entry has the requested ID.
+var ErrNotFound = errors.New("journal entry not found")
+
+// ErrNothingToUndo is returned when every entry has already been undone.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// Entry is one destructive run.
+type Entry struct {
+	ID        string    `json:"id"`
+	Command   string    `json:"command"`
+	CreatedAt time.Time `json:"created_at"`
+	// UndoneAt is set once `sentei undo` has fully reversed the entry.
+	UndoneAt *time.Time `json:"undone_at,omitempty"`
+
+	Branches       []Branch        `json:"branches,omitempty"`
+	ConfigSections []ConfigSection `json:"config_sections,omitempty"`
+	Worktrees      []Worktree      `json:"worktrees,omitempty"`
+	RemoteRefs     []Ref           `json:"remote_refs,omitempty"`
+}
+
+// Branch is a deleted local branch and the commit it pointed at.
+type Branch struct {
+	Name string `json:"name"`
+	SHA  string `json:"sha"`
+}
+
+// ConfigSection is a section removed from the repository's git config,
+// kept as the raw lines (header first) so undo can append it back verbatim.
+type ConfigSection struct {
+	Header string   `json:"header"`
+	Lines  []string `json:"lines"`
+}
+
+// Worktree is a removed worktree. ArchiveID is set when remove --archive
+// snapshotted it first; undo then restores the archive instead of checking
+// out Head.
+type Worktree struct {
+	Path      string `json:"path"`
+	Branch    string `json:"branch,omitempty"`
+	Head      string `json:"head"`
+	ArchiveID string `json:"archive_id,omitempty"`
+}
+
+// Ref is a pruned remote-tracking ref (full name, e.g.
+// refs/remotes/origin/feature) and its last known tip.
+type Ref struct {
+	Name string `json:"name"`
+	SHA  string `json:"sha"`
+}
+
+// Empty reports whether the entry records nothing to undo.
+func (e Entry) Empty() bool {
+	return len(e.Branches) == 0 && len(e.ConfigSections) == 0 && len(e.Worktrees) == 0 && len(e.RemoteRefs) == 0
+}
+
+// Undone reports whether the entry has been reversed.
+func (e Entry) Undone() bool {
+	return e.UndoneAt != nil
+}
+
+// Path returns the journal file for a git common dir.
+func Path(commonDir string) string {
+	return filepath.Join(commonDir, fileName)
+}
+
+// Append assigns e an ID and timestamp, then appends it to the journal.
+// An empty entry is not written.
+func Append(commonDir string, e Entry, now time.Time) (Entry, error) {
+	if e.Empty() {
+		return e, nil
+	}
+	entries, err := List(commonDir)
+	if err != nil {
+		return e, err
+	}
+	e.CreatedAt = now.UTC().Truncate(time.Second)
+	e.ID = uniqueID(entries, e.CreatedAt)
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return e, fmt.Errorf("encoding journal entry: %w", err)
+	}
+	f, err := os.OpenFile(Path(commonDir), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return e, fmt.Errorf("opening journal: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return e, fmt.Errorf("writing journal: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return e, fmt.Errorf("closing journal: %w", err)
+	}
+	return e, nil
+}
+
+// List returns every entry, oldest first. A missing journal is empty.
+func List(commonDir string) ([]Entry, error) {
+	f, err := os.Open(Path(commonDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading journal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal([]byte(text), &e); err != nil {
+			return nil, fmt.Errorf("parsing journal line %d: %w", line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading journal: %w", err)
+	}
+	return entries, nil
+}
+
+// Find returns the entry with the given ID.
+func Find(commonDir, id string) (Entry, error) {
+	entries, err := List(commonDir)
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// Last returns the newest entry that has not been undone.
+func Last(commonDir string) (Entry, error) {
+	entries, err := List(commonDir)
+	if err != nil {
+		return Entry{}, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !entries[i].Undone() {
+			return entries[i], nil
+		}
+	}
+	return Entry{}, ErrNothingToUndo
+}
+
+// MarkUndone stamps the entry with the given ID as undone, rewriting the
+// journal atomically.
+func MarkUndone(commonDir, id string, now time.Time) error {
+	entries, err := List(commonDir)
+	if err != nil {
+		return err
+	}
+	found := false
+	var b strings.Builder
+	for _, e := range entries {
+		if e.ID == id {
+			at := now.UTC().Truncate(time.Second)
+			e.UndoneAt = &at
+			found = true
+		}
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("encoding journal entry: %w", err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return writeAtomic(Path(commonDir), b.String())
+}
+
+func writeAtomic(path, content string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".sentei-journal-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	if _, err := tmp.WriteString(content); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("writing journal: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("renaming journal: %w", err)
+	}
+	return nil
+}
+
+// uniqueID returns the timestamp, suffixed when another entry was written
+// in the same second.
+func uniqueID(entries []Entry, created time.Time) string {
+	taken := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		taken[e.ID] = true
+	}
+	base := created.Format(stampLayout)
+	id := base
+	for n := 2; taken[id]; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	return id
+}
+
+// RecordRemovals appends a remove entry for the given worktrees. A worktree
+// archived at or after since is linked to its archive, so undo brings back
+// its uncommitted and untracked files too.
+func RecordRemovals(commonDir string, removed []git.Worktree, since, now time.Time) (Entry, error) {
+	archives, err := trash.List(commonDir)
+	if err != nil {
+		return Entry{}, err
+	}
+	since = since.UTC().Truncate(time.Second)
+
+	e := Entry{Command: "remove"}
+	for _, wt := range removed {
+		rec := Worktree{Path: wt.Path, Branch: strings.TrimPrefix(wt.Branch, "refs/heads/"), Head: wt.HEAD}
+		for _, a := range archives {
+			if a.WorktreePath == wt.Path && !a.CreatedAt.Before(since) {
+				rec.ArchiveID = a.ID
+				break
+			}
+		}
+		e.Worktrees = append(e.Worktrees, rec)
+	}
+	return Append(commonDir, e, now)
+}
@@ -0,0 +1,68 @@
+package journal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var stamp = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func TestAppend_AssignsUniqueIDsAndSkipsEmptyEntries(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := Append(dir, Entry{Command: "cleanup"}, stamp); err != nil {
+		t.Fatalf("Append(empty) error = %v", err)
+	}
+	first, err := Append(dir, Entry{Command: "cleanup", Branches: []Branch{{Name: "a", SHA: "1"}}}, stamp)
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	second, err := Append(dir, Entry{Command: "remove", Worktrees: []Worktree{{Path: "/wt", Head: "2"}}}, stamp)
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if first.ID != "20261018T120000Z" || second.ID != "20261018T120000Z-2" {
+		t.Errorf("IDs = %q, %q", first.ID, second.ID)
+	}
+
+	entries, err := List(dir)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Command != "cleanup" || entries[1].Command != "remove" {
+		t.Errorf("List() = %+v, want the two non-empty entries oldest first", entries)
+	}
+}
+
+func TestLast_SkipsUndoneEntries(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Last(dir); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("Last() on empty journal error = %v, want ErrNothingToUndo", err)
+	}
+
+	first, _ := Append(dir, Entry{Command: "cleanup", Branches: []Branch{{Name: "a", SHA: "1"}}}, stamp)
+	second, _ := Append(dir, Entry{Command: "remove", Worktrees: []Worktree{{Path: "/wt", Head: "2"}}}, stamp.Add(time.Minute))
+
+	if err := MarkUndone(dir, second.ID, stamp.Add(time.Hour)); err != nil {
+		t.Fatalf("MarkUndone() error = %v", err)
+	}
+	last, err := Last(dir)
+	if err != nil {
+		t.Fatalf("Last() error = %v", err)
+	}
+	if last.ID != first.ID {
+		t.Errorf("Last() = %q, want %q", last.ID, first.ID)
+	}
+	undone, err := Find(dir, second.ID)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if !undone.Undone() {
+		t.Error("entry should be marked undone")
+	}
+
+	if err := MarkUndone(dir, "missing", stamp); !errors.Is(err, ErrNotFound) {
+		t.Errorf("MarkUndone(missing) error = %v, want ErrNotFound", err)
+	}
+}
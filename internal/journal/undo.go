@@ -0,0 +1,171 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/trash"
+)
+
+// OutcomeStatus is the result of reversing one recorded step.
+type OutcomeStatus string
+
+const (
+	OutcomeRestored OutcomeStatus = "restored"
+	// OutcomeSkipped means the target already exists again (restored by hand
+	// or by an earlier, partial undo), so there was nothing to do.
+	OutcomeSkipped OutcomeStatus = "skipped"
+	OutcomeFailed  OutcomeStatus = "failed"
+)
+
+// Outcome reports one reversed step.
+type Outcome struct {
+	Kind   string // "remote-ref", "branch", "config-section" or "worktree"
+	Target string
+	Status OutcomeStatus
+	Err    error
+}
+
+// Tips returns the commit every local branch and remote-tracking ref points
+// at, keyed by full ref name. Destructive commands take it before deleting
+// refs so their entries can record the tips.
+func Tips(runner git.CommandRunner, repoPath string) (map[string]string, error) {
+	out, err := runner.Run(repoPath, "for-each-ref", "--format=%(objectname) %(refname)", "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, fmt.Errorf("reading ref tips: %w", err)
+	}
+	tips := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		sha, name, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if ok {
+			tips[name] = sha
+		}
+	}
+	return tips, nil
+}
+
+// Undo reverses e as far as the repository allows: remote refs first, then
+// branches (worktrees and config sections may refer to them), then config
+// sections and finally worktrees. Every step is attempted; a target that
+// already exists is skipped rather than overwritten. The returned error
+// joins the failures.
+func Undo(runner git.CommandRunner, repoPath, commonDir string, e Entry) ([]Outcome, error) {
+	var outcomes []Outcome
+	var errs error
+	record := func(kind, target string, skipped bool, err error) {
+		o := Outcome{Kind: kind, Target: target, Status: OutcomeRestored}
+		switch {
+		case err != nil:
+			o.Status, o.Err = OutcomeFailed, err
+			errs = errors.Join(errs, fmt.Errorf("%s %s: %w", kind, target, err))
+		case skipped:
+			o.Status = OutcomeSkipped
+		}
+		outcomes = append(outcomes, o)
+	}
+
+	for _, ref := range e.RemoteRefs {
+		skipped, err := restoreRef(runner, repoPath, ref.Name, ref.SHA)
+		record("remote-ref", strings.TrimPrefix(ref.Name, "refs/remotes/"), skipped, err)
+	}
+	for _, b := range e.Branches {
+		skipped, err := restoreRef(runner, repoPath, "refs/heads/"+b.Name, b.SHA)
+		record("branch", b.Name, skipped, err)
+	}
+	for _, section := range e.ConfigSections {
+		skipped, err := restoreConfigSection(filepath.Join(commonDir, "config"), section)
+		record("config-section", section.Header, skipped, err)
+	}
+	for _, wt := range e.Worktrees {
+		skipped, err := restoreWorktree(runner, repoPath, commonDir, wt)
+		record("worktree", wt.Path, skipped, err)
+	}
+	return outcomes, errs
+}
+
+// restoreRef recreates ref at sha unless it exists. update-ref with an empty
+// old value refuses to overwrite a ref created in the meantime.
+func restoreRef(runner git.CommandRunner, repoPath, ref, sha string) (bool, error) {
+	if refExists(runner, repoPath, ref) {
+		return true, nil
+	}
+	if sha == "" {
+		return false, fmt.Errorf("tip was not recorded")
+	}
+	if _, err := runner.Run(repoPath, "update-ref", ref, sha, ""); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+func refExists(runner git.CommandRunner, repoPath, ref string) bool {
+	_, err := runner.Run(repoPath, "show-ref", "--verify", "--quiet", ref)
+	return err == nil
+}
+
+// restoreConfigSection appends section to the config file unless a section
+// with the same header is already there.
+func restoreConfigSection(configPath string, section ConfigSection) (bool, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return false, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == strings.TrimSpace(section.Header) {
+			return true, nil
+		}
+	}
+	var b strings.Builder
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		b.WriteByte('\n')
+	}
+	for _, line := range section.Lines {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	f, err := os.OpenFile(configPath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return false, err
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		_ = f.Close()
+		return false, err
+	}
+	return false, f.Close()
+}
+
+// restoreWorktree re-adds a removed worktree. An archived worktree comes
+// back with its uncommitted and untracked files; otherwise it is checked out
+// clean at its recorded branch or head.
+func restoreWorktree(runner git.CommandRunner, repoPath, commonDir string, wt Worktree) (bool, error) {
+	if _, err := os.Stat(wt.Path); err == nil {
+		return true, nil
+	}
+	if wt.ArchiveID != "" {
+		a, err := trash.Find(commonDir, wt.ArchiveID)
+		if err == nil {
+			_, err := trash.Restore(runner, repoPath, commonDir, a, wt.Path)
+			return false, err
+		}
+		if !errors.Is(err, trash.ErrNotFound) {
+			return false, err
+		}
+		// The archive was restored or purged; fall back to the recorded head.
+	}
+
+	var args []string
+	switch {
+	case wt.Branch == "":
+		args = []string{"worktree", "add", "--detach", wt.Path, wt.Head}
+	case refExists(runner, repoPath, "refs/heads/"+wt.Branch):
+		args = []string{"worktree", "add", wt.Path, wt.Branch}
+	default:
+		args = []string{"worktree", "add", "-b", wt.Branch, wt.Path, wt.Head}
+	}
+	_, err := runner.Run(repoPath, args...)
+	return false, err
+}
@@ -0,0 +1,101 @@
+package journal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/git"
+)
+
+func mustGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// setupRepo creates a repo with one commit and a branch feature, returning
+// the repo dir and its git common dir.
+func setupRepo(t *testing.T) (repoDir, commonDir string) {
+	t.Helper()
+	base := t.TempDir()
+	repoDir = filepath.Join(base, "repo")
+	mustGit(t, base, "init", "--initial-branch=main", repoDir)
+	if err := os.WriteFile(filepath.Join(repoDir, "file.txt"), []byte("v1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mustGit(t, repoDir, "add", ".")
+	mustGit(t, repoDir, "commit", "-m", "initial")
+	mustGit(t, repoDir, "branch", "feature")
+	return repoDir, filepath.Join(repoDir, ".git")
+}
+
+func TestUndo_RestoresBranchesRefsConfigAndWorktrees(t *testing.T) {
+	repoDir, commonDir := setupRepo(t)
+	runner := &git.GitRunner{}
+	head := mustGit(t, repoDir, "rev-parse", "HEAD")
+
+	tips, err := Tips(runner, repoDir)
+	if err != nil {
+		t.Fatalf("Tips() error = %v", err)
+	}
+	if tips["refs/heads/feature"] != head {
+		t.Fatalf("Tips() = %v, want feature at %s", tips, head)
+	}
+
+	mustGit(t, repoDir, "branch", "-D", "feature")
+	wtPath := filepath.Join(filepath.Dir(repoDir), "feature-wt")
+	e := Entry{
+		Branches:       []Branch{{Name: "feature", SHA: head}},
+		RemoteRefs:     []Ref{{Name: "refs/remotes/origin/feature", SHA: head}},
+		ConfigSections: []ConfigSection{{Header: `[branch "feature"]`, Lines: []string{`[branch "feature"]`, "\tremote = origin"}}},
+		Worktrees:      []Worktree{{Path: wtPath, Branch: "feature", Head: head}},
+	}
+
+	outcomes, err := Undo(runner, repoDir, commonDir, e)
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	for _, o := range outcomes {
+		if o.Status != OutcomeRestored {
+			t.Errorf("%s %s: status %s", o.Kind, o.Target, o.Status)
+		}
+	}
+	if got := mustGit(t, repoDir, "rev-parse", "refs/remotes/origin/feature"); got != head {
+		t.Errorf("origin/feature = %s, want %s", got, head)
+	}
+	if got := mustGit(t, repoDir, "config", "branch.feature.remote"); got != "origin" {
+		t.Errorf("branch.feature.remote = %q, want origin", got)
+	}
+	if got := mustGit(t, wtPath, "rev-parse", "--abbrev-ref", "HEAD"); got != "feature" {
+		t.Errorf("worktree branch = %q, want feature", got)
+	}
+
+	// A second undo finds everything in place and changes nothing.
+	outcomes, err = Undo(runner, repoDir, commonDir, e)
+	if err != nil {
+		t.Fatalf("second Undo() error = %v", err)
+	}
+	for _, o := range outcomes {
+		if o.Status != OutcomeSkipped {
+			t.Errorf("second undo: %s %s: status %s, want skipped", o.Kind, o.Target, o.Status)
+		}
+	}
+}
+
+func TestUndo_ReportsBranchWithoutRecordedTip(t *testing.T) {
+	repoDir, commonDir := setupRepo(t)
+
+	outcomes, err := Undo(&git.GitRunner{}, repoDir, commonDir, Entry{Branches: []Branch{{Name: "gone"}}})
+	if err == nil {
+		t.Fatal("Undo() should fail without a recorded tip")
+	}
+	if len(outcomes) != 1 || outcomes[0].Status != OutcomeFailed {
+		t.Errorf("outcomes = %+v, want one failure", outcomes)
+	}
+}
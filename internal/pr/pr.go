@@ -0,0 +1,164 @@
+// Package pr looks up the pull request state of branches through the gh CLI,
+// so branches merged by squash or rebase on GitHub — which git's ancestry
+// checks report as unmerged — can still be found. It also resolves a pull
+// request number to the branch it was opened from.
+package pr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/abiswas97/sentei/internal/repo"
+)
+
+// State is a branch's pull request state as gh reports it.
+type State string
+
+const (
+	// StateNone means no pull request was opened from the branch.
+	StateNone   State = ""
+	StateOpen   State = "OPEN"
+	StateMerged State = "MERGED"
+	StateClosed State = "CLOSED"
+)
+
+// Label is the lower-case form shown in tables; "-" for StateNone.
+func (s State) Label() string {
+	if s == StateNone {
+		return "-"
+	}
+	return strings.ToLower(string(s))
+}
+
+// ErrUnavailable wraps the failure that disabled a Checker: gh missing, not
+// authenticated, or the repository not hosted on GitHub.
+var ErrUnavailable = errors.New("pull request status unavailable")
+
+// Checker answers pull request state per branch, querying gh at most once
+// per branch. The first gh failure disables it for the rest of the run: the
+// same failure would repeat for every branch. A Checker is safe for
+// concurrent use.
+type Checker struct {
+	gh  repo.GhRunner
+	dir string
+
+	mu     sync.Mutex
+	states map[string]State
+	err    error
+}
+
+// NewChecker returns a Checker that runs gh in dir.
+func NewChecker(gh repo.GhRunner, dir string) *Checker {
+	return &Checker{gh: gh, dir: dir, states: make(map[string]State)}
+}
+
+// State returns the pull request state for a short branch name. Once the
+// Checker is disabled it returns an error wrapping ErrUnavailable.
+func (c *Checker) State(branch string) (State, error) {
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return StateNone, err
+	}
+	if s, ok := c.states[branch]; ok {
+		c.mu.Unlock()
+		return s, nil
+	}
+	c.mu.Unlock()
+
+	out, err := c.gh.RunGh(c.dir, "pr", "list", "--head", branch, "--state", "all", "--json", "state,isCrossRepository")
+	var s State
+	if err == nil {
+		s, err = ParseStates(out)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		if c.err == nil {
+			c.err = fmt.Errorf("%w: %s", ErrUnavailable, describe(err))
+		}
+		return StateNone, c.err
+	}
+	c.states[branch] = s
+	return s, nil
+}
+
+// Merged reports whether branch has a merged pull request and no open one.
+// It is false whenever the state cannot be determined.
+func (c *Checker) Merged(branch string) bool {
+	s, err := c.State(branch)
+	return err == nil && s == StateMerged
+}
+
+// Err returns the failure that disabled the Checker, or nil.
+func (c *Checker) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// ParseStates reduces `gh pr list --json state,isCrossRepository` output to
+// one state. Pull requests from forks are left out: --head matches a fork's
+// branch of the same name, which says nothing about the local one. A branch
+// reused after its pull request merged can have several; an open one wins
+// (the branch still has work under review), then merged, then closed.
+func ParseStates(output string) (State, error) {
+	var prs []struct {
+		State             State `json:"state"`
+		IsCrossRepository bool  `json:"isCrossRepository"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &prs); err != nil {
+		return StateNone, fmt.Errorf("parsing gh output: %w", err)
+	}
+	best := StateNone
+	rank := map[State]int{StateNone: 0, StateClosed: 1, StateMerged: 2, StateOpen: 3}
+	for _, p := range prs {
+		if !p.IsCrossRepository && rank[p.State] > rank[best] {
+			best = p.State
+		}
+	}
+	return best, nil
+}
+
+// Head is the branch a pull request was opened from.
+type Head struct {
+	Number int
+	Branch string
+	// CrossRepository is set when the branch lives in a fork, which the
+	// repository's remotes cannot fetch by branch name.
+	CrossRepository bool
+}
+
+// ResolveHead asks gh, run in dir, for pull request number's head branch. A
+// failure wraps ErrUnavailable.
+func ResolveHead(gh repo.GhRunner, dir string, number int) (Head, error) {
+	out, err := gh.RunGh(dir, "pr", "view", strconv.Itoa(number), "--json", "headRefName,isCrossRepository")
+	if err != nil {
+		return Head{}, fmt.Errorf("%w: %s", ErrUnavailable, describe(err))
+	}
+	var view struct {
+		HeadRefName       string `json:"headRefName"`
+		IsCrossRepository bool   `json:"isCrossRepository"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &view); err != nil {
+		return Head{}, fmt.Errorf("parsing gh output: %w", err)
+	}
+	if view.HeadRefName == "" {
+		return Head{}, fmt.Errorf("pull request #%d has no head branch", number)
+	}
+	return Head{Number: number, Branch: view.HeadRefName, CrossRepository: view.IsCrossRepository}, nil
+}
+
+func describe(err error) string {
+	if errors.Is(err, exec.ErrNotFound) {
+		return "gh not found"
+	}
+	return err.Error()
+}
@@ -0,0 +1,102 @@
+package pr
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/testutil/mock"
+)
+
+func prList(branch string) string {
+	return "/repo:gh[pr list --head " + branch + " --state all --json state,isCrossRepository]"
+}
+
+func TestParseStates(t *testing.T) {
+	cases := []struct {
+		output string
+		want   State
+	}{
+		{`[]`, StateNone},
+		{`[{"state":"MERGED"}]`, StateMerged},
+		{`[{"state":"CLOSED"}]`, StateClosed},
+		{`[{"state":"MERGED"},{"state":"OPEN"}]`, StateOpen},
+		{`[{"state":"CLOSED"},{"state":"MERGED"}]`, StateMerged},
+		{`[{"state":"MERGED","isCrossRepository":true}]`, StateNone},
+		{`[{"state":"MERGED","isCrossRepository":true},{"state":"CLOSED","isCrossRepository":false}]`, StateClosed},
+	}
+	for _, tc := range cases {
+		got, err := ParseStates(tc.output)
+		if err != nil || got != tc.want {
+			t.Errorf("ParseStates(%s) = %q, %v; want %q", tc.output, got, err, tc.want)
+		}
+	}
+	if _, err := ParseStates("not json"); err == nil {
+		t.Error("expected an error for unparsable output")
+	}
+}
+
+func TestChecker_CachesPerBranch(t *testing.T) {
+	gh := &mock.Runner{Responses: map[string]mock.Response{
+		prList("feature/squashed"): {Output: `[{"state":"MERGED"}]`},
+		prList("feature/open"):     {Output: `[{"state":"OPEN"}]`},
+		prList("feature/none"):     {Output: `[]`},
+	}}
+	c := NewChecker(gh, "/repo")
+
+	for i := 0; i < 2; i++ {
+		if !c.Merged("feature/squashed") {
+			t.Error("squash-merged PR should report merged")
+		}
+		if c.Merged("feature/open") || c.Merged("feature/none") {
+			t.Error("open or missing PRs should not report merged")
+		}
+	}
+	if len(gh.Calls) != 3 {
+		t.Errorf("expected one gh call per branch, got %v", gh.Calls)
+	}
+	if s, _ := c.State("feature/none"); s.Label() != "-" {
+		t.Errorf("Label for no PR = %q, want -", s.Label())
+	}
+}
+
+func TestChecker_DisablesAfterFirstFailure(t *testing.T) {
+	gh := &mock.Runner{Responses: map[string]mock.Response{
+		prList("a"): {Err: fmt.Errorf("gh pr list: %w", &exec.Error{Name: "gh", Err: exec.ErrNotFound})},
+	}}
+	c := NewChecker(gh, "/repo")
+
+	if c.Merged("a") || c.Merged("b") {
+		t.Error("an unavailable checker never reports merged")
+	}
+	if len(gh.Calls) != 1 {
+		t.Errorf("gh should not be retried after failing, calls = %v", gh.Calls)
+	}
+	err := c.Err()
+	if !errors.Is(err, ErrUnavailable) || !strings.Contains(err.Error(), "gh not found") {
+		t.Errorf("Err() = %v, want ErrUnavailable mentioning gh not found", err)
+	}
+}
+
+func TestResolveHead(t *testing.T) {
+	view := func(n string) string { return "/repo:gh[pr view " + n + " --json headRefName,isCrossRepository]" }
+	gh := &mock.Runner{Responses: map[string]mock.Response{
+		view("42"): {Output: `{"headRefName":"feature/x","isCrossRepository":false}`},
+		view("7"):  {Output: `{"headRefName":"main","isCrossRepository":true}`},
+		view("9"):  {Err: errors.New("no pull requests found")},
+	}}
+
+	head, err := ResolveHead(gh, "/repo", 42)
+	if err != nil || head != (Head{Number: 42, Branch: "feature/x"}) {
+		t.Errorf("ResolveHead(42) = %+v, %v", head, err)
+	}
+	head, err = ResolveHead(gh, "/repo", 7)
+	if err != nil || !head.CrossRepository || head.Branch != "main" {
+		t.Errorf("ResolveHead(7) = %+v, %v; want a fork's main", head, err)
+	}
+	if _, err := ResolveHead(gh, "/repo", 9); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("ResolveHead(9) error = %v, want ErrUnavailable", err)
+	}
+}
@@ -5,6 +5,7 @@ package repo
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/abiswas97/sentei/internal/git"
@@ -223,3 +224,166 @@ func TestE2E_MigrateRepo(t *testing.T) {
 		t.Error("backup should be deleted after cleanup")
 	}
 }
+
+func TestE2E_MigrateCarriesRepositoryState(t *testing.T) {
+	dir := testtmp.RobustTempDir(t)
+	repoPath := filepath.Join(dir, "to-migrate")
+	os.MkdirAll(repoPath, 0755)
+
+	runner := &git.GitRunner{}
+	shell := &git.DefaultShellRunner{}
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := runner.Run(repoPath, append([]string{"-c", "user.email=test@test.com", "-c", "user.name=Test"}, args...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	run("init")
+	run("checkout", "-b", "main")
+	os.WriteFile(filepath.Join(repoPath, "file.txt"), []byte("hello"), 0644)
+	os.MkdirAll(filepath.Join(repoPath, "build"), 0755)
+	os.WriteFile(filepath.Join(repoPath, "build", "keep.txt"), []byte("tracked"), 0644)
+	run("add", "file.txt", "build/keep.txt")
+	run("commit", "-m", "init")
+	run("remote", "add", "upstream", "https://example.com/upstream.git")
+	run("config", "user.name", "Local Name")
+	run("config", "core.hooksPath", ".githooks")
+	os.WriteFile(filepath.Join(repoPath, ".git", "hooks", "pre-commit"), []byte("#!/bin/sh\nexit 0\n"), 0755)
+	os.WriteFile(filepath.Join(repoPath, ".git", "info", "exclude"), []byte("# git ls-files --others\nscratch/\n"), 0644)
+	os.WriteFile(filepath.Join(repoPath, "file.txt"), []byte("stashed"), 0644)
+	run("stash", "push", "-m", "wip")
+	run("worktree", "add", "-b", "side", filepath.Join(dir, "side"))
+	run("worktree", "add", "-b", "nested", filepath.Join(repoPath, ".worktrees", "nested"))
+	run("worktree", "add", "-b", "build-wt", filepath.Join(repoPath, "build", "wt"))
+
+	inventory, err := InventoryMigration(runner, repoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inventory.Stashes) != 1 || len(inventory.Worktrees) != 3 || len(inventory.Hooks) != 1 || inventory.Exclude != 1 {
+		t.Fatalf("inventory = %+v", inventory)
+	}
+
+	result := Migrate(runner, shell, MigrateOptions{RepoPath: repoPath, Inventory: inventory}, (&mock.EventCollector[progress.Event]{}).Emit)
+	if result.Err != nil || progress.PhasesHaveFailures(result.Phases) {
+		t.Fatalf("migration failed: err=%v phases=%+v", result.Err, result.Phases)
+	}
+
+	barePath := filepath.Join(repoPath, ".bare")
+	if url, err := runner.Run(barePath, "remote", "get-url", "upstream"); err != nil || url != "https://example.com/upstream.git" {
+		t.Errorf("upstream remote = %q, %v", url, err)
+	}
+	if name, err := runner.Run(barePath, "config", "user.name"); err != nil || name != "Local Name" {
+		t.Errorf("user.name = %q, %v", name, err)
+	}
+	if hooks, err := runner.Run(barePath, "config", "core.hooksPath"); err != nil || hooks != ".githooks" {
+		t.Errorf("core.hooksPath = %q, %v", hooks, err)
+	}
+	if bare, err := runner.Run(barePath, "config", "--get-all", "core.bare"); err != nil || bare != "true" {
+		t.Errorf("core.bare = %q, %v", bare, err)
+	}
+	if _, err := os.Stat(filepath.Join(barePath, "hooks", "pre-commit")); err != nil {
+		t.Errorf("pre-commit hook not carried: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(barePath, "info", "exclude")); !strings.Contains(string(data), "scratch/") {
+		t.Errorf("info/exclude = %q", data)
+	}
+	if out, err := runner.Run(barePath, "show", "refs/stashes/0:file.txt"); err != nil || out != "stashed" {
+		t.Errorf("refs/stashes/0 file.txt = %q, %v", out, err)
+	}
+	for _, path := range []string{filepath.Join(dir, "side"), filepath.Join(repoPath, ".worktrees", "nested"), filepath.Join(repoPath, "build", "wt")} {
+		if _, err := runner.Run(path, "status", "--porcelain"); err != nil {
+			t.Errorf("worktree %s not usable after migration: %v", path, err)
+		}
+	}
+	listed, err := runner.Run(repoPath, "worktree", "list")
+	if err != nil || strings.Contains(listed, "prunable") || !strings.Contains(listed, "[side]") || !strings.Contains(listed, "[nested]") {
+		t.Errorf("worktree list = %q, %v", listed, err)
+	}
+	if _, err := os.Stat(filepath.Join(result.WorktreePath, ".worktrees")); !os.IsNotExist(err) {
+		t.Errorf("nested worktree copied into the new worktree: %v", err)
+	}
+	if status, err := runner.Run(result.WorktreePath, "status", "--porcelain"); err != nil || status != "" {
+		t.Errorf("new worktree status = %q, %v; build/keep.txt must come along beside build/wt", status, err)
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, "build", "keep.txt")); !os.IsNotExist(err) {
+		t.Errorf("build/keep.txt left at the root: %v", err)
+	}
+}
+
+func TestE2E_UnmigrateRoundTrip(t *testing.T) {
+	dir := testtmp.RobustTempDir(t)
+	repoPath := filepath.Join(dir, "round-trip")
+	os.MkdirAll(repoPath, 0755)
+
+	runner := &git.GitRunner{}
+	shell := &git.DefaultShellRunner{}
+	run := func(path string, args ...string) string {
+		t.Helper()
+		out, err := runner.Run(path, append([]string{"-c", "user.email=test@test.com", "-c", "user.name=Test"}, args...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	run(repoPath, "init")
+	run(repoPath, "checkout", "-b", "main")
+	os.MkdirAll(filepath.Join(repoPath, "main"), 0755)
+	os.WriteFile(filepath.Join(repoPath, "main", "nested.txt"), []byte("same name as the worktree dir"), 0644)
+	os.WriteFile(filepath.Join(repoPath, "file.txt"), []byte("hello"), 0644)
+	run(repoPath, "add", ".")
+	run(repoPath, "commit", "-m", "init")
+
+	migrated := Migrate(runner, shell, MigrateOptions{RepoPath: repoPath}, func(progress.Event) {})
+	if migrated.HasFailures() {
+		t.Fatalf("migration failed: err=%v phases=%+v", migrated.Err, migrated.Phases)
+	}
+	run(repoPath, "worktree", "add", "-b", "feature", git.WorktreePath(repoPath, "feature"))
+	run(repoPath, "worktree", "add", "-b", "outside", filepath.Join(dir, "outside"))
+	run(repoPath, "worktree", "add", "-b", "gone", git.WorktreePath(repoPath, "gone"))
+	os.RemoveAll(git.WorktreePath(repoPath, "gone"))
+	os.WriteFile(filepath.Join(migrated.WorktreePath, "file.txt"), []byte("edited"), 0644)
+
+	result := Unmigrate(runner, UnmigrateOptions{BareRoot: repoPath, Worktree: "main"}, func(progress.Event) {})
+	if result.HasFailures() {
+		t.Fatalf("unmigrate failed: err=%v phases=%+v", result.Err, result.Phases)
+	}
+	if !result.IsDirty || result.BackupPath == "" {
+		t.Errorf("result = %+v", result)
+	}
+	if len(result.Converted) != 2 || len(result.Dropped) != 1 {
+		t.Errorf("converted = %v, dropped = %v", result.Converted, result.Dropped)
+	}
+
+	if info, err := os.Stat(filepath.Join(repoPath, ".git")); err != nil || !info.IsDir() {
+		t.Fatalf(".git is not a directory: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, ".bare")); !os.IsNotExist(err) {
+		t.Errorf(".bare still present: %v", err)
+	}
+	if out := run(repoPath, "rev-parse", "--is-bare-repository"); out != "false" {
+		t.Errorf("is-bare-repository = %q", out)
+	}
+	if out := run(repoPath, "branch", "--show-current"); out != "main" {
+		t.Errorf("current branch = %q", out)
+	}
+	if out := run(repoPath, "status", "--porcelain"); out != "M file.txt" {
+		t.Errorf("status = %q, want only the carried edit", out)
+	}
+	if data, _ := os.ReadFile(filepath.Join(repoPath, "main", "nested.txt")); string(data) != "same name as the worktree dir" {
+		t.Errorf("main/nested.txt = %q", data)
+	}
+	for _, path := range []string{git.WorktreePath(repoPath, "feature"), filepath.Join(dir, "outside")} {
+		if _, err := runner.Run(path, "status", "--porcelain"); err != nil {
+			t.Errorf("worktree %s not usable after unmigrate: %v", path, err)
+		}
+	}
+	listed := run(repoPath, "worktree", "list")
+	if strings.Contains(listed, "prunable") || strings.Contains(listed, "[gone]") || !strings.Contains(listed, "[feature]") {
+		t.Errorf("worktree list = %q", listed)
+	}
+}
@@ -2,7 +2,6 @@ package repo
 
 import (
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -13,6 +12,9 @@ import (
 
 type MigrateOptions struct {
 	RepoPath string
+	// Inventory is what to carry over beyond branches, tags and origin, as
+	// InventoryMigration found it. The zero value carries nothing more.
+	Inventory MigrateInventory
 }
 
 type MigrateResult struct {
@@ -22,6 +24,7 @@ type MigrateResult struct {
 	BackupSize   string
 	Branch       string
 	IsDirty      bool
+	Inventory    MigrateInventory
 	Phases       []progress.Phase
 	Err          error
 }
@@ -70,38 +73,7 @@ func copyTree(src, dst string) error {
 }
 
 func calculateDirSize(path string) string {
-	var totalSize int64
-	_ = filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-		if !entry.IsDir() {
-			info, err := entry.Info()
-			if err == nil {
-				totalSize += info.Size()
-			}
-		}
-		return nil
-	})
-	return formatSize(totalSize)
-}
-
-func formatSize(bytes int64) string {
-	const (
-		KB = 1024
-		MB = KB * 1024
-		GB = MB * 1024
-	)
-	switch {
-	case bytes >= GB:
-		return fmt.Sprintf("%.1f GB", float64(bytes)/float64(GB))
-	case bytes >= MB:
-		return fmt.Sprintf("%.0f MB", float64(bytes)/float64(MB))
-	case bytes >= KB:
-		return fmt.Sprintf("%.0f KB", float64(bytes)/float64(KB))
-	default:
-		return fmt.Sprintf("%d B", bytes)
-	}
+	return fileutil.FormatSize(fileutil.DirSize(path))
 }
 
 func DeleteBackup(backupPath string) error {
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/abiswas97/sentei/internal/progress"
@@ -134,3 +135,29 @@ func TestPreparedMigrate_ResultMatchesCompletedStream(t *testing.T) {
 	result := prepared.run(func(event progress.Event) { events = append(events, event) })
 	assertRepoStreamParity(t, events, result.Phases)
 }
+
+func TestPrepareMigrate_DeclaresCarryOverFromInventory(t *testing.T) {
+	runner := &mock.Runner{Responses: map[string]mock.Response{"/repo:[remote get-url origin]": {}}}
+	inventory := MigrateInventory{
+		Stashes:      []MigrateStash{{Commit: "abc", Ref: "refs/stashes/0"}},
+		Hooks:        []string{"pre-commit"},
+		Worktrees:    []MigrateWorktree{{Path: "/elsewhere/side", admin: "side"}},
+		remoteConfig: []configEntry{{key: "remote.upstream.url", value: "https://example.com/u.git"}},
+		localConfig:  []configEntry{{key: "user.name", value: "Local"}},
+	}
+	prepared := prepareMigrate(runner, runner, MigrateOptions{RepoPath: "/repo", Inventory: inventory})
+
+	var carried []progress.StepID
+	for _, operation := range prepared.operations {
+		if operation.phaseID == "migrate:carry" {
+			carried = append(carried, operation.stepID)
+		}
+	}
+	want := []progress.StepID{"remotes", "config", "hooks", "stashes", "worktree:side"}
+	if fmt.Sprint(carried) != fmt.Sprint(want) {
+		t.Fatalf("carry-over steps = %v, want %v", carried, want)
+	}
+	if len(runner.Calls) != 1 {
+		t.Fatalf("preflight calls = %v, want only the origin lookup", runner.Calls)
+	}
+}
@@ -0,0 +1,316 @@
+package repo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/fileutil"
+	"github.com/abiswas97/sentei/internal/git"
+)
+
+// MigrateInventory is what a migration carries over from .git besides the
+// current branch and origin, and what it has to leave behind. The bare clone
+// alone keeps only branches and tags.
+type MigrateInventory struct {
+	Remotes   []MigrateRemote   // every remote, origin included
+	Stashes   []MigrateStash    // newest first, as git stash list shows them
+	Hooks     []string          // installed hooks; git's samples are left out
+	Exclude   int               // patterns in .git/info/exclude
+	Worktrees []MigrateWorktree // linked worktrees
+	Unmovable []string          // what stays behind, each with how to get it back
+
+	remoteConfig []configEntry // remote.* settings origin's restore does not cover
+	localConfig  []configEntry // repo-local settings outside remote and extensions, bare-owned core keys aside
+}
+
+// bareOwnedConfig are the core settings the bare clone writes for itself;
+// carrying the originals over would turn it back into a non-bare repository
+// or point it at the old layout.
+var bareOwnedConfig = map[string]bool{
+	"core.bare":                    true,
+	"core.repositoryformatversion": true,
+	"core.worktree":                true,
+	"core.logallrefupdates":        true,
+}
+
+type MigrateRemote struct {
+	Name string
+	URL  string
+}
+
+// MigrateStash is a stash entry. Migration keeps it as Ref in the bare
+// repository, where git stash apply accepts it.
+type MigrateStash struct {
+	Commit  string
+	Message string
+	Ref     string
+}
+
+// MigrateWorktree is a linked worktree, re-registered under the bare
+// repository where it is.
+type MigrateWorktree struct {
+	Path   string
+	Branch string // empty for a detached HEAD
+
+	admin string // its directory under .git/worktrees
+}
+
+type configEntry struct {
+	key   string
+	value string
+}
+
+// ConfigKeys lists the distinct repo-local settings carried over.
+func (inv MigrateInventory) ConfigKeys() []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, entry := range inv.localConfig {
+		if !seen[entry.key] {
+			seen[entry.key] = true
+			keys = append(keys, entry.key)
+		}
+	}
+	return keys
+}
+
+// Empty reports whether there is nothing beyond the bare clone to carry or
+// to report.
+func (inv MigrateInventory) Empty() bool {
+	return len(inv.Remotes) <= 1 && len(inv.remoteConfig) == 0 && len(inv.Stashes) == 0 &&
+		len(inv.Hooks) == 0 && inv.Exclude == 0 && len(inv.Worktrees) == 0 &&
+		len(inv.localConfig) == 0 && len(inv.Unmovable) == 0
+}
+
+// keepAtRoot names the linked worktrees inside the repository root, as paths
+// relative to it. Migration leaves each in place while clearing and
+// restoring the files around it.
+func (inv MigrateInventory) keepAtRoot(repoPath string) map[string]bool {
+	keep := map[string]bool{}
+	for _, wt := range inv.Worktrees {
+		rel, err := filepath.Rel(repoPath, wt.Path)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		keep[rel] = true
+	}
+	return keep
+}
+
+// outsideWorktrees lists, relative to root, the entries that neither are nor
+// hold a kept worktree: whole top-level entries where possible, and the
+// siblings along the way down to each nested worktree. .git and .bare are
+// left out.
+func outsideWorktrees(root string, keep map[string]bool) ([]string, error) {
+	holdsWorktree := func(rel string) bool {
+		for path := range keep {
+			if strings.HasPrefix(path, rel+string(filepath.Separator)) {
+				return true
+			}
+		}
+		return false
+	}
+	var items []string
+	var walk func(rel string) error
+	walk = func(rel string) error {
+		entries, err := os.ReadDir(filepath.Join(root, rel))
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			child := filepath.Join(rel, entry.Name())
+			switch {
+			case rel == "" && (entry.Name() == ".git" || entry.Name() == ".bare"):
+			case keep[child]:
+			case entry.IsDir() && holdsWorktree(child):
+				if err := walk(child); err != nil {
+					return err
+				}
+			default:
+				items = append(items, child)
+			}
+		}
+		return nil
+	}
+	return items, walk("")
+}
+
+// InventoryMigration reads what migrating the repository at repoPath would
+// carry over. It changes nothing, so the confirmation can show it and the
+// migration can be planned from exactly what was shown.
+func InventoryMigration(runner git.CommandRunner, repoPath string) (MigrateInventory, error) {
+	var inv MigrateInventory
+	gitDir := filepath.Join(repoPath, ".git")
+
+	config, err := runner.Run(repoPath, "config", "--local", "--list", "-z")
+	if err != nil {
+		return inv, fmt.Errorf("reading repository config: %w", err)
+	}
+	urls := map[string]string{}
+	var remoteNames []string
+	for _, entry := range parseConfigList(config) {
+		section, _, _ := strings.Cut(entry.key, ".")
+		switch section {
+		case "extensions":
+		case "remote":
+			name, field := remoteKey(entry.key)
+			if field == "url" {
+				if _, ok := urls[name]; !ok {
+					remoteNames = append(remoteNames, name)
+				}
+				urls[name] = entry.value
+			}
+			// origin's URL and fetch refspec are restored by their own steps.
+			if name != "origin" || (field != "url" && field != "fetch") {
+				inv.remoteConfig = append(inv.remoteConfig, entry)
+			}
+		default:
+			if !bareOwnedConfig[strings.ToLower(entry.key)] {
+				inv.localConfig = append(inv.localConfig, entry)
+			}
+		}
+	}
+	for _, name := range remoteNames {
+		inv.Remotes = append(inv.Remotes, MigrateRemote{Name: name, URL: urls[name]})
+	}
+
+	stashes, err := runner.Run(repoPath, "stash", "list", "--format=%H %gs")
+	if err != nil {
+		return inv, fmt.Errorf("listing stashes: %w", err)
+	}
+	for _, line := range strings.Split(stashes, "\n") {
+		commit, message, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if commit == "" {
+			continue
+		}
+		ref := fmt.Sprintf("refs/stashes/%d", len(inv.Stashes))
+		inv.Stashes = append(inv.Stashes, MigrateStash{Commit: commit, Message: message, Ref: ref})
+	}
+
+	if inv.Hooks, err = installedHooks(filepath.Join(gitDir, "hooks")); err != nil {
+		return inv, err
+	}
+	if inv.Exclude, err = countPatterns(filepath.Join(gitDir, "info", "exclude")); err != nil {
+		return inv, err
+	}
+
+	worktrees, err := git.ListWorktrees(runner, repoPath)
+	if err != nil {
+		return inv, err
+	}
+	for _, wt := range worktrees[min(1, len(worktrees)):] {
+		if wt.IsPrunable {
+			inv.Unmovable = append(inv.Unmovable, fmt.Sprintf("worktree %s: its directory is gone (git worktree prune clears it)", wt.Path))
+			continue
+		}
+		admin, err := worktreeAdminDir(wt.Path, gitDir)
+		if err != nil {
+			inv.Unmovable = append(inv.Unmovable, fmt.Sprintf("worktree %s: %v", wt.Path, err))
+			continue
+		}
+		inv.Worktrees = append(inv.Worktrees, MigrateWorktree{Path: wt.Path, Branch: strings.TrimPrefix(wt.Branch, "refs/heads/"), admin: admin})
+	}
+
+	tracking, err := runner.Run(repoPath, "for-each-ref", "--format=%(refname)", "refs/remotes")
+	if err != nil {
+		return inv, fmt.Errorf("listing remote-tracking branches: %w", err)
+	}
+	if n := len(strings.Fields(tracking)); n > 0 {
+		inv.Unmovable = append(inv.Unmovable, fmt.Sprintf("%d remote-tracking branches: git fetch --all restores them", n))
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "modules")); err == nil {
+		inv.Unmovable = append(inv.Unmovable, "submodule repositories in .git/modules: git submodule update --init restores them")
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "lfs", "objects")); err == nil {
+		inv.Unmovable = append(inv.Unmovable, "Git LFS objects in .git/lfs: git lfs fetch restores them")
+	}
+	return inv, nil
+}
+
+// parseConfigList parses git config --list -z: each entry is the key, then a
+// newline and the value unless the key stands alone, ended by a NUL.
+func parseConfigList(output string) []configEntry {
+	var entries []configEntry
+	for _, record := range strings.Split(output, "\x00") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(record, "\n")
+		entries = append(entries, configEntry{key: key, value: value})
+	}
+	return entries
+}
+
+// remoteKey splits remote.<name>.<field>; a remote name may contain dots.
+func remoteKey(key string) (name, field string) {
+	rest := strings.TrimPrefix(key, "remote.")
+	i := strings.LastIndex(rest, ".")
+	if i < 0 {
+		return rest, ""
+	}
+	return rest[:i], rest[i+1:]
+}
+
+func installedHooks(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading hooks: %w", err)
+	}
+	var hooks []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".sample") {
+			continue
+		}
+		hooks = append(hooks, entry.Name())
+	}
+	sort.Strings(hooks)
+	return hooks, nil
+}
+
+func countPatterns(path string) (int, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading info/exclude: %w", err)
+	}
+	defer f.Close()
+	n := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			n++
+		}
+	}
+	return n, scanner.Err()
+}
+
+// worktreeAdminDir returns the name of a linked worktree's directory under
+// gitDir/worktrees, read from the worktree's .git file.
+func worktreeAdminDir(path, gitDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(path, ".git"))
+	if err != nil {
+		return "", fmt.Errorf("reading its .git file: %w", err)
+	}
+	target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("its .git file has no gitdir")
+	}
+	target = strings.TrimSpace(target)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(path, target)
+	}
+	if fileutil.ResolvePath(filepath.Dir(target)) != fileutil.ResolvePath(filepath.Join(gitDir, "worktrees")) {
+		return "", fmt.Errorf("it belongs to %s, not this repository's .git", target)
+	}
+	return filepath.Base(target), nil
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,6 +24,7 @@ const (
 	migrateBackupCopy
 	migrateBackupSize
 	migrateWorktree
+	migrateRollback
 )
 
 type migrateOperation struct {
@@ -53,7 +55,7 @@ func prepareMigrate(runner git.CommandRunner, _ git.ShellRunner, opts MigrateOpt
 	branch := ""
 	isDirty := false
 	prepared := preparedMigrate{
-		result: MigrateResult{BareRoot: repoPath}, backupPath: backupPath,
+		result: MigrateResult{BareRoot: repoPath, Inventory: opts.Inventory}, backupPath: backupPath,
 		branch: &branch, isDirty: &isDirty, backupCopyIndex: -1,
 	}
 	if originErr != nil && !isMissingOrigin(originErr) {
@@ -121,16 +123,14 @@ func prepareMigrate(runner git.CommandRunner, _ git.ShellRunner, opts MigrateOpt
 			return originURL, err
 		})
 	}
+	inventory := opts.Inventory
+	keep := inventory.keepAtRoot(repoPath)
 	add("migrate:convert", "Migrate", "clean-root", "Clean root directory", migrateRegular, func(execution *progress.Execution) (string, error) {
-		entries, err := os.ReadDir(repoPath)
+		items, err := outsideWorktrees(repoPath, keep)
 		if err != nil {
 			return "", err
 		}
-		for _, entry := range entries {
-			name := entry.Name()
-			if name == ".bare" || name == ".git" {
-				continue
-			}
+		for _, name := range items {
 			if err := os.RemoveAll(filepath.Join(repoPath, name)); err != nil {
 				_ = execution.Running("migrate:convert", "clean-root", 0, fmt.Sprintf("warning: could not remove %s: %v", name, err))
 			}
@@ -142,13 +142,117 @@ func prepareMigrate(runner git.CommandRunner, _ git.ShellRunner, opts MigrateOpt
 		return "", err
 	})
 	add("migrate:copy", "Copy", "restore-files", "Restore working files", migrateRegular, func(execution *progress.Execution) (string, error) {
-		return restoreWorkingFiles(*backupPath, git.WorktreePath(repoPath, branch), copyTree, func(message string) {
+		return restoreWorkingFiles(*backupPath, git.WorktreePath(repoPath, branch), keep, copyTree, func(message string) {
 			_ = execution.Running("migrate:copy", "restore-files", 0, message)
 		})
 	})
+	prepareCarryOver(add, runner, barePath, backupPath, inventory)
 	return prepared
 }
 
+// prepareCarryOver declares a step for each part of the inventory the bare
+// clone dropped. Files are read from the backup's .git, which outlives the
+// original.
+func prepareCarryOver(add func(phaseID, phaseLabel, stepID, label string, kind migrateOperationKind, run func(*progress.Execution) (string, error)), runner git.CommandRunner, barePath string, backupPath *string, inventory MigrateInventory) {
+	carry := func(stepID, label string, run func() (string, error)) {
+		add("migrate:carry", "Carry over", stepID, label, migrateRegular, func(*progress.Execution) (string, error) { return run() })
+	}
+	// The first value of each key replaces whatever the clone wrote, such as
+	// its own core.filemode; later values of a multi-valued key are added.
+	setConfig := func(entries []configEntry) error {
+		seen := map[string]bool{}
+		for _, entry := range entries {
+			mode := "--add"
+			if !seen[entry.key] {
+				seen[entry.key] = true
+				mode = "--replace-all"
+			}
+			if _, err := runner.Run(barePath, "config", mode, entry.key, entry.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(inventory.remoteConfig) > 0 {
+		carry("remotes", "Restore remotes", func() (string, error) {
+			var names []string
+			for _, entry := range inventory.remoteConfig {
+				if name, _ := remoteKey(entry.key); !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			}
+			return strings.Join(names, ", "), setConfig(inventory.remoteConfig)
+		})
+	}
+	if len(inventory.localConfig) > 0 {
+		carry("config", "Restore local config", func() (string, error) {
+			return fmt.Sprintf("%d settings", len(inventory.ConfigKeys())), setConfig(inventory.localConfig)
+		})
+	}
+	if len(inventory.Hooks) > 0 {
+		carry("hooks", "Restore hooks", func() (string, error) {
+			for _, hook := range inventory.Hooks {
+				if err := copyTree(filepath.Join(*backupPath, ".git", "hooks", hook), filepath.Join(barePath, "hooks", hook)); err != nil {
+					return "", fmt.Errorf("copy hook %s: %w", hook, err)
+				}
+			}
+			return strings.Join(inventory.Hooks, ", "), nil
+		})
+	}
+	if inventory.Exclude > 0 {
+		carry("exclude", "Restore info/exclude", func() (string, error) {
+			if err := os.MkdirAll(filepath.Join(barePath, "info"), 0755); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d patterns", inventory.Exclude),
+				copyTree(filepath.Join(*backupPath, ".git", "info", "exclude"), filepath.Join(barePath, "info", "exclude"))
+		})
+	}
+	if len(inventory.Stashes) > 0 {
+		carry("stashes", "Keep stashes as refs", func() (string, error) {
+			for _, stash := range inventory.Stashes {
+				if _, err := runner.Run(barePath, "update-ref", stash.Ref, stash.Commit); err != nil {
+					return "", err
+				}
+			}
+			return fmt.Sprintf("%d under refs/stashes", len(inventory.Stashes)), nil
+		})
+	}
+	for _, wt := range inventory.Worktrees {
+		carry("worktree:"+wt.admin, "Re-register worktree "+filepath.Base(wt.Path), func() (string, error) {
+			return reregisterWorktree(filepath.Join(*backupPath, ".git"), barePath, wt)
+		})
+	}
+}
+
+// reregisterWorktree moves a linked worktree's administrative directory
+// from the backup into the bare repository and points the worktree at it.
+// The directory keeps the worktree's index and HEAD; its commondir is
+// relative, so it resolves to the bare repository from its new place.
+func reregisterWorktree(backupGitDir, barePath string, wt MigrateWorktree) (string, error) {
+	name := wt.admin
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(filepath.Join(barePath, "worktrees", name)); os.IsNotExist(err) {
+			break
+		}
+		name = fmt.Sprintf("%s%d", wt.admin, i)
+	}
+	admin := filepath.Join(barePath, "worktrees", name)
+	if err := os.MkdirAll(filepath.Dir(admin), 0755); err != nil {
+		return "", err
+	}
+	if err := copyTree(filepath.Join(backupGitDir, "worktrees", wt.admin), admin); err != nil {
+		return "", fmt.Errorf("copy worktree metadata: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(admin, "commondir"), []byte("../..\n"), 0644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(wt.Path, ".git"), []byte("gitdir: "+admin+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("point worktree at the bare repository: %w", err)
+	}
+	return wt.Path, nil
+}
+
 func copyRepositoryBackup(source, preferredPath string, copyFn func(string, string) error) (string, error) {
 	backupPath := preferredPath
 	for suffix := 0; ; suffix++ {
@@ -196,19 +300,22 @@ func copyRepositoryContents(source, destination string) error {
 // restoreWorkingFiles attempts every backup entry so one bad file does not
 // hide later failures, but returns the aggregate error so callers preserve the
 // backup instead of treating a partial restore as a successful migration.
-func restoreWorkingFiles(backupPath, targetPath string, copyFn func(string, string) error, warn func(string)) (string, error) {
-	entries, err := os.ReadDir(backupPath)
+// Paths in keep are linked worktrees, which stay where they are; the files
+// around them are restored.
+func restoreWorkingFiles(backupPath, targetPath string, keep map[string]bool, copyFn func(string, string) error, warn func(string)) (string, error) {
+	items, err := outsideWorktrees(backupPath, keep)
 	if err != nil {
 		return "nothing restored", fmt.Errorf("read migration backup: %w", err)
 	}
 	copied := 0
 	var restoreErr error
-	for _, entry := range entries {
-		name := entry.Name()
-		if name == ".git" || name == ".bare" {
-			continue
+	for _, name := range items {
+		dst := filepath.Join(targetPath, name)
+		err := os.MkdirAll(filepath.Dir(dst), 0755)
+		if err == nil {
+			err = copyFn(filepath.Join(backupPath, name), dst)
 		}
-		if err := copyFn(filepath.Join(backupPath, name), filepath.Join(targetPath, name)); err != nil {
+		if err != nil {
 			warn(fmt.Sprintf("warning: could not copy %s: %v", name, err))
 			restoreErr = errors.Join(restoreErr, fmt.Errorf("copy %s from migration backup: %w", name, err))
 			continue
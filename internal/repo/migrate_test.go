@@ -477,6 +477,7 @@ func TestRestoreWorkingFiles_MissingBackupFails(t *testing.T) {
 	_, err := restoreWorkingFiles(
 		filepath.Join(t.TempDir(), "missing-backup"),
 		t.TempDir(),
+		nil,
 		copyTree,
 		func(string) {},
 	)
@@ -485,6 +486,35 @@ func TestRestoreWorkingFiles_MissingBackupFails(t *testing.T) {
 	}
 }
 
+func TestRestoreWorkingFiles_RestoresSiblingsOfANestedWorktree(t *testing.T) {
+	backup := t.TempDir()
+	target := t.TempDir()
+	for _, name := range []string{"top.txt", "build/keep.txt", "build/wt/file.txt", ".git/HEAD"} {
+		path := filepath.Join(backup, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keep := map[string]bool{filepath.Join("build", "wt"): true}
+	if _, err := restoreWorkingFiles(backup, target, keep, copyTree, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"top.txt", "build/keep.txt"} {
+		if _, err := os.Stat(filepath.Join(target, name)); err != nil {
+			t.Errorf("%s should be restored next to the nested worktree: %v", name, err)
+		}
+	}
+	for _, name := range []string{"build/wt", ".git"} {
+		if _, err := os.Stat(filepath.Join(target, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should not be restored: %v", name, err)
+		}
+	}
+}
+
 func TestRestoreWorkingFiles_AttemptsAllEntriesAndReturnsCopyFailures(t *testing.T) {
 	backup := t.TempDir()
 	target := t.TempDir()
@@ -495,7 +525,7 @@ func TestRestoreWorkingFiles_AttemptsAllEntriesAndReturnsCopyFailures(t *testing
 	}
 	want := fmt.Errorf("disk full")
 	var copied, warnings []string
-	message, err := restoreWorkingFiles(backup, target, func(src, _ string) error {
+	message, err := restoreWorkingFiles(backup, target, nil, func(src, _ string) error {
 		name := filepath.Base(src)
 		copied = append(copied, name)
 		if name == "b" {
@@ -519,3 +549,65 @@ func TestRestoreWorkingFiles_AttemptsAllEntriesAndReturnsCopyFailures(t *testing
 		t.Fatalf("warnings = %v", warnings)
 	}
 }
+
+func TestInventoryMigration(t *testing.T) {
+	repoPath := t.TempDir()
+	gitDir := filepath.Join(repoPath, ".git")
+	for _, d := range []string{"hooks", "info", "worktrees/side", "modules"} {
+		os.MkdirAll(filepath.Join(gitDir, d), 0755)
+	}
+	os.WriteFile(filepath.Join(gitDir, "hooks", "pre-commit"), []byte("#!/bin/sh\n"), 0755)
+	os.WriteFile(filepath.Join(gitDir, "hooks", "pre-push.sample"), []byte("#!/bin/sh\n"), 0755)
+	os.WriteFile(filepath.Join(gitDir, "info", "exclude"), []byte("# comment\n\n*.log\ntmp/\n"), 0644)
+	side := filepath.Join(t.TempDir(), "side")
+	os.MkdirAll(side, 0755)
+	os.WriteFile(filepath.Join(side, ".git"), []byte("gitdir: "+filepath.Join(gitDir, "worktrees", "side")+"\n"), 0644)
+
+	config := strings.Join([]string{
+		"core.bare\nfalse",
+		"core.repositoryformatversion\n0",
+		"core.logallrefupdates\ntrue",
+		"core.hooksPath\n.githooks",
+		"remote.origin.url\ngit@example.com:o/r.git",
+		"remote.origin.fetch\n+refs/heads/*:refs/remotes/origin/*",
+		"remote.origin.pushurl\ngit@example.com:me/r.git",
+		"remote.up.stream.url\nhttps://example.com/u.git",
+		"user.email\nme@example.com",
+		"include.path\n../shared.gitconfig",
+	}, "\x00") + "\x00"
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		repoPath + ":[config --local --list -z]":   {Output: config},
+		repoPath + ":[stash list --format=%H %gs]": {Output: "aaa On main: wip\nbbb WIP on main: 123 init"},
+		repoPath + ":[rev-parse --git-dir]":        {Output: ".git"},
+		repoPath + ":[worktree list --porcelain]": {Output: fmt.Sprintf(
+			"worktree %s\nHEAD 1\nbranch refs/heads/main\n\nworktree %s\nHEAD 2\nbranch refs/heads/side\n\nworktree /gone\nHEAD 3\nbranch refs/heads/gone\nprunable gitdir file points to non-existent location\n",
+			repoPath, side)},
+		repoPath + ":[for-each-ref --format=%(refname) refs/remotes]": {Output: "refs/remotes/origin/main"},
+	}}
+
+	inv, err := InventoryMigration(runner, repoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(inv.Remotes) != "[{origin git@example.com:o/r.git} {up.stream https://example.com/u.git}]" {
+		t.Errorf("Remotes = %v", inv.Remotes)
+	}
+	if len(inv.remoteConfig) != 2 {
+		t.Errorf("remoteConfig = %v, want origin's pushurl and up.stream's url", inv.remoteConfig)
+	}
+	if fmt.Sprint(inv.ConfigKeys()) != "[core.hooksPath user.email include.path]" {
+		t.Errorf("ConfigKeys = %v", inv.ConfigKeys())
+	}
+	if len(inv.Stashes) != 2 || inv.Stashes[1].Commit != "bbb" || inv.Stashes[1].Ref != "refs/stashes/1" || inv.Stashes[0].Message != "On main: wip" {
+		t.Errorf("Stashes = %+v", inv.Stashes)
+	}
+	if fmt.Sprint(inv.Hooks) != "[pre-commit]" || inv.Exclude != 2 {
+		t.Errorf("Hooks = %v, Exclude = %d", inv.Hooks, inv.Exclude)
+	}
+	if len(inv.Worktrees) != 1 || inv.Worktrees[0].Path != side || inv.Worktrees[0].Branch != "side" || inv.Worktrees[0].admin != "side" {
+		t.Errorf("Worktrees = %+v", inv.Worktrees)
+	}
+	if len(inv.Unmovable) != 3 {
+		t.Errorf("Unmovable = %q, want the gone worktree, remote-tracking branches and submodules", inv.Unmovable)
+	}
+}
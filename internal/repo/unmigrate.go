@@ -0,0 +1,452 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/fileutil"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/progress"
+)
+
+const unmigrateRollbackPhaseID progress.PhaseID = "unmigrate:rollback"
+
+type UnmigrateOptions struct {
+	// BareRoot is the directory holding .bare and the .git pointer.
+	BareRoot string
+	// Worktree is the worktree whose files become the checkout, by path or
+	// branch name. It must live under BareRoot.
+	Worktree string
+}
+
+type UnmigrateResult struct {
+	RepoPath   string
+	Worktree   string
+	Branch     string
+	IsDirty    bool
+	BackupPath string
+	BackupSize string
+	// Converted are the other worktrees, pointed at the new .git. Dropped
+	// are worktrees git already reports as prunable; their metadata is
+	// pruned.
+	Converted []string
+	Dropped   []string
+	// RolledBack is set when a failed conversion was undone by moving the
+	// backup back into place; the backup no longer exists.
+	RolledBack bool
+	Phases     []progress.Phase
+	Err        error
+}
+
+func (r UnmigrateResult) HasFailures() bool {
+	return r.Err != nil || progress.PhasesHaveFailures(r.Phases)
+}
+
+func (r UnmigrateResult) RestoreCommand() string {
+	return fmt.Sprintf("rm -rf %q && mv %q %q", r.RepoPath, r.BackupPath, r.RepoPath)
+}
+
+// Unmigrate turns a bare layout back into a regular repository: .bare
+// becomes .git and the chosen worktree's files move up to the root. It is
+// the reverse of Migrate and takes the same backup first.
+func Unmigrate(runner git.CommandRunner, opts UnmigrateOptions, emit func(progress.Event)) UnmigrateResult {
+	return prepareUnmigrate(runner, opts).run(emit)
+}
+
+// UnmigrateCandidates lists the worktrees that can become the checkout:
+// live worktrees under the bare root.
+func UnmigrateCandidates(runner git.CommandRunner, bareRoot string) ([]git.Worktree, error) {
+	wts, err := git.ListWorktrees(runner, bareRoot)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []git.Worktree
+	for _, wt := range wts {
+		if !wt.IsBare && !wt.IsPrunable && insideRoot(bareRoot, wt.Path) {
+			candidates = append(candidates, wt)
+		}
+	}
+	return candidates, nil
+}
+
+type preparedUnmigrate struct {
+	result     UnmigrateResult
+	plan       progress.Plan
+	operations []migrateOperation
+	backupPath *string
+	isDirty    *bool
+	err        error
+}
+
+func prepareUnmigrate(runner git.CommandRunner, opts UnmigrateOptions) preparedUnmigrate {
+	root := opts.BareRoot
+	barePath := filepath.Join(root, ".bare")
+	gitDir := filepath.Join(root, ".git")
+	preferredBackupPath := fmt.Sprintf("%s_backup_%s", root, time.Now().Format("20060102_150405"))
+	backupPath := new(string)
+	isDirty := false
+	prepared := preparedUnmigrate{
+		result:     UnmigrateResult{RepoPath: root},
+		backupPath: backupPath,
+		isDirty:    &isDirty,
+	}
+
+	if info, err := os.Stat(barePath); err != nil || !info.IsDir() {
+		prepared.err = fmt.Errorf("%s has no .bare directory; only sentei's bare layout can be unmigrated", root)
+		return prepared
+	}
+	// The backup is made private, so the rollback that renames it back
+	// restores the root's own permissions afterwards.
+	rootInfo, err := os.Stat(root)
+	if err != nil {
+		prepared.err = fmt.Errorf("reading %s: %w", root, err)
+		return prepared
+	}
+	rootMode := rootInfo.Mode().Perm()
+	wts, err := git.ListWorktrees(runner, root)
+	if err != nil {
+		prepared.err = fmt.Errorf("listing worktrees before unmigrating: %w", err)
+		return prepared
+	}
+	chosen, others, err := chooseUnmigrateWorktree(wts, root, opts.Worktree)
+	if err != nil {
+		prepared.err = err
+		return prepared
+	}
+	chosenAdmin, err := worktreeAdminDir(chosen.Path, barePath)
+	if err != nil {
+		prepared.err = fmt.Errorf("worktree %s: %w", chosen.Path, err)
+		return prepared
+	}
+	if err := checkUnmigrateCollisions(root, chosen.Path); err != nil {
+		prepared.err = err
+		return prepared
+	}
+	prepared.result.Worktree = chosen.Path
+	prepared.result.Branch = strings.TrimPrefix(chosen.Branch, "refs/heads/")
+
+	// pointer is the worktree's .git file as it was, put back by the
+	// rollback for worktrees outside the root, which the backup lacks.
+	type convertedWorktree struct {
+		path, admin string
+		pointer     []byte
+	}
+	var converted []convertedWorktree
+	for _, wt := range others {
+		if wt.IsPrunable {
+			prepared.result.Dropped = append(prepared.result.Dropped, wt.Path)
+			continue
+		}
+		admin, err := worktreeAdminDir(wt.Path, barePath)
+		if err != nil {
+			prepared.err = fmt.Errorf("worktree %s: %w", wt.Path, err)
+			return prepared
+		}
+		pointer, err := os.ReadFile(filepath.Join(wt.Path, ".git"))
+		if err != nil {
+			prepared.err = fmt.Errorf("worktree %s: %w", wt.Path, err)
+			return prepared
+		}
+		converted = append(converted, convertedWorktree{path: wt.Path, admin: admin, pointer: pointer})
+		prepared.result.Converted = append(prepared.result.Converted, wt.Path)
+	}
+
+	add := func(phaseID, phaseLabel, stepID, label string, kind migrateOperationKind, run func(*progress.Execution) (string, error)) {
+		if len(prepared.plan.Phases) == 0 || prepared.plan.Phases[len(prepared.plan.Phases)-1].ID != phaseID {
+			prepared.plan.Phases = append(prepared.plan.Phases, progress.PlannedPhase{ID: phaseID, Label: phaseLabel})
+		}
+		phase := &prepared.plan.Phases[len(prepared.plan.Phases)-1]
+		phase.Steps = append(phase.Steps, progress.PlannedStep{ID: stepID, Label: label})
+		prepared.operations = append(prepared.operations, migrateOperation{phaseID: phaseID, stepID: stepID, label: label, kind: kind, run: run})
+	}
+	add("unmigrate:validate", "Validate", "status", "Check worktree status", migrateStatus, func(*progress.Execution) (string, error) {
+		output, err := runner.Run(chosen.Path, "status", "--porcelain")
+		if err != nil {
+			return "", err
+		}
+		isDirty = strings.TrimSpace(output) != ""
+		if isDirty {
+			return "uncommitted changes detected", nil
+		}
+		return "clean", nil
+	})
+	add("unmigrate:backup", "Backup", "copy", "Copy repository to backup", migrateBackupCopy, func(*progress.Execution) (string, error) {
+		path, err := copyRepositoryBackup(root, preferredBackupPath, copyRepositoryContents)
+		if err != nil {
+			return "", err
+		}
+		*backupPath = path
+		return path, nil
+	})
+	add("unmigrate:backup", "Backup", "size", "Calculate backup size", migrateBackupSize, func(*progress.Execution) (string, error) {
+		return calculateDirSize(*backupPath), nil
+	})
+	add("unmigrate:convert", "Unmigrate", "git-dir", "Move .bare to .git", migrateRegular, func(*progress.Execution) (string, error) {
+		if err := os.Remove(gitDir); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("remove .git pointer: %w", err)
+		}
+		return "", os.Rename(barePath, gitDir)
+	})
+	add("unmigrate:convert", "Unmigrate", "core-bare", "Mark repository non-bare", migrateRegular, func(*progress.Execution) (string, error) {
+		_, err := runner.Run(root, "config", "--file", filepath.Join(gitDir, "config"), "core.bare", "false")
+		return "", err
+	})
+	add("unmigrate:convert", "Unmigrate", "head", "Adopt worktree HEAD and index", migrateRegular, func(*progress.Execution) (string, error) {
+		return prepared.result.Branch, adoptWorktreeAdmin(filepath.Join(gitDir, "worktrees", chosenAdmin), gitDir)
+	})
+	add("unmigrate:convert", "Unmigrate", "move-files", "Move working files to root", migrateRegular, func(*progress.Execution) (string, error) {
+		return moveWorktreeToRoot(chosen.Path, root)
+	})
+	for _, wt := range converted {
+		add("unmigrate:worktrees", "Worktrees", "worktree:"+wt.admin, "Re-point worktree "+filepath.Base(wt.path), migrateRegular, func(*progress.Execution) (string, error) {
+			admin := filepath.Join(gitDir, "worktrees", wt.admin)
+			return wt.path, os.WriteFile(filepath.Join(wt.path, ".git"), []byte("gitdir: "+admin+"\n"), 0644)
+		})
+	}
+	var nested []string
+	for _, wt := range converted {
+		if insideRoot(root, wt.path) {
+			rel, _ := filepath.Rel(fileutil.ResolvePath(root), fileutil.ResolvePath(wt.path))
+			nested = append(nested, "/"+filepath.ToSlash(rel)+"/")
+		}
+	}
+	if len(nested) > 0 {
+		add("unmigrate:worktrees", "Worktrees", "exclude", "Exclude nested worktrees", migrateRegular, func(*progress.Execution) (string, error) {
+			return fmt.Sprintf("%d paths", len(nested)), appendExclude(gitDir, nested)
+		})
+	}
+	if len(prepared.result.Dropped) > 0 {
+		add("unmigrate:worktrees", "Worktrees", "prune", "Prune missing worktrees", migrateRegular, func(*progress.Execution) (string, error) {
+			_, err := runner.Run(root, "worktree", "prune")
+			return fmt.Sprintf("%d dropped", len(prepared.result.Dropped)), err
+		})
+	}
+	add("unmigrate:verify", "Verify", "status", "Check repository status", migrateRegular, func(*progress.Execution) (string, error) {
+		output, err := runner.Run(root, "status", "--porcelain")
+		if err != nil {
+			return "", err
+		}
+		output = strings.TrimSpace(output)
+		if output == "" {
+			return "clean", nil
+		}
+		return fmt.Sprintf("%d changes", strings.Count(output, "\n")+1), nil
+	})
+	add(unmigrateRollbackPhaseID, "Rollback", "restore-backup", "Restore bare layout from backup", migrateRollback, func(*progress.Execution) (string, error) {
+		if err := fileutil.RemoveAllRetry(root); err != nil {
+			return "", fmt.Errorf("remove partial conversion: %w", err)
+		}
+		if err := os.Rename(*backupPath, root); err != nil {
+			return "", fmt.Errorf("move backup back to %s: %w", root, err)
+		}
+		if err := os.Chmod(root, rootMode); err != nil {
+			return "", fmt.Errorf("restore permissions of %s: %w", root, err)
+		}
+		var pointerErr error
+		for _, wt := range converted {
+			if !insideRoot(root, wt.path) {
+				pointerErr = errors.Join(pointerErr, os.WriteFile(filepath.Join(wt.path, ".git"), wt.pointer, 0644))
+			}
+		}
+		return root, pointerErr
+	})
+	return prepared
+}
+
+// chooseUnmigrateWorktree finds the worktree named by want, a path or a
+// branch, and returns it apart from the other linked worktrees.
+func chooseUnmigrateWorktree(wts []git.Worktree, root, want string) (git.Worktree, []git.Worktree, error) {
+	if want == "" {
+		return git.Worktree{}, nil, errors.New("no worktree chosen to become the checkout")
+	}
+	var chosen *git.Worktree
+	var others []git.Worktree
+	for i, wt := range wts {
+		if wt.IsBare {
+			continue
+		}
+		matches := strings.TrimPrefix(wt.Branch, "refs/heads/") == want ||
+			fileutil.ResolvePath(wt.Path) == fileutil.ResolvePath(want)
+		if chosen == nil && matches {
+			chosen = &wts[i]
+			continue
+		}
+		others = append(others, wt)
+	}
+	if chosen == nil {
+		return git.Worktree{}, nil, fmt.Errorf("no worktree for %q", want)
+	}
+	if chosen.IsPrunable {
+		return git.Worktree{}, nil, fmt.Errorf("worktree %s is missing (%s)", chosen.Path, chosen.PruneReason)
+	}
+	if !insideRoot(root, chosen.Path) {
+		return git.Worktree{}, nil, fmt.Errorf("worktree %s is outside %s; move it under the root first", chosen.Path, root)
+	}
+	return *chosen, others, nil
+}
+
+// checkUnmigrateCollisions refuses a worktree whose entries would land on
+// something already at the root, so moving files never overwrites.
+func checkUnmigrateCollisions(root, worktreePath string) error {
+	entries, err := os.ReadDir(worktreePath)
+	if err != nil {
+		return fmt.Errorf("reading worktree %s: %w", worktreePath, err)
+	}
+	// The worktree's own directory moves out of the way first.
+	own := ""
+	if fileutil.ResolvePath(filepath.Dir(worktreePath)) == fileutil.ResolvePath(root) {
+		own = filepath.Base(worktreePath)
+	}
+	var collisions []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == ".git" || name == own {
+			continue
+		}
+		if _, err := os.Lstat(filepath.Join(root, name)); err == nil {
+			collisions = append(collisions, name)
+		}
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("already at %s: %s; move them aside first", root, strings.Join(collisions, ", "))
+	}
+	return nil
+}
+
+// adoptWorktreeAdmin makes the worktree's HEAD, index and HEAD reflog the
+// repository's own, then drops its administrative directory.
+func adoptWorktreeAdmin(admin, gitDir string) error {
+	for _, name := range []string{"HEAD", "index", filepath.Join("logs", "HEAD")} {
+		src := filepath.Join(admin, name)
+		if _, err := os.Lstat(src); os.IsNotExist(err) && name != "HEAD" {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(gitDir, name)), 0755); err != nil {
+			return err
+		}
+		if err := copyTree(src, filepath.Join(gitDir, name)); err != nil {
+			return fmt.Errorf("copy %s: %w", name, err)
+		}
+	}
+	return fileutil.RemoveAllRetry(admin)
+}
+
+// moveWorktreeToRoot renames every entry of the worktree into the root. A
+// worktree directly under the root is first renamed aside, so an entry that
+// shares its directory's name does not collide with it.
+func moveWorktreeToRoot(worktreePath, root string) (string, error) {
+	source := worktreePath
+	if fileutil.ResolvePath(filepath.Dir(worktreePath)) == fileutil.ResolvePath(root) {
+		source = filepath.Join(root, ".sentei-unmigrate-"+filepath.Base(worktreePath))
+		if err := os.Rename(worktreePath, source); err != nil {
+			return "", err
+		}
+	}
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		return "", err
+	}
+	moved := 0
+	for _, entry := range entries {
+		if entry.Name() == ".git" {
+			continue
+		}
+		if err := os.Rename(filepath.Join(source, entry.Name()), filepath.Join(root, entry.Name())); err != nil {
+			return "", fmt.Errorf("move %s: %w", entry.Name(), err)
+		}
+		moved++
+	}
+	if err := fileutil.RemoveAllRetry(source); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d items moved", moved), nil
+}
+
+func appendExclude(gitDir string, patterns []string) error {
+	if err := os.MkdirAll(filepath.Join(gitDir, "info"), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(gitDir, "info", "exclude"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "# worktrees kept by sentei unmigrate\n%s\n", strings.Join(patterns, "\n"))
+	return errors.Join(err, f.Close())
+}
+
+func insideRoot(root, path string) bool {
+	rel, err := filepath.Rel(fileutil.ResolvePath(root), fileutil.ResolvePath(path))
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..")
+}
+
+func (p preparedUnmigrate) run(emit func(progress.Event)) UnmigrateResult {
+	result := p.result
+	if p.err != nil {
+		result.Err = p.err
+		return result
+	}
+	execution, err := progress.Start(p.plan, emit)
+	if err != nil {
+		result.Err = fmt.Errorf("starting repository unmigration: %w", err)
+		return result
+	}
+	failedBy := ""
+	var failedPhase progress.PhaseID
+	for _, operation := range p.operations {
+		if operation.kind == migrateRollback {
+			// Only a failed convert or worktrees step leaves a half-converted
+			// layout behind; earlier failures changed nothing, and the
+			// verify step only reads.
+			if failedPhase != "unmigrate:convert" && failedPhase != "unmigrate:worktrees" {
+				_, err = execution.Skip(operation.phaseID, operation.stepID, "rollback not required")
+			} else {
+				var step progress.StepResult
+				step, err = execution.Run(operation.phaseID, operation.stepID, func() (string, error) { return operation.run(execution) })
+				if err == nil && step.Status == progress.StepDone {
+					result.RolledBack = true
+					result.BackupPath, result.BackupSize = "", ""
+				}
+			}
+			if err != nil {
+				result.Err = errors.Join(result.Err, fmt.Errorf("executing rollback: %w", err))
+			}
+			continue
+		}
+		if failedBy != "" {
+			_, err = execution.Skip(operation.phaseID, operation.stepID, "blocked by "+failedBy)
+		} else {
+			var step progress.StepResult
+			step, err = execution.Run(operation.phaseID, operation.stepID, func() (string, error) { return operation.run(execution) })
+			if operation.kind == migrateBackupCopy && step.Status == progress.StepDone {
+				result.BackupPath = *p.backupPath
+			}
+			if err == nil && step.Status == progress.StepFailed {
+				failedBy = operation.label
+			}
+		}
+		if err != nil {
+			result.Err = errors.Join(result.Err, fmt.Errorf("executing %s: %w", operation.label, err))
+			failedBy = operation.label
+		}
+		if failedBy != "" && failedPhase == "" {
+			failedPhase = operation.phaseID
+		}
+		if failedBy != "" {
+			continue
+		}
+		switch operation.kind {
+		case migrateStatus:
+			result.IsDirty = *p.isDirty
+		case migrateBackupSize:
+			result.BackupSize = calculateDirSize(*p.backupPath)
+		}
+	}
+	finishErr := execution.Finish("repository unmigration finished")
+	result.Phases = execution.Phases()
+	result.Err = errors.Join(result.Err, finishErr)
+	return result
+}
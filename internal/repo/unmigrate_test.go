@@ -0,0 +1,212 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/testutil/mock"
+)
+
+// bareLayout builds sentei's bare layout on disk with a linked worktree per
+// name under the root, and a runner that lists them.
+func bareLayout(t *testing.T, names ...string) (string, *mock.Runner) {
+	t.Helper()
+	root := t.TempDir()
+	bare := filepath.Join(root, ".bare")
+	os.WriteFile(filepath.Join(root, ".git"), []byte("gitdir: .bare\n"), 0644)
+	listing := fmt.Sprintf("worktree %s\nbare\n", bare)
+	for _, name := range names {
+		admin := filepath.Join(bare, "worktrees", name)
+		path := filepath.Join(root, name)
+		os.MkdirAll(admin, 0755)
+		os.MkdirAll(path, 0755)
+		os.WriteFile(filepath.Join(admin, "HEAD"), []byte("ref: refs/heads/"+name+"\n"), 0644)
+		os.WriteFile(filepath.Join(path, ".git"), []byte("gitdir: "+admin+"\n"), 0644)
+		os.WriteFile(filepath.Join(path, name+".txt"), []byte(name), 0644)
+		listing += fmt.Sprintf("\nworktree %s\nHEAD abc123\nbranch refs/heads/%s\n", path, name)
+	}
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		root + ":[rev-parse --git-dir]":                         {Output: bare},
+		root + ":[worktree list --porcelain]":                   {Output: listing},
+		filepath.Join(root, names[0]) + ":[status --porcelain]": {Output: ""},
+	}}
+	return root, runner
+}
+
+func TestPrepareUnmigrate_RejectsBeforeAnyChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(root string)
+		choose  string
+		wantErr string
+	}{
+		{name: "unknown worktree", choose: "nope", wantErr: `no worktree for "nope"`},
+		{name: "no worktree chosen", choose: "", wantErr: "no worktree chosen"},
+		{
+			name:    "file collides with root",
+			setup:   func(root string) { os.WriteFile(filepath.Join(root, "main.txt"), []byte("x"), 0644) },
+			choose:  "main",
+			wantErr: "main.txt",
+		},
+		{
+			name:    "not a bare layout",
+			setup:   func(root string) { os.RemoveAll(filepath.Join(root, ".bare")) },
+			choose:  "main",
+			wantErr: "no .bare directory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, runner := bareLayout(t, "main", "feature")
+			if tt.setup != nil {
+				tt.setup(root)
+			}
+			result := Unmigrate(runner, UnmigrateOptions{BareRoot: root, Worktree: tt.choose}, func(progress.Event) {})
+			if result.Err == nil || !strings.Contains(result.Err.Error(), tt.wantErr) {
+				t.Fatalf("Err = %v, want %q", result.Err, tt.wantErr)
+			}
+			if len(result.Phases) != 0 {
+				t.Fatalf("destructive work started: %+v", result.Phases)
+			}
+			if _, err := os.Stat(filepath.Join(root, "main", "main.txt")); err != nil {
+				t.Fatalf("worktree touched: %v", err)
+			}
+		})
+	}
+}
+
+func TestPrepareUnmigrate_DeclaresOtherWorktrees(t *testing.T) {
+	root, runner := bareLayout(t, "main", "feature")
+	prepared := prepareUnmigrate(runner, UnmigrateOptions{BareRoot: root, Worktree: filepath.Join(root, "main")})
+	if prepared.err != nil {
+		t.Fatal(prepared.err)
+	}
+	if prepared.result.Branch != "main" || len(prepared.result.Converted) != 1 || prepared.result.Converted[0] != filepath.Join(root, "feature") {
+		t.Fatalf("result = %+v", prepared.result)
+	}
+	var steps []string
+	for _, operation := range prepared.operations {
+		steps = append(steps, string(operation.phaseID)+"/"+string(operation.stepID))
+	}
+	for _, want := range []string{"unmigrate:worktrees/worktree:feature", "unmigrate:worktrees/exclude"} {
+		if !strings.Contains(strings.Join(steps, " "), want) {
+			t.Errorf("steps %v missing %s", steps, want)
+		}
+	}
+	if strings.Contains(strings.Join(steps, " "), "prune") {
+		t.Errorf("prune declared with nothing to drop: %v", steps)
+	}
+}
+
+func TestPreparedUnmigrate_FailurePolicyAndBackupInformation(t *testing.T) {
+	root, runner := bareLayout(t, "main")
+	base := prepareUnmigrate(runner, UnmigrateOptions{BareRoot: root, Worktree: "main"})
+	if base.err != nil {
+		t.Fatal(base.err)
+	}
+	backupIndex, rollback := -1, len(base.operations)-1
+	for i, operation := range base.operations {
+		if operation.kind == migrateBackupCopy {
+			backupIndex = i
+		}
+	}
+	if base.operations[rollback].kind != migrateRollback {
+		t.Fatalf("last operation = %s, want the declared rollback", base.operations[rollback].label)
+	}
+	for failedAt := range base.operations[:rollback] {
+		t.Run(base.operations[failedAt].label, func(t *testing.T) {
+			prepared := base
+			backupPath := root + "_backup_20260715_120000"
+			prepared.backupPath = &backupPath
+			prepared.operations = append([]migrateOperation(nil), base.operations...)
+			for i := range prepared.operations {
+				prepared.operations[i].run = func(*progress.Execution) (string, error) { return "", nil }
+			}
+			prepared.operations[failedAt].run = func(*progress.Execution) (string, error) { return "", errors.New("injected") }
+			result := prepared.run(func(progress.Event) {})
+			if result.Err != nil {
+				t.Fatalf("Err = %v", result.Err)
+			}
+			failed := prepared.operations[failedAt]
+			if step := resultStepByID(t, result.Phases, failed.phaseID, failed.stepID); step.Status != progress.StepFailed {
+				t.Fatalf("failed result = %#v", step)
+			}
+			for _, later := range prepared.operations[failedAt+1 : rollback] {
+				if step := resultStepByID(t, result.Phases, later.phaseID, later.stepID); step.Status != progress.StepSkipped {
+					t.Fatalf("later %s = %#v", later.label, step)
+				}
+			}
+			converting := failed.phaseID == "unmigrate:convert" || failed.phaseID == "unmigrate:worktrees"
+			wantRollback := progress.StepSkipped
+			if converting {
+				wantRollback = progress.StepDone
+			}
+			restore := prepared.operations[rollback]
+			if step := resultStepByID(t, result.Phases, restore.phaseID, restore.stepID); step.Status != wantRollback || result.RolledBack != converting {
+				t.Fatalf("rollback = %#v, RolledBack = %v; want status %v", step, result.RolledBack, wantRollback)
+			}
+			if (result.BackupPath != "") != (failedAt > backupIndex && !converting) {
+				t.Fatalf("BackupPath=%q failedAt=%d backupIndex=%d", result.BackupPath, failedAt, backupIndex)
+			}
+		})
+	}
+}
+
+func TestUnmigrate_FailedConversionRestoresBackup(t *testing.T) {
+	root, runner := bareLayout(t, "main", "feature")
+	if err := os.Chmod(root, 0o751); err != nil {
+		t.Fatal(err)
+	}
+	// core.bare is not mocked, so the conversion fails after .bare has
+	// already become .git.
+	result := Unmigrate(runner, UnmigrateOptions{BareRoot: root, Worktree: "main"}, func(progress.Event) {})
+
+	if !result.HasFailures() || !result.RolledBack || result.BackupPath != "" {
+		t.Fatalf("result = %+v, want a rolled-back failure", result)
+	}
+	if info, err := os.Stat(filepath.Join(root, ".bare")); err != nil || !info.IsDir() {
+		t.Fatalf(".bare not restored: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(root, ".git")); string(got) != "gitdir: .bare\n" {
+		t.Errorf(".git = %q, want the bare pointer back", got)
+	}
+	if got, _ := os.ReadFile(filepath.Join(root, "feature", "feature.txt")); string(got) != "feature" {
+		t.Errorf("feature worktree not restored: %q", got)
+	}
+	if backups, _ := filepath.Glob(root + "_backup_*"); len(backups) != 0 {
+		t.Errorf("backup left behind after rollback: %v", backups)
+	}
+	if info, err := os.Stat(root); err != nil || info.Mode().Perm() != 0o751 {
+		t.Errorf("root mode after rollback = %v, %v; want 0751 as before", info.Mode().Perm(), err)
+	}
+}
+
+func TestMoveWorktreeToRoot_EntryNamedLikeItsDirectory(t *testing.T) {
+	root := t.TempDir()
+	worktree := filepath.Join(root, "main")
+	os.MkdirAll(filepath.Join(worktree, "main"), 0755)
+	os.WriteFile(filepath.Join(worktree, "main", "inner.txt"), []byte("inner"), 0644)
+	os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: elsewhere\n"), 0644)
+
+	if _, err := moveWorktreeToRoot(worktree, root); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "main", "inner.txt")); err != nil || string(data) != "inner" {
+		t.Fatalf("main/inner.txt = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".git")); !os.IsNotExist(err) {
+		t.Fatalf("worktree .git pointer moved to root: %v", err)
+	}
+}
+
+func TestUnmigrateResult_RestoreCommand_QuotesPaths(t *testing.T) {
+	result := UnmigrateResult{RepoPath: "/tmp/my repo", BackupPath: "/tmp/my repo_backup"}
+	if got, want := result.RestoreCommand(), `rm -rf "/tmp/my repo" && mv "/tmp/my repo_backup" "/tmp/my repo"`; got != want {
+		t.Fatalf("RestoreCommand() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,763 @@
+package report
+
+import (
+	"strings"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/cleanup"
+	"github.com/abiswas97/sentei/internal/creator"
+	"github.com/abiswas97/sentei/internal/doctor"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/journal"
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/trash"
+	"github.com/abiswas97/sentei/internal/worktree"
+)
+
+// Worktree is the document form of git.Worktree. Branch is the short name;
+// LastCommitDate is omitted when enrichment did not produce one.
+type Worktree struct {
+	Path                  string     `json:"path"`
+	Branch                string     `json:"branch"`
+	Head                  string     `json:"head"`
+	Bare                  bool       `json:"bare"`
+	Detached              bool       `json:"detached"`
+	Locked                bool       `json:"locked"`
+	LockReason            string     `json:"lock_reason,omitempty"`
+	Prunable              bool       `json:"prunable"`
+	PruneReason           string     `json:"prune_reason,omitempty"`
+	Protected             bool       `json:"protected"`
+	LastCommitDate        *time.Time `json:"last_commit_date,omitempty"`
+	LastCommitSubject     string     `json:"last_commit_subject,omitempty"`
+	HasUncommittedChanges bool       `json:"has_uncommitted_changes"`
+	HasUntrackedFiles     bool       `json:"has_untracked_files"`
+	HasUnpushedCommits    bool       `json:"has_unpushed_commits"`
+	Enriched              bool       `json:"enriched"`
+	EnrichmentError       string     `json:"enrichment_error,omitempty"`
+
+	// Upstream is absent when the branch tracks nothing; ahead/behind are
+	// absent unless the upstream still exists. default_ahead/default_behind
+	// compare against the repository's default branch.
+	Upstream      string `json:"upstream,omitempty"`
+	UpstreamGone  bool   `json:"upstream_gone"`
+	Ahead         *int   `json:"ahead,omitempty"`
+	Behind        *int   `json:"behind,omitempty"`
+	DefaultAhead  int    `json:"default_ahead"`
+	DefaultBehind int    `json:"default_behind"`
+
+	// Set only by commands that compute it (list with the size column);
+	// absent means "not computed".
+	DiskSize *int64 `json:"disk_size,omitempty"`
+}
+
+// NewWorktree converts wt, marking it protected when the policy covers its
+// branch. A nil policy applies the built-in set.
+func NewWorktree(wt git.Worktree, protection *git.ProtectionPolicy) Worktree {
+	doc := Worktree{
+		Path:                  wt.Path,
+		Branch:                strings.TrimPrefix(wt.Branch, "refs/heads/"),
+		Head:                  wt.HEAD,
+		Bare:                  wt.IsBare,
+		Detached:              wt.IsDetached,
+		Locked:                wt.IsLocked,
+		LockReason:            wt.LockReason,
+		Prunable:              wt.IsPrunable,
+		PruneReason:           wt.PruneReason,
+		Protected:             protection.IsProtected(wt.Branch),
+		LastCommitDate:        optionalTime(wt.LastCommitDate),
+		LastCommitSubject:     wt.LastCommitSubject,
+		HasUncommittedChanges: wt.HasUncommittedChanges,
+		HasUntrackedFiles:     wt.HasUntrackedFiles,
+		HasUnpushedCommits:    wt.HasUnpushedCommits,
+		Enriched:              wt.IsEnriched,
+		EnrichmentError:       wt.EnrichmentError,
+		Upstream:              wt.Upstream,
+		UpstreamGone:          wt.UpstreamGone,
+		DefaultAhead:          wt.DefaultAhead,
+		DefaultBehind:         wt.DefaultBehind,
+	}
+	if wt.HasUpstream() {
+		ahead, behind := wt.Ahead, wt.Behind
+		doc.Ahead, doc.Behind = &ahead, &behind
+	}
+	return doc
+}
+
+// NewWorktrees converts a slice of worktrees, preserving order.
+func NewWorktrees(worktrees []git.Worktree, protection *git.ProtectionPolicy) []Worktree {
+	docs := make([]Worktree, len(worktrees))
+	for i, wt := range worktrees {
+		docs[i] = NewWorktree(wt, protection)
+	}
+	return docs
+}
+
+// Step is the document form of progress.StepResult.
+type Step struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Phase is the document form of progress.Phase.
+type Phase struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Steps []Step `json:"steps"`
+}
+
+// NewPhases converts recorded phase results.
+func NewPhases(phases []progress.Phase) []Phase {
+	docs := make([]Phase, len(phases))
+	for i, p := range phases {
+		steps := make([]Step, len(p.Steps))
+		for j, s := range p.Steps {
+			steps[j] = Step{
+				ID:      s.ID,
+				Name:    s.Name,
+				Status:  StatusName(s.Status),
+				Message: s.Message,
+				Error:   errorString(s.Error),
+			}
+		}
+		docs[i] = Phase{ID: p.ID, Name: p.Name, Steps: steps}
+	}
+	return docs
+}
+
+// Event is the document form of progress.Event, streamed under NDJSON.
+type Event struct {
+	Phase      string `json:"phase"`
+	PhaseLabel string `json:"phase_label,omitempty"`
+	Step       string `json:"step,omitempty"`
+	StepLabel  string `json:"step_label,omitempty"`
+	Status     string `json:"status"`
+	Checkpoint int    `json:"checkpoint,omitempty"`
+	Of         int    `json:"of,omitempty"`
+	Close      bool   `json:"close,omitempty"`
+	Message    string `json:"message,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// NewEvent converts a progress event.
+func NewEvent(e progress.Event) Event {
+	return Event{
+		Phase:      e.Phase,
+		PhaseLabel: e.PhaseLabel,
+		Step:       e.Step,
+		StepLabel:  e.StepLabel,
+		Status:     StatusName(e.Status),
+		Checkpoint: e.Checkpoint,
+		Of:         e.Of,
+		Close:      e.Close,
+		Message:    e.Message,
+		Error:      errorString(e.Error),
+	}
+}
+
+// StatusName is the stable document spelling of a step status.
+func StatusName(s progress.StepStatus) string {
+	switch s {
+	case progress.StepPending:
+		return "pending"
+	case progress.StepRunning:
+		return "running"
+	case progress.StepDone:
+		return "done"
+	case progress.StepFailed:
+		return "failed"
+	case progress.StepSkipped:
+		return "skipped"
+	}
+	return "unknown"
+}
+
+// Remove is the document the remove command writes. Worktrees are the ones
+// the filters selected; Result is absent on a dry run.
+type Remove struct {
+	DryRun           bool       `json:"dry_run"`
+	Worktrees        []Worktree `json:"worktrees"`
+	ProtectedSkipped int        `json:"protected_skipped"`
+	// LockedSkipped counts the locked worktrees the filters passed over;
+	// --include-locked selects them.
+	LockedSkipped int             `json:"locked_skipped"`
+	Result        *DeletionResult `json:"result,omitempty"`
+	// Archives are the snapshots taken before removal (--archive).
+	Archives []Archive `json:"archives,omitempty"`
+}
+
+// Archive is the document form of trash.Archive.
+type Archive struct {
+	ID           string `json:"id"`
+	Ref          string `json:"ref"`
+	Branch       string `json:"branch,omitempty"`
+	Head         string `json:"head"`
+	Snapshot     string `json:"snapshot"`
+	WorktreePath string `json:"worktree_path"`
+	Untracked    int    `json:"untracked_files"`
+	// SkippedIgnored are ignored paths too large to archive.
+	SkippedIgnored []string  `json:"skipped_ignored,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// NewArchive converts a worktree archive.
+func NewArchive(a trash.Archive) Archive {
+	return Archive{
+		ID:             a.ID,
+		Ref:            a.Ref,
+		Branch:         a.Branch,
+		Head:           a.Head,
+		Snapshot:       a.Snapshot,
+		WorktreePath:   a.WorktreePath,
+		Untracked:      a.Untracked,
+		SkippedIgnored: a.SkippedIgnored,
+		CreatedAt:      a.CreatedAt,
+	}
+}
+
+// NewArchives converts a list of archives; the result is never nil.
+func NewArchives(archives []trash.Archive) []Archive {
+	docs := make([]Archive, len(archives))
+	for i, a := range archives {
+		docs[i] = NewArchive(a)
+	}
+	return docs
+}
+
+// Restore is the document the restore command writes. Detached is set when
+// the archived branch had moved and the worktree was checked out at the
+// archived head instead; Kept when the archive was not dropped because no
+// branch reaches that head.
+type Restore struct {
+	Archive  Archive `json:"archive"`
+	Path     string  `json:"path"`
+	Detached bool    `json:"detached,omitempty"`
+	Kept     bool    `json:"archive_kept,omitempty"`
+}
+
+// JournalEntry is the document form of journal.Entry. The recorded steps
+// keep the journal's own JSON shape.
+type JournalEntry struct {
+	ID             string                  `json:"id"`
+	Command        string                  `json:"command"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UndoneAt       *time.Time              `json:"undone_at,omitempty"`
+	Branches       []journal.Branch        `json:"branches"`
+	ConfigSections []journal.ConfigSection `json:"config_sections"`
+	Worktrees      []journal.Worktree      `json:"worktrees"`
+	RemoteRefs     []journal.Ref           `json:"remote_refs"`
+}
+
+// NewJournalEntry converts a journal entry; its slices are never nil.
+func NewJournalEntry(e journal.Entry) JournalEntry {
+	return JournalEntry{
+		ID:             e.ID,
+		Command:        e.Command,
+		CreatedAt:      e.CreatedAt,
+		UndoneAt:       e.UndoneAt,
+		Branches:       nonNil(e.Branches),
+		ConfigSections: nonNil(e.ConfigSections),
+		Worktrees:      nonNil(e.Worktrees),
+		RemoteRefs:     nonNil(e.RemoteRefs),
+	}
+}
+
+// NewJournal converts the journal, oldest entry first; the result is never nil.
+func NewJournal(entries []journal.Entry) []JournalEntry {
+	docs := make([]JournalEntry, len(entries))
+	for i, e := range entries {
+		docs[i] = NewJournalEntry(e)
+	}
+	return docs
+}
+
+// UndoOutcome is the document form of journal.Outcome.
+type UndoOutcome struct {
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Undo is the document the undo command writes. Complete is false when a
+// step failed; the entry then stays in the journal to retry.
+type Undo struct {
+	Entry    JournalEntry  `json:"entry"`
+	Outcomes []UndoOutcome `json:"outcomes"`
+	Complete bool          `json:"complete"`
+}
+
+// NewUndo converts an undo run.
+func NewUndo(e journal.Entry, outcomes []journal.Outcome) Undo {
+	doc := Undo{Entry: NewJournalEntry(e), Outcomes: make([]UndoOutcome, len(outcomes)), Complete: true}
+	for i, o := range outcomes {
+		doc.Outcomes[i] = UndoOutcome{Kind: o.Kind, Target: o.Target, Status: string(o.Status)}
+		if o.Err != nil {
+			doc.Outcomes[i].Error = o.Err.Error()
+			doc.Complete = false
+		}
+	}
+	return doc
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// WorktreeOutcome is the document form of worktree.WorktreeOutcome.
+type WorktreeOutcome struct {
+	Path    string `json:"path"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// DeletionResult is the document form of worktree.DeletionResult.
+type DeletionResult struct {
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Outcomes  []WorktreeOutcome `json:"outcomes"`
+	Phases    []Phase           `json:"phases"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// NewDeletionResult converts a worktree deletion result.
+func NewDeletionResult(r worktree.DeletionResult) *DeletionResult {
+	outcomes := make([]WorktreeOutcome, len(r.Outcomes))
+	for i, o := range r.Outcomes {
+		outcomes[i] = WorktreeOutcome{Path: o.Path, Success: o.Success, Error: errorString(o.Error)}
+	}
+	return &DeletionResult{
+		Succeeded: r.SuccessCount,
+		Failed:    r.FailureCount,
+		Outcomes:  outcomes,
+		Phases:    NewPhases(r.Phases),
+		Error:     errorString(r.Err),
+	}
+}
+
+// CreateResult is the document form of creator.Result.
+type CreateResult struct {
+	WorktreePath string    `json:"worktree_path"`
+	EnvFiles     []EnvFile `json:"env_files,omitempty"`
+	Failed       bool      `json:"failed"`
+	Phases       []Phase   `json:"phases"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// EnvFile is one env file a creation set up, with how it was set up.
+type EnvFile struct {
+	Name string `json:"name"`
+	Mode string `json:"mode"`
+	Port int    `json:"port,omitempty"`
+}
+
+// NewCreateResult converts a worktree creation result.
+func NewCreateResult(r creator.Result) CreateResult {
+	doc := CreateResult{
+		WorktreePath: r.WorktreePath,
+		Failed:       r.HasFailures(),
+		Phases:       NewPhases(r.Phases),
+		Error:        errorString(r.Err),
+	}
+	for _, f := range r.EnvFiles {
+		doc.EnvFiles = append(doc.EnvFiles, EnvFile{Name: f.Name, Mode: f.Mode, Port: f.Port})
+	}
+	return doc
+}
+
+// CreateBatchResult is the document form of creator.BatchResult. Phases
+// holds every worktree's phases, each prefixed with its branch.
+type CreateBatchResult struct {
+	Worktrees []CreateBatchWorktree `json:"worktrees"`
+	Failed    bool                  `json:"failed"`
+	Phases    []Phase               `json:"phases"`
+	Error     string                `json:"error,omitempty"`
+}
+
+// CreateBatchWorktree is one worktree's outcome in a CreateBatchResult.
+type CreateBatchWorktree struct {
+	Branch       string `json:"branch"`
+	WorktreePath string `json:"worktree_path"`
+	Failed       bool   `json:"failed"`
+}
+
+// NewCreateBatchResult converts a batch creation result.
+func NewCreateBatchResult(r creator.BatchResult) CreateBatchResult {
+	doc := CreateBatchResult{
+		Worktrees: make([]CreateBatchWorktree, 0, len(r.Worktrees)),
+		Failed:    r.HasFailures(),
+		Phases:    NewPhases(r.Phases),
+		Error:     errorString(r.Err),
+	}
+	for _, wt := range r.Worktrees {
+		doc.Worktrees = append(doc.Worktrees, CreateBatchWorktree{Branch: wt.Branch, WorktreePath: wt.WorktreePath, Failed: wt.HasFailures()})
+	}
+	return doc
+}
+
+// CloneResult is the document form of repo.CloneResult.
+type CloneResult struct {
+	RepoPath      string  `json:"repo_path"`
+	WorktreePath  string  `json:"worktree_path"`
+	DefaultBranch string  `json:"default_branch"`
+	OriginURL     string  `json:"origin_url"`
+	Failed        bool    `json:"failed"`
+	Phases        []Phase `json:"phases"`
+	Error         string  `json:"error,omitempty"`
+}
+
+// NewCloneResult converts a clone result.
+func NewCloneResult(r repo.CloneResult) CloneResult {
+	return CloneResult{
+		RepoPath:      r.RepoPath,
+		WorktreePath:  r.WorktreePath,
+		DefaultBranch: r.DefaultBranch,
+		OriginURL:     r.OriginURL,
+		Failed:        r.Err != nil || progress.PhasesHaveFailures(r.Phases),
+		Phases:        NewPhases(r.Phases),
+		Error:         errorString(r.Err),
+	}
+}
+
+// MigrateResult is the document form of repo.MigrateResult.
+type MigrateResult struct {
+	BareRoot     string   `json:"bare_root"`
+	WorktreePath string   `json:"worktree_path"`
+	BackupPath   string   `json:"backup_path,omitempty"`
+	BackupSize   string   `json:"backup_size,omitempty"`
+	Branch       string   `json:"branch"`
+	Dirty        bool     `json:"dirty"`
+	StashRefs    []string `json:"stash_refs,omitempty"`
+	Worktrees    []string `json:"reregistered_worktrees,omitempty"`
+	Unmovable    []string `json:"unmovable,omitempty"`
+	Failed       bool     `json:"failed"`
+	Phases       []Phase  `json:"phases"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// NewMigrateResult converts a migration result.
+func NewMigrateResult(r repo.MigrateResult) MigrateResult {
+	doc := MigrateResult{
+		BareRoot:     r.BareRoot,
+		WorktreePath: r.WorktreePath,
+		BackupPath:   r.BackupPath,
+		BackupSize:   r.BackupSize,
+		Branch:       r.Branch,
+		Dirty:        r.IsDirty,
+		Unmovable:    r.Inventory.Unmovable,
+		Failed:       r.Err != nil || progress.PhasesHaveFailures(r.Phases),
+		Phases:       NewPhases(r.Phases),
+		Error:        errorString(r.Err),
+	}
+	for _, stash := range r.Inventory.Stashes {
+		doc.StashRefs = append(doc.StashRefs, stash.Ref)
+	}
+	for _, wt := range r.Inventory.Worktrees {
+		doc.Worktrees = append(doc.Worktrees, wt.Path)
+	}
+	return doc
+}
+
+// UnmigrateResult is the document form of repo.UnmigrateResult.
+type UnmigrateResult struct {
+	RepoPath   string   `json:"repo_path"`
+	Worktree   string   `json:"worktree"`
+	Branch     string   `json:"branch"`
+	Dirty      bool     `json:"dirty"`
+	BackupPath string   `json:"backup_path,omitempty"`
+	BackupSize string   `json:"backup_size,omitempty"`
+	Converted  []string `json:"converted_worktrees,omitempty"`
+	Dropped    []string `json:"dropped_worktrees,omitempty"`
+	Failed     bool     `json:"failed"`
+	// RolledBack: the failed conversion was undone from the backup.
+	RolledBack bool    `json:"rolled_back,omitempty"`
+	Phases     []Phase `json:"phases"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// NewUnmigrateResult converts an unmigration result.
+func NewUnmigrateResult(r repo.UnmigrateResult) UnmigrateResult {
+	return UnmigrateResult{
+		RepoPath:   r.RepoPath,
+		Worktree:   r.Worktree,
+		Branch:     r.Branch,
+		Dirty:      r.IsDirty,
+		BackupPath: r.BackupPath,
+		BackupSize: r.BackupSize,
+		Converted:  r.Converted,
+		Dropped:    r.Dropped,
+		Failed:     r.HasFailures(),
+		RolledBack: r.RolledBack,
+		Phases:     NewPhases(r.Phases),
+		Error:      errorString(r.Err),
+	}
+}
+
+// MoveResult is the document form of worktree.MoveResult.
+type MoveResult struct {
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	OldBranch string  `json:"old_branch,omitempty"`
+	NewBranch string  `json:"new_branch,omitempty"`
+	Failed    bool    `json:"failed"`
+	Phases    []Phase `json:"phases"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// NewMoveResult converts a move result.
+func NewMoveResult(r worktree.MoveResult) MoveResult {
+	return MoveResult{
+		From:      r.From,
+		To:        r.To,
+		OldBranch: r.OldBranch,
+		NewBranch: r.NewBranch,
+		Failed:    r.HasFailures(),
+		Phases:    NewPhases(r.Phases),
+		Error:     errorString(r.Err),
+	}
+}
+
+// Lock is the document lock and unlock write: the worktree's lock state
+// after the command. Changed is false when unlock found it unlocked.
+type Lock struct {
+	Path    string `json:"path"`
+	Branch  string `json:"branch,omitempty"`
+	Locked  bool   `json:"locked"`
+	Reason  string `json:"reason,omitempty"`
+	Changed bool   `json:"changed"`
+}
+
+// CleanupBranch is the document form of cleanup.BranchInfo.
+type CleanupBranch struct {
+	Name              string     `json:"name"`
+	LastCommitDate    *time.Time `json:"last_commit_date,omitempty"`
+	LastCommitSubject string     `json:"last_commit_subject,omitempty"`
+	Merged            bool       `json:"merged"`
+}
+
+// OperationError is the document form of cleanup.OperationError.
+type OperationError struct {
+	Step  string `json:"step"`
+	Error string `json:"error"`
+}
+
+// CleanupPreview is the document form of cleanup.DryRunResult.
+type CleanupPreview struct {
+	StaleRefs          int              `json:"stale_refs"`
+	ConfigDuplicates   int              `json:"config_duplicates"`
+	GoneBranches       []string         `json:"gone_branches"`
+	OrphanedConfigs    int              `json:"orphaned_configs"`
+	PrunableWorktrees  int              `json:"prunable_worktrees"`
+	ExpiredArchives    int              `json:"expired_archives"`
+	AggressiveBranches []CleanupBranch  `json:"aggressive_branches"`
+	Errors             []OperationError `json:"errors"`
+}
+
+// NewCleanupPreview converts a cleanup dry-run result.
+func NewCleanupPreview(r cleanup.DryRunResult) CleanupPreview {
+	branches := make([]CleanupBranch, len(r.AggressiveBranches))
+	for i, b := range r.AggressiveBranches {
+		branches[i] = CleanupBranch{
+			Name:              b.Name,
+			LastCommitDate:    optionalTime(b.LastCommitDate),
+			LastCommitSubject: b.LastCommitSubject,
+			Merged:            b.Merged,
+		}
+	}
+	gone := r.GoneBranches
+	if gone == nil {
+		gone = []string{}
+	}
+	return CleanupPreview{
+		StaleRefs:          r.StaleRefs,
+		ConfigDuplicates:   r.ConfigDuplicates,
+		GoneBranches:       gone,
+		OrphanedConfigs:    r.OrphanedConfigs,
+		PrunableWorktrees:  r.PrunableWorktrees,
+		ExpiredArchives:    r.ExpiredArchives,
+		AggressiveBranches: branches,
+		Errors:             newOperationErrors(r.Errors),
+	}
+}
+
+// SkippedBranch is the document form of cleanup.SkippedBranch.
+type SkippedBranch struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// CleanupResult is the document form of cleanup.Result.
+type CleanupResult struct {
+	Mode                    string           `json:"mode"`
+	DryRun                  bool             `json:"dry_run"`
+	StaleRefsRemoved        int              `json:"stale_refs_removed"`
+	ConfigDuplicatesRemoved int              `json:"config_duplicates_removed"`
+	OrphanedConfigsRemoved  int              `json:"orphaned_configs_removed"`
+	GoneBranchesDeleted     int              `json:"gone_branches_deleted"`
+	NonWtBranchesDeleted    int              `json:"non_worktree_branches_deleted"`
+	NonWtBranchesRemaining  int              `json:"non_worktree_branches_remaining"`
+	WorktreesPruned         int              `json:"worktrees_pruned"`
+	ArchivesPurged          int              `json:"archives_purged"`
+	BranchesSkipped         []SkippedBranch  `json:"branches_skipped"`
+	Errors                  []OperationError `json:"errors"`
+}
+
+// NewCleanupResult converts a cleanup result.
+func NewCleanupResult(r cleanup.Result, opts cleanup.Options) CleanupResult {
+	skipped := make([]SkippedBranch, len(r.BranchesSkipped))
+	for i, s := range r.BranchesSkipped {
+		skipped[i] = SkippedBranch{Name: s.Name, Reason: string(s.Reason)}
+	}
+	return CleanupResult{
+		Mode:                    string(opts.Mode),
+		DryRun:                  opts.DryRun,
+		StaleRefsRemoved:        r.StaleRefsRemoved,
+		ConfigDuplicatesRemoved: r.ConfigDedupResult.Removed,
+		OrphanedConfigsRemoved:  r.ConfigOrphanResult.Removed,
+		GoneBranchesDeleted:     r.GoneBranchesDeleted,
+		NonWtBranchesDeleted:    r.NonWtBranchesDeleted,
+		NonWtBranchesRemaining:  r.NonWtBranchesRemaining,
+		WorktreesPruned:         r.WorktreesPruned,
+		ArchivesPurged:          r.ArchivesPurged,
+		BranchesSkipped:         skipped,
+		Errors:                  newOperationErrors(r.Errors),
+	}
+}
+
+// CleanupEvent is the document form of cleanup.Event, streamed under NDJSON.
+type CleanupEvent struct {
+	Step    string `json:"step"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// NewCleanupEvent converts a cleanup event.
+func NewCleanupEvent(e cleanup.Event) CleanupEvent {
+	level := "step"
+	switch e.Level {
+	case cleanup.LevelInfo:
+		level = "info"
+	case cleanup.LevelWarn:
+		level = "warn"
+	case cleanup.LevelDetail:
+		level = "detail"
+	}
+	return CleanupEvent{Step: e.Step, Level: level, Message: e.Message}
+}
+
+// Ecosystem is one row of the ecosystems document.
+type Ecosystem struct {
+	Name        string   `json:"name"`
+	DetectFiles []string `json:"detect_files"`
+	Install     string   `json:"install"`
+	Source      string   `json:"source"`
+	Enabled     bool     `json:"enabled"`
+}
+
+// Integration is one row of the integrations document.
+type Integration struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Installed   bool   `json:"installed"`
+	Source      string `json:"source"` // "built-in", or the config layer that declared it
+}
+
+// IntegrationsRefresh is the result of integrations refresh: one phase per
+// worktree, one step per enabled integration.
+type IntegrationsRefresh struct {
+	Phases []Phase `json:"phases"`
+}
+
+// ConfigLayer is one config source, in merge order. Path is empty for the
+// embedded defaults.
+type ConfigLayer struct {
+	Source string `json:"source"`
+	Path   string `json:"path,omitempty"`
+	Found  bool   `json:"found"`
+}
+
+// Config is the config document: the effective values under their YAML
+// keys, and for each dotted field path the layer that set it. Fields absent
+// from Sources hold the default.
+type Config struct {
+	Layers  []ConfigLayer     `json:"layers"`
+	Values  map[string]any    `json:"values"`
+	Sources map[string]string `json:"sources"`
+}
+
+// ConfigProblem is one finding of config validate. Path is empty for a
+// problem in the merged config.
+type ConfigProblem struct {
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// ConfigCheck is the config-check document.
+type ConfigCheck struct {
+	Valid    bool            `json:"valid"`
+	Problems []ConfigProblem `json:"problems"`
+}
+
+// DoctorCheck is the document form of doctor.Check.
+type DoctorCheck struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Status   string   `json:"status"`
+	Detail   string   `json:"detail,omitempty"`
+	Problems []string `json:"problems,omitempty"`
+	Fix      string   `json:"fix,omitempty"`
+	Hint     string   `json:"hint,omitempty"`
+}
+
+// Doctor is the doctor document. With --fix, Phases holds the repair and
+// Checks the diagnosis taken after it.
+type Doctor struct {
+	Root    string        `json:"root"`
+	Healthy bool          `json:"healthy"`
+	Checks  []DoctorCheck `json:"checks"`
+	Phases  []Phase       `json:"phases,omitempty"`
+}
+
+// NewDoctor converts a diagnosis and the phases of any repair run before it.
+func NewDoctor(r doctor.Report, phases []progress.Phase) Doctor {
+	doc := Doctor{
+		Root:    r.Root,
+		Healthy: r.Worst() != doctor.StatusFail,
+		Checks:  make([]DoctorCheck, len(r.Checks)),
+		Phases:  NewPhases(phases),
+	}
+	for i, c := range r.Checks {
+		doc.Checks[i] = DoctorCheck{
+			ID:       c.ID,
+			Name:     c.Name,
+			Status:   string(c.Status),
+			Detail:   c.Detail,
+			Problems: c.Problems,
+			Fix:      c.Fix,
+			Hint:     c.Hint,
+		}
+	}
+	return doc
+}
+
+func newOperationErrors(errs []cleanup.OperationError) []OperationError {
+	docs := make([]OperationError, len(errs))
+	for i, e := range errs {
+		docs[i] = OperationError{Step: e.Step, Error: errorString(e.Err)}
+	}
+	return docs
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
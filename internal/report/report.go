@@ -0,0 +1,132 @@
+// Package report is sentei's machine-readable output. Every command that
+// accepts --format json|ndjson writes Records: a versioned envelope around one
+// of the documents defined here. The documents are deliberately separate from
+// the internal result types so those can change shape without breaking
+// scripts; any incompatible change to a document bumps SchemaVersion.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/abiswas97/sentei/internal/progress"
+)
+
+// SchemaVersion identifies the shape of every Record sentei emits. Adding a
+// field is compatible; renaming, removing or retyping one is not.
+const SchemaVersion = 1
+
+// Format selects how a command writes its output.
+type Format string
+
+const (
+	FormatText   Format = "text"   // human-oriented tables and progress lines
+	FormatJSON   Format = "json"   // one indented Record holding the final result
+	FormatNDJSON Format = "ndjson" // one compact Record per line: progress events, then the result
+)
+
+// ParseFormat validates a --format value. The empty string means text.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON, FormatNDJSON:
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("invalid value for --format: must be 'text', 'json' or 'ndjson'")
+}
+
+// Machine reports whether f is meant for programs rather than people. Machine
+// formats own stdout: commands must send anything else to stderr.
+func (f Format) Machine() bool {
+	return f == FormatJSON || f == FormatNDJSON
+}
+
+// Record kinds. A consumer switches on Kind to decode Data.
+const (
+	KindWorktrees           = "worktrees"
+	KindEcosystems          = "ecosystems"
+	KindIntegrations        = "integrations"
+	KindIntegrationsRefresh = "integrations-refresh"
+	KindRemove              = "remove"
+	KindCleanupPreview      = "cleanup-preview"
+	KindCleanup             = "cleanup"
+	KindCreate              = "create"
+	KindCreateBatch         = "create-batch"
+	KindClone               = "clone"
+	KindMigrate             = "migrate"
+	KindUnmigrate           = "unmigrate"
+	KindMove                = "move"
+	KindLock                = "lock"
+	KindArchives            = "archives"
+	KindRestore             = "restore"
+	KindJournal             = "journal"
+	KindUndo                = "undo"
+	KindConfig              = "config"
+	KindConfigCheck         = "config-check"
+	KindDoctor              = "doctor"
+
+	// KindEvent and KindCleanupEvent only appear in NDJSON streams.
+	KindEvent        = "event"
+	KindCleanupEvent = "cleanup-event"
+)
+
+// Record is the envelope around every document.
+type Record struct {
+	Schema int    `json:"schema"`
+	Kind   string `json:"kind"`
+	Data   any    `json:"data"`
+}
+
+// Writer writes Records in one machine format. It is safe for concurrent use
+// so a progress callback and the final result can share it.
+type Writer struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format Format
+}
+
+// NewWriter returns a Writer for format, which must be a machine format.
+func NewWriter(w io.Writer, format Format) *Writer {
+	return &Writer{w: w, format: format}
+}
+
+// Write emits data as a Record of the given kind.
+func (w *Writer) Write(kind string, data any) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	rec := Record{Schema: SchemaVersion, Kind: kind, Data: data}
+	enc := json.NewEncoder(w.w)
+	enc.SetEscapeHTML(false)
+	if w.format == FormatJSON {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(rec)
+}
+
+// Streaming reports whether intermediate records (progress events) are
+// written. JSON output is a single document, so only NDJSON streams.
+func (w *Writer) Streaming() bool {
+	return w.format == FormatNDJSON
+}
+
+// Events returns a progress callback that streams each event as a Record
+// under NDJSON and discards it under JSON, where the phases in the final
+// result carry the same outcome.
+func (w *Writer) Events() func(progress.Event) {
+	return func(e progress.Event) {
+		if w.Streaming() {
+			_ = w.Write(KindEvent, NewEvent(e))
+		}
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,133 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/progress"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatText, false},
+		{"text", FormatText, false},
+		{"json", FormatJSON, false},
+		{"ndjson", FormatNDJSON, false},
+		{"yaml", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFormat(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriter_JSONWritesOneVersionedRecord(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, FormatJSON)
+
+	w.Events()(progress.Event{Phase: "p", Step: "s", Status: progress.StepRunning})
+	if err := w.Write(KindWorktrees, []Worktree{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var rec struct {
+		Schema int             `json:"schema"`
+		Kind   string          `json:"kind"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("output is not a single JSON document: %v\n%s", err, buf.String())
+	}
+	if rec.Schema != SchemaVersion || rec.Kind != KindWorktrees || string(rec.Data) != "[]" {
+		t.Errorf("record = %+v (data %s)", rec, rec.Data)
+	}
+}
+
+func TestWriter_NDJSONStreamsEventsThenResult(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, FormatNDJSON)
+
+	emit := w.Events()
+	emit(progress.Event{Phase: "remove", Step: "remove-0", Status: progress.StepRunning})
+	emit(progress.Event{Phase: "remove", Step: "remove-0", Status: progress.StepFailed, Error: errors.New("boom")})
+	if err := w.Write(KindRemove, Remove{}); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	var failed struct {
+		Kind string `json:"kind"`
+		Data Event  `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &failed); err != nil {
+		t.Fatal(err)
+	}
+	if failed.Kind != KindEvent || failed.Data.Status != "failed" || failed.Data.Error != "boom" {
+		t.Errorf("event line = %+v", failed)
+	}
+	if !strings.Contains(lines[2], `"kind":"remove"`) {
+		t.Errorf("last line must be the result, got %s", lines[2])
+	}
+}
+
+func TestNewWorktree(t *testing.T) {
+	date := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	doc := NewWorktree(git.Worktree{
+		Path: "/repo/main", Branch: "refs/heads/main", HEAD: "abc",
+		LastCommitDate: date, HasUncommittedChanges: true,
+	}, nil)
+
+	if doc.Branch != "main" || !doc.Protected || !doc.HasUncommittedChanges {
+		t.Errorf("doc = %+v", doc)
+	}
+	if doc.LastCommitDate == nil || !doc.LastCommitDate.Equal(date) {
+		t.Errorf("LastCommitDate = %v, want %v", doc.LastCommitDate, date)
+	}
+
+	data, err := json.Marshal(NewWorktree(git.Worktree{Branch: "refs/heads/feature"}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "last_commit_date") {
+		t.Errorf("an unknown commit date must be omitted: %s", data)
+	}
+}
+
+func TestNewPhases(t *testing.T) {
+	phases := NewPhases([]progress.Phase{{
+		ID: "deps", Name: "Dependencies",
+		Steps: []progress.StepResult{
+			{ID: "a", Name: "npm", Status: progress.StepDone, Message: "ok"},
+			{ID: "b", Name: "go", Status: progress.StepFailed, Error: errors.New("exit 1")},
+			{ID: "c", Name: "pip", Status: progress.StepSkipped},
+		},
+	}})
+
+	got := []string{phases[0].Steps[0].Status, phases[0].Steps[1].Status, phases[0].Steps[2].Status}
+	want := []string{"done", "failed", "skipped"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("step %d status = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if phases[0].Steps[1].Error != "exit 1" {
+		t.Errorf("error = %q", phases[0].Steps[1].Error)
+	}
+}
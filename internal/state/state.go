@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"time"
 )
 
 const stateFile = "sentei.json"
@@ -18,6 +19,89 @@ type State struct {
 	// through the TUI, across all sessions. Garnish, not bookkeeping:
 	// it exists so summaries can whisper at milestones.
 	LifetimeRemoved int `json:"lifetime_removed,omitempty"`
+	// RecentWorktrees records when sentei switch last chose each worktree,
+	// by path, so recently used worktrees rank first.
+	RecentWorktrees map[string]time.Time `json:"recent_worktrees,omitempty"`
+	// Ports records the port allocated to each worktree, by path, for env
+	// file templates' {{.Port}}.
+	Ports map[string]int `json:"ports,omitempty"`
+}
+
+// The range ports are allocated from.
+const (
+	FirstPort = 3100
+	LastPort  = 3999
+)
+
+// AllocatePort returns the port recorded for the worktree at path, or
+// records the lowest port in range that no other worktree holds and free
+// accepts.
+func (s *State) AllocatePort(path string, free func(port int) bool) (int, error) {
+	if port, ok := s.Ports[path]; ok {
+		return port, nil
+	}
+	taken := make(map[int]bool, len(s.Ports))
+	for _, port := range s.Ports {
+		taken[port] = true
+	}
+	for port := FirstPort; port <= LastPort; port++ {
+		if taken[port] || !free(port) {
+			continue
+		}
+		if s.Ports == nil {
+			s.Ports = make(map[string]int)
+		}
+		s.Ports[path] = port
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free port between %d and %d", FirstPort, LastPort)
+}
+
+// PrunePorts releases ports held by paths not in live.
+func (s *State) PrunePorts(live []string) {
+	for path := range s.Ports {
+		if !slices.Contains(live, path) {
+			delete(s.Ports, path)
+		}
+	}
+}
+
+// MarkUsed records that the worktree at path was switched to at t.
+func (s *State) MarkUsed(path string, t time.Time) {
+	if s.RecentWorktrees == nil {
+		s.RecentWorktrees = make(map[string]time.Time)
+	}
+	s.RecentWorktrees[path] = t
+}
+
+// PruneRecent drops recency records for paths not in live, so removed
+// worktrees do not accumulate.
+func (s *State) PruneRecent(live []string) {
+	for path := range s.RecentWorktrees {
+		if !slices.Contains(live, path) {
+			delete(s.RecentWorktrees, path)
+		}
+	}
+}
+
+// MoveWorktree carries what is recorded for the worktree at from over to
+// its new path, so a moved worktree keeps its port and recency.
+func (s *State) MoveWorktree(from, to string) {
+	if t, ok := s.RecentWorktrees[from]; ok {
+		delete(s.RecentWorktrees, from)
+		s.RecentWorktrees[to] = t
+	}
+	if port, ok := s.Ports[from]; ok {
+		delete(s.Ports, from)
+		s.Ports[to] = port
+	}
+}
+
+// Records reports whether anything is recorded for the worktree at path.
+func (s *State) Records(path string) bool {
+	_, recent := s.RecentWorktrees[path]
+	_, port := s.Ports[path]
+	return recent || port
 }
 
 // HasIntegration reports whether name is in the Integrations slice.
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/abiswas97/sentei/internal/state"
 )
@@ -152,3 +153,70 @@ func TestHasIntegration(t *testing.T) {
 		})
 	}
 }
+
+func TestRecentWorktrees_MarkPruneRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	used := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	s := &state.State{}
+	s.MarkUsed("/wt/a", used)
+	s.MarkUsed("/wt/b", used)
+	s.PruneRecent([]string{"/wt/a"})
+	if err := state.Save(dir, s); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.RecentWorktrees) != 1 || !got.RecentWorktrees["/wt/a"].Equal(used) {
+		t.Errorf("RecentWorktrees = %v, want only /wt/a at %v", got.RecentWorktrees, used)
+	}
+}
+
+func TestAllocatePort(t *testing.T) {
+	s := &state.State{}
+	busy := func(port int) bool { return port != state.FirstPort }
+
+	first, err := s.AllocatePort("/repo/a", busy)
+	if err != nil || first != state.FirstPort+1 {
+		t.Fatalf("AllocatePort = %d, %v; want the lowest free port %d", first, err, state.FirstPort+1)
+	}
+	second, _ := s.AllocatePort("/repo/b", busy)
+	if second != state.FirstPort+2 {
+		t.Errorf("second worktree got %d, want %d", second, state.FirstPort+2)
+	}
+	if again, _ := s.AllocatePort("/repo/a", func(int) bool { return false }); again != first {
+		t.Errorf("a worktree keeps its port, got %d want %d", again, first)
+	}
+
+	s.PrunePorts([]string{"/repo/b"})
+	if third, _ := s.AllocatePort("/repo/c", busy); third != first {
+		t.Errorf("a pruned worktree's port should be reused, got %d want %d", third, first)
+	}
+
+	if _, err := (&state.State{}).AllocatePort("/repo/d", func(int) bool { return false }); err == nil {
+		t.Error("expected an error when no port is free")
+	}
+}
+
+func TestMoveWorktree_CarriesPortAndRecency(t *testing.T) {
+	used := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := &state.State{}
+	s.MarkUsed("/repo/old", used)
+	port, _ := s.AllocatePort("/repo/old", func(int) bool { return true })
+
+	s.MoveWorktree("/repo/old", "/repo/new")
+	if s.Records("/repo/old") {
+		t.Errorf("old path still recorded: %+v", s)
+	}
+	if !s.RecentWorktrees["/repo/new"].Equal(used) || s.Ports["/repo/new"] != port {
+		t.Errorf("new path = %v, port %d; want %v, port %d", s.RecentWorktrees["/repo/new"], s.Ports["/repo/new"], used, port)
+	}
+
+	s.MoveWorktree("/repo/unknown", "/repo/elsewhere")
+	if s.Records("/repo/elsewhere") {
+		t.Error("moving an unrecorded path recorded the destination")
+	}
+}
@@ -0,0 +1,143 @@
+// Package switcher ranks worktrees against a fuzzy query for sentei switch.
+package switcher
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/abiswas97/sentei/internal/git"
+)
+
+// Candidate is a worktree that matched the query.
+type Candidate struct {
+	Worktree git.Worktree
+	Branch   string // short branch name, empty when detached
+	// Exact is set when the query equals the branch name or the worktree's
+	// directory name.
+	Exact    bool
+	Score    int
+	LastUsed time.Time // zero when never switched to
+}
+
+// Name is what the picker shows first: the branch, or the directory for a
+// detached worktree.
+func (c Candidate) Name() string {
+	if c.Branch != "" {
+		return c.Branch
+	}
+	return filepath.Base(c.Worktree.Path)
+}
+
+// Rank returns the worktrees matching query, best first. Exact matches lead,
+// then recently used worktrees (most recent first), then the rest by match
+// score. An empty query matches everything. The bare entry and prunable
+// worktrees are never candidates.
+func Rank(wts []git.Worktree, query string, recent map[string]time.Time) []Candidate {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var out []Candidate
+	for _, wt := range wts {
+		if wt.IsBare || wt.IsPrunable {
+			continue
+		}
+		c := Candidate{
+			Worktree: wt,
+			Branch:   strings.TrimPrefix(wt.Branch, "refs/heads/"),
+			LastUsed: recent[wt.Path],
+		}
+		if query != "" {
+			base := strings.ToLower(filepath.Base(wt.Path))
+			branch := strings.ToLower(c.Branch)
+			c.Exact = query == branch || query == base
+			score, ok := bestScore(query, branch, base, strings.ToLower(wt.Path))
+			if !ok {
+				continue
+			}
+			c.Score = score
+		}
+		out = append(out, c)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Exact != b.Exact {
+			return a.Exact
+		}
+		if !a.LastUsed.Equal(b.LastUsed) {
+			return a.LastUsed.After(b.LastUsed)
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Name() < b.Name()
+	})
+	return out
+}
+
+// bestScore scores query against the branch and directory name, falling
+// back to the full path at a discount.
+func bestScore(query, branch, base, path string) (int, bool) {
+	best, found := 0, false
+	for _, text := range []string{branch, base} {
+		if s, ok := Score(query, text); ok && (!found || s > best) {
+			best, found = s, true
+		}
+	}
+	if found {
+		return best, true
+	}
+	if s, ok := Score(query, path); ok {
+		return s / 2, true
+	}
+	return 0, false
+}
+
+// Score reports whether query's characters appear in text in order, and how
+// well: consecutive runs, matches at word starts (after / - _ . or a space)
+// and a match at the very start score higher; a plain substring beats any
+// scattered match. Both strings are compared as given; callers lowercase.
+func Score(query, text string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	if i := strings.Index(text, query); i >= 0 {
+		score := 100 + 10*len(query)
+		if i == 0 {
+			score += 50
+		} else if r, _ := utf8.DecodeLastRuneInString(text[:i]); isBoundary(r) {
+			score += 25
+		}
+		return score - len(text), true
+	}
+
+	q, t := []rune(query), []rune(text)
+	score, qi, prev := 0, 0, -2
+	for ti, r := range t {
+		if qi == len(q) {
+			break
+		}
+		if r != q[qi] {
+			continue
+		}
+		score++
+		if ti == prev+1 {
+			score += 5
+		}
+		if ti == 0 || isBoundary(t[ti-1]) {
+			score += 8
+		}
+		prev = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score - len(text)/4, true
+}
+
+func isBoundary(r rune) bool {
+	return r == '/' || r == '-' || r == '_' || r == '.' || unicode.IsSpace(r)
+}
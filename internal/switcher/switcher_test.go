@@ -0,0 +1,84 @@
+package switcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/git"
+)
+
+var testWorktrees = []git.Worktree{
+	{Path: "/repo", IsBare: true},
+	{Path: "/repo/main", Branch: "refs/heads/main"},
+	{Path: "/repo/feat-login", Branch: "refs/heads/feature/login"},
+	{Path: "/repo/feat-logout", Branch: "refs/heads/feature/logout"},
+	{Path: "/repo/fix-api", Branch: "refs/heads/fix/api-timeout"},
+	{Path: "/repo/scratch", IsDetached: true},
+	{Path: "/repo/gone", Branch: "refs/heads/gone", IsPrunable: true},
+}
+
+func names(cs []Candidate) []string {
+	out := make([]string, len(cs))
+	for i, c := range cs {
+		out[i] = c.Name()
+	}
+	return out
+}
+
+func TestRank(t *testing.T) {
+	used := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		query  string
+		recent map[string]time.Time
+		want   []string
+	}{
+		{"empty query lists all but bare and prunable", "", nil,
+			[]string{"feature/login", "feature/logout", "fix/api-timeout", "main", "scratch"}},
+		{"substring beats scattered match", "api", nil, []string{"fix/api-timeout"}},
+		{"fuzzy subsequence", "fapt", nil, []string{"fix/api-timeout"}},
+		{"word starts rank first", "fat", nil, []string{"fix/api-timeout", "feature/login", "feature/logout"}},
+		{"directory name matches", "scratch", nil, []string{"scratch"}},
+		{"exact branch first", "feature/logout", nil, []string{"feature/logout"}},
+		{"recently used first", "log", map[string]time.Time{"/repo/feat-logout": used},
+			[]string{"feature/logout", "feature/login"}},
+		{"no match", "zzz", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := names(Rank(testWorktrees, tt.query, tt.recent))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Rank(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Rank(%q) = %v, want %v", tt.query, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRank_ExactOutranksRecent(t *testing.T) {
+	recent := map[string]time.Time{"/repo/feat-logout": time.Now()}
+	got := Rank(testWorktrees, "feat-login", recent)
+	if len(got) == 0 || got[0].Name() != "feature/login" || !got[0].Exact {
+		t.Errorf("an exact directory match should lead, got %v", names(got))
+	}
+}
+
+func TestScore(t *testing.T) {
+	if _, ok := Score("xyz", "feature/login"); ok {
+		t.Error("characters out of order must not match")
+	}
+	prefix, _ := Score("feat", "feature/login")
+	inner, _ := Score("feat", "old-feature")
+	if prefix <= inner {
+		t.Errorf("a prefix match (%d) should outscore an inner one (%d)", prefix, inner)
+	}
+	boundary, _ := Score("fl", "feature/login")
+	scattered, _ := Score("fl", "fooled")
+	if boundary <= scattered {
+		t.Errorf("word-start matches (%d) should outscore scattered ones (%d)", boundary, scattered)
+	}
+}
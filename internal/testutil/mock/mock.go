@@ -15,8 +15,10 @@ type Response struct {
 	Err    error
 }
 
-// Runner fakes git.CommandRunner and git.ShellRunner. Expected calls are
-// keyed "dir:[arg1 arg2 ...]" for Run and "dir:shell[command]" for RunShell;
+// Runner fakes git.CommandRunner, git.ShellRunner and repo.GhRunner.
+// Expected calls are keyed "dir:[arg1 arg2 ...]" for Run,
+// "dir:[arg1 arg2 ...]<input" for RunInput, "dir:shell[command]" for
+// RunShell and "dir:gh[arg1 arg2 ...]" for RunGh;
 // an unmatched key returns an error naming the call so the failing fixture is
 // obvious from the test output.
 type Runner struct {
@@ -41,6 +43,15 @@ func (m *Runner) Run(dir string, args ...string) (string, error) {
 	return "", fmt.Errorf("unexpected call: %s", key)
 }
 
+func (m *Runner) RunInput(dir, input string, args ...string) (string, error) {
+	key := fmt.Sprintf("%s:%v<%s", dir, args, input)
+	m.record(key)
+	if resp, ok := m.Responses[key]; ok {
+		return resp.Output, resp.Err
+	}
+	return "", fmt.Errorf("unexpected call: %s", key)
+}
+
 func (m *Runner) RunShell(dir string, command string) (string, error) {
 	key := fmt.Sprintf("%s:shell[%s]", dir, command)
 	m.record(key)
@@ -50,6 +61,15 @@ func (m *Runner) RunShell(dir string, command string) (string, error) {
 	return "", fmt.Errorf("unexpected shell call: %s", key)
 }
 
+func (m *Runner) RunGh(dir string, args ...string) (string, error) {
+	key := fmt.Sprintf("%s:gh%v", dir, args)
+	m.record(key)
+	if resp, ok := m.Responses[key]; ok {
+		return resp.Output, resp.Err
+	}
+	return "", fmt.Errorf("unexpected gh call: %s", key)
+}
+
 func (m *Runner) record(key string) {
 	m.mu.Lock()
 	m.Calls = append(m.Calls, key)
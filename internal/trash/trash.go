@@ -0,0 +1,406 @@
+// Package trash keeps recoverable snapshots of worktrees sentei removes.
+//
+// An archive has three parts. A stash-like commit records HEAD plus the
+// uncommitted tracked changes and lives on refs/sentei/trash/<branch>/<stamp>,
+// which also keeps the branch tip reachable after the branch is deleted. A
+// gzipped tarball holds the untracked and ignored files git cannot store,
+// short of ignored trees past IgnoredEntryLimit. A JSON manifest
+// ties the two together. The tarball and the manifest live in
+// <git common dir>/sentei-trash, next to sentei.json.
+package trash
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/git"
+)
+
+// RefPrefix is the namespace every archive ref lives under.
+const RefPrefix = "refs/sentei/trash/"
+
+const (
+	dirName     = "sentei-trash"
+	stampLayout = "20060102T150405Z"
+)
+
+// IgnoredEntryLimit caps each ignored file or directory an archive keeps.
+// Ignored files such as .env exist nowhere else, so they go in the tarball;
+// dependency and build trees past the cap (node_modules, target) are left
+// out and named in Archive.SkippedIgnored.
+const IgnoredEntryLimit = 16 << 20
+
+// ErrNotFound is returned when no archive has the requested ID.
+var ErrNotFound = errors.New("archive not found")
+
+// Archive describes one archived worktree.
+type Archive struct {
+	// ID is the ref name below RefPrefix: "<branch>/<timestamp>".
+	ID  string `json:"id"`
+	Ref string `json:"ref"`
+	// Branch is the short branch name; empty for a detached worktree.
+	Branch string `json:"branch,omitempty"`
+	// Head is the commit the worktree had checked out.
+	Head string `json:"head"`
+	// Snapshot is the commit on Ref. It equals Head when there were no
+	// uncommitted tracked changes; otherwise it is a stash commit on Head.
+	Snapshot     string `json:"snapshot"`
+	WorktreePath string `json:"worktree_path"`
+	// Untracked is the number of files in the tarball, ignored ones
+	// included; 0 means no tarball.
+	Untracked int `json:"untracked"`
+	// SkippedIgnored are the ignored paths larger than IgnoredEntryLimit,
+	// which the archive does not hold.
+	SkippedIgnored []string  `json:"skipped_ignored,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// HasChanges reports whether the snapshot carries uncommitted changes.
+func (a Archive) HasChanges() bool {
+	return a.Snapshot != a.Head
+}
+
+// Dir returns the directory archives are stored in for a git common dir.
+func Dir(commonDir string) string {
+	return filepath.Join(commonDir, dirName)
+}
+
+// Create archives wt: it snapshots uncommitted tracked changes with
+// `git stash create` (which leaves the worktree untouched), tars untracked
+// and ignored files, points a trash ref at the snapshot and writes the
+// manifest. A failure leaves nothing behind.
+func Create(runner git.CommandRunner, commonDir string, wt git.Worktree, now time.Time) (Archive, error) {
+	head := wt.HEAD
+	if head == "" {
+		out, err := runner.Run(wt.Path, "rev-parse", "HEAD")
+		if err != nil {
+			return Archive{}, fmt.Errorf("resolving HEAD: %w", err)
+		}
+		head = strings.TrimSpace(out)
+	}
+
+	branch := strings.TrimPrefix(wt.Branch, "refs/heads/")
+	a := Archive{
+		Branch:       branch,
+		Head:         head,
+		Snapshot:     head,
+		WorktreePath: wt.Path,
+		CreatedAt:    now.UTC().Truncate(time.Second),
+	}
+
+	label := branch
+	if label == "" {
+		label = "detached-" + shortSHA(head)
+	}
+	stash, err := runner.Run(wt.Path, "stash", "create", "sentei: archive of "+label)
+	if err != nil {
+		return Archive{}, fmt.Errorf("snapshotting changes: %w", err)
+	}
+	if s := strings.TrimSpace(stash); s != "" {
+		a.Snapshot = s
+	}
+
+	dir := Dir(commonDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Archive{}, fmt.Errorf("creating trash dir: %w", err)
+	}
+	a.ID = uniqueID(dir, label, a.CreatedAt)
+	a.Ref = RefPrefix + a.ID
+
+	untracked, err := git.RunRaw(runner, wt.Path, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return Archive{}, fmt.Errorf("listing untracked files: %w", err)
+	}
+	ignored, err := git.RunRaw(runner, wt.Path, "ls-files", "--others", "--ignored", "--exclude-standard", "--directory", "-z")
+	if err != nil {
+		return Archive{}, fmt.Errorf("listing ignored files: %w", err)
+	}
+	kept, skipped, err := collectIgnored(wt.Path, splitNUL(ignored))
+	if err != nil {
+		return Archive{}, fmt.Errorf("listing ignored files: %w", err)
+	}
+	a.SkippedIgnored = skipped
+	if files := append(splitNUL(untracked), kept...); len(files) > 0 {
+		if err := writeTarball(tarballPath(dir, a.ID), wt.Path, files); err != nil {
+			_ = os.Remove(tarballPath(dir, a.ID))
+			return Archive{}, fmt.Errorf("archiving untracked files: %w", err)
+		}
+		a.Untracked = len(files)
+	}
+
+	if _, err := runner.Run(commonDir, "update-ref", a.Ref, a.Snapshot); err != nil {
+		_ = os.Remove(tarballPath(dir, a.ID))
+		return Archive{}, fmt.Errorf("writing %s: %w", a.Ref, err)
+	}
+	if err := writeManifest(dir, a); err != nil {
+		_, _ = runner.Run(commonDir, "update-ref", "-d", a.Ref)
+		_ = os.Remove(tarballPath(dir, a.ID))
+		return Archive{}, err
+	}
+	return a, nil
+}
+
+// List returns every archive, newest first. A missing trash dir is an empty
+// list; an unreadable manifest is an error.
+func List(commonDir string) ([]Archive, error) {
+	dir := Dir(commonDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading trash dir: %w", err)
+	}
+	var archives []Archive
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading archive manifest: %w", err)
+		}
+		var a Archive
+		if err := json.Unmarshal(data, &a); err != nil {
+			return nil, fmt.Errorf("parsing archive manifest %s: %w", entry.Name(), err)
+		}
+		archives = append(archives, a)
+	}
+	sort.SliceStable(archives, func(i, j int) bool {
+		return archives[i].CreatedAt.After(archives[j].CreatedAt)
+	})
+	return archives, nil
+}
+
+// Find returns the archive with the given ID.
+func Find(commonDir, id string) (Archive, error) {
+	archives, err := List(commonDir)
+	if err != nil {
+		return Archive{}, err
+	}
+	for _, a := range archives {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+	return Archive{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// Restored describes how Restore brought an archive back.
+type Restored struct {
+	// Detached is set when the branch moved after the archive was taken:
+	// the worktree is checked out at Head, leaving the branch alone.
+	Detached bool
+	// Kept is set when the archive was not dropped because no branch
+	// reaches Head, so its ref is all that keeps those commits.
+	Kept bool
+}
+
+// Restore resurrects a as a worktree at path, then drops it. The branch is
+// checked out if it still points at Head and recreated at Head if it is
+// gone; a branch that has moved since is left alone and the worktree is
+// detached at Head instead. Uncommitted changes are re-applied and untracked
+// files extracted on top. The archive is kept if any step fails, or while no
+// branch reaches Head, so nothing it holds is lost.
+func Restore(runner git.CommandRunner, repoPath, commonDir string, a Archive, path string) (Restored, error) {
+	var restored Restored
+	if _, err := os.Stat(path); err == nil {
+		return restored, fmt.Errorf("restore target already exists: %s", path)
+	}
+
+	var args []string
+	switch {
+	case a.Branch == "":
+		args = []string{"worktree", "add", "--detach", path, a.Head}
+	case branchExists(runner, repoPath, a.Branch):
+		tip, err := runner.Run(repoPath, "rev-parse", "refs/heads/"+a.Branch)
+		if err != nil {
+			return restored, fmt.Errorf("resolving branch %s: %w", a.Branch, err)
+		}
+		if tip == a.Head {
+			args = []string{"worktree", "add", path, a.Branch}
+		} else {
+			args = []string{"worktree", "add", "--detach", path, a.Head}
+			restored.Detached = true
+		}
+	default:
+		args = []string{"worktree", "add", "-b", a.Branch, path, a.Head}
+	}
+	if _, err := runner.Run(repoPath, args...); err != nil {
+		return restored, fmt.Errorf("adding worktree: %w", err)
+	}
+
+	if a.HasChanges() {
+		if _, err := runner.Run(path, "stash", "apply", a.Snapshot); err != nil {
+			return restored, fmt.Errorf("re-applying uncommitted changes: %w", err)
+		}
+	}
+	if a.Untracked > 0 {
+		if err := extractTarball(tarballPath(Dir(commonDir), a.ID), path); err != nil {
+			return restored, fmt.Errorf("restoring untracked files: %w", err)
+		}
+	}
+	if restored.Detached && !reachableFromBranch(runner, repoPath, a.Head) {
+		restored.Kept = true
+		return restored, nil
+	}
+	return restored, Drop(runner, commonDir, a)
+}
+
+// Drop deletes a's ref, tarball and manifest.
+func Drop(runner git.CommandRunner, commonDir string, a Archive) error {
+	if _, err := runner.Run(commonDir, "update-ref", "-d", a.Ref); err != nil {
+		return fmt.Errorf("deleting %s: %w", a.Ref, err)
+	}
+	dir := Dir(commonDir)
+	if err := os.Remove(tarballPath(dir, a.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("deleting untracked-file tarball: %w", err)
+	}
+	if err := os.Remove(manifestPath(dir, a.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("deleting archive manifest: %w", err)
+	}
+	return nil
+}
+
+// Expired returns the archives created more than retention before now.
+func Expired(commonDir string, retention time.Duration, now time.Time) ([]Archive, error) {
+	archives, err := List(commonDir)
+	if err != nil {
+		return nil, err
+	}
+	var expired []Archive
+	for _, a := range archives {
+		if now.Sub(a.CreatedAt) > retention {
+			expired = append(expired, a)
+		}
+	}
+	return expired, nil
+}
+
+func branchExists(runner git.CommandRunner, repoPath, branch string) bool {
+	_, err := runner.Run(repoPath, "show-ref", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// reachableFromBranch reports whether any local branch contains commit.
+func reachableFromBranch(runner git.CommandRunner, repoPath, commit string) bool {
+	out, err := runner.Run(repoPath, "for-each-ref", "--contains", commit, "--format=%(refname)", "refs/heads/")
+	return err == nil && strings.TrimSpace(out) != ""
+}
+
+// uniqueID returns "<label>/<stamp>", suffixed when an archive of the same
+// branch was already taken in the same second.
+func uniqueID(dir, label string, created time.Time) string {
+	base := label + "/" + created.Format(stampLayout)
+	id := base
+	for n := 2; ; n++ {
+		if _, err := os.Stat(manifestPath(dir, id)); errors.Is(err, os.ErrNotExist) {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+func writeManifest(dir string, a Archive) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding archive manifest: %w", err)
+	}
+	if err := os.WriteFile(manifestPath(dir, a.ID), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing archive manifest: %w", err)
+	}
+	return nil
+}
+
+// fileKey flattens an ID (which contains slashes) into one file name.
+func fileKey(id string) string {
+	return url.PathEscape(id)
+}
+
+func manifestPath(dir, id string) string {
+	return filepath.Join(dir, fileKey(id)+".json")
+}
+
+func tarballPath(dir, id string) string {
+	return filepath.Join(dir, fileKey(id)+".tar.gz")
+}
+
+// splitNUL splits -z output. Paths are kept byte for byte: a name may
+// start or end with a space.
+func splitNUL(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, "\x00") {
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// collectIgnored expands the ignored entries ls-files lists (directories
+// end in a slash) into the files under root to archive, leaving out each
+// entry larger than IgnoredEntryLimit.
+func collectIgnored(root string, entries []string) (files, skipped []string, err error) {
+	for _, entry := range entries {
+		var entryFiles []string
+		var size int64
+		err := filepath.WalkDir(filepath.Join(root, entry), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			entryFiles = append(entryFiles, rel)
+			if size > IgnoredEntryLimit {
+				return fs.SkipAll
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if size > IgnoredEntryLimit {
+			skipped = append(skipped, entry)
+			continue
+		}
+		files = append(files, entryFiles...)
+	}
+	return files, skipped, nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// Archiver returns a worktree.ArchiveWorktrees callback that archives into
+// commonDir and reports the new archive's ID as the step message.
+func Archiver(runner git.CommandRunner, commonDir string) func(git.Worktree) (string, error) {
+	return func(wt git.Worktree) (string, error) {
+		a, err := Create(runner, commonDir, wt, time.Now())
+		if err != nil {
+			return "", err
+		}
+		return "Archived as " + a.ID, nil
+	}
+}
@@ -0,0 +1,292 @@
+package trash
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/git"
+)
+
+// setupRepo creates a repo with one commit and a worktree on branch feature,
+// returning the repo dir, its git common dir and the worktree path.
+func setupRepo(t *testing.T) (repoDir, commonDir, wtPath string) {
+	t.Helper()
+	base := t.TempDir()
+	repoDir = filepath.Join(base, "repo")
+	mustGit(t, base, "init", "--initial-branch=main", repoDir)
+	mustWrite(t, filepath.Join(repoDir, "tracked.txt"), "v1\n")
+	mustGit(t, repoDir, "add", ".")
+	mustGit(t, repoDir, "commit", "-m", "initial")
+
+	wtPath = filepath.Join(base, "feature")
+	mustGit(t, repoDir, "worktree", "add", "-b", "feature", wtPath)
+	return repoDir, filepath.Join(repoDir, ".git"), wtPath
+}
+
+func mustGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustRead(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCreateAndRestore_RoundTripsChangesAndUntrackedFiles(t *testing.T) {
+	repoDir, commonDir, wtPath := setupRepo(t)
+	runner := &git.GitRunner{}
+
+	mustWrite(t, filepath.Join(wtPath, "tracked.txt"), "v2\n")
+	mustWrite(t, filepath.Join(wtPath, "notes", "todo.txt"), "untracked\n")
+	head := mustGit(t, wtPath, "rev-parse", "HEAD")
+
+	a, err := Create(runner, commonDir, git.Worktree{Path: wtPath, Branch: "refs/heads/feature", HEAD: head},
+		time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if a.ID != "feature/20261018T120000Z" {
+		t.Errorf("ID = %q, want feature/20261018T120000Z", a.ID)
+	}
+	if !a.HasChanges() {
+		t.Error("archive should carry the uncommitted change")
+	}
+	if a.Untracked != 1 {
+		t.Errorf("Untracked = %d, want 1", a.Untracked)
+	}
+	if got := mustGit(t, repoDir, "rev-parse", a.Ref); got != a.Snapshot {
+		t.Errorf("%s = %s, want snapshot %s", a.Ref, got, a.Snapshot)
+	}
+	if got := mustRead(t, filepath.Join(wtPath, "tracked.txt")); got != "v2\n" {
+		t.Errorf("Create must leave the worktree untouched, tracked.txt = %q", got)
+	}
+
+	// Remove the worktree and its branch, as remove plus cleanup would.
+	mustGit(t, repoDir, "worktree", "remove", "--force", wtPath)
+	mustGit(t, repoDir, "branch", "-D", "feature")
+
+	if _, err := Restore(runner, repoDir, commonDir, a, wtPath); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := mustRead(t, filepath.Join(wtPath, "tracked.txt")); got != "v2\n" {
+		t.Errorf("restored tracked.txt = %q, want v2", got)
+	}
+	if got := mustRead(t, filepath.Join(wtPath, "notes", "todo.txt")); got != "untracked\n" {
+		t.Errorf("restored notes/todo.txt = %q", got)
+	}
+	if got := mustGit(t, wtPath, "branch", "--show-current"); got != "feature" {
+		t.Errorf("restored branch = %q, want feature", got)
+	}
+
+	archives, err := List(commonDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archives) != 0 {
+		t.Errorf("restore should drop the archive, %d left", len(archives))
+	}
+	if _, err := runner.Run(repoDir, "rev-parse", "--verify", "--quiet", a.Ref); err == nil {
+		t.Errorf("restore should delete %s", a.Ref)
+	}
+}
+
+func TestCreateAndRestore_KeepsIgnoredFilesUnderTheCap(t *testing.T) {
+	repoDir, commonDir, wtPath := setupRepo(t)
+	runner := &git.GitRunner{}
+
+	mustWrite(t, filepath.Join(wtPath, ".gitignore"), ".env\nbuild/\n")
+	mustWrite(t, filepath.Join(wtPath, ".env"), "TOKEN=secret\n")
+	mustWrite(t, filepath.Join(wtPath, " spaced .txt"), "kept as named\n")
+	big := filepath.Join(wtPath, "build", "bundle.bin")
+	mustWrite(t, big, "")
+	if err := os.Truncate(big, IgnoredEntryLimit+1); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := Create(runner, commonDir, git.Worktree{Path: wtPath, Branch: "refs/heads/feature", HEAD: mustGit(t, wtPath, "rev-parse", "HEAD")},
+		time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if a.Untracked != 3 {
+		t.Errorf("Untracked = %d, want 3 (.gitignore, .env, \" spaced .txt\")", a.Untracked)
+	}
+	if len(a.SkippedIgnored) != 1 || a.SkippedIgnored[0] != "build/" {
+		t.Errorf("SkippedIgnored = %q, want [build/]", a.SkippedIgnored)
+	}
+
+	mustGit(t, repoDir, "worktree", "remove", "--force", wtPath)
+	mustGit(t, repoDir, "branch", "-D", "feature")
+	if _, err := Restore(runner, repoDir, commonDir, a, wtPath); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := mustRead(t, filepath.Join(wtPath, ".env")); got != "TOKEN=secret\n" {
+		t.Errorf("restored .env = %q", got)
+	}
+	if got := mustRead(t, filepath.Join(wtPath, " spaced .txt")); got != "kept as named\n" {
+		t.Errorf("restored \" spaced .txt\" = %q", got)
+	}
+	if _, err := os.Stat(big); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("oversized ignored file restored: %v", err)
+	}
+}
+
+func TestCreate_CleanWorktreeSnapshotsHead(t *testing.T) {
+	_, commonDir, wtPath := setupRepo(t)
+	head := mustGit(t, wtPath, "rev-parse", "HEAD")
+
+	a, err := Create(&git.GitRunner{}, commonDir, git.Worktree{Path: wtPath, Branch: "refs/heads/feature"}, time.Now())
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if a.Head != head || a.Snapshot != head {
+		t.Errorf("Head/Snapshot = %s/%s, want both %s", a.Head, a.Snapshot, head)
+	}
+	if a.HasChanges() || a.Untracked != 0 {
+		t.Errorf("clean worktree archive has changes=%v untracked=%d", a.HasChanges(), a.Untracked)
+	}
+}
+
+func TestCreate_SameSecondGetsUniqueID(t *testing.T) {
+	_, commonDir, wtPath := setupRepo(t)
+	now := time.Now()
+	wt := git.Worktree{Path: wtPath, Branch: "refs/heads/feature"}
+
+	first, err := Create(&git.GitRunner{}, commonDir, wt, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Create(&git.GitRunner{}, commonDir, wt, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID == second.ID {
+		t.Fatalf("both archives got ID %q", first.ID)
+	}
+}
+
+func TestRestore_RefusesExistingPath(t *testing.T) {
+	repoDir, commonDir, wtPath := setupRepo(t)
+	runner := &git.GitRunner{}
+	a, err := Create(runner, commonDir, git.Worktree{Path: wtPath, Branch: "refs/heads/feature"}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Restore(runner, repoDir, commonDir, a, wtPath); err == nil {
+		t.Fatal("Restore() onto an existing directory should fail")
+	}
+	if _, err := Find(commonDir, a.ID); err != nil {
+		t.Errorf("a failed restore must keep the archive: %v", err)
+	}
+}
+
+func TestRestore_MovedBranchDetachesAndKeepsTheArchive(t *testing.T) {
+	repoDir, commonDir, wtPath := setupRepo(t)
+	runner := &git.GitRunner{}
+
+	// An unpushed commit only the archive will hold once the branch moves.
+	mustWrite(t, filepath.Join(wtPath, "tracked.txt"), "unpushed\n")
+	mustGit(t, wtPath, "commit", "-am", "unpushed")
+	head := mustGit(t, wtPath, "rev-parse", "HEAD")
+	a, err := Create(runner, commonDir, git.Worktree{Path: wtPath, Branch: "refs/heads/feature", HEAD: head}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustGit(t, repoDir, "worktree", "remove", "--force", wtPath)
+	mustGit(t, repoDir, "branch", "-f", "feature", "main")
+
+	restored, err := Restore(runner, repoDir, commonDir, a, wtPath)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if !restored.Detached || !restored.Kept {
+		t.Errorf("Restore() = %+v, want detached and kept", restored)
+	}
+	if got := mustGit(t, wtPath, "rev-parse", "HEAD"); got != head {
+		t.Errorf("restored HEAD = %s, want archived head %s", got, head)
+	}
+	if got := mustGit(t, repoDir, "rev-parse", "refs/heads/feature"); got == head {
+		t.Error("restore must not move the branch")
+	}
+	if _, err := Find(commonDir, a.ID); err != nil {
+		t.Errorf("the archive must be kept while no branch reaches its head: %v", err)
+	}
+
+	// Once a branch holds the commits again, a later restore may drop it.
+	mustGit(t, repoDir, "worktree", "remove", "--force", wtPath)
+	mustGit(t, repoDir, "branch", "rescued", head)
+	if restored, err = Restore(runner, repoDir, commonDir, a, wtPath); err != nil {
+		t.Fatalf("second Restore() error = %v", err)
+	}
+	if restored.Kept {
+		t.Error("an archive whose head is on a branch should be dropped")
+	}
+}
+
+func TestFind_UnknownID(t *testing.T) {
+	_, commonDir, _ := setupRepo(t)
+	if _, err := Find(commonDir, "nope/20260101T000000Z"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestExpired_OnlyReturnsArchivesPastRetention(t *testing.T) {
+	_, commonDir, wtPath := setupRepo(t)
+	runner := &git.GitRunner{}
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	wt := git.Worktree{Path: wtPath, Branch: "refs/heads/feature"}
+
+	old, err := Create(runner, commonDir, wt, now.Add(-40*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Create(runner, commonDir, wt, now.Add(-24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := Expired(commonDir, 30*24*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 1 || expired[0].ID != old.ID {
+		t.Fatalf("Expired() = %+v, want only %s", expired, old.ID)
+	}
+}
+
+func TestExtractTarball_RejectsEscapingEntries(t *testing.T) {
+	src := t.TempDir()
+	mustWrite(t, filepath.Join(src, "evil.txt"), "x\n")
+	tarball := filepath.Join(t.TempDir(), "a.tar.gz")
+	if err := writeTarball(tarball, src, []string{"../" + filepath.Base(src) + "/evil.txt"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := extractTarball(tarball, t.TempDir()); err == nil {
+		t.Fatal("extractTarball() should refuse an entry outside the root")
+	}
+}
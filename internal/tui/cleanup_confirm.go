@@ -54,7 +54,8 @@ func (m Model) updateCleanupConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 		opts := m.resolvedCleanupOpts()
 		m.view = cleanupResultView
 		m.cleanupResult = nil
-		return m, runCleanupWithOpts(m.runner, m.repoPath, opts)
+		opts.ArchiveRetention = m.archiveRetention()
+		return m, runCleanupWithOpts(m.runner, m.repoPath, opts, m.protectedPatterns())
 
 	case ConfirmBackMsg:
 		if m.cleanupOpts != nil {
@@ -76,9 +77,13 @@ func (m Model) viewCleanupConfirm() string {
 	return m.cleanupConfirmationVM().View()
 }
 
-// runCleanupWithOpts runs cleanup with the given options.
-func runCleanupWithOpts(runner git.CommandRunner, repoPath string, opts cleanup.Options) tea.Cmd {
+// runCleanupWithOpts runs cleanup with the given options. The protection
+// policy is resolved inside the command: default-branch detection runs git.
+func runCleanupWithOpts(runner git.CommandRunner, repoPath string, opts cleanup.Options, protectedPatterns []string) tea.Cmd {
 	return func() tea.Msg {
+		if opts.Protection == nil {
+			opts.Protection = git.DetectProtectionPolicy(runner, repoPath, protectedPatterns)
+		}
 		result := cleanup.Run(runner, repoPath, opts, func(_ cleanup.Event) {})
 		return standaloneCleanupDoneMsg{result: result}
 	}
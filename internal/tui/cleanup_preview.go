@@ -9,6 +9,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 
 	"github.com/abiswas97/sentei/internal/cleanup"
+	"github.com/abiswas97/sentei/internal/git"
 )
 
 // inlineBranchPreview is how many aggressive branch names the preview shows
@@ -32,12 +33,12 @@ func (m Model) startCleanupScan() (Model, tea.Cmd) {
 	m.cleanupAggressiveConfirm = false
 	m.progressStartedAt = time.Now()
 	m.progressToken++
-	runner, repoPath := m.runner, m.repoPath
+	runner, repoPath, patterns, retention := m.runner, m.repoPath, m.protectedPatterns(), m.archiveRetention()
 	// No explicit spinner tick: the dispatch wrapper starts the chain on
 	// the transition into this working state; a second start here would
 	// double the frame rate.
 	return m, func() tea.Msg {
-		result, err := cleanup.DryRun(runner, repoPath)
+		result, err := cleanup.DryRun(runner, repoPath, git.DetectProtectionPolicy(runner, repoPath, patterns), retention)
 		return cleanupScanDoneMsg{result: result, err: err}
 	}
 }
@@ -114,7 +115,7 @@ func (m Model) startCleanupRun(mode cleanup.Mode) (tea.Model, tea.Cmd) {
 	m.view = cleanupResultView
 	m.cleanupResult = nil
 	m.cleanupRanMode = mode
-	return m, runCleanupWithOpts(m.runner, m.repoPath, cleanup.Options{Mode: mode})
+	return m, runCleanupWithOpts(m.runner, m.repoPath, cleanup.Options{Mode: mode, ArchiveRetention: m.archiveRetention()}, m.protectedPatterns())
 }
 
 func (m Model) viewCleanupPreview() string {
@@ -169,6 +170,9 @@ func (m Model) viewCleanupPreview() string {
 	writePreviewLine(&b, scan.ConfigDuplicates, "config %s would be removed", "duplicate", "duplicates", "No config duplicates")
 	writePreviewLine(&b, scan.OrphanedConfigs, "orphaned config %s would be removed", "section", "sections", "No orphaned config sections")
 	writePreviewLine(&b, scan.PrunableWorktrees, "stale %s would be pruned", "worktree", "worktrees", "No stale worktrees")
+	if scan.ExpiredArchives > 0 {
+		writePreviewLine(&b, scan.ExpiredArchives, "expired worktree %s would be purged", "archive", "archives", "")
+	}
 	b.WriteString("\n")
 
 	if scan.AggressiveHasWork() {
@@ -53,7 +53,7 @@ func (m Model) viewCleanupResult() string {
 	// Check if anything was actually done
 	totalActions := r.StaleRefsRemoved + r.ConfigDedupResult.Removed +
 		r.GoneBranchesDeleted + r.ConfigOrphanResult.Removed +
-		r.NonWtBranchesDeleted + r.WorktreesPruned
+		r.NonWtBranchesDeleted + r.WorktreesPruned + r.ArchivesPurged
 
 	switch {
 	case len(r.Errors) == 0 && totalActions == 0 && len(r.BranchesSkipped) > 0:
@@ -125,6 +125,15 @@ func (m Model) viewCleanupResult() string {
 			styleIndicatorPending.Render(indicatorPending))
 	}
 
+	// Purged archives: only mentioned when there were any, since archiving
+	// is opt-in.
+	if r.ArchivesPurged > 0 {
+		fmt.Fprintf(&b, "  %s Purged %d expired worktree %s\n",
+			styleIndicatorDone.Render(indicatorDone),
+			r.ArchivesPurged,
+			pluralize(r.ArchivesPurged, "archive", "archives"))
+	}
+
 	// Skipped branches: a confirmed aggressive run must never look like a
 	// silent success when the engine skipped unmerged branches.
 	if len(r.BranchesSkipped) > 0 {
@@ -133,7 +142,11 @@ func (m Model) viewCleanupResult() string {
 			len(r.BranchesSkipped),
 			pluralize(len(r.BranchesSkipped), "branch", "branches"))
 		for _, s := range r.BranchesSkipped {
-			fmt.Fprintf(&b, "      %s\n", styleDim.Render(truncateWithEllipsis(s.Name, max(m.width-8, 20))))
+			name := s.Name
+			if s.Reason == cleanup.SkipSquashMerged {
+				name += " (" + string(s.Reason) + ")"
+			}
+			fmt.Fprintf(&b, "      %s\n", styleDim.Render(truncateWithEllipsis(name, max(m.width-8, 20))))
 		}
 	}
 
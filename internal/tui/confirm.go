@@ -16,7 +16,9 @@ import (
 	"github.com/abiswas97/sentei/internal/creator"
 	"github.com/abiswas97/sentei/internal/git"
 	"github.com/abiswas97/sentei/internal/integration"
+	"github.com/abiswas97/sentei/internal/journal"
 	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/trash"
 	"github.com/abiswas97/sentei/internal/worktree"
 )
 
@@ -38,6 +40,11 @@ func (m Model) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyPressMsg:
 		switch {
 		case key.Matches(msg, keys.Yes):
+			// Whether a worktree needs archiving depends on its status;
+			// wait for the rows still being checked.
+			if m.selectionPending() {
+				return m, nil
+			}
 			return m.beginRemoval()
 
 		case key.Matches(msg, keys.No), key.Matches(msg, keys.Back):
@@ -59,14 +66,17 @@ func (m Model) beginRemoval() (tea.Model, tea.Cmd) {
 	selected := m.selectedWorktrees()
 	m.remove.run = newRemovalRun(selected)
 
-	integrations := integration.All()
+	integrations := m.integrations()
 	prepared, err := prepareRemoval(selected, integrations)
 	if err != nil {
 		m.remove.run.result.Err = err
 		m.view = summaryView
 		return m, nil
 	}
-	ch := make(chan progress.Event, (len(prepared.targets)+len(prepared.teardownOps)+len(prepared.unlockOps)+2)*5)
+	if m.archiveBeforeRemove() {
+		planArchives(&prepared)
+	}
+	ch := make(chan progress.Event, (len(prepared.targets)*2+len(prepared.teardownOps)+len(prepared.unlockOps)+2)*5)
 	execution, err := progress.Start(prepared.plan, func(event progress.Event) { ch <- event })
 	if err != nil {
 		close(ch)
@@ -78,7 +88,10 @@ func (m Model) beginRemoval() (tea.Model, tea.Cmd) {
 	m.remove.run.progressCh = ch
 	m.remove.run.targets = prepared.targets
 	m.remove.run.teardownOps = prepared.teardownOps
-	declarationEvents := len(prepared.unlockOps) + len(prepared.teardownOps) + len(prepared.targets) + 2 + len(prepared.plan.Phases)
+	declarationEvents := len(prepared.plan.Phases)
+	for _, phase := range prepared.plan.Phases {
+		declarationEvents += len(phase.Steps)
+	}
 	for range declarationEvents {
 		m.remove.run.events = append(m.remove.run.events, <-ch)
 	}
@@ -179,6 +192,30 @@ func prepareRemoval(worktrees []git.Worktree, integrations []integration.Integra
 	return prepared, nil
 }
 
+// planArchives adds an archive phase, just before removal, with a step for
+// every target that would lose work. Teardown has already run by then, so
+// the archive skips regenerable integration artifacts.
+func planArchives(prepared *removalPreparation) {
+	archive := progress.PlannedPhase{ID: worktree.ArchivePhaseID, Label: worktree.ArchivePhaseName}
+	for i, target := range prepared.targets {
+		if !worktree.NeedsArchive(target.Worktree) {
+			continue
+		}
+		stepID := removalSemanticStepID("archive", normalizedWorktreeIdentity(target.Worktree))
+		prepared.targets[i].ArchiveStepID = stepID
+		archive.Steps = append(archive.Steps, progress.PlannedStep{ID: stepID, Label: worktreeLabel(target.Worktree)})
+	}
+	if len(archive.Steps) == 0 {
+		return
+	}
+	for i, phase := range prepared.plan.Phases {
+		if phase.ID == worktree.RemovalPhaseID {
+			prepared.plan.Phases = append(prepared.plan.Phases[:i], append([]progress.PlannedPhase{archive}, prepared.plan.Phases[i:]...)...)
+			return
+		}
+	}
+}
+
 func normalizedWorktreeIdentity(wt git.Worktree) string {
 	return filepath.Clean(strings.TrimSpace(wt.Path)) + "\x00" + strings.TrimSpace(wt.Branch)
 }
@@ -188,6 +225,44 @@ func removalSemanticStepID(kind, identity string) progress.StepID {
 	return progress.StepID(fmt.Sprintf("%s:%x", kind, sum[:8]))
 }
 
+// selectionPending reports whether any selected worktree's status checks are
+// still running.
+func (m Model) selectionPending() bool {
+	for _, wt := range m.selectedWorktrees() {
+		if m.remove.pending[wt.Path] {
+			return true
+		}
+	}
+	return false
+}
+
+// recheckCachedSelection runs the status passes without the cache for the
+// selected worktrees whose status the cache served. The cache misses edits
+// that leave the index and root untouched; deciding on a stale "clean"
+// would skip the archive and force-remove the changes.
+func (m Model) recheckCachedSelection() (Model, tea.Cmd) {
+	var stale []git.Worktree
+	for _, wt := range m.selectedWorktrees() {
+		if m.remove.cached[wt.Path] {
+			stale = append(stale, wt)
+			m.remove.pending[wt.Path] = true
+			delete(m.remove.cached, wt.Path)
+		}
+	}
+	if len(stale) == 0 {
+		return m, nil
+	}
+	ch := make(chan worktree.Update, 2*len(stale))
+	runner := m.runner
+	go func() {
+		defer close(ch)
+		worktree.EnrichStatus(runner, stale, worktree.DefaultEnrichConcurrency, nil, func(u worktree.Update) {
+			ch <- u
+		})
+	}()
+	return m, waitForEnrichment(ch, m.worktreeGeneration)
+}
+
 // worktreeAtRisk reports whether removing wt could lose work that exists
 // nowhere else: uncommitted or untracked changes, commits not on a remote,
 // or a lock someone placed deliberately.
@@ -200,12 +275,37 @@ func worktreeAtRisk(wt git.Worktree) bool {
 func (m Model) startDeletions() (tea.Model, tea.Cmd) {
 	execution := m.remove.run.execution
 	targets := append([]worktree.RemovalTarget(nil), m.remove.run.targets...)
+	runner, repoPath := m.runner, m.repoPath
 	return m, func() tea.Msg {
+		started := time.Now()
+		commonDir, commonDirErr := git.CommonDir(runner, repoPath)
+		var archiveErr error
+		if hasArchiveSteps(targets) {
+			archiver := trash.Archiver(runner, commonDir)
+			if commonDirErr != nil {
+				archiver = func(git.Worktree) (string, error) { return "", commonDirErr }
+			}
+			targets, archiveErr = worktree.ArchiveWorktrees(execution, worktree.ArchivePhaseID, worktree.RemovalPhaseID, archiver, targets)
+		}
 		result := worktree.DeleteWorktrees(execution, worktree.RemovalPhaseID, os.RemoveAll, targets, 5)
-		return deletionsCompleteMsg{Result: result}
+		result.Err = errors.Join(archiveErr, result.Err)
+		var journalErr error
+		if commonDirErr == nil {
+			_, journalErr = journal.RecordRemovals(commonDir, result.Removed(targets), started, time.Now())
+		}
+		return deletionsCompleteMsg{Result: result, JournalErr: journalErr}
 	}
 }
 
+func hasArchiveSteps(targets []worktree.RemovalTarget) bool {
+	for _, target := range targets {
+		if target.ArchiveStepID != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func findIntegrationByName(integrations []integration.Integration, name string) *integration.Integration {
 	for i := range integrations {
 		if integrations[i].Name == name {
@@ -308,7 +408,8 @@ func (m Model) viewConfirm() string {
 	}
 	nameWidth = min(nameWidth, confirmNameWidthCap)
 
-	var dirtyCount, untrackedCount, lockedCount, unpushedCount int
+	archive := m.archiveBeforeRemove()
+	var dirtyCount, untrackedCount, lockedCount, unpushedCount, archivedCount int
 	for _, wt := range selected {
 		var badge, note string
 		risky := true
@@ -325,9 +426,18 @@ func (m Model) viewConfirm() string {
 		case wt.HasUnpushedCommits:
 			badge, note = "[^]", "commits not on any remote"
 			unpushedCount++
+		case m.remove.pending[wt.Path]:
+			badge, note = "[…]", "checking for untracked files…"
 		default:
 			badge, risky = "[ok]", false
 		}
+		if archive && worktree.NeedsArchive(wt) {
+			note = "archived first — restorable"
+			archivedCount++
+			if wt.IsLocked {
+				note = "locked — will force-remove, archived first"
+			}
+		}
 
 		badgeStyle := styleStatusClean
 		if risky {
@@ -346,7 +456,7 @@ func (m Model) viewConfirm() string {
 	b.WriteString("\n")
 
 	// Integration teardown info
-	integrations := integration.All()
+	integrations := m.integrations()
 	dirCounts := make(map[string]int)
 	for _, wt := range selected {
 		artifacts := creator.ScanArtifacts(wt.Path, integrations)
@@ -364,6 +474,11 @@ func (m Model) viewConfirm() string {
 		b.WriteString("\n")
 	}
 
+	if archivedCount > 0 {
+		fmt.Fprintf(&b, "  %d %s will be archived first; bring %s back with `sentei restore`\n",
+			archivedCount, pluralize(archivedCount, "worktree", "worktrees"), pluralize(archivedCount, "it", "them"))
+		dirtyCount, untrackedCount, unpushedCount = 0, 0, 0
+	}
 	if dirtyCount > 0 {
 		b.WriteString(styleWarning.Render(
 			fmt.Sprintf("  ⚠ %d %s with uncommitted changes that will be lost", dirtyCount, pluralize(dirtyCount, "worktree", "worktrees")),
@@ -389,6 +504,11 @@ func (m Model) viewConfirm() string {
 		b.WriteString("\n")
 	}
 
+	if m.selectionPending() {
+		b.WriteString(styleDim.Render("  Waiting for status checks before deleting…"))
+		b.WriteString("\n")
+	}
+
 	b.WriteString("\n")
 	b.WriteString(viewSeparator(m.width))
 	b.WriteString("\n\n")
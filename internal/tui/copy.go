@@ -24,11 +24,22 @@ const (
 	titleMigrate           = "Migrate to bare repository"
 	titleConfirmMigration  = "Confirm migration"
 	titleMigrationComplete = "Migration complete"
+	titleUnmigrate         = "Convert to regular repository"
+	titleConfirmUnmigrate  = "Confirm conversion"
+	titleUnmigratingRepo   = "Converting repository"
+	titleUnmigrateComplete = "Conversion complete"
+	titleMoveWorktree      = "Move worktree"
+	titleMovingWorktree    = "Moving worktree"
+	titleLockWorktree      = "Lock worktree"
+	titleUnlockWorktree    = "Unlock worktree"
 	titleIntegrations      = "Integrations"
 	titleSetUpIntegrations = "Set up integrations"
 	titleApplyingChanges   = "Applying integration changes"
 	titleApplyComplete     = "Apply complete"
 	titleApplyErrors       = "Apply finished with errors"
+	titleRefreshing        = "Refreshing integrations"
+	titleRefreshComplete   = "Refresh complete"
+	titleRefreshErrors     = "Refresh finished with errors"
 	titleCleanupPreview    = "Cleanup preview"
 	titleConfirmCleanup    = "Confirm cleanup"
 	titleRunningCleanup    = "Running cleanup"
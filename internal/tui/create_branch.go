@@ -1,16 +1,21 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/abiswas97/sentei/internal/creator"
 	"github.com/abiswas97/sentei/internal/ecosystem"
 	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/repo"
 	"github.com/abiswas97/sentei/internal/state"
 )
 
@@ -18,13 +23,53 @@ import (
 // reset on menu entry restores it.
 const defaultBaseBranch = "main"
 
+// createSource is what the create-branch view's second field names: a local
+// base branch, a remote branch to track, or a pull request.
+type createSource int
+
+const (
+	createFromBase createSource = iota
+	createFromRemote
+	createFromPR
+)
+
+func (s createSource) label() string {
+	switch s {
+	case createFromRemote:
+		return "Remote branch"
+	case createFromPR:
+		return "Pull request"
+	}
+	return "Base branch"
+}
+
+func (s createSource) placeholder() string {
+	switch s {
+	case createFromRemote:
+		return "origin/feature-x"
+	case createFromPR:
+		return "123"
+	}
+	return defaultBaseBranch
+}
+
+// setCreateSource switches the second field to source, discarding anything
+// resolved for the previous one.
+func (m *Model) setCreateSource(source createSource, value string) {
+	m.create.source = source
+	m.create.remoteSource = nil
+	m.create.baseInput.Placeholder = source.placeholder()
+	m.create.baseInput.SetValue(value)
+}
+
 // withCreateFlowReset restores the create flow to its construction defaults
 // so a menu entry never inherits inputs, toggles, events, or results from a
 // previous run (completed or abandoned).
 func (m Model) withCreateFlowReset() Model {
 	m.create.branchInput.SetValue("")
 	m.create.branchInput.Focus()
-	m.create.baseInput.SetValue(defaultBaseBranch)
+	m.setCreateSource(createFromBase, defaultBaseBranch)
+	m.create.resolving = false
 	m.create.baseInput.Blur()
 	m.create.focusedField = 0
 	m.create.validationErr = ""
@@ -32,6 +77,8 @@ func (m Model) withCreateFlowReset() Model {
 	m.create.mergeBase = true
 	m.create.copyEnvFiles = true
 	m.create.optionsCursor = 0
+	m.create.profile = ""
+	m.create.integrations = nil
 	m.create.events = nil
 	m.create.result = nil
 	return m
@@ -79,44 +126,129 @@ func (m Model) updateCreateBranch(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.create.branchInput.CursorEnd()
 			return m, m.create.branchInput.Focus()
 
-		case key.Matches(msg, keys.QuickCreate):
-			branch := m.create.branchInput.Value()
-			var existingPaths []string
-			for _, wt := range m.remove.worktrees {
-				existingPaths = append(existingPaths, wt.Path)
+		case key.Matches(msg, keys.Source):
+			next := (m.create.source + 1) % 3
+			value := ""
+			if next == createFromBase {
+				value = defaultBaseBranch
 			}
-			if err := validateBranchName(branch, existingPaths); err != nil {
-				m.create.validationErr = err.message
-				return m, nil
-			}
-
+			m.setCreateSource(next, value)
 			m.create.validationErr = ""
-			m.prepareCreateOptions()
-			m.startCreation()
-			m.progressStartedAt = time.Now()
-			m.progressToken++
-			m.view = createProgressView
-			return m, m.waitForCreateEvent()
+			return m, nil
+
+		case key.Matches(msg, keys.QuickCreate):
+			return m.submitCreateBranch(true)
 
 		case key.Matches(msg, keys.Confirm):
-			branch := m.create.branchInput.Value()
-			var existingPaths []string
-			for _, wt := range m.remove.worktrees {
-				existingPaths = append(existingPaths, wt.Path)
-			}
-			if err := validateBranchName(branch, existingPaths); err != nil {
-				m.create.validationErr = err.message
-				return m, nil
-			}
+			return m.submitCreateBranch(false)
+		}
 
-			m.create.validationErr = ""
-			m.prepareCreateOptions()
-			m.view = createOptionsView
+	}
+	if msg, ok := msg.(createSourceResolvedMsg); ok {
+		return m.applyResolvedSource(msg)
+	}
+	return m.updateCreateBranchInput(msg)
+}
+
+// submitCreateBranch validates the form and moves on to the options view,
+// or straight to creation when quick. A remote branch or pull request is
+// resolved first, off the update loop, since gh goes over the network.
+func (m Model) submitCreateBranch(quick bool) (tea.Model, tea.Cmd) {
+	if m.create.source != createFromBase && m.create.remoteSource == nil {
+		if m.create.resolving {
 			return m, nil
 		}
+		m.create.resolving = true
+		m.create.validationErr = ""
+		return m, m.resolveCreateSource(quick)
+	}
+	if err := validateBranchName(m.create.branchInput.Value(), m.existingWorktreePaths()); err != nil {
+		m.create.validationErr = err.message
+		return m, nil
+	}
+	m.create.validationErr = ""
+	m.prepareCreateOptions()
+	if !quick {
+		m.view = createOptionsView
+		return m, nil
+	}
+	m.startCreation()
+	m.progressStartedAt = time.Now()
+	m.progressToken++
+	m.view = createProgressView
+	return m, m.waitForCreateEvent()
+}
 
+func (m Model) existingWorktreePaths() []string {
+	var paths []string
+	for _, wt := range m.remove.worktrees {
+		paths = append(paths, wt.Path)
+	}
+	return paths
+}
+
+type createSourceResolvedMsg struct {
+	source *creator.RemoteSource
+	err    error
+	quick  bool // continue straight to creation
+}
+
+func (m Model) resolveCreateSource(quick bool) tea.Cmd {
+	runner, gh, repoPath := m.runner, m.gh, m.repoPath
+	kind, spec := m.create.source, strings.TrimSpace(m.create.baseInput.Value())
+	return func() tea.Msg {
+		source, err := resolveCreateSpec(runner, gh, repoPath, kind, spec)
+		return createSourceResolvedMsg{source: source, err: err, quick: quick}
 	}
-	return m.updateCreateBranchInput(msg)
+}
+
+func resolveCreateSpec(runner git.CommandRunner, gh repo.GhRunner, repoPath string, kind createSource, spec string) (*creator.RemoteSource, error) {
+	if kind == createFromRemote {
+		if spec == "" {
+			return nil, errors.New("remote branch is required")
+		}
+		return creator.ResolveSource(runner, gh, repoPath, spec, 0)
+	}
+	number, err := strconv.Atoi(strings.TrimPrefix(spec, "#"))
+	if err != nil || number <= 0 {
+		return nil, errors.New("pull request number is required")
+	}
+	if gh == nil {
+		return nil, errors.New("pull requests need gh")
+	}
+	return creator.ResolveSource(runner, gh, repoPath, "", number)
+}
+
+// applyResolvedSource records a resolved remote source, naming the branch
+// after it when the branch field is empty, and resumes the submission. A
+// failure returns to the form with the reason.
+func (m Model) applyResolvedSource(msg createSourceResolvedMsg) (tea.Model, tea.Cmd) {
+	m.create.resolving = false
+	if msg.err != nil {
+		m.create.validationErr = msg.err.Error()
+		m.view = createBranchView
+		return m, nil
+	}
+	m.create.remoteSource = msg.source
+	if strings.TrimSpace(m.create.branchInput.Value()) == "" {
+		m.create.branchInput.SetValue(msg.source.Branch)
+	}
+	if m.view == createConfirmView {
+		m.startCreation()
+		m.progressStartedAt = time.Now()
+		m.progressToken++
+		m.view = createProgressView
+		return m, m.waitForCreateEvent()
+	}
+	return m.submitCreateBranch(msg.quick)
+}
+
+// createFromLabel names what the new branch starts from.
+func (m Model) createFromLabel() string {
+	if m.create.remoteSource != nil {
+		return m.create.remoteSource.Label
+	}
+	return m.create.baseInput.Value()
 }
 
 func (m Model) updateCreateBranchInput(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -125,7 +257,11 @@ func (m Model) updateCreateBranchInput(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.create.focusedField == 0 {
 		m.create.branchInput, cmd = m.create.branchInput.Update(msg)
 	} else {
+		before := m.create.baseInput.Value()
 		m.create.baseInput, cmd = m.create.baseInput.Update(msg)
+		if m.create.baseInput.Value() != before {
+			m.create.remoteSource = nil
+		}
 	}
 	return m, cmd
 }
@@ -143,7 +279,9 @@ func (m *Model) prepareCreateOptions() {
 		m.create.ecosystems = nil
 		for _, eco := range detected {
 			m.create.ecosystems = append(m.create.ecosystems, eco.Config)
-			m.create.ecoEnabled[eco.Name] = true
+			// --ecosystems (or the profile's) narrows what is installed.
+			m.create.ecoEnabled[eco.Name] = m.createOpts == nil || m.createOpts.Ecosystems == nil ||
+				slices.Contains(m.createOpts.Ecosystems, eco.Name)
 		}
 	}
 
@@ -200,9 +338,19 @@ func (m Model) viewCreateBranch() string {
 	}
 	b.WriteString("\n")
 
-	b.WriteString(inputFieldLabel("Base branch", m.create.focusedField == 1))
+	// The footer is full at 80 columns, so the source switch is hinted
+	// beside the field it changes.
+	sourceHelp := keys.Source.Help()
+	b.WriteString(strings.TrimSuffix(inputFieldLabel(m.create.source.label(), m.create.focusedField == 1), "\n"))
+	b.WriteString(styleDim.Render("  " + sourceHelp.Key + " " + sourceHelp.Desc))
+	b.WriteString("\n")
 	b.WriteString("  " + m.create.baseInput.View())
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	if m.create.resolving {
+		b.WriteString("  " + styleDim.Render("resolving "+strings.ToLower(m.create.source.label())+"…"))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
 
 	b.WriteString(viewSeparator(m.width))
 	b.WriteString("\n\n")
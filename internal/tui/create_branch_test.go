@@ -9,6 +9,7 @@ import (
 	"github.com/abiswas97/sentei/internal/config"
 	"github.com/abiswas97/sentei/internal/git"
 	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/testutil/mock"
 )
 
 func createBranchModel() Model {
@@ -177,3 +178,85 @@ func TestViewCreateBranch_RendersChromeFieldsAndError(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateCreateBranch_CtrlTCyclesSource(t *testing.T) {
+	m := createBranchModel()
+	ctrlT := tea.KeyPressMsg{Code: 't', Mod: tea.ModCtrl}
+
+	want := []struct {
+		source createSource
+		label  string
+		value  string
+	}{
+		{createFromRemote, "Remote branch", ""},
+		{createFromPR, "Pull request", ""},
+		{createFromBase, "Base branch", defaultBaseBranch},
+	}
+	for _, w := range want {
+		updated, _ := m.updateCreateBranch(ctrlT)
+		m = updated.(Model)
+		if m.create.source != w.source || m.create.baseInput.Value() != w.value {
+			t.Fatalf("source = %d with value %q, want %d with %q", m.create.source, m.create.baseInput.Value(), w.source, w.value)
+		}
+		if view := stripANSI(m.viewCreateBranch()); !strings.Contains(view, w.label) {
+			t.Errorf("view missing %q:\n%s", w.label, view)
+		}
+	}
+}
+
+func TestUpdateCreateBranch_RemoteSourceResolvesAndNamesBranch(t *testing.T) {
+	m := createBranchModel()
+	m.runner.(*mock.Runner).Responses["/repo:[remote]"] = mock.Response{Output: "origin"}
+	m.setCreateSource(createFromRemote, "origin/feature-x")
+
+	updated, cmd := m.updateCreateBranch(tea.KeyPressMsg{Code: tea.KeyEnter})
+	model := updated.(Model)
+	if !model.create.resolving || cmd == nil {
+		t.Fatal("enter should start resolving the remote branch")
+	}
+	updated, _ = model.updateCreateBranch(cmd())
+	model = updated.(Model)
+
+	if model.view != createOptionsView {
+		t.Fatalf("view = %d, want options (err %q)", model.view, model.create.validationErr)
+	}
+	if got := model.create.branchInput.Value(); got != "feature-x" {
+		t.Errorf("branch = %q, want it named after the remote branch", got)
+	}
+	for _, item := range model.buildOptionItems() {
+		if item.key == "merge" {
+			t.Error("a tracked remote branch should not offer merging the base")
+		}
+	}
+	if got := model.createFromLabel(); got != "origin/feature-x" {
+		t.Errorf("from label = %q", got)
+	}
+}
+
+func TestUpdateCreateBranch_PullRequestErrorsStayOnForm(t *testing.T) {
+	cases := []struct {
+		name  string
+		value string
+		gh    bool
+		want  string
+	}{
+		{"not a number", "abc", true, "pull request number is required"},
+		{"gh unavailable", "42", false, "pull requests need gh"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := createBranchModel()
+			if tc.gh {
+				m.gh = m.runner.(*mock.Runner)
+			}
+			m.setCreateSource(createFromPR, tc.value)
+
+			updated, cmd := m.updateCreateBranch(tea.KeyPressMsg{Code: tea.KeyEnter})
+			updated, _ = updated.(Model).updateCreateBranch(cmd())
+			model := updated.(Model)
+			if model.view != createBranchView || model.create.validationErr != tc.want {
+				t.Errorf("view %d, error %q; want the form with %q", model.view, model.create.validationErr, tc.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,16 +11,24 @@ import (
 )
 
 // SetCreateOpts sets the create options and starts at the appropriate view.
-// If branch and base are both set, starts at createConfirmView.
+// If branch and base are both set, or a remote branch or pull request is,
+// starts at createConfirmView.
 // If only branch is set, starts at createOptionsView (base selection done).
 // If nothing is set, starts at createBranchView (normal flow).
 func (m *Model) SetCreateOpts(opts *CreateOpts) {
 	m.createOpts = opts
+	m.create.profile = opts.Profile
+	m.create.integrations = opts.Integrations
 
 	if opts.Branch != "" {
 		m.create.branchInput.SetValue(opts.Branch)
 	}
-	if opts.Base != "" {
+	switch {
+	case opts.Track != "":
+		m.setCreateSource(createFromRemote, opts.Track)
+	case opts.PR != 0:
+		m.setCreateSource(createFromPR, strconv.Itoa(opts.PR))
+	case opts.Base != "":
 		m.create.baseInput.SetValue(opts.Base)
 	}
 	if opts.MergeBase {
@@ -28,6 +37,12 @@ func (m *Model) SetCreateOpts(opts *CreateOpts) {
 	if opts.CopyEnv {
 		m.create.copyEnvFiles = true
 	}
+	// With a profile the options are settled as the command line's would
+	// be, rather than on top of the TUI's defaults.
+	if opts.Profile != "" {
+		m.create.mergeBase = opts.MergeBase
+		m.create.copyEnvFiles = opts.CopyEnv
+	}
 	if len(opts.Ecosystems) > 0 {
 		for _, eco := range opts.Ecosystems {
 			m.create.ecoEnabled[eco] = true
@@ -35,7 +50,8 @@ func (m *Model) SetCreateOpts(opts *CreateOpts) {
 	}
 
 	switch {
-	case opts.Branch != "" && opts.Base != "":
+	case opts.Branch != "" && opts.Base != "", opts.Track != "", opts.PR != 0:
+		m.prepareCreateOptions()
 		m.view = createConfirmView
 	case opts.Branch != "":
 		m.prepareCreateOptions()
@@ -48,11 +64,28 @@ func (m *Model) SetCreateOpts(opts *CreateOpts) {
 // createConfirmationVM builds the ConfirmationViewModel for the create flow.
 func (m Model) createConfirmationVM() ConfirmationViewModel {
 	branch := m.create.branchInput.Value()
-	base := m.create.baseInput.Value()
+	base := strings.TrimSpace(m.create.baseInput.Value())
 
+	shownBranch := branch
+	if shownBranch == "" && m.create.source != createFromBase {
+		shownBranch = "(named after the " + strings.ToLower(m.create.source.label()) + ")"
+	}
 	items := []ConfirmationItem{
-		{Label: "Branch:", Value: branch},
-		{Label: "Base:", Value: base},
+		{Label: "Branch:", Value: shownBranch},
+		{Label: "Base:", Value: m.createFromLabel()},
+	}
+	switch m.create.source {
+	case createFromRemote:
+		items[1].Label = "Track:"
+	case createFromPR:
+		items[1].Label = "Pull request:"
+		if m.create.remoteSource == nil {
+			items[1].Value = "#" + strings.TrimPrefix(base, "#")
+		}
+	}
+
+	if m.create.profile != "" {
+		items = append(items, ConfirmationItem{Label: "Profile:", Value: m.create.profile})
 	}
 
 	var enabledEcos []string
@@ -68,11 +101,14 @@ func (m Model) createConfirmationVM() ConfirmationViewModel {
 		})
 	}
 
-	mergeBase := "no"
-	if m.create.mergeBase {
-		mergeBase = "yes"
+	mergeBase := m.create.mergeBase && m.create.source == createFromBase
+	if m.create.source == createFromBase {
+		value := "no"
+		if mergeBase {
+			value = "yes"
+		}
+		items = append(items, ConfirmationItem{Label: "Merge base:", Value: value})
 	}
-	items = append(items, ConfirmationItem{Label: "Merge base:", Value: mergeBase})
 
 	copyEnv := "no"
 	if m.create.copyEnvFiles {
@@ -80,23 +116,48 @@ func (m Model) createConfirmationVM() ConfirmationViewModel {
 	}
 	items = append(items, ConfirmationItem{Label: "Copy env:", Value: copyEnv})
 
+	if m.create.integrations != nil {
+		value := strings.Join(m.create.integrations, ", ")
+		if value == "" {
+			value = "none"
+		}
+		items = append(items, ConfirmationItem{Label: "Integrations:", Value: value})
+	}
+
 	// Build CLI command from current model state.
 	flags := make(map[string]string)
 	if branch != "" {
 		flags["branch"] = branch
 	}
 	if base != "" {
-		flags["base"] = base
+		switch m.create.source {
+		case createFromRemote:
+			flags["track"] = base
+		case createFromPR:
+			flags["pr"] = strings.TrimPrefix(base, "#")
+		default:
+			flags["base"] = base
+		}
 	}
 	if len(enabledEcos) > 0 {
 		flags["ecosystems"] = strings.Join(enabledEcos, ",")
 	}
-	if m.create.mergeBase {
+	if mergeBase {
 		flags["merge-base"] = "true"
 	}
 	if m.create.copyEnvFiles {
 		flags["copy-env"] = "true"
 	}
+	if p, ok := m.createProfile(); ok {
+		flags["profile"] = m.create.profile
+		// An option the profile turns on is switched off explicitly.
+		if !mergeBase && m.create.source == createFromBase && p.MergeBase != nil && *p.MergeBase {
+			flags["merge-base"] = "false"
+		}
+		if !m.create.copyEnvFiles && p.CopyEnv != nil && *p.CopyEnv {
+			flags["copy-env"] = "false"
+		}
+	}
 
 	return ConfirmationViewModel{
 		Width:      m.width,
@@ -107,14 +168,24 @@ func (m Model) createConfirmationVM() ConfirmationViewModel {
 }
 
 func (m Model) updateCreateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg.(type) {
+	switch msg := msg.(type) {
 	case ConfirmProceedMsg:
+		if m.create.source != createFromBase && m.create.remoteSource == nil {
+			if m.create.resolving {
+				return m, nil
+			}
+			m.create.resolving = true
+			return m, m.resolveCreateSource(true)
+		}
 		m.startCreation()
 		m.progressStartedAt = time.Now()
 		m.progressToken++
 		m.view = createProgressView
 		return m, m.waitForCreateEvent()
 
+	case createSourceResolvedMsg:
+		return m.applyResolvedSource(msg)
+
 	case ConfirmBackMsg:
 		if m.createOpts != nil {
 			return m, tea.Quit
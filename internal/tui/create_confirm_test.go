@@ -213,3 +213,59 @@ func TestCreateConfirm_ViewDispatch(t *testing.T) {
 		t.Error("View() should dispatch to viewCreateConfirm")
 	}
 }
+
+func TestSetCreateOpts_PullRequestEntersConfirmAndResolvesOnProceed(t *testing.T) {
+	m := makeCreateConfirmModel(&CreateOpts{PR: 42, MergeBase: true})
+	if m.view != createConfirmView {
+		t.Fatalf("expected createConfirmView, got %d", m.view)
+	}
+
+	vm := m.createConfirmationVM()
+	var labels []string
+	for _, item := range vm.Items {
+		labels = append(labels, item.Label+" "+item.Value)
+	}
+	joined := strings.Join(labels, "\n")
+	for _, want := range []string{"Branch: (named after the pull request)", "Pull request: #42"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("items missing %q:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "Merge base") || strings.Contains(vm.CLICommand, "--merge-base") {
+		t.Errorf("a pull request is checked out as it is, got items:\n%s\ncommand %s", joined, vm.CLICommand)
+	}
+	if !strings.Contains(vm.CLICommand, "--pr 42") {
+		t.Errorf("CLI command = %q, want --pr 42", vm.CLICommand)
+	}
+
+	updated, cmd := m.updateCreateConfirm(ConfirmProceedMsg{})
+	model := updated.(Model)
+	if !model.create.resolving || cmd == nil || model.view != createConfirmView {
+		t.Fatal("proceeding should resolve the pull request before creating")
+	}
+}
+
+func TestCreateConfirmationVM_EchoesProfile(t *testing.T) {
+	on := true
+	m := NewMenuModel(bareDirRunner("/repo"), nil, "/repo", &config.Config{
+		Profiles: map[string]config.Profile{"review": {Base: "main", CopyEnv: &on, Integrations: []string{}}},
+	}, repo.ContextBareRepo)
+	m.width, m.height = 80, 24
+	// As main.go passes it: the profile already applied, --copy-env=false on top.
+	m.SetCreateOpts(&CreateOpts{Branch: "feature/p", Base: "main", Profile: "review", Integrations: []string{}})
+	if m.view != createConfirmView {
+		t.Fatalf("view = %d, want createConfirmView", m.view)
+	}
+
+	vm := m.createConfirmationVM()
+	if want := "sentei create --base main --branch feature/p --copy-env=false --profile review"; vm.CLICommand != want {
+		t.Errorf("CLICommand = %q, want %q", vm.CLICommand, want)
+	}
+	values := make(map[string]string)
+	for _, item := range vm.Items {
+		values[item.Label] = item.Value
+	}
+	if values["Profile:"] != "review" || values["Integrations:"] != "none" {
+		t.Errorf("items should show the profile and its integrations, got %v", values)
+	}
+}
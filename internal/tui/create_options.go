@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -25,6 +26,17 @@ type optionItem struct {
 func (m Model) buildOptionItems() []optionItem {
 	var items []optionItem
 
+	// Profiles come first: each presets the items below it.
+	if m.cfg != nil {
+		for _, name := range m.cfg.ProfileNames() {
+			items = append(items, optionItem{
+				label: "Profile: " + name,
+				hint:  profileHint(m.cfg.Profiles[name]),
+				key:   "profile:" + name,
+			})
+		}
+	}
+
 	for _, eco := range m.create.ecosystems {
 		items = append(items, optionItem{
 			label: fmt.Sprintf("Install dependencies (%s)", eco.Name),
@@ -33,11 +45,14 @@ func (m Model) buildOptionItems() []optionItem {
 		})
 	}
 
-	items = append(items, optionItem{
-		label: "Merge default branch",
-		hint:  fmt.Sprintf("%s \u2192 %s", m.create.baseInput.Value(), m.create.branchInput.Value()),
-		key:   "merge",
-	})
+	// A remote branch or pull request is checked out as it is.
+	if m.create.source == createFromBase {
+		items = append(items, optionItem{
+			label: "Merge default branch",
+			hint:  fmt.Sprintf("%s \u2192 %s", m.create.baseInput.Value(), m.create.branchInput.Value()),
+			key:   "merge",
+		})
+	}
 
 	hasEnvFiles := false
 	var envFileNames []string
@@ -60,6 +75,8 @@ func (m Model) buildOptionItems() []optionItem {
 
 func (m Model) isOptionEnabled(item optionItem) bool {
 	switch {
+	case strings.HasPrefix(item.key, "profile:"):
+		return m.create.profile == strings.TrimPrefix(item.key, "profile:")
 	case strings.HasPrefix(item.key, "eco:"):
 		name := strings.TrimPrefix(item.key, "eco:")
 		return m.create.ecoEnabled[name]
@@ -73,6 +90,14 @@ func (m Model) isOptionEnabled(item optionItem) bool {
 
 func (m *Model) toggleOption(item optionItem) {
 	switch {
+	case strings.HasPrefix(item.key, "profile:"):
+		name := strings.TrimPrefix(item.key, "profile:")
+		if m.create.profile == name {
+			m.create.profile = ""
+			m.create.integrations = nil
+			return
+		}
+		m.applyCreateProfile(name, m.cfg.Profiles[name])
 	case strings.HasPrefix(item.key, "eco:"):
 		name := strings.TrimPrefix(item.key, "eco:")
 		m.create.ecoEnabled[name] = !m.create.ecoEnabled[name]
@@ -83,6 +108,60 @@ func (m *Model) toggleOption(item optionItem) {
 	}
 }
 
+// applyCreateProfile sets the options a profile fixes and leaves the rest.
+// The branch and its base are already chosen by now, so the profile's base
+// only applies through `create --profile`.
+func (m *Model) applyCreateProfile(name string, p config.Profile) {
+	m.create.profile = name
+	if p.Ecosystems != nil {
+		for _, eco := range m.create.ecosystems {
+			m.create.ecoEnabled[eco.Name] = slices.Contains(p.Ecosystems, eco.Name)
+		}
+	}
+	if p.MergeBase != nil && m.create.source == createFromBase {
+		m.create.mergeBase = *p.MergeBase
+	}
+	if p.CopyEnv != nil {
+		m.create.copyEnvFiles = *p.CopyEnv
+	}
+	m.create.integrations = p.Integrations
+}
+
+// createProfile returns the profile last applied, if any.
+func (m Model) createProfile() (config.Profile, bool) {
+	if m.create.profile == "" || m.cfg == nil {
+		return config.Profile{}, false
+	}
+	p, ok := m.cfg.Profiles[m.create.profile]
+	return p, ok
+}
+
+// profileHint summarizes what applying a profile changes.
+func profileHint(p config.Profile) string {
+	var parts []string
+	switch {
+	case p.Ecosystems == nil:
+	case len(p.Ecosystems) == 0:
+		parts = append(parts, "no ecosystems")
+	default:
+		parts = append(parts, strings.Join(p.Ecosystems, ", "))
+	}
+	switch {
+	case p.Integrations == nil:
+	case len(p.Integrations) == 0:
+		parts = append(parts, "no integrations")
+	default:
+		parts = append(parts, strings.Join(p.Integrations, ", "))
+	}
+	if p.MergeBase != nil && *p.MergeBase {
+		parts = append(parts, "merge base")
+	}
+	if p.CopyEnv != nil && *p.CopyEnv {
+		parts = append(parts, "copy env")
+	}
+	return strings.Join(parts, " \u00b7 ")
+}
+
 func (m Model) updateCreateOptions(msg tea.Msg) (tea.Model, tea.Cmd) {
 	items := m.buildOptionItems()
 
@@ -127,16 +206,22 @@ func (m *Model) startCreation() {
 		}
 	}
 
-	st, err := m.loadRepoState()
-	if err != nil {
-		st = &state.State{}
-	}
-	var enabledInts []integration.Integration
 	enabledSet := make(map[string]bool)
-	for _, name := range st.Integrations {
-		enabledSet[name] = true
+	if m.create.integrations != nil {
+		for _, name := range m.create.integrations {
+			enabledSet[name] = true
+		}
+	} else {
+		st, err := m.loadRepoState()
+		if err != nil {
+			st = &state.State{}
+		}
+		for _, name := range st.Integrations {
+			enabledSet[name] = true
+		}
 	}
-	for _, integ := range integration.All() {
+	var enabledInts []integration.Integration
+	for _, integ := range m.integrations() {
 		if enabledSet[integ.Name] {
 			enabledInts = append(enabledInts, integ)
 		}
@@ -152,6 +237,10 @@ func (m *Model) startCreation() {
 		Ecosystems:     enabledEcos,
 		Integrations:   enabledInts,
 	}
+	if m.create.source != createFromBase {
+		opts.BaseBranch = ""
+		opts.Source = m.create.remoteSource
+	}
 
 	ch := make(chan progress.Event, 50)
 	resultCh := make(chan creator.Result, 1)
@@ -190,7 +279,7 @@ func (m Model) viewCreateOptions() string {
 	items := m.buildOptionItems()
 
 	branch := m.create.branchInput.Value()
-	base := m.create.baseInput.Value()
+	base := m.createFromLabel()
 
 	b.WriteString(viewTitle(titleCreateWorktree))
 	b.WriteString("\n\n")
@@ -234,7 +323,15 @@ func (m Model) viewCreateOptions() string {
 	}
 
 	b.WriteString("\n")
-	if len(m.create.activeIntegrationNames) > 0 {
+	switch {
+	case m.create.integrations != nil && len(m.create.integrations) == 0:
+		b.WriteString(styleDim.Render(fmt.Sprintf("  No integrations (profile %s)", m.create.profile)))
+		b.WriteString("\n")
+	case m.create.integrations != nil:
+		b.WriteString(styleDim.Render(fmt.Sprintf("  Integrations from profile %s: %s",
+			m.create.profile, strings.Join(m.create.integrations, ", "))))
+		b.WriteString("\n")
+	case len(m.create.activeIntegrationNames) > 0:
 		b.WriteString(styleDim.Render(fmt.Sprintf("  Integrations from main: %s",
 			strings.Join(m.create.activeIntegrationNames, ", "))))
 		b.WriteString("\n")
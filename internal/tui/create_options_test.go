@@ -313,3 +313,42 @@ func TestViewCreateOptions_RendersOptionsAndIntegrations(t *testing.T) {
 		}
 	}
 }
+
+func TestToggleOption_ProfileAppliesPreset(t *testing.T) {
+	off := false
+	m := createOptionsModel()
+	m.cfg.Profiles = map[string]config.Profile{
+		"review": {Base: "develop", Ecosystems: []string{"go"}, Integrations: []string{}, MergeBase: &off},
+	}
+	m.create.ecosystems = []config.EcosystemConfig{{Name: "node"}, {Name: "go"}}
+	m.create.ecoEnabled = map[string]bool{"node": true, "go": true}
+	m.create.mergeBase, m.create.copyEnvFiles = true, true
+
+	items := m.buildOptionItems()
+	if items[0].key != "profile:review" || items[0].hint != "go · no integrations" {
+		t.Fatalf("profiles should come first with a summary, got %+v", items[0])
+	}
+
+	m.toggleOption(items[0])
+	if !m.isOptionEnabled(items[0]) {
+		t.Error("the applied profile should show as selected")
+	}
+	if m.create.ecoEnabled["node"] || !m.create.ecoEnabled["go"] || m.create.mergeBase || !m.create.copyEnvFiles {
+		t.Errorf("preset should set what the profile fixes and keep the rest: eco=%v merge=%v copy=%v",
+			m.create.ecoEnabled, m.create.mergeBase, m.create.copyEnvFiles)
+	}
+	if m.create.baseInput.Value() != "main" {
+		t.Error("the base is already chosen; the preset must not change it")
+	}
+	if m.create.integrations == nil || len(m.create.integrations) != 0 {
+		t.Errorf("integrations = %#v, want the profile's empty list", m.create.integrations)
+	}
+	if view := m.viewCreateOptions(); !strings.Contains(view, "No integrations (profile review)") {
+		t.Errorf("view should say the profile turns integrations off:\n%s", view)
+	}
+
+	m.toggleOption(items[0])
+	if m.create.profile != "" || m.create.integrations != nil {
+		t.Errorf("toggling again should drop the profile, got %q %#v", m.create.profile, m.create.integrations)
+	}
+}
@@ -26,7 +26,7 @@ func (m Model) updateCreateProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.worktreeGeneration++
 		syncCmd := m.syncProgressBar()
 		updated, holdCmd := m.holdOrAdvance(createSummaryView)
-		return updated, tea.Batch(syncCmd, holdCmd, loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration))
+		return updated, tea.Batch(syncCmd, holdCmd, loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration, !m.noEnrichCache))
 	}
 	return m, nil
 }
@@ -35,7 +35,7 @@ func (m Model) createLayout() ProgressLayout {
 	return m.withProgressDetails(ProgressLayout{
 		Title:     titleCreatingWorktree,
 		Completed: m.create.result != nil,
-		Subtitle:  fmt.Sprintf("%s \u2192 from %s", m.create.branchInput.Value(), m.create.baseInput.Value()),
+		Subtitle:  fmt.Sprintf("%s \u2192 from %s", m.create.branchInput.Value(), m.createFromLabel()),
 		Phases:    progress.Snapshot(m.create.events),
 		Width:     m.width,
 		Height:    m.progressHeight(),
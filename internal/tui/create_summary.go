@@ -34,7 +34,7 @@ func (m Model) viewCreateSummary() string {
 	var b strings.Builder
 
 	branch := m.create.branchInput.Value()
-	base := m.create.baseInput.Value()
+	base := m.createFromLabel()
 
 	result := m.create.result
 	wtPath := git.WorktreePath(m.repoPath, branch)
@@ -69,11 +69,16 @@ func (m Model) viewCreateSummary() string {
 	fmt.Fprintf(&b, "    %-10s %s (from %s)\n", styleDim.Render("Branch"), branch, base)
 
 	if result != nil {
+		for _, f := range result.EnvFiles {
+			fmt.Fprintf(&b, "    %-10s %s\n", styleDim.Render("Env"), f)
+		}
 		for _, phase := range result.Phases {
 			label := ""
 			switch phase.Name {
 			case "Dependencies":
 				label = "Deps"
+			case "Post-install":
+				label = "Hooks"
 			case "Integrations":
 				label = "Index"
 			default:
@@ -81,8 +86,11 @@ func (m Model) viewCreateSummary() string {
 			}
 			for _, step := range phase.Steps {
 				status := styleIndicatorDone.Render(indicatorDone)
-				if step.Status == progress.StepFailed {
+				switch step.Status {
+				case progress.StepFailed:
 					status = styleIndicatorFailed.Render(indicatorFailed)
+				case progress.StepSkipped:
+					status = styleDim.Render("skipped")
 				}
 				fmt.Fprintf(&b, "    %-10s %s %s\n", styleDim.Render(label), step.Name, status)
 				if step.Status == progress.StepFailed && step.Error != nil {
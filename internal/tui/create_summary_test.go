@@ -78,6 +78,23 @@ func TestViewCreateSummary_SuccessShowsReady(t *testing.T) {
 	}
 }
 
+func TestViewCreateSummary_ShowsEnvFileModes(t *testing.T) {
+	m := createOptionsModel()
+	m.create.result = &creator.Result{WorktreePath: "/repo/feature-x", EnvFiles: []creator.EnvFile{
+		{Name: ".env", Mode: "copy"},
+		{Name: ".env.local", Mode: "template", Port: 3104},
+		{Name: "secrets.env", Mode: "symlink"},
+	}}
+
+	view := stripANSI(m.viewCreateSummary())
+
+	for _, want := range []string{"Env", ".env\n", ".env.local (template, port 3104)", "secrets.env (symlink)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
+
 func TestViewCreateSummary_NilResultFallsBackToDerivedPath(t *testing.T) {
 	m := createOptionsModel()
 
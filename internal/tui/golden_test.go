@@ -32,7 +32,7 @@ func goldenModel(t *testing.T) Model {
 	m := NewModel(goldenWorktrees(), nil, "/repo")
 	m.width = 80
 	m.height = 30
-	m.remove.defaultBranch = "main"
+	m.remove.protection = git.NewProtectionPolicy("main", nil)
 	return m
 }
 
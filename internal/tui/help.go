@@ -10,6 +10,7 @@ import (
 	"github.com/abiswas97/sentei/internal/git"
 	"github.com/abiswas97/sentei/internal/progress"
 	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/worktree"
 )
 
 // renderHelpSections formats key bindings as an aligned two-column table
@@ -79,9 +80,11 @@ func (m Model) helpForView() (string, []keySection) {
 	case integrationProgressView:
 		return "Applying Integrations", progressSections
 
-	case summaryView, createSummaryView, repoSummaryView, migrateSummaryView, integrationSummaryView:
+	case summaryView, createSummaryView, repoSummaryView, migrateSummaryView, unmigrateSummaryView, integrationSummaryView:
 		return "Summary", summarySections
-	case createBranchView, repoNameView, cloneInputView:
+	case createBranchView:
+		return "Input", createBranchSections
+	case repoNameView, cloneInputView, moveView, lockView:
 		return "Input", inputSections
 	case createOptionsView, repoOptionsView:
 		return "Options", optionsSections
@@ -89,7 +92,7 @@ func (m Model) helpForView() (string, []keySection) {
 		return "Integrations", integrationSections
 	case cleanupPreviewView:
 		return "Cleanup Preview", cleanupPreviewSections
-	case cleanupConfirmView, createConfirmView, cloneConfirmView, migrateConfirmView:
+	case cleanupConfirmView, createConfirmView, cloneConfirmView, migrateConfirmView, unmigrateConfirmView:
 		return "Confirmation", confirmationSections
 
 	default:
@@ -167,6 +170,10 @@ func (m Model) progressTopLevelError() error {
 			return result.Err
 		case repo.MigrateResult:
 			return result.Err
+		case repo.UnmigrateResult:
+			return result.Err
+		case worktree.MoveResult:
+			return result.Err
 		}
 	case integrationProgressView:
 		return errors.Join(m.integ.prepareErr, m.integ.executionErr, m.integ.saveErr)
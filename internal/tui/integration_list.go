@@ -43,7 +43,7 @@ type integrationPreparedMsg struct {
 
 func (m Model) loadIntegrationState() tea.Cmd {
 	return func() tea.Msg {
-		all := integration.All()
+		all := m.integrations()
 		mainWT := m.findSourceWorktree()
 		current := make(map[string]bool)
 		if mainWT != "" {
@@ -142,6 +142,40 @@ func (m Model) startIntegrationApply() (Model, tea.Cmd) {
 	}
 }
 
+// refreshableIntegrations returns the active integrations that declare a
+// refresh command.
+func (m Model) refreshableIntegrations() []integration.Integration {
+	var refreshable []integration.Integration
+	for _, integ := range m.integ.integrations {
+		if m.integ.current[integ.Name] && integ.Refresh.Command != "" {
+			refreshable = append(refreshable, integ)
+		}
+	}
+	return refreshable
+}
+
+// startIntegrationRefresh prepares a refresh of the active integrations in
+// every worktree; it runs through the same progress view as an apply.
+func (m Model) startIntegrationRefresh() (Model, tea.Cmd) {
+	var wtPaths []string
+	for _, wt := range m.remove.worktrees {
+		wtPaths = append(wtPaths, wt.Path)
+	}
+
+	m.integ.targetWorktrees = wtPaths
+	m.integ.lifecycle = integrationPreparing
+	m.integ.prepareErr = nil
+	m.integ.executionErr = nil
+	m.integ.saveErr = nil
+
+	repoPath := m.repoPath
+	refreshable := m.refreshableIntegrations()
+	return m, func() tea.Msg {
+		prepared, err := integration.PrepareRefresh(repoPath, refreshable, wtPaths)
+		return integrationPreparedMsg{prepared: prepared, err: err}
+	}
+}
+
 func (m Model) startPreparedIntegrationApply(prepared integration.PreparedApply) (Model, tea.Cmd) {
 	ch := make(chan progress.Event, 50)
 	resultCh := make(chan integrationApplyResult, 1)
@@ -219,6 +253,7 @@ func (m Model) updateIntegrationList(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.integ.events = nil
 				m.integ.lifecycle = integrationIdle
 				m.integ.returnView = integrationListView
+				m.integ.refreshing = false
 				m.progressStartedAt = time.Now()
 				m.progressToken++
 				m.view = integrationProgressView
@@ -226,6 +261,21 @@ func (m Model) updateIntegrationList(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return updated, cmd
 			}
 
+		case key.Matches(msg, keys.Refresh):
+			// Staged changes are applied first, so a refresh never runs
+			// against a list that is not what is on disk.
+			if !m.integrationHasPendingChanges() && len(m.refreshableIntegrations()) > 0 {
+				m.integ.events = nil
+				m.integ.lifecycle = integrationIdle
+				m.integ.returnView = integrationListView
+				m.integ.refreshing = true
+				m.progressStartedAt = time.Now()
+				m.progressToken++
+				m.view = integrationProgressView
+				updated, cmd := m.startIntegrationRefresh()
+				return updated, cmd
+			}
+
 		case key.Matches(msg, keys.Back):
 			for _, integ := range m.integ.integrations {
 				m.integ.staged[integ.Name] = m.integ.current[integ.Name]
@@ -294,7 +344,14 @@ func (m Model) viewIntegrationList() string {
 			b.WriteString("  " + checkbox + " " + integ.Name)
 		}
 		b.WriteString("\n")
-		b.WriteString("       " + styleDim.Render(integ.ShortDescription))
+		summary := integ.ShortDescription
+		if summary == "" {
+			summary = integ.Description
+		}
+		if integ.Source != integration.SourceBuiltIn {
+			summary = strings.TrimSpace(summary + " (from " + integ.Source + " config)")
+		}
+		b.WriteString("       " + styleDim.Render(summary))
 		b.WriteString("\n")
 
 		if i < len(m.integ.integrations)-1 {
@@ -326,9 +383,12 @@ func (m Model) viewIntegrationList() string {
 	b.WriteString(legend)
 	b.WriteString("\n\n")
 
-	if pending > 0 {
+	switch {
+	case pending > 0:
 		b.WriteString(viewFooter(m.width, integrationPendingFooter))
-	} else {
+	case len(m.refreshableIntegrations()) > 0:
+		b.WriteString(viewFooter(m.width, integrationRefreshFooter))
+	default:
 		b.WriteString(viewFooter(m.width, integrationFooter))
 	}
 	b.WriteString("\n")
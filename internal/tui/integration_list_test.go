@@ -2,6 +2,8 @@ package tui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -454,3 +456,54 @@ func TestStartIntegrationApply_ComputesPlanAndAppliesChanges(t *testing.T) {
 		t.Errorf("expected disable-me teardown to run, events: %v", events)
 	}
 }
+
+func TestUpdateIntegrationList_RefreshRunsActiveIntegrationsWithoutSaving(t *testing.T) {
+	setUp, bare := t.TempDir(), t.TempDir()
+	if err := os.Mkdir(filepath.Join(setUp, ".code-review-graph"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	m := makeIntegrationModel()
+	m.view = integrationListView
+	m.remove.worktrees = []git.Worktree{{Path: setUp, Branch: "refs/heads/main"}, {Path: bare, Branch: "refs/heads/feature"}}
+	m.shell = &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:shell[code-review-graph update --repo '" + setUp + "']": {Output: "updated"},
+	}}
+
+	// Staged changes must be applied before a refresh.
+	m.integ.staged["cocoindex-code"] = true
+	updated, cmd := m.updateIntegrationList(keyMsg("r"))
+	if updated.(Model).view != integrationListView || cmd != nil {
+		t.Fatal("refresh should wait for pending changes to be applied")
+	}
+	m.integ.staged["cocoindex-code"] = false
+
+	updated, cmd = m.updateIntegrationList(keyMsg("r"))
+	m = updated.(Model)
+	if m.view != integrationProgressView || !m.integ.refreshing || cmd == nil {
+		t.Fatalf("view=%v refreshing=%v cmd=%v, want refresh preparing", m.view, m.integ.refreshing, cmd)
+	}
+	updated, _ = m.updateIntegrationProgress(cmd())
+	m = updated.(Model)
+
+	var done integrationApplyDoneMsg
+	for done.result.phases == nil {
+		switch msg := waitForIntegrationEvent(m.integ.eventCh, m.integ.resultCh)().(type) {
+		case integrationEventMsg:
+			m.integ.events = append(m.integ.events, msg.Event)
+		case integrationApplyDoneMsg:
+			done = msg
+		}
+	}
+	updated, _ = m.updateIntegrationProgress(done)
+	m = updated.(Model)
+	if m.integ.lifecycle != integrationSettling || m.integ.executionErr != nil {
+		t.Fatalf("lifecycle=%v executionErr=%v, want settled without saving", m.integ.lifecycle, m.integ.executionErr)
+	}
+
+	view := stripAnsi(m.viewIntegrationSummary())
+	for _, want := range []string{titleRefreshComplete, "1 step refreshed", "not set up in this worktree"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("summary missing %q:\n%s", want, view)
+		}
+	}
+}
@@ -59,6 +59,13 @@ func (m Model) updateIntegrationProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 			updated, holdCmd := m.holdOrAdvance(integrationSummaryView)
 			return updated, tea.Batch(finalSync, holdCmd)
 		}
+		if m.integ.refreshing {
+			// A refresh leaves the enabled set alone: nothing to save, and
+			// steps skipped in worktrees without the integration are normal.
+			m.integ.lifecycle = integrationSettling
+			updated, holdCmd := m.holdOrAdvance(integrationSummaryView)
+			return updated, tea.Batch(m.syncProgressBar(), holdCmd)
+		}
 		switch classifyIntegrationExecution(msg.result) {
 		case integrationExecutionCompleted, integrationExecutionEmpty:
 			m.integ.lifecycle = integrationSaving
@@ -88,7 +95,7 @@ func (m Model) updateIntegrationProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err == nil {
 			m.worktreeGeneration++
 			updated, holdCmd := m.holdOrAdvance(integrationSummaryView)
-			return updated, tea.Batch(finalSync, holdCmd, loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration))
+			return updated, tea.Batch(finalSync, holdCmd, loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration, !m.noEnrichCache))
 		}
 		updated, holdCmd := m.holdOrAdvance(integrationSummaryView)
 		return updated, tea.Batch(finalSync, holdCmd)
@@ -136,7 +143,7 @@ func (m Model) finalizeIntegrationApply() tea.Cmd {
 
 func (m Model) integrationLayout() ProgressLayout {
 	return m.withProgressDetails(ProgressLayout{
-		Title:     titleApplyingChanges,
+		Title:     m.integrationProgressTitle(),
 		Phases:    m.buildIntegrationPhases(),
 		Width:     m.width,
 		Height:    m.progressHeight(),
@@ -145,6 +152,13 @@ func (m Model) integrationLayout() ProgressLayout {
 	})
 }
 
+func (m Model) integrationProgressTitle() string {
+	if m.integ.refreshing {
+		return titleRefreshing
+	}
+	return titleApplyingChanges
+}
+
 func (m Model) viewIntegrationProgress() string {
 	if m.integ.lifecycle == integrationPreparing {
 		return m.viewIntegrationPreparing()
@@ -158,7 +172,7 @@ func (m Model) viewIntegrationPreparing() string {
 		return ""
 	}
 	width := max(m.width, 1)
-	title := fitProgressLine(viewTitle(titleApplyingChanges), width)
+	title := fitProgressLine(viewTitle(m.integrationProgressTitle()), width)
 	wait := fitProgressLine("  "+shimmerLine(starFrame(m.motionTick)+" Preparing plan...", rampAccent, m.motionTick), width)
 	separator := fitProgressLine(viewSeparator(width), width)
 
@@ -46,6 +46,7 @@ func (m Model) updateIntegrationSummary(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch {
 		case key.Matches(msg, keys.Confirm), key.Matches(msg, keys.Back):
 			m.integ.lifecycle = integrationIdle
+			m.integ.refreshing = false
 			if m.integ.returnView == migrateNextView {
 				m.view = migrateNextView
 				return m, nil
@@ -188,9 +189,15 @@ func (m Model) viewIntegrationSummary() string {
 
 	// "Complete" would oversell a run with failures: the title states the
 	// outcome, and the headline leads with the count that matters.
-	title := titleApplyComplete
+	title, verb := titleApplyComplete, "applied"
+	if m.integ.refreshing {
+		title, verb = titleRefreshComplete, "refreshed"
+	}
 	if failed > 0 || m.integ.prepareErr != nil || m.integ.executionErr != nil || m.integ.saveErr != nil {
 		title = titleApplyErrors
+		if m.integ.refreshing {
+			title = titleRefreshErrors
+		}
 	}
 	header := []string{viewTitle(title), "", viewSeparator(m.width), ""}
 
@@ -218,12 +225,12 @@ func (m Model) viewIntegrationSummary() string {
 			verdict.WriteString(styleError.Render(fmt.Sprintf("%s %d failed", indicatorFailed, failed)))
 			if applied > 0 {
 				verdict.WriteString(", ")
-				verdict.WriteString(styleSuccess.Render(fmt.Sprintf("%d %s applied", applied, pluralize(applied, "step", "steps"))))
+				verdict.WriteString(styleSuccess.Render(fmt.Sprintf("%d %s %s", applied, pluralize(applied, "step", "steps"), verb)))
 			}
 		case applied == 0:
 			verdict.WriteString(styleDim.Render("  No integration work was needed"))
 		default:
-			verdict.WriteString(styleSuccess.Render(fmt.Sprintf("  %s %d %s applied", indicatorDone, applied, pluralize(applied, "step", "steps"))))
+			verdict.WriteString(styleSuccess.Render(fmt.Sprintf("  %s %d %s %s", indicatorDone, applied, pluralize(applied, "step", "steps"), verb)))
 		}
 		header = append(header, verdict.String(), "")
 	}
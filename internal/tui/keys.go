@@ -16,10 +16,14 @@ type keyMap struct {
 	No          key.Binding
 	Back        key.Binding
 	Tab         key.Binding
+	Source      key.Binding
 	Sort        key.Binding
 	ReverseSort key.Binding
 	Filter      key.Binding
 	Info        key.Binding
+	Refresh     key.Binding
+	Move        key.Binding
+	Lock        key.Binding
 	GlobalHelp  key.Binding
 }
 
@@ -76,6 +80,10 @@ var keys = keyMap{
 		key.WithKeys("tab"),
 		key.WithHelp("tab", "switch field"),
 	),
+	Source: key.NewBinding(
+		key.WithKeys("ctrl+t"),
+		key.WithHelp("ctrl+t", "base/remote/PR"),
+	),
 	Sort: key.NewBinding(
 		key.WithKeys("s"),
 		key.WithHelp("s", "sort"),
@@ -92,6 +100,18 @@ var keys = keyMap{
 		key.WithKeys("?"),
 		key.WithHelp("?", "details"),
 	),
+	Refresh: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "refresh"),
+	),
+	Move: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "move"),
+	),
+	Lock: key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "lock"),
+	),
 	GlobalHelp: key.NewBinding(
 		key.WithKeys("f1"),
 		key.WithHelp("F1", "help"),
@@ -162,6 +182,8 @@ var (
 			withDesc(keys.Filter, "filter by name"),
 			hintOnly("s / S", "cycle / reverse sort"),
 			withDesc(keys.Info, "details for highlighted worktree"),
+			withDesc(keys.Move, "rename or move highlighted worktree"),
+			withDesc(keys.Lock, "lock or unlock highlighted worktree"),
 		}},
 	}
 
@@ -178,11 +200,21 @@ var (
 	}
 	cloneInputFooter = []key.Binding{withDesc(keys.Confirm, "clone"), keys.Tab, keys.Back}
 	repoNameFooter   = []key.Binding{withDesc(keys.Confirm, "continue"), keys.Tab, keys.Back}
+	moveFooter       = []key.Binding{withDesc(keys.Confirm, "move"), keys.Back}
+	lockFooter       = []key.Binding{withDesc(keys.Confirm, "lock"), keys.Back}
+	unlockFooter     = []key.Binding{withDesc(keys.Confirm, "unlock"), keys.Back}
 	inputSections    = []keySection{{name: "Editing", bindings: []key.Binding{
 		keys.Tab,
 		withDesc(keys.Confirm, "continue"),
 		keys.Back,
 	}}}
+	createBranchSections = []keySection{{name: "Editing", bindings: []key.Binding{
+		keys.Tab,
+		withDesc(keys.Source, "start from a base branch, a remote branch or a pull request"),
+		withDesc(keys.Confirm, "continue"),
+		keys.QuickCreate,
+		keys.Back,
+	}}}
 
 	optionsFooter   = []key.Binding{navHint, keys.Toggle, withDesc(keys.Confirm, "create"), keys.Back}
 	optionsSections = []keySection{{name: "Actions", bindings: []key.Binding{
@@ -220,6 +252,7 @@ var (
 	}}}
 
 	integrationFooter        = []key.Binding{navHint, keys.Toggle, keys.Info, keys.Back}
+	integrationRefreshFooter = []key.Binding{navHint, keys.Toggle, keys.Info, keys.Refresh, keys.Back}
 	integrationPendingFooter = []key.Binding{navHint, keys.Toggle, keys.Info, withDesc(keys.Confirm, "apply"), keys.Back}
 
 	integrationSections = []keySection{{name: "Actions", bindings: []key.Binding{
@@ -227,6 +260,7 @@ var (
 		withDesc(keys.Toggle, "stage/unstage"),
 		withDesc(keys.Info, "integration info"),
 		withDesc(keys.Confirm, "apply changes"),
+		withDesc(keys.Refresh, "refresh active integrations in every worktree"),
 		keys.Back,
 	}}}
 
@@ -242,6 +276,7 @@ var (
 
 	migrateConfirmFooter = []key.Binding{withDesc(keys.Yes, "delete"), withDesc(keys.No, "keep"), keys.Quit}
 	migrateOpenFooter    = []key.Binding{withDesc(keys.Confirm, "open in sentei"), withDesc(keys.Quit, "exit")}
+	unmigrateFooter      = []key.Binding{navHint, withDesc(keys.Confirm, "convert"), keys.Back, keys.Quit}
 	cleanupSafeHint      = withDesc(keys.Confirm, "safe cleanup")
 	integrationsOpenHint = withDesc(keys.Confirm, "integrations")
 )
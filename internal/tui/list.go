@@ -11,6 +11,7 @@ import (
 	"charm.land/lipgloss/v2"
 	"charm.land/lipgloss/v2/table"
 
+	"github.com/abiswas97/sentei/internal/dryrun"
 	"github.com/abiswas97/sentei/internal/git"
 )
 
@@ -64,6 +65,10 @@ func relativeTime(t time.Time) string {
 	}
 }
 
+// mergedSuffix follows the branch name of a worktree whose branch is merged
+// into the default branch, by merge commit, rebase or squash.
+const mergedSuffix = " merged"
+
 func stripBranchPrefix(ref string) string {
 	return strings.TrimPrefix(ref, "refs/heads/")
 }
@@ -163,7 +168,7 @@ func (m Model) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, keys.Toggle):
 			if len(m.remove.visibleIndices) > 0 {
 				wt := m.remove.worktrees[m.remove.visibleIndices[m.remove.cursor]]
-				if git.IsProtectedBranchWith(wt.Branch, m.remove.defaultBranch) {
+				if m.remove.protection.IsProtected(wt.Branch) {
 					break
 				}
 				if m.remove.selected[wt.Path] {
@@ -177,7 +182,7 @@ func (m Model) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 			allSelected := true
 			for _, idx := range m.remove.visibleIndices {
 				wt := m.remove.worktrees[idx]
-				if git.IsProtectedBranchWith(wt.Branch, m.remove.defaultBranch) {
+				if m.remove.protection.IsProtected(wt.Branch) {
 					continue
 				}
 				if !m.remove.selected[wt.Path] {
@@ -188,7 +193,7 @@ func (m Model) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if allSelected {
 				for _, idx := range m.remove.visibleIndices {
 					wt := m.remove.worktrees[idx]
-					if git.IsProtectedBranchWith(wt.Branch, m.remove.defaultBranch) {
+					if m.remove.protection.IsProtected(wt.Branch) {
 						continue
 					}
 					delete(m.remove.selected, wt.Path)
@@ -196,23 +201,37 @@ func (m Model) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				for _, idx := range m.remove.visibleIndices {
 					wt := m.remove.worktrees[idx]
-					if git.IsProtectedBranchWith(wt.Branch, m.remove.defaultBranch) {
+					if m.remove.protection.IsProtected(wt.Branch) {
 						continue
 					}
 					m.remove.selected[wt.Path] = true
 				}
 			}
 
+		case key.Matches(msg, keys.Move):
+			if len(m.remove.visibleIndices) > 0 {
+				return m.startMove(m.remove.worktrees[m.remove.visibleIndices[m.remove.cursor]])
+			}
+
+		case key.Matches(msg, keys.Lock):
+			if len(m.remove.visibleIndices) > 0 {
+				return m.startLock(m.remove.worktrees[m.remove.visibleIndices[m.remove.cursor]])
+			}
+
 		case key.Matches(msg, keys.Confirm):
 			if len(m.remove.selected) == 0 {
 				break
 			}
+			// Cached status can miss edits, so the selection is checked
+			// afresh; until that lands its rows are pending and stop here.
+			var recheck tea.Cmd
+			m, recheck = m.recheckCachedSelection()
 			// Safety gate: only at-risk selections need a confirmation stop;
 			// clean-and-pushed worktrees delete without friction.
 			for _, wt := range m.selectedWorktrees() {
-				if worktreeAtRisk(wt) {
+				if worktreeAtRisk(wt) || m.remove.pending[wt.Path] {
 					m.view = confirmView
-					return m, nil
+					return m, recheck
 				}
 			}
 			return m.beginRemoval()
@@ -317,6 +336,8 @@ func (m Model) viewList() string {
 	}
 	hdrBranch := "Branch"
 	hdrAge := "Age"
+	hdrSync := "Upstream"
+	hdrPR := "PR"
 	hdrSubject := "Subject"
 	switch m.remove.sortField {
 	case SortByBranch:
@@ -329,11 +350,24 @@ func (m Model) viewList() string {
 	// Width 0 (untested sizing) counts as wide.
 	showAge := m.width == 0 || m.width >= 56
 	showSubject := m.width == 0 || m.width >= 72
+	showSync := m.width == 0 || m.width >= 96
+	// PR state needs gh; the column appears once the lookups finish.
+	showPR := m.remove.prStates != nil && (m.width == 0 || m.width >= 108)
 
 	headers := []string{"", "", "", hdrBranch}
 	if showAge {
 		headers = append(headers, hdrAge)
 	}
+	syncCol := -1
+	if showSync {
+		syncCol = len(headers)
+		headers = append(headers, hdrSync)
+	}
+	prCol := -1
+	if showPR {
+		prCol = len(headers)
+		headers = append(headers, hdrPR)
+	}
 	if showSubject {
 		headers = append(headers, hdrSubject)
 	}
@@ -353,6 +387,12 @@ func (m Model) viewList() string {
 	if showAge {
 		fixedWidth += colWidthAge
 	}
+	if showSync {
+		fixedWidth += colWidthSync
+	}
+	if showPR {
+		fixedWidth += colWidthPR
+	}
 	colPadding := 3
 	remaining := max(m.width-fixedWidth-colPadding, 20)
 	branchWidth := remaining
@@ -371,7 +411,7 @@ func (m Model) viewList() string {
 		}
 
 		var checkbox string
-		if git.IsProtectedBranchWith(wt.Branch, m.remove.defaultBranch) {
+		if m.remove.protection.IsProtected(wt.Branch) {
 			checkbox = styleStatusProtected.Render("[P]")
 		} else if m.remove.selected[wt.Path] {
 			checkbox = "[x]"
@@ -380,6 +420,11 @@ func (m Model) viewList() string {
 		}
 
 		status := statusIndicator(wt)
+		if m.remove.pending[wt.Path] && !wt.IsLocked && !wt.HasUncommittedChanges {
+			// Tracked changes are known after the first pass; untracked
+			// files are still being scanned.
+			status = styleDim.Render("[…]")
+		}
 
 		branch := worktreeLabel(wt)
 
@@ -390,12 +435,23 @@ func (m Model) viewList() string {
 			subject = wt.EnrichmentError
 		}
 
-		// One-line rows are law: cells truncate with …, never wrap.
-		branch = truncateWithEllipsis(branch, max(branchWidth-2, 4))
+		// One-line rows are law: cells truncate with …, never wrap. The
+		// merged marker keeps its place; the branch name gives way.
+		if m.remove.merged[wt.Path] {
+			branch = truncateWithEllipsis(branch, max(branchWidth-2-len(mergedSuffix), 4)) + styleDim.Render(mergedSuffix)
+		} else {
+			branch = truncateWithEllipsis(branch, max(branchWidth-2, 4))
+		}
 		row := []string{cursor, checkbox, status, branch}
 		if showAge {
 			row = append(row, age)
 		}
+		if showSync {
+			row = append(row, dryrun.AheadBehind(wt))
+		}
+		if showPR {
+			row = append(row, m.remove.prStates[wt.Path].Label())
+		}
 		if showSubject {
 			row = append(row, truncateWithEllipsis(subject, max(subjectWidth-2, 4)))
 		}
@@ -426,6 +482,10 @@ func (m Model) viewList() string {
 			return base.Width(branchWidth).Padding(0, 1)
 		case showAge && col == colAge:
 			return base.Width(colWidthAge).Padding(0, 1)
+		case col == syncCol:
+			return base.Width(colWidthSync).Padding(0, 1)
+		case col == prCol:
+			return base.Width(colWidthPR).Padding(0, 1)
 		case showSubject:
 			return base.Width(subjectWidth).Padding(0, 1)
 		}
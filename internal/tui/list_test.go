@@ -219,7 +219,7 @@ func TestSelectAll_SkipsNonStandardDefaultBranch(t *testing.T) {
 		{Path: "/work/feature", Branch: "refs/heads/feature/x"},
 	}
 	m := NewModel(wts, nil, "/repo")
-	m.remove.defaultBranch = "production" // set by loadWorktreeContext in production
+	m.remove.protection = git.NewProtectionPolicy("production", nil) // set by loadWorktreeContext in production
 
 	updated, _ := m.Update(keyMsg("a"))
 	m = updated.(Model)
@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/worktree"
+)
+
+// lockState holds the lock toggle for the highlighted worktree: a reason
+// prompt when it is unlocked, a confirmation when it is locked.
+type lockState struct {
+	worktree      git.Worktree
+	reason        textinput.Model
+	validationErr string
+	busy          bool
+}
+
+type lockDoneMsg struct{ err error }
+
+// startLock opens the lock view for wt.
+func (m Model) startLock(wt git.Worktree) (tea.Model, tea.Cmd) {
+	reason := textinput.New()
+	reason.Placeholder = "optional"
+	reason.SetWidth(formInputWidth)
+
+	m.lock = lockState{worktree: wt, reason: reason}
+	m.view = lockView
+	if wt.IsLocked {
+		return m, nil
+	}
+	return m, m.lock.reason.Focus()
+}
+
+// toggleLock locks an unlocked worktree with reason, or unlocks a locked one.
+func toggleLock(runner git.CommandRunner, repoPath string, wt git.Worktree, reason string) tea.Cmd {
+	return func() tea.Msg {
+		if wt.IsLocked {
+			return lockDoneMsg{err: worktree.UnlockWorktree(runner, repoPath, wt.Path)}
+		}
+		return lockDoneMsg{err: worktree.LockWorktree(runner, repoPath, wt.Path, reason)}
+	}
+}
+
+func (m Model) updateLock(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case lockDoneMsg:
+		m.lock.busy = false
+		if msg.err != nil {
+			m.lock.validationErr = msg.err.Error()
+			return m, nil
+		}
+		// Reload so the row's [L] and the lock reason follow.
+		m.view = listView
+		m.worktreeGeneration++
+		return m, loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration, !m.noEnrichCache)
+
+	case tea.KeyPressMsg:
+		if m.lock.busy {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, keys.Back):
+			m.view = listView
+			return m, nil
+
+		case key.Matches(msg, keys.Confirm):
+			m.lock.validationErr = ""
+			m.lock.busy = true
+			reason := strings.TrimSpace(m.lock.reason.Value())
+			return m, toggleLock(m.runner, m.repoPath, m.lock.worktree, reason)
+		}
+	}
+
+	if m.lock.worktree.IsLocked {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.lock.reason, cmd = m.lock.reason.Update(msg)
+	return m, cmd
+}
+
+func (m Model) viewLock() string {
+	var b strings.Builder
+	wt := m.lock.worktree
+
+	title, footer := titleLockWorktree, lockFooter
+	if wt.IsLocked {
+		title, footer = titleUnlockWorktree, unlockFooter
+	}
+	b.WriteString(viewTitle(title))
+	b.WriteString("\n\n")
+	b.WriteString(styleDim.Render("  " + wt.Path))
+	b.WriteString("\n\n")
+	b.WriteString(viewSeparator(m.width))
+	b.WriteString("\n\n")
+
+	if wt.IsLocked {
+		reason := wt.LockReason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		fmt.Fprintf(&b, "  %s Locked: %s\n", styleStatusLocked.Render("[L]"), reason)
+		b.WriteString(styleDim.Render("  Unlocking lets remove's filters select it again."))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(inputFieldLabel("Reason", true))
+		b.WriteString("  " + m.lock.reason.View())
+		b.WriteString("\n")
+		b.WriteString(styleDim.Render("  git will refuse to prune, move or remove it until it is unlocked."))
+		b.WriteString("\n")
+	}
+
+	if m.lock.validationErr != "" {
+		b.WriteString("\n  " + styleError.Render(m.lock.validationErr))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(viewSeparator(m.width))
+	b.WriteString("\n\n")
+	b.WriteString(viewFooter(m.width, footer))
+	b.WriteString("\n")
+
+	return b.String()
+}
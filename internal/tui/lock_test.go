@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/abiswas97/sentei/internal/git"
+)
+
+func TestLock_PromptsForReasonThenReloads(t *testing.T) {
+	runner := &stubRunner{responses: map[string]stubResponse{
+		"/repo worktree lock --reason on a usb drive /work/feature": {},
+	}}
+	m := NewModel([]git.Worktree{{Path: "/work/feature", Branch: "refs/heads/feature"}}, runner, "/repo")
+	m.width, m.height = 80, 24
+
+	updated, _ := m.Update(keyMsg("l"))
+	m = updated.(Model)
+	if m.view != lockView {
+		t.Fatalf("view = %v, want lockView", m.view)
+	}
+	if out := stripAnsi(m.viewLock()); !strings.Contains(out, "Reason") {
+		t.Fatalf("no reason prompt:\n%s", out)
+	}
+
+	m.lock.reason.SetValue("on a usb drive")
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+	updated, reload := m.Update(cmd())
+	m = updated.(Model)
+	if m.view != listView || reload == nil {
+		t.Fatalf("view = %v, reload = %v; want the list reloading", m.view, reload != nil)
+	}
+}
+
+func TestLock_UnlockFailureStaysOnView(t *testing.T) {
+	runner := &stubRunner{responses: map[string]stubResponse{
+		"/repo worktree unlock /work/feature": {err: errors.New("permission denied")},
+	}}
+	wts := []git.Worktree{{Path: "/work/feature", Branch: "refs/heads/feature", IsLocked: true, LockReason: "on a usb drive"}}
+	m := NewModel(wts, runner, "/repo")
+	m.width, m.height = 80, 24
+
+	updated, _ := m.Update(keyMsg("l"))
+	m = updated.(Model)
+	if out := stripAnsi(m.viewLock()); !strings.Contains(out, "Locked: on a usb drive") {
+		t.Fatalf("lock reason not shown:\n%s", out)
+	}
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if m.view != lockView || !strings.Contains(m.lock.validationErr, "permission denied") {
+		t.Fatalf("view = %v, err = %q", m.view, m.lock.validationErr)
+	}
+}
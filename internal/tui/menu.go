@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 
 	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/pr"
 	"github.com/abiswas97/sentei/internal/repo"
 	"github.com/abiswas97/sentei/internal/worktree"
 )
@@ -18,26 +20,181 @@ type worktreeContextMsg struct {
 	defaultBranch string
 	err           error
 	generation    uint64
+	// enrichment streams the status passes for worktrees, in order; nil
+	// when there is nothing to enrich. It is closed once every row settles.
+	enrichment <-chan worktree.Update
 }
 
-func loadWorktreeContext(runner git.CommandRunner, repoPath string, generation uint64) tea.Cmd {
+// enrichmentUpdateMsg carries one status update for the worktree list.
+type enrichmentUpdateMsg struct {
+	update     worktree.Update
+	enrichment <-chan worktree.Update
+	generation uint64
+}
+
+// loadWorktreeContext lists the worktrees and reads everything that lives in
+// refs before returning, so rows arrive with branch, age and subject. The
+// per-worktree status checks run after, streamed through the message's
+// enrichment channel so rows fill in as they finish. With useCache, rows
+// unchanged since the last load are answered from the enrichment cache.
+func loadWorktreeContext(runner git.CommandRunner, repoPath string, generation uint64, useCache bool) tea.Cmd {
 	return func() tea.Msg {
 		wts, err := git.ListWorktrees(runner, repoPath)
 		if err != nil {
 			return worktreeContextMsg{err: err, generation: generation}
 		}
-		wts = worktree.EnrichWorktrees(runner, wts, worktree.DefaultEnrichConcurrency)
+		wts = worktree.EnrichRefs(runner, wts)
 		var filtered []git.Worktree
 		for _, wt := range wts {
 			if !wt.IsBare && !wt.IsPrunable {
 				filtered = append(filtered, wt)
 			}
 		}
-		return worktreeContextMsg{
+		msg := worktreeContextMsg{
 			worktrees:     filtered,
 			defaultBranch: git.DetectDefaultBranch(runner, repoPath),
 			generation:    generation,
 		}
+		if len(filtered) == 0 {
+			return msg
+		}
+
+		// Buffered for both passes of every worktree, so the goroutine
+		// finishes even if the model stops listening.
+		ch := make(chan worktree.Update, 2*len(filtered))
+		pending := append([]git.Worktree(nil), filtered...)
+		go func() {
+			defer close(ch)
+			var cache *worktree.Cache
+			if useCache {
+				cache = loadEnrichCache(runner, repoPath)
+			}
+			pending = worktree.EnrichStatus(runner, pending, worktree.DefaultEnrichConcurrency, cache, func(u worktree.Update) {
+				ch <- u
+			})
+			if cache != nil {
+				// A cache that cannot be written only costs the next load
+				// its head start.
+				_ = cache.Save(pending)
+			}
+		}()
+		msg.enrichment = ch
+		return msg
+	}
+}
+
+// prStatesMsg carries pull request state by worktree path, or nil states
+// when gh could not answer.
+type prStatesMsg struct {
+	states     map[string]pr.State
+	generation uint64
+}
+
+// prLookupConcurrency bounds parallel gh calls; each is a network round trip
+// subject to API rate limits.
+const prLookupConcurrency = 4
+
+// loadPRStates looks up the pull request state of every worktree's branch.
+// Detached worktrees and the default branch have none. The first gh failure
+// abandons the lookups and the PR column stays hidden.
+func loadPRStates(gh repo.GhRunner, repoPath string, worktrees []git.Worktree, defaultBranch string, generation uint64) tea.Cmd {
+	return func() tea.Msg {
+		checker := pr.NewChecker(gh, repoPath)
+		states := make(map[string]pr.State, len(worktrees))
+		var mu sync.Mutex
+		sem := make(chan struct{}, prLookupConcurrency)
+		var wg sync.WaitGroup
+		for _, wt := range worktrees {
+			branch := stripBranchPrefix(wt.Branch)
+			if branch == "" || branch == defaultBranch {
+				continue
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(path, branch string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if s, err := checker.State(branch); err == nil {
+					mu.Lock()
+					states[path] = s
+					mu.Unlock()
+				}
+			}(wt.Path, branch)
+		}
+		wg.Wait()
+		if checker.Err() != nil {
+			return prStatesMsg{generation: generation}
+		}
+		return prStatesMsg{states: states, generation: generation}
+	}
+}
+
+// mergedStatesMsg carries, by worktree path, which branches are merged into
+// the default branch.
+type mergedStatesMsg struct {
+	merged     map[string]bool
+	generation uint64
+}
+
+// mergeCheckConcurrency bounds parallel merge detection; each check is up to
+// five local git commands.
+const mergeCheckConcurrency = 4
+
+// loadMergedStates runs the shared merge detector (ancestry, rebase and squash
+// merges) for every worktree's branch against the default branch. Detached
+// worktrees and the default branch itself are never merged.
+func loadMergedStates(runner git.CommandRunner, repoPath string, worktrees []git.Worktree, defaultBranch string, generation uint64) tea.Cmd {
+	return func() tea.Msg {
+		merged := make(map[string]bool, len(worktrees))
+		var mu sync.Mutex
+		sem := make(chan struct{}, mergeCheckConcurrency)
+		var wg sync.WaitGroup
+		for _, wt := range worktrees {
+			branch := stripBranchPrefix(wt.Branch)
+			if branch == "" || branch == defaultBranch {
+				continue
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(path, branch string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if git.MergedInto(runner, repoPath, branch, defaultBranch).Merged() {
+					mu.Lock()
+					merged[path] = true
+					mu.Unlock()
+				}
+			}(wt.Path, branch)
+		}
+		wg.Wait()
+		return mergedStatesMsg{merged: merged, generation: generation}
+	}
+}
+
+// loadEnrichCache opens the repository's enrichment cache, or returns nil
+// when the common dir cannot be resolved. An unreadable cache file starts
+// over empty.
+func loadEnrichCache(runner git.CommandRunner, repoPath string) *worktree.Cache {
+	commonDir, err := git.CommonDir(runner, repoPath)
+	if err != nil {
+		return nil
+	}
+	cache, _ := worktree.LoadCache(commonDir)
+	return cache
+}
+
+// waitForEnrichment delivers the next status update, or nothing once the
+// channel closes.
+func waitForEnrichment(ch <-chan worktree.Update, generation uint64) tea.Cmd {
+	if ch == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		u, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return enrichmentUpdateMsg{update: u, enrichment: ch, generation: generation}
 	}
 }
 
@@ -88,7 +245,7 @@ func (m Model) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.remove.selected = make(map[string]bool)
 					if len(m.remove.worktrees) == 0 {
 						m.worktreeGeneration++
-						return m, loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration)
+						return m, loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration, !m.noEnrichCache)
 					}
 				case "Cleanup & exit":
 					return m.startCleanupScan()
@@ -108,7 +265,12 @@ func (m Model) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, m.repo.urlInput.Focus()
 				case "Migrate to bare repository":
 					m.view = migrateConfirmView
-					return m, loadMigrateInfo(m.runner, m.repoPath)
+					return m, tea.Batch(loadMigrateInfo(m.runner, m.repoPath), loadMigrateInventory(m.runner, m.repoPath))
+				case "Convert to regular repository":
+					m.repo.unmigrateInfo = UnmigrateInfo{}
+					m.repo.validationErr = ""
+					m.view = unmigrateConfirmView
+					return m, loadUnmigrateInfo(m.runner, m.repoPath)
 				}
 			}
 		}
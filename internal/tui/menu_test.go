@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	tea "charm.land/bubbletea/v2"
@@ -9,7 +10,10 @@ import (
 	"github.com/abiswas97/sentei/internal/cleanup"
 	"github.com/abiswas97/sentei/internal/config"
 	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/pr"
 	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/testutil/mock"
+	"github.com/abiswas97/sentei/internal/worktree"
 )
 
 func TestLoadWorktreeContext_IncludesGeneration(t *testing.T) {
@@ -20,7 +24,7 @@ func TestLoadWorktreeContext_IncludesGeneration(t *testing.T) {
 	}
 
 	var generation uint64 = 42
-	cmd := loadWorktreeContext(runner, "/repo", generation)
+	cmd := loadWorktreeContext(runner, "/repo", generation, false)
 	msg := cmd()
 
 	ctx, ok := msg.(worktreeContextMsg)
@@ -38,7 +42,7 @@ func TestLoadWorktreeContext_IncludesGenerationOnError(t *testing.T) {
 	}
 
 	var generation uint64 = 7
-	cmd := loadWorktreeContext(runner, "/repo", generation)
+	cmd := loadWorktreeContext(runner, "/repo", generation, false)
 	msg := cmd()
 
 	ctx, ok := msg.(worktreeContextMsg)
@@ -76,6 +80,34 @@ func TestGlobalHandler_AppliesWorktreesOnMatchingGeneration(t *testing.T) {
 	}
 }
 
+func TestGlobalHandler_AppliesConfiguredProtection(t *testing.T) {
+	cfg := &config.Config{ProtectedBranches: []string{"release/*"}}
+	m := NewMenuModel(nil, nil, "/repo", cfg, repo.ContextBareRepo)
+	m.worktreeGeneration = 1
+
+	updated, _ := m.Update(worktreeContextMsg{
+		worktrees: []git.Worktree{
+			{Path: "/repo/release-1.0", Branch: "refs/heads/release/1.0"},
+			{Path: "/repo/trunk", Branch: "refs/heads/trunk"},
+			{Path: "/repo/feat", Branch: "refs/heads/feat"},
+		},
+		defaultBranch: "trunk",
+		generation:    1,
+	})
+	m = updated.(Model)
+	m.view = listView
+
+	updated, _ = m.Update(keyMsg("a"))
+	m = updated.(Model)
+
+	if m.remove.selected["/repo/release-1.0"] || m.remove.selected["/repo/trunk"] {
+		t.Errorf("configured patterns and the default branch must be protected, selected: %v", m.remove.selected)
+	}
+	if !m.remove.selected["/repo/feat"] {
+		t.Error("the unprotected worktree should be selectable")
+	}
+}
+
 func TestGlobalHandler_DiscardsOnGenerationMismatch(t *testing.T) {
 	m := NewMenuModel(nil, nil, "/repo", &config.Config{}, repo.ContextBareRepo)
 	m.worktreeGeneration = 5
@@ -94,6 +126,179 @@ func TestGlobalHandler_DiscardsOnGenerationMismatch(t *testing.T) {
 	}
 }
 
+func TestGlobalHandler_StreamsEnrichmentUpdates(t *testing.T) {
+	m := NewMenuModel(nil, nil, "/repo", &config.Config{}, repo.ContextBareRepo)
+	m.worktreeGeneration = 2
+
+	ch := make(chan worktree.Update, 2)
+	updated, cmd := m.Update(worktreeContextMsg{
+		worktrees:  []git.Worktree{{Path: "/repo/feat-a", Branch: "refs/heads/feat-a"}},
+		generation: 2,
+		enrichment: ch,
+	})
+	m = updated.(Model)
+	if !m.remove.pending["/repo/feat-a"] {
+		t.Fatal("row should be pending until its status arrives")
+	}
+	if cmd == nil {
+		t.Fatal("expected a command waiting for enrichment")
+	}
+
+	ch <- worktree.Update{Worktree: git.Worktree{Path: "/repo/feat-a", Branch: "refs/heads/feat-a", HasUncommittedChanges: true}}
+	updated, cmd = m.Update(cmd())
+	m = updated.(Model)
+	if !m.remove.worktrees[0].HasUncommittedChanges || !m.remove.pending["/repo/feat-a"] {
+		t.Errorf("first pass should apply but leave the row pending, got %+v", m.remove.worktrees[0])
+	}
+
+	ch <- worktree.Update{Worktree: git.Worktree{Path: "/repo/feat-a", Branch: "refs/heads/feat-a", HasUncommittedChanges: true, IsEnriched: true}, Final: true}
+	close(ch)
+	updated, cmd = m.Update(cmd())
+	m = updated.(Model)
+	if m.remove.pending["/repo/feat-a"] || !m.remove.worktrees[0].IsEnriched {
+		t.Errorf("final update should settle the row, got %+v", m.remove.worktrees[0])
+	}
+	if msg := cmd(); msg != nil {
+		t.Errorf("closed channel should end the stream, got %T", msg)
+	}
+}
+
+func TestGlobalHandler_DiscardsStaleEnrichment(t *testing.T) {
+	m := NewMenuModel(nil, nil, "/repo", &config.Config{}, repo.ContextBareRepo)
+	m.worktreeGeneration = 4
+	m.remove.worktrees = []git.Worktree{{Path: "/repo/feat-a"}}
+
+	updated, cmd := m.Update(enrichmentUpdateMsg{
+		update:     worktree.Update{Worktree: git.Worktree{Path: "/repo/feat-a", HasUntrackedFiles: true}, Final: true},
+		generation: 3,
+	})
+	if cmd != nil {
+		t.Error("stale stream should stop")
+	}
+	if updated.(Model).remove.worktrees[0].HasUntrackedFiles {
+		t.Error("stale update should not apply")
+	}
+}
+
+func TestLoadPRStates(t *testing.T) {
+	wts := []git.Worktree{
+		{Path: "/repo/main", Branch: "refs/heads/main"},
+		{Path: "/repo/feat-a", Branch: "refs/heads/feat-a"},
+		{Path: "/repo/detached", IsDetached: true},
+	}
+	gh := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:gh[pr list --head feat-a --state all --json state,isCrossRepository]": {Output: `[{"state":"MERGED"}]`},
+	}}
+
+	msg := loadPRStates(gh, "/repo", wts, "main", 3)().(prStatesMsg)
+	if msg.generation != 3 || msg.states["/repo/feat-a"] != pr.StateMerged {
+		t.Errorf("msg = %+v", msg)
+	}
+	if len(gh.Calls) != 1 {
+		t.Errorf("only feat-a needs a lookup, got %v", gh.Calls)
+	}
+
+	missing := loadPRStates(&mock.Runner{}, "/repo", wts, "main", 3)().(prStatesMsg)
+	if missing.states != nil {
+		t.Error("without gh the PR column should stay hidden")
+	}
+}
+
+func TestLoadMergedStates(t *testing.T) {
+	wts := []git.Worktree{
+		{Path: "/repo/main", Branch: "refs/heads/main"},
+		{Path: "/repo/feat-a", Branch: "refs/heads/feat-a"},
+		{Path: "/repo/feat-b", Branch: "refs/heads/feat-b"},
+		{Path: "/repo/detached", IsDetached: true},
+	}
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo:[merge-base --is-ancestor feat-a main]": {},
+	}}
+
+	msg := loadMergedStates(runner, "/repo", wts, "main", 3)().(mergedStatesMsg)
+	if msg.generation != 3 || !msg.merged["/repo/feat-a"] || len(msg.merged) != 1 {
+		t.Errorf("only feat-a is merged, got %+v", msg)
+	}
+	for _, call := range runner.Calls {
+		if strings.Contains(call, " main main") || strings.Contains(call, "detached") {
+			t.Errorf("the default branch and detached worktrees need no check, ran %s", call)
+		}
+	}
+}
+
+func TestGlobalHandler_RequestsPRStatesWithGh(t *testing.T) {
+	m := NewMenuModel(nil, nil, "/repo", &config.Config{}, repo.ContextBareRepo, WithGhRunner(&mock.Runner{}))
+	m.worktreeGeneration = 1
+
+	_, cmd := m.Update(worktreeContextMsg{
+		worktrees:  []git.Worktree{{Path: "/repo/feat-a", Branch: "refs/heads/feat-a"}},
+		generation: 1,
+	})
+	if cmd == nil {
+		t.Fatal("expected a pull request lookup")
+	}
+	if _, ok := cmd().(prStatesMsg); !ok {
+		t.Error("the only command should be the pull request lookup")
+	}
+
+	updated, _ := m.Update(prStatesMsg{states: map[string]pr.State{"/repo/feat-a": pr.StateOpen}, generation: 0})
+	if updated.(Model).remove.prStates != nil {
+		t.Error("stale pull request states should be discarded")
+	}
+}
+
+func TestConfirm_WaitsForPendingStatus(t *testing.T) {
+	m := NewMenuModel(nil, nil, "/repo", &config.Config{}, repo.ContextBareRepo)
+	m.remove.worktrees = []git.Worktree{{Path: "/repo/feat-a", Branch: "refs/heads/feat-a"}}
+	m.remove.selected = map[string]bool{"/repo/feat-a": true}
+	m.remove.pending = map[string]bool{"/repo/feat-a": true}
+	m.reindex()
+	m.view = listView
+
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+	if m.view != confirmView {
+		t.Fatalf("a pending selection should stop at the confirmation, got view %d", m.view)
+	}
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if updated.(Model).view != confirmView {
+		t.Error("confirming should wait until the status checks finish")
+	}
+}
+
+func TestConfirm_RechecksCachedStatus(t *testing.T) {
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/repo/feat-a:[status --porcelain --untracked-files=no]":                               {Output: " M main.go"},
+		"/repo/feat-a:[ls-files --others --exclude-standard --directory --no-empty-directory]": {},
+	}}
+	m := NewMenuModel(runner, nil, "/repo", &config.Config{}, repo.ContextBareRepo)
+	m.worktreeGeneration = 1
+	updated, _ := m.Update(worktreeContextMsg{
+		worktrees:  []git.Worktree{{Path: "/repo/feat-a", Branch: "refs/heads/feat-a"}},
+		generation: 1,
+	})
+	m = updated.(Model)
+	// The cache remembers the worktree as clean, but a file changed since.
+	updated, _ = m.Update(enrichmentUpdateMsg{
+		update:     worktree.Update{Worktree: git.Worktree{Path: "/repo/feat-a", Branch: "refs/heads/feat-a", IsEnriched: true}, Final: true, Cached: true},
+		generation: 1,
+	})
+	m = updated.(Model)
+	m.remove.selected = map[string]bool{"/repo/feat-a": true}
+	m.view = listView
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+	if m.view != confirmView || !m.remove.pending["/repo/feat-a"] || cmd == nil {
+		t.Fatalf("a cached selection should be checked afresh before removal, view %d", m.view)
+	}
+	m = pumpCmds(m, cmd).(Model)
+	if m.remove.pending["/repo/feat-a"] || !m.remove.worktrees[0].HasUncommittedChanges {
+		t.Fatalf("fresh status not applied: %+v", m.remove.worktrees[0])
+	}
+}
+
 func TestEmptyListReload_IncrementsGeneration(t *testing.T) {
 	m := NewMenuModel(nil, nil, "/repo", &config.Config{}, repo.ContextBareRepo)
 	m.view = menuView
@@ -33,22 +33,44 @@ func loadMigrateInfo(runner git.CommandRunner, repoPath string) tea.Cmd {
 	}
 }
 
+type migrateInventoryMsg struct {
+	inventory repo.MigrateInventory
+	err       error
+}
+
+func loadMigrateInventory(runner git.CommandRunner, repoPath string) tea.Cmd {
+	return func() tea.Msg {
+		inventory, err := repo.InventoryMigration(runner, repoPath)
+		return migrateInventoryMsg{inventory: inventory, err: err}
+	}
+}
+
 func (m Model) updateMigrateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case migrateInfoMsg:
 		if msg.err != nil {
 			m.repo.validationErr = fmt.Sprintf("failed to load repo info: %v", msg.err)
 		} else {
-			m.repo.migrateInfo = MigrateInfo{
-				Branch:  msg.branch,
-				IsDirty: msg.isDirty,
-			}
+			m.repo.migrateInfo.Branch = msg.branch
+			m.repo.migrateInfo.IsDirty = msg.isDirty
+		}
+		return m, nil
+
+	case migrateInventoryMsg:
+		if msg.err != nil {
+			m.repo.validationErr = fmt.Sprintf("failed to inventory repo: %v", msg.err)
+		} else {
+			m.repo.migrateInfo.Inventory = &msg.inventory
 		}
 		return m, nil
 
 	case ConfirmProceedMsg:
+		if m.repo.migrateInfo.Inventory == nil {
+			return m, nil
+		}
 		opts := repo.MigrateOptions{
-			RepoPath: m.repoPath,
+			RepoPath:  m.repoPath,
+			Inventory: *m.repo.migrateInfo.Inventory,
 		}
 		m.repo.events = nil
 		m.repo.result = nil
@@ -85,17 +107,67 @@ func (m Model) migrateConfirmationVM() ConfirmationViewModel {
 		flags["delete-backup"] = "true"
 	}
 
+	items := []ConfirmationItem{
+		{Label: "Repo path:", Value: m.repoPath},
+		{Label: "Delete backup:", Value: deleteBackup},
+	}
+	switch {
+	case m.repo.validationErr != "":
+		items = append(items, ConfirmationItem{Label: "Inventory:", Value: m.repo.validationErr})
+	case m.repo.migrateInfo.Inventory == nil:
+		items = append(items, ConfirmationItem{Label: "Inventory:", Value: "reading\u2026"})
+	default:
+		for _, item := range migrateInventoryItems(*m.repo.migrateInfo.Inventory) {
+			items = append(items, ConfirmationItem{Label: item.Label + ":", Value: item.Value})
+		}
+	}
+
 	return ConfirmationViewModel{
-		Width: m.width,
-		Title: titleConfirmMigration,
-		Items: []ConfirmationItem{
-			{Label: "Repo path:", Value: m.repoPath},
-			{Label: "Delete backup:", Value: deleteBackup},
-		},
+		Width:      m.width,
+		Title:      titleConfirmMigration,
+		Items:      items,
 		CLICommand: BuildCLICommand("migrate", flags),
 	}
 }
 
+const labelLeftBehind = "Left behind"
+
+// migrateInventoryItems describes what the migration carries over beyond
+// the current branch, and what it leaves behind.
+func migrateInventoryItems(inv repo.MigrateInventory) []ConfirmationItem {
+	var items []ConfirmationItem
+	if len(inv.Remotes) > 0 {
+		names := make([]string, len(inv.Remotes))
+		for i, remote := range inv.Remotes {
+			names[i] = remote.Name
+		}
+		items = append(items, ConfirmationItem{Label: "Remotes", Value: strings.Join(names, ", ")})
+	}
+	if keys := inv.ConfigKeys(); len(keys) > 0 {
+		items = append(items, ConfirmationItem{Label: "Local config", Value: strings.Join(keys, ", ")})
+	}
+	if len(inv.Hooks) > 0 {
+		items = append(items, ConfirmationItem{Label: "Hooks", Value: strings.Join(inv.Hooks, ", ")})
+	}
+	if inv.Exclude > 0 {
+		items = append(items, ConfirmationItem{Label: "info/exclude", Value: fmt.Sprintf("%d patterns", inv.Exclude)})
+	}
+	if n := len(inv.Stashes); n > 0 {
+		items = append(items, ConfirmationItem{Label: "Stashes", Value: fmt.Sprintf("%d, kept as refs/stashes/0..%d", n, n-1)})
+	}
+	if len(inv.Worktrees) > 0 {
+		paths := make([]string, len(inv.Worktrees))
+		for i, wt := range inv.Worktrees {
+			paths[i] = wt.Path
+		}
+		items = append(items, ConfirmationItem{Label: "Worktrees", Value: strings.Join(paths, ", ")})
+	}
+	for _, item := range inv.Unmovable {
+		items = append(items, ConfirmationItem{Label: labelLeftBehind, Value: item})
+	}
+	return items
+}
+
 func (m Model) viewMigrateConfirm() string {
 	if m.migrateOpts != nil {
 		return m.migrateConfirmationVM().View()
@@ -135,6 +207,26 @@ func (m Model) viewMigrateConfirm() string {
 	fmt.Fprintf(&b, "    %s Convert to bare repository structure\n", styleDim.Render("\u25cf"))
 	fmt.Fprintf(&b, "    %s Create worktree for %s\n", styleDim.Render("\u25cf"),
 		filepath.Base(m.repoPath)+"/"+branch)
+	if inv := m.repo.migrateInfo.Inventory; inv != nil {
+		if len(inv.Worktrees) > 0 {
+			fmt.Fprintf(&b, "    %s Re-register %d linked worktrees in place\n", styleDim.Render("\u25cf"), len(inv.Worktrees))
+		}
+		heading := "\n  Carried over:\n"
+		for _, item := range migrateInventoryItems(*inv) {
+			if item.Label == labelLeftBehind {
+				continue
+			}
+			b.WriteString(heading)
+			heading = ""
+			fmt.Fprintf(&b, "    %-16s %s\n", styleDim.Render(item.Label), item.Value)
+		}
+		if len(inv.Unmovable) > 0 {
+			b.WriteString("\n")
+		}
+		for _, item := range inv.Unmovable {
+			fmt.Fprintf(&b, "  %s\n", styleIndicatorWarning.Render(indicatorWarning+" Left behind: "+item))
+		}
+	}
 
 	if m.repo.migrateInfo.IsDirty {
 		b.WriteString("\n")
@@ -158,6 +158,45 @@ func TestUpdateMigrateConfirm_MigrateInfoUpdatesState(t *testing.T) {
 	}
 }
 
+func TestUpdateMigrateConfirm_ProceedWaitsForInventory(t *testing.T) {
+	m := makeMigrateConfirmModel(nil)
+	// Every git call fails, so the started migration stops at its origin lookup.
+	m.runner = &stubRunner{}
+
+	updated, cmd := m.updateMigrateConfirm(ConfirmProceedMsg{})
+	if result := updated.(Model); result.view != migrateConfirmView || cmd != nil {
+		t.Fatalf("proceeded before the inventory loaded: view=%d", result.view)
+	}
+
+	updated, _ = m.updateMigrateConfirm(migrateInventoryMsg{inventory: repo.MigrateInventory{Hooks: []string{"pre-commit"}}})
+	updated, _ = updated.(Model).updateMigrateConfirm(ConfirmProceedMsg{})
+	if result := updated.(Model); result.view != migrateProgressView {
+		t.Errorf("expected migrateProgressView once inventoried, got %d", result.view)
+	}
+}
+
+func TestMigrateConfirmView_ShowsInventory(t *testing.T) {
+	inventory := repo.MigrateInventory{
+		Remotes:   []repo.MigrateRemote{{Name: "origin"}, {Name: "upstream"}},
+		Stashes:   []repo.MigrateStash{{Commit: "a", Ref: "refs/stashes/0"}},
+		Worktrees: []repo.MigrateWorktree{{Path: "/elsewhere/side"}},
+		Unmovable: []string{"submodule repositories in .git/modules: git submodule update --init restores them"},
+	}
+	for name, opts := range map[string]*MigrateOpts{"menu": nil, "direct": {RepoPath: "/some/repo"}} {
+		t.Run(name, func(t *testing.T) {
+			m := makeMigrateConfirmModel(opts)
+			updated, _ := m.updateMigrateConfirm(migrateInventoryMsg{inventory: inventory})
+
+			output := stripAnsi(updated.(Model).viewMigrateConfirm())
+			for _, want := range []string{"origin, upstream", "refs/stashes/0", "/elsewhere/side", "Left behind", ".git/modules"} {
+				if !strings.Contains(output, want) {
+					t.Errorf("view missing %q:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
+
 func TestUpdateMigrateConfirm_WindowSizeMsg(t *testing.T) {
 	m := makeMigrateConfirmModel(nil)
 
@@ -19,9 +19,8 @@ type migrateIntegrationDetectedMsg struct {
 	detected     map[string]bool
 }
 
-func loadMigrateIntegrations(worktreePath string) tea.Cmd {
+func loadMigrateIntegrations(worktreePath string, all []integration.Integration) tea.Cmd {
 	return func() tea.Msg {
-		all := integration.All()
 		detected := integration.DetectAllPresent(worktreePath, all)
 		return migrateIntegrationDetectedMsg{
 			integrations: all,
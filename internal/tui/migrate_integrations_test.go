@@ -142,7 +142,7 @@ func TestLoadMigrateIntegrations_DetectsArtifactDirs(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	msg := loadMigrateIntegrations(dir)()
+	msg := loadMigrateIntegrations(dir, integration.All())()
 
 	detected, ok := msg.(migrateIntegrationDetectedMsg)
 	if !ok {
@@ -39,12 +39,12 @@ func (m Model) updateMigrateSummary(msg tea.Msg) (tea.Model, tea.Cmd) {
 				_ = repo.DeleteBackup(result.BackupPath)
 			}
 			m.view = migrateIntegrationsView
-			return m, loadMigrateIntegrations(m.migrateWorktreePath(result))
+			return m, loadMigrateIntegrations(m.migrateWorktreePath(result), m.integrations())
 
 		case key.Matches(msg, keys.No):
 			// Keep backup, show integration selection screen
 			m.view = migrateIntegrationsView
-			return m, loadMigrateIntegrations(m.migrateWorktreePath(result))
+			return m, loadMigrateIntegrations(m.migrateWorktreePath(result), m.integrations())
 
 		case key.Matches(msg, keys.Quit):
 			return m, tea.Quit
@@ -116,6 +116,22 @@ func (m Model) viewMigrateSummary() string {
 		}
 		fmt.Fprintf(&b, "    %-10s %s%s\n", styleDim.Render("Backup"), backupName, sizeHint)
 	}
+	inv := result.Inventory
+	if n := len(inv.Stashes); n > 0 {
+		fmt.Fprintf(&b, "    %-10s %d under refs/stashes  %s\n", styleDim.Render("Stashes"), n,
+			styleDim.Render("git stash apply "+inv.Stashes[0].Ref))
+	}
+	if n := len(inv.Worktrees); n > 0 {
+		fmt.Fprintf(&b, "    %-10s %d re-registered in place\n", styleDim.Render("Worktrees"), n)
+	}
+	if len(inv.Unmovable) > 0 {
+		b.WriteString("\n")
+		b.WriteString(styleIndicatorWarning.Render(fmt.Sprintf("  %s Not carried over:", indicatorWarning)))
+		b.WriteString("\n")
+		for _, item := range inv.Unmovable {
+			fmt.Fprintf(&b, "    %s\n", styleDim.Render(item))
+		}
+	}
 
 	b.WriteString("\n")
 	b.WriteString("  Delete backup?\n")
@@ -84,6 +84,24 @@ func TestViewMigrateSummary_Success_OffersDeleteBackup(t *testing.T) {
 	}
 }
 
+func TestViewMigrateSummary_Success_ListsCarryOver(t *testing.T) {
+	result := repo.MigrateResult{
+		BareRoot: "/repo/proj",
+		Branch:   "main",
+		Inventory: repo.MigrateInventory{
+			Stashes:   []repo.MigrateStash{{Commit: "a", Ref: "refs/stashes/0"}, {Commit: "b", Ref: "refs/stashes/1"}},
+			Worktrees: []repo.MigrateWorktree{{Path: "/elsewhere/side"}},
+			Unmovable: []string{"3 remote-tracking branches: git fetch --all restores them"},
+		},
+	}
+	out := stripAnsi(makeMigrateSummaryModel(result).viewMigrateSummary())
+	for _, want := range []string{"2 under refs/stashes", "git stash apply refs/stashes/0", "1 re-registered", "Not carried over", "remote-tracking branches"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+}
+
 func TestUpdateMigrateSummary_Failure_YIsInert(t *testing.T) {
 	result := repo.MigrateResult{
 		BareRoot: "/repo/proj",
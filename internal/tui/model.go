@@ -19,6 +19,7 @@ import (
 	"github.com/abiswas97/sentei/internal/creator"
 	"github.com/abiswas97/sentei/internal/git"
 	"github.com/abiswas97/sentei/internal/integration"
+	"github.com/abiswas97/sentei/internal/pr"
 	"github.com/abiswas97/sentei/internal/progress"
 	"github.com/abiswas97/sentei/internal/repo"
 )
@@ -61,6 +62,10 @@ const (
 	cleanupResultView
 	createConfirmView
 	cloneConfirmView
+	unmigrateConfirmView
+	unmigrateSummaryView
+	moveView
+	lockView
 )
 
 type SortField int
@@ -85,15 +90,29 @@ type MigrateOpts struct {
 	RepoPath     string
 }
 
+// UnmigrateOpts holds unmigrate options passed to the TUI from the CLI layer.
+// This mirrors cmd.UnmigrateOptions without creating a dependency on cmd.
+type UnmigrateOpts struct {
+	Worktree     string
+	DeleteBackup bool
+	RepoPath     string
+}
+
 // CreateOpts holds create options passed to the TUI from the CLI layer.
 // This mirrors cmd.CreateOptions without creating a dependency on cmd.
 type CreateOpts struct {
 	Branch     string
 	Base       string
+	Track      string
+	PR         int
 	Ecosystems []string
 	MergeBase  bool
 	CopyEnv    bool
-	RepoPath   string
+	Profile    string // already applied to the fields above
+	// Integrations to set up, from the profile; nil keeps the repo's
+	// enabled integrations.
+	Integrations []string
+	RepoPath     string
 }
 
 // CloneOpts holds clone options passed to the TUI from the CLI layer.
@@ -106,9 +125,13 @@ type CloneOpts struct {
 // removeState holds all state for the worktree removal flow.
 type removeState struct {
 	worktrees      []git.Worktree
-	defaultBranch  string // repo default branch; always protected from removal
+	protection     *git.ProtectionPolicy // built-in, default branch and configured patterns; nil until loaded
 	selected       map[string]bool
-	milestone      int // power of ten crossed by the last run, 0 if none
+	pending        map[string]bool     // paths whose status checks are still running
+	cached         map[string]bool     // paths whose status came from the enrichment cache
+	prStates       map[string]pr.State // pull request state by path; nil until loaded or when gh is unavailable
+	merged         map[string]bool     // paths whose branch is merged into the default branch
+	milestone      int                 // power of ten crossed by the last run, 0 if none
 	visibleIndices []int
 	cursor         int
 	offset         int
@@ -140,6 +163,9 @@ type createState struct {
 	baseInput     textinput.Model
 	focusedField  int // 0 = branch, 1 = base
 	validationErr string
+	source        createSource          // what baseInput names
+	remoteSource  *creator.RemoteSource // resolved from baseInput; nil for createFromBase or until resolved
+	resolving     bool
 
 	ecosystems             []config.EcosystemConfig
 	ecoEnabled             map[string]bool
@@ -147,6 +173,8 @@ type createState struct {
 	mergeBase              bool
 	copyEnvFiles           bool
 	optionsCursor          int
+	profile                string   // preset last applied; empty when none
+	integrations           []string // from the profile; nil sets up the repo's enabled integrations
 
 	eventCh  chan progress.Event
 	resultCh chan creator.Result
@@ -158,6 +186,18 @@ type createState struct {
 type MigrateInfo struct {
 	Branch  string
 	IsDirty bool
+	// Inventory is nil until loaded; migration waits for it so nothing it
+	// would carry over is dropped.
+	Inventory *repo.MigrateInventory
+}
+
+// UnmigrateInfo holds the worktrees an unmigration can check out at the
+// root, and which one is chosen.
+type UnmigrateInfo struct {
+	BareRoot   string
+	Candidates []git.Worktree
+	Cursor     int
+	Loaded     bool
 }
 
 // repoState holds all state for repo create/clone/migrate flows.
@@ -183,14 +223,15 @@ type repoState struct {
 	nameManuallyEdited bool
 
 	// Migrate fields
-	migrateInfo MigrateInfo
+	migrateInfo   MigrateInfo
+	unmigrateInfo UnmigrateInfo
 
 	// Shared progress/summary
 	eventCh  chan progress.Event
-	resultCh chan interface{} // receives CreateResult, CloneResult, or MigrateResult
+	resultCh chan interface{} // receives CreateResult, CloneResult, MigrateResult, UnmigrateResult or MoveResult
 	events   []progress.Event
 	result   interface{}
-	opType   string // "create", "clone", "migrate"
+	opType   string // "create", "clone", "migrate", "unmigrate", "move"
 }
 
 type integrationLifecycle uint8
@@ -222,6 +263,9 @@ type integrationState struct {
 
 	// Context: where to return after progress completes
 	returnView viewState //nolint:unused
+	// refreshing marks a run of the active integrations' refresh commands,
+	// which changes no integration state and so saves nothing.
+	refreshing bool
 
 	// Apply outcome stages remain distinct so summaries identify the failed contract.
 	prepareErr   error
@@ -244,7 +288,9 @@ type Model struct {
 
 	menuItems          []menuItem
 	menuCursor         int
-	worktreeGeneration uint64 // Monotonic token passed to loadWorktreeContext; global handler discards mismatched responses.
+	worktreeGeneration uint64        // Monotonic token passed to loadWorktreeContext; global handler discards mismatched responses.
+	noEnrichCache      bool          // --no-cache: check every worktree's status afresh
+	gh                 repo.GhRunner // pull request lookups for the PR column and create; nil disables them
 
 	cleanupOpts   *cleanup.Options
 	cleanupResult *cleanup.Result // standalone cleanup flow ("Cleanup & exit" / sentei cleanup)
@@ -259,11 +305,14 @@ type Model struct {
 	createOpts               *CreateOpts
 	cloneOpts                *CloneOpts
 	migrateOpts              *MigrateOpts
+	unmigrateOpts            *UnmigrateOpts
 
 	remove removeState
 	create createState
 	repo   repoState
 	integ  integrationState
+	move   moveState
+	lock   lockState
 	portal DetailPortal
 
 	// motionTick is the one animation clock: star frames and shimmer band
@@ -307,6 +356,23 @@ func WithMinProgressDuration(d time.Duration) ModelOption {
 	}
 }
 
+// WithoutEnrichCache makes worktree loads check every worktree's status
+// afresh instead of reusing the enrichment cache (sentei --no-cache).
+func WithoutEnrichCache() ModelOption {
+	return func(m *Model) {
+		m.noEnrichCache = true
+	}
+}
+
+// WithGhRunner enables the list's PR column, looking up each branch's pull
+// request state through gh after the worktrees load, and lets the create
+// flow start from a pull request.
+func WithGhRunner(gh repo.GhRunner) ModelOption {
+	return func(m *Model) {
+		m.gh = gh
+	}
+}
+
 func NewModel(worktrees []git.Worktree, runner git.CommandRunner, repoPath string) Model {
 	ti := textinput.New()
 	ti.Prompt = "filter: "
@@ -375,6 +441,7 @@ func NewMenuModel(runner git.CommandRunner, shell git.ShellRunner, repoPath stri
 			{label: "Manage integrations", enabled: true},
 			{label: "Remove worktrees", hint: "loading\u2026", enabled: false, loading: true},
 			{label: "Cleanup & exit", hint: "prune refs, remove gone branches", enabled: true},
+			{label: "Convert to regular repository", enabled: true},
 		}
 	case repo.ContextNoRepo:
 		items = []menuItem{
@@ -564,12 +631,28 @@ func (m *Model) SetMigrateOpts(opts *MigrateOpts) {
 	m.view = migrateConfirmView
 }
 
+// SetUnmigrateOpts sets the unmigrate options and starts at the unmigrate
+// confirmation view.
+func (m *Model) SetUnmigrateOpts(opts *UnmigrateOpts) {
+	m.unmigrateOpts = opts
+	if opts.RepoPath != "" {
+		m.repoPath = opts.RepoPath
+	}
+	m.view = unmigrateConfirmView
+}
+
 func (m Model) Init() tea.Cmd {
+	if m.view == migrateConfirmView {
+		return tea.Batch(tea.RequestBackgroundColor, loadMigrateInfo(m.runner, m.repoPath), loadMigrateInventory(m.runner, m.repoPath))
+	}
+	if m.view == unmigrateConfirmView {
+		return tea.Batch(tea.RequestBackgroundColor, loadUnmigrateInfo(m.runner, m.repoPath))
+	}
 	if m.view == menuView && m.context == repo.ContextBareRepo {
 		if m.motionPreference == MotionOff {
-			return tea.Batch(tea.RequestBackgroundColor, loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration))
+			return tea.Batch(tea.RequestBackgroundColor, loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration, !m.noEnrichCache))
 		}
-		return tea.Batch(tea.RequestBackgroundColor, motionTickCmd(), loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration))
+		return tea.Batch(tea.RequestBackgroundColor, motionTickCmd(), loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration, !m.noEnrichCache))
 	}
 	return tea.RequestBackgroundColor
 }
@@ -622,13 +705,71 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if ctx, ok := msg.(worktreeContextMsg); ok {
 		if ctx.generation == m.worktreeGeneration && ctx.err == nil {
 			m.remove.worktrees = ctx.worktrees
-			m.remove.defaultBranch = ctx.defaultBranch
+			m.remove.protection = git.NewProtectionPolicy(ctx.defaultBranch, m.protectedPatterns())
+			m.remove.pending = make(map[string]bool, len(ctx.worktrees))
+			m.remove.cached = make(map[string]bool)
+			if ctx.enrichment != nil {
+				for _, wt := range ctx.worktrees {
+					m.remove.pending[wt.Path] = true
+				}
+			}
+			m.remove.prStates = nil
+			m.remove.merged = nil
 			m.reindex()
 			m.updateMenuHints()
+			cmd := waitForEnrichment(ctx.enrichment, ctx.generation)
+			if ctx.defaultBranch != "" && len(ctx.worktrees) > 0 {
+				cmd = tea.Batch(cmd, loadMergedStates(m.runner, m.repoPath, ctx.worktrees, ctx.defaultBranch, ctx.generation))
+			}
+			if m.gh != nil && len(ctx.worktrees) > 0 {
+				cmd = tea.Batch(cmd, loadPRStates(m.gh, m.repoPath, ctx.worktrees, ctx.defaultBranch, ctx.generation))
+			}
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	if ps, ok := msg.(prStatesMsg); ok {
+		if ps.generation == m.worktreeGeneration {
+			m.remove.prStates = ps.states
+		}
+		return m, nil
+	}
+
+	if ms, ok := msg.(mergedStatesMsg); ok {
+		if ms.generation == m.worktreeGeneration {
+			m.remove.merged = ms.merged
 		}
 		return m, nil
 	}
 
+	if eu, ok := msg.(enrichmentUpdateMsg); ok {
+		if eu.generation != m.worktreeGeneration {
+			return m, nil
+		}
+		// Rows are matched by path: the list may have been re-sorted or
+		// filtered since the load. Status never changes sort order, so
+		// there is no reindex.
+		for i := range m.remove.worktrees {
+			if m.remove.worktrees[i].Path == eu.update.Worktree.Path {
+				m.remove.worktrees[i] = eu.update.Worktree
+				break
+			}
+		}
+		if eu.update.Final {
+			delete(m.remove.pending, eu.update.Worktree.Path)
+		}
+		if eu.update.Cached {
+			if m.remove.cached == nil {
+				m.remove.cached = make(map[string]bool)
+			}
+			m.remove.cached[eu.update.Worktree.Path] = true
+		} else {
+			delete(m.remove.cached, eu.update.Worktree.Path)
+		}
+		return m, waitForEnrichment(eu.enrichment, eu.generation)
+	}
+
 	if size, ok := msg.(tea.WindowSizeMsg); ok {
 		// Sizing is global: the portal tracks the raw terminal size, views
 		// share the chrome-budgeted body height. No view sizes itself.
@@ -818,6 +959,14 @@ func (m Model) dispatchByView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateCreateConfirm(msg)
 	case cloneConfirmView:
 		return m.updateCloneConfirm(msg)
+	case unmigrateConfirmView:
+		return m.updateUnmigrateConfirm(msg)
+	case unmigrateSummaryView:
+		return m.updateUnmigrateSummary(msg)
+	case moveView:
+		return m.updateMove(msg)
+	case lockView:
+		return m.updateLock(msg)
 	}
 	return m, nil
 }
@@ -892,10 +1041,50 @@ func (m Model) viewContent() string {
 		return m.viewCreateConfirm()
 	case cloneConfirmView:
 		return m.viewCloneConfirm()
+	case unmigrateConfirmView:
+		return m.viewUnmigrateConfirm()
+	case unmigrateSummaryView:
+		return m.viewUnmigrateSummary()
+	case moveView:
+		return m.viewMove()
+	case lockView:
+		return m.viewLock()
 	}
 	return ""
 }
 
+// protectedPatterns returns the configured protected_branches, or nil when
+// no config was loaded.
+func (m Model) protectedPatterns() []string {
+	if m.cfg == nil {
+		return nil
+	}
+	return m.cfg.ProtectedBranches
+}
+
+// archiveBeforeRemove reports whether archive_before_remove is configured.
+func (m Model) archiveBeforeRemove() bool {
+	return m.cfg != nil && m.cfg.ArchivesEnabled()
+}
+
+// integrations returns the built-in integrations with those declared in the
+// config merged in.
+func (m Model) integrations() []integration.Integration {
+	if m.cfg == nil {
+		return integration.All()
+	}
+	return integration.Resolve(m.cfg.Integrations)
+}
+
+// archiveRetention returns how long cleanup keeps archives; without a
+// loaded config it is the default retention.
+func (m Model) archiveRetention() time.Duration {
+	if m.cfg == nil {
+		return (&config.Config{}).ArchiveRetention()
+	}
+	return m.cfg.ArchiveRetention()
+}
+
 func (m Model) selectedWorktrees() []git.Worktree {
 	var result []git.Worktree
 	for _, wt := range m.remove.worktrees {
@@ -985,6 +1174,12 @@ func (m Model) flowIdentity() (sentence, verb string) {
 		}
 		return "repository creation", "creating"
 	case migrateProgressView:
+		switch m.repo.opType {
+		case "unmigrate":
+			return "repository unmigration", "unmigrating"
+		case "move":
+			return "worktree move", "moving"
+		}
 		return "repository migration", "migrating"
 	case integrationProgressView:
 		return "integration apply", "applying"
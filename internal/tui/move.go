@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/integration"
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/state"
+	"github.com/abiswas97/sentei/internal/worktree"
+)
+
+// moveState holds the move flow, started from the list for the highlighted
+// worktree.
+type moveState struct {
+	worktree      git.Worktree
+	input         textinput.Model
+	validationErr string
+	preparing     bool
+}
+
+type movePreparedMsg struct {
+	prepared worktree.PreparedMove
+	err      error
+}
+
+// startMove opens the move view for wt, with its branch (or directory name
+// when detached) ready to edit.
+func (m Model) startMove(wt git.Worktree) (tea.Model, tea.Cmd) {
+	input := textinput.New()
+	input.Placeholder = "new-branch or ./path"
+	input.SetWidth(formInputWidth)
+	current := stripBranchPrefix(wt.Branch)
+	if current == "" {
+		current = "./" + filepath.Base(wt.Path)
+	}
+	input.SetValue(current)
+	input.CursorEnd()
+
+	m.move = moveState{worktree: wt, input: input}
+	m.view = moveView
+	return m, m.move.input.Focus()
+}
+
+// prepareMove validates the move off the update loop: it asks git about the
+// new branch and reads which integrations are enabled.
+func prepareMove(runner git.CommandRunner, repoPath string, wt git.Worktree, target string, all []integration.Integration) tea.Cmd {
+	return func() tea.Msg {
+		branch, path := worktree.SplitMoveTarget(target)
+		if path != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(repoPath, path)
+		}
+		var enabled []integration.Integration
+		if commonDir, err := git.CommonDir(runner, repoPath); err == nil {
+			if st, err := state.Load(commonDir); err == nil {
+				for _, integ := range all {
+					if st.HasIntegration(integ.Name) {
+						enabled = append(enabled, integ)
+					}
+				}
+			}
+		}
+		prepared, err := worktree.PrepareMove(runner, worktree.MoveOptions{
+			Worktree: wt, Branch: branch, Path: path,
+			RepoPath: repoPath, Integrations: enabled,
+		})
+		return movePreparedMsg{prepared: prepared, err: err}
+	}
+}
+
+func (m Model) updateMove(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case movePreparedMsg:
+		m.move.preparing = false
+		if msg.err != nil {
+			m.move.validationErr = msg.err.Error()
+			return m, nil
+		}
+		m.move.input.Blur()
+		m.repo.events = nil
+		m.repo.result = nil
+		m.repo.opType = "move"
+		m.progressStartedAt = time.Now()
+		m.progressToken++
+		m.view = migrateProgressView
+		return m, m.startRepoPipeline(msg.prepared)
+
+	case tea.KeyPressMsg:
+		if m.move.preparing {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, keys.Back):
+			m.view = listView
+			return m, nil
+
+		case key.Matches(msg, keys.Confirm):
+			target := strings.TrimSpace(m.move.input.Value())
+			if target == "" {
+				m.move.validationErr = "a branch name or path is required"
+				return m, nil
+			}
+			integrations := m.integrations()
+			if m.shell == nil {
+				integrations = nil
+			}
+			m.move.validationErr = ""
+			m.move.preparing = true
+			return m, prepareMove(m.runner, m.repoPath, m.move.worktree, target, integrations)
+		}
+	}
+
+	m.move.validationErr = ""
+	var cmd tea.Cmd
+	m.move.input, cmd = m.move.input.Update(msg)
+	return m, cmd
+}
+
+// moveFinished returns to the list after a clean move, reloading it so the
+// row shows the new branch and path. A failure stays on the move view with
+// the error, since the list would not show what went wrong.
+func (m Model) moveFinished(result worktree.MoveResult) (tea.Model, tea.Cmd) {
+	if !result.HasFailures() {
+		m.remove.selected = make(map[string]bool)
+		m.worktreeGeneration++
+		syncCmd := m.syncProgressBar()
+		updated, holdCmd := m.holdOrAdvance(listView)
+		return updated, tea.Batch(syncCmd, holdCmd, loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration, !m.noEnrichCache))
+	}
+	errMsg := "move failed"
+	if result.Err != nil {
+		errMsg = result.Err.Error()
+	} else if _, step, ok := progress.FirstFailure(result.Phases); ok && step.Error != nil {
+		errMsg = fmt.Sprintf("%s: %v", step.Name, step.Error)
+	}
+	m.move.validationErr = errMsg
+	syncCmd := m.syncProgressBar()
+	updated, holdCmd := m.holdOrAdvance(moveView)
+	return updated, tea.Batch(syncCmd, holdCmd)
+}
+
+func (m Model) viewMove() string {
+	var b strings.Builder
+
+	b.WriteString(viewTitle(titleMoveWorktree))
+	b.WriteString("\n\n")
+	b.WriteString(styleDim.Render("  " + m.move.worktree.Path))
+	b.WriteString("\n\n")
+	b.WriteString(viewSeparator(m.width))
+	b.WriteString("\n\n")
+
+	b.WriteString(inputFieldLabel("New branch or path", true))
+	b.WriteString("  " + m.move.input.View())
+	b.WriteString("\n")
+	b.WriteString(styleDim.Render("  A branch renames branch and directory; ./path moves only the directory."))
+	b.WriteString("\n")
+
+	switch {
+	case m.move.preparing:
+		b.WriteString("\n  " + styleDim.Render("checking\u2026"))
+		b.WriteString("\n")
+	case m.move.validationErr != "":
+		b.WriteString("\n  " + styleError.Render(m.move.validationErr))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(viewSeparator(m.width))
+	b.WriteString("\n\n")
+	b.WriteString(viewFooter(m.width, moveFooter))
+	b.WriteString("\n")
+
+	return b.String()
+}
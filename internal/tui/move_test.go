@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/abiswas97/sentei/internal/git"
+)
+
+func TestMove_OpensForHighlightedWorktree(t *testing.T) {
+	wts := []git.Worktree{
+		{Path: "/work/main", Branch: "refs/heads/main"},
+		{Path: "/work/feature", Branch: "refs/heads/feature/x"},
+	}
+	m := NewModel(wts, nil, "/repo")
+	m.remove.cursor = 1
+
+	updated, _ := m.Update(keyMsg("m"))
+	m = updated.(Model)
+	if m.view != moveView || m.move.worktree.Path != "/work/feature" {
+		t.Fatalf("view = %v, worktree = %s", m.view, m.move.worktree.Path)
+	}
+	if got := m.move.input.Value(); got != "feature/x" {
+		t.Fatalf("input = %q, want the current branch", got)
+	}
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if updated.(Model).view != listView {
+		t.Fatal("esc did not return to the list")
+	}
+}
+
+func TestMove_DetachedStartsWithDirectory(t *testing.T) {
+	m := NewModel([]git.Worktree{{Path: "/work/scratch", IsDetached: true}}, nil, "/repo")
+
+	updated, _ := m.Update(keyMsg("m"))
+	if got := updated.(Model).move.input.Value(); got != "./scratch" {
+		t.Fatalf("input = %q, want the directory", got)
+	}
+}
+
+func TestMove_RejectionStaysOnView(t *testing.T) {
+	runner := &stubRunner{responses: map[string]stubResponse{
+		"/repo rev-parse --git-common-dir":              {output: "/repo/.bare"},
+		"/work/feature rev-parse --git-common-dir":      {output: "/repo/.bare"},
+		"/repo/.bare check-ref-format --branch main":    {output: "main"},
+		"/repo/.bare show-ref --verify refs/heads/main": {},
+	}}
+	wts := []git.Worktree{{Path: "/work/feature", Branch: "refs/heads/feature"}}
+	m := NewModel(wts, runner, "/repo")
+	m.width, m.height = 80, 24
+
+	updated, _ := m.Update(keyMsg("m"))
+	m = updated.(Model)
+	m.move.input.SetValue("main")
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.move.preparing || cmd == nil {
+		t.Fatal("enter did not start preparing the move")
+	}
+
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if m.view != moveView || !strings.Contains(m.move.validationErr, `branch "main" already exists`) {
+		t.Fatalf("view = %v, err = %q", m.view, m.move.validationErr)
+	}
+	if out := stripAnsi(m.viewMove()); !strings.Contains(out, "already exists") {
+		t.Fatalf("error not shown:\n%s", out)
+	}
+}
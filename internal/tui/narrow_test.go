@@ -7,6 +7,7 @@ import (
 	"charm.land/lipgloss/v2"
 
 	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/pr"
 )
 
 func narrowListModel(width int) Model {
@@ -45,8 +46,16 @@ func TestList_OneLineRowsAtNarrowWidth(t *testing.T) {
 
 func TestList_ColumnPriorityDropsDetail(t *testing.T) {
 	wide := stripAnsi(narrowListModel(100).viewList())
-	if !strings.Contains(wide, "Subject") || !strings.Contains(wide, "Age") {
-		t.Fatal("wide layout must show Subject and Age")
+	if !strings.Contains(wide, "Subject") || !strings.Contains(wide, "Age") || !strings.Contains(wide, "Upstream") {
+		t.Fatal("wide layout must show Subject, Age and Upstream")
+	}
+
+	standard := stripAnsi(narrowListModel(80).viewList())
+	if strings.Contains(standard, "Upstream") {
+		t.Error("Upstream column must be dropped below 96 cols")
+	}
+	if !strings.Contains(standard, "Subject") {
+		t.Error("Subject column must survive at 80 cols")
 	}
 
 	mid := stripAnsi(narrowListModel(60).viewList())
@@ -66,6 +75,51 @@ func TestList_ColumnPriorityDropsDetail(t *testing.T) {
 	}
 }
 
+func TestList_PRColumnOnceStatesLoad(t *testing.T) {
+	m := narrowListModel(120)
+	if strings.Contains(stripAnsi(m.viewList()), " PR ") {
+		t.Fatal("PR column must stay hidden until pull request states load")
+	}
+
+	m.remove.prStates = map[string]pr.State{"/work/a": pr.StateMerged}
+	out := stripAnsi(m.viewList())
+	if !strings.Contains(out, "PR") || !strings.Contains(out, "merged") {
+		t.Errorf("PR column should show loaded states:\n%s", out)
+	}
+	for i, line := range strings.Split(out, "\n") {
+		if w := lipgloss.Width(line); w > 120 {
+			t.Errorf("line %d exceeds 120 cols (%d): %q", i, w, line)
+		}
+	}
+
+	m.width = 100
+	if strings.Contains(stripAnsi(m.viewList()), "merged") {
+		t.Error("PR column must be dropped below 108 cols")
+	}
+}
+
+func TestList_MergedMarkerSurvivesTruncation(t *testing.T) {
+	m := narrowListModel(60)
+	m.remove.merged = map[string]bool{"/work/a": true}
+	out := stripAnsi(m.viewList())
+
+	var row string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "feature/") {
+			row = line
+		}
+		if w := lipgloss.Width(line); w > 60 {
+			t.Errorf("line exceeds 60 cols (%d): %q", w, line)
+		}
+	}
+	if !strings.Contains(row, "… merged") {
+		t.Errorf("a truncated merged branch should keep its marker, got %q", row)
+	}
+	if strings.Contains(out, "cleanup merged") {
+		t.Error("only merged branches carry the marker")
+	}
+}
+
 func TestPortal_FitsNarrowTerminal(t *testing.T) {
 	m := narrowListModel(60)
 	m.portal = m.portal.SetSize(60, 18)
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"errors"
+	"time"
 
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
@@ -25,7 +26,13 @@ type cleanupCompleteMsg struct {
 
 type removalEventMsg struct{ event progress.Event }
 type removalEventsCompleteMsg struct{}
-type deletionsCompleteMsg struct{ Result worktree.DeletionResult }
+
+// deletionsCompleteMsg carries the removals' result. JournalErr is a failure
+// to record them for undo: the removals stand, so it is only a warning.
+type deletionsCompleteMsg struct {
+	Result     worktree.DeletionResult
+	JournalErr error
+}
 type pruneCompleteMsg struct{ Err error }
 
 func waitForRemovalEvent(ch <-chan progress.Event) tea.Cmd {
@@ -45,9 +52,15 @@ func runPrune(runner git.CommandRunner, repoPath string) tea.Cmd {
 	}
 }
 
-func runCleanup(runner git.CommandRunner, repoPath string) tea.Cmd {
+// runCleanup runs the post-removal safe cleanup under the list's protection
+// policy, or one detected here when the list never loaded it.
+func runCleanup(runner git.CommandRunner, repoPath string, protection *git.ProtectionPolicy, protectedPatterns []string, archiveRetention time.Duration) tea.Cmd {
 	return func() tea.Msg {
-		result := cleanup.Run(runner, repoPath, cleanup.Options{Mode: cleanup.ModeSafe}, func(cleanup.Event) {})
+		opts := cleanup.Options{Mode: cleanup.ModeSafe, Protection: protection, ArchiveRetention: archiveRetention}
+		if opts.Protection == nil {
+			opts.Protection = git.DetectProtectionPolicy(runner, repoPath, protectedPatterns)
+		}
+		result := cleanup.Run(runner, repoPath, opts, func(cleanup.Event) {})
 		return cleanupCompleteMsg{Result: result}
 	}
 }
@@ -93,6 +106,7 @@ func (m Model) updateProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case deletionsCompleteMsg:
 		msg.Result.Err = errors.Join(m.remove.run.result.Err, msg.Result.Err)
 		m.remove.run.result = msg.Result
+		m.remove.run.journalErr = msg.JournalErr
 		return m, tea.Batch(m.syncProgressBar(), runPrune(m.runner, m.repoPath))
 
 	case pruneCompleteMsg:
@@ -107,7 +121,7 @@ func (m Model) updateProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.remove.run.result.Err = errors.Join(m.remove.run.result.Err, transitionErr)
 			}
 		}
-		return m, tea.Batch(m.syncProgressBar(), runCleanup(m.runner, m.repoPath))
+		return m, tea.Batch(m.syncProgressBar(), runCleanup(m.runner, m.repoPath, m.remove.protection, m.protectedPatterns(), m.archiveRetention()))
 
 	case cleanupCompleteMsg:
 		m.remove.run.cleanupResult = &msg.Result
@@ -118,7 +132,7 @@ func (m Model) updateProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 			updated, holdCmd := m.holdOrAdvance(summaryView)
 			return updated, tea.Batch(syncCmd, holdCmd,
 				recordRemovals(m.repoPath, m.remove.run.result.SuccessCount),
-				loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration))
+				loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration, !m.noEnrichCache))
 		}
 		if len(msg.Result.Errors) > 0 {
 			cleanupErrors := make([]error, len(msg.Result.Errors))
@@ -147,7 +161,7 @@ func (m Model) updateProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 		updated, holdCmd := m.holdOrAdvance(summaryView)
 		return updated, tea.Batch(syncCmd, holdCmd,
 			recordRemovals(m.repoPath, m.remove.run.result.SuccessCount),
-			loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration))
+			loadWorktreeContext(m.runner, m.repoPath, m.worktreeGeneration, !m.noEnrichCache))
 
 	}
 	return m, nil
@@ -10,6 +10,7 @@ import (
 	"github.com/abiswas97/sentei/internal/git"
 	"github.com/abiswas97/sentei/internal/integration"
 	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/worktree"
 )
 
 func TestPrepareRemoval_SemanticIDsSurviveSelectionReordering(t *testing.T) {
@@ -135,3 +136,29 @@ func stepIDsByLabel(plan progress.Plan) map[string]progress.StepID {
 	}
 	return ids
 }
+
+func TestPlanArchives_AddsPhaseBeforeRemovalForAtRiskTargetsOnly(t *testing.T) {
+	clean := git.Worktree{Path: "/repo/clean", Branch: "refs/heads/clean"}
+	dirty := git.Worktree{Path: "/repo/dirty", Branch: "refs/heads/dirty", HasUncommittedChanges: true}
+
+	prepared, err := prepareRemoval([]git.Worktree{clean, dirty}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	planArchives(&prepared)
+
+	var phaseIDs []progress.PhaseID
+	for _, phase := range prepared.plan.Phases {
+		phaseIDs = append(phaseIDs, phase.ID)
+	}
+	want := []progress.PhaseID{worktree.ArchivePhaseID, worktree.RemovalPhaseID, cleanupPhaseID}
+	if !reflect.DeepEqual(phaseIDs, want) {
+		t.Fatalf("phases = %v, want %v", phaseIDs, want)
+	}
+	if len(prepared.plan.Phases[0].Steps) != 1 {
+		t.Fatalf("archive phase has %d steps, want 1", len(prepared.plan.Phases[0].Steps))
+	}
+	if prepared.targets[0].ArchiveStepID != "" || prepared.targets[1].ArchiveStepID == "" {
+		t.Errorf("only the dirty target should be archived: %+v", prepared.targets)
+	}
+}
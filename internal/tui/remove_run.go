@@ -30,6 +30,7 @@ type removalRun struct {
 	teardownOps     []teardownOperation
 
 	pruneErr      *error
+	journalErr    error // recording the run for undo failed; the removals stand
 	cleanupResult *cleanup.Result
 }
 
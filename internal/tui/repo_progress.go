@@ -6,12 +6,13 @@ import (
 
 	"github.com/abiswas97/sentei/internal/progress"
 	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/worktree"
 )
 
 type repoEventMsg progress.Event
 
 type repoDoneMsg struct {
-	result interface{} // CreateResult, CloneResult, or MigrateResult
+	result interface{} // CreateResult, CloneResult, MigrateResult, UnmigrateResult or MoveResult
 }
 
 // startRepoPipeline launches the appropriate pipeline based on opts type.
@@ -43,6 +44,18 @@ func (m *Model) startRepoPipeline(opts interface{}) tea.Cmd {
 			close(ch)
 			resultCh <- result
 		}()
+	case repo.UnmigrateOptions:
+		go func() {
+			result := repo.Unmigrate(runner, o, func(e progress.Event) { ch <- e })
+			close(ch)
+			resultCh <- result
+		}()
+	case worktree.PreparedMove:
+		go func() {
+			result := o.Run(runner, shell, func(e progress.Event) { ch <- e })
+			close(ch)
+			resultCh <- result
+		}()
 	}
 
 	return m.waitForRepoEvent()
@@ -73,9 +86,15 @@ func (m Model) updateRepoProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case repoDoneMsg:
 		m.repo.result = msg.result
+		if result, ok := msg.result.(worktree.MoveResult); ok {
+			return m.moveFinished(result)
+		}
 		targetView := repoSummaryView
-		if m.repo.opType == "migrate" {
+		switch m.repo.opType {
+		case "migrate":
 			targetView = migrateSummaryView
+		case "unmigrate":
+			targetView = unmigrateSummaryView
 		}
 		syncCmd := m.syncProgressBar()
 		updated, holdCmd := m.holdOrAdvance(targetView)
@@ -96,6 +115,12 @@ func (m Model) repoLayout() ProgressLayout {
 	case "migrate":
 		title = titleMigratingRepo
 		subject = m.repoPath
+	case "unmigrate":
+		title = titleUnmigratingRepo
+		subject = m.repoPath
+	case "move":
+		title = titleMovingWorktree
+		subject = m.move.worktree.Path
 	}
 
 	return m.withProgressDetails(ProgressLayout{
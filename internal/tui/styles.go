@@ -234,6 +234,8 @@ var (
 	colWidthCheckbox = 5  // "[x]" (3) + 2 gap
 	colWidthStatus   = 6  // "[ok]" (4) + 2 gap
 	colWidthAge      = 16 // "12 hours ago" (12) + headroom
+	colWidthSync     = 12 // "+12/-340" (8) + headroom
+	colWidthPR       = 9  // "merged" (6) + headroom
 )
 
 // Indicator characters. The star family carries the item lifecycle and the
@@ -88,6 +88,10 @@ func (m Model) renderRemovalSummary() string {
 		b.WriteString(styleDim.Render("  Pruned orphaned worktree metadata"))
 		b.WriteString("\n")
 	}
+	if m.remove.run.journalErr != nil {
+		b.WriteString(styleWarning.Render(fmt.Sprintf("  Warning: failed to record removal in the undo journal: %s", m.remove.run.journalErr)))
+		b.WriteString("\n")
+	}
 
 	if cr := m.remove.run.cleanupResult; cr != nil {
 		if len(cr.Errors) > 0 {
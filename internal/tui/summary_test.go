@@ -94,6 +94,27 @@ func TestViewSummary_DenseFailuresExposeOmittedResultsInDetails(t *testing.T) {
 	}
 }
 
+func TestViewSummary_JournalFailureIsAWarningNotARemovalFailure(t *testing.T) {
+	m := NewModel(nil, nil, "/repo")
+	m.view = summaryView
+	m.width, m.windowHeight, m.height = 100, 40, 34
+	m.portal = m.portal.SetSize(100, 40)
+
+	updated, _ := m.updateProgress(deletionsCompleteMsg{
+		Result:     worktree.DeletionResult{SuccessCount: 1, Outcomes: []worktree.WorktreeOutcome{{Path: "/repo/a", Success: true}}},
+		JournalErr: errors.New("disk full"),
+	})
+	m = updated.(Model)
+	if m.remove.run.result.Err != nil {
+		t.Fatalf("result.Err = %v, want the journal failure kept out of it", m.remove.run.result.Err)
+	}
+	m.view = summaryView
+	view := stripANSI(m.viewSummary())
+	if !strings.Contains(view, "Warning: failed to record removal in the undo journal: disk full") {
+		t.Errorf("summary missing the journal warning:\n%s", view)
+	}
+}
+
 func TestUpdateSummary_MenuLaunch_KeysReturnToMenu(t *testing.T) {
 	cases := []struct {
 		name string
@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/switcher"
+)
+
+// switchPickerRows caps the picker's height: it renders inline under the
+// prompt, not on the alternate screen.
+const switchPickerRows = 8
+
+var (
+	pickerUp     = key.NewBinding(key.WithKeys("up", "ctrl+p", "ctrl+k"))
+	pickerDown   = key.NewBinding(key.WithKeys("down", "ctrl+n", "ctrl+j"))
+	pickerCancel = key.NewBinding(key.WithKeys("esc", "ctrl+c"))
+)
+
+// SwitchPicker is the compact picker sentei switch opens when a query is
+// ambiguous. Typing refines the query; enter picks the highlighted worktree.
+type SwitchPicker struct {
+	worktrees []git.Worktree
+	recent    map[string]time.Time
+	input     textinput.Model
+	matches   []switcher.Candidate
+	cursor    int
+	chosen    string
+	width     int
+}
+
+// NewSwitchPicker opens the picker on query, ranking as switcher.Rank does.
+func NewSwitchPicker(worktrees []git.Worktree, query string, recent map[string]time.Time) SwitchPicker {
+	input := textinput.New()
+	input.Prompt = "switch to: "
+	input.SetValue(query)
+	input.Focus()
+	return SwitchPicker{
+		worktrees: worktrees,
+		recent:    recent,
+		input:     input,
+		matches:   switcher.Rank(worktrees, query, recent),
+	}
+}
+
+// Chosen returns the picked worktree's path, or "" when the picker was
+// cancelled.
+func (p SwitchPicker) Chosen() string {
+	return p.chosen
+}
+
+func (p SwitchPicker) Init() tea.Cmd {
+	return tea.RequestBackgroundColor
+}
+
+func (p SwitchPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.BackgroundColorMsg:
+		if !msg.IsDark() {
+			applyPalette(lightPalette)
+		}
+		return p, nil
+
+	case tea.WindowSizeMsg:
+		p.width = msg.Width
+		return p, nil
+
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, pickerCancel):
+			return p, tea.Quit
+		case key.Matches(msg, keys.Confirm):
+			if len(p.matches) > 0 {
+				p.chosen = p.matches[p.cursor].Worktree.Path
+				return p, tea.Quit
+			}
+			return p, nil
+		case key.Matches(msg, pickerUp):
+			if p.cursor > 0 {
+				p.cursor--
+			}
+			return p, nil
+		case key.Matches(msg, pickerDown):
+			if p.cursor < len(p.matches)-1 {
+				p.cursor++
+			}
+			return p, nil
+		}
+	}
+
+	before := p.input.Value()
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	if p.input.Value() != before {
+		p.matches = switcher.Rank(p.worktrees, p.input.Value(), p.recent)
+		p.cursor = 0
+	}
+	return p, cmd
+}
+
+func (p SwitchPicker) View() tea.View {
+	var b strings.Builder
+	b.WriteString(p.input.View())
+	b.WriteString("\n")
+
+	if len(p.matches) == 0 {
+		b.WriteString(styleDim.Render("  no matching worktrees"))
+		b.WriteString("\n")
+	}
+
+	// Keep the cursor in a window of switchPickerRows.
+	start := max(0, p.cursor-switchPickerRows+1)
+	end := min(len(p.matches), start+switchPickerRows)
+	nameWidth := 0
+	for _, c := range p.matches[start:end] {
+		nameWidth = max(nameWidth, len([]rune(c.Name())))
+	}
+	width := p.width
+	if width == 0 {
+		width = 80
+	}
+	nameWidth = min(nameWidth, max(width/2, 12))
+
+	for i := start; i < end; i++ {
+		c := p.matches[i]
+		name := truncateWithEllipsis(c.Name(), nameWidth)
+		pad := strings.Repeat(" ", nameWidth-len([]rune(name)))
+		path := truncateWithEllipsis(c.Worktree.Path, max(width-nameWidth-6, 8))
+		if i == p.cursor {
+			fmt.Fprintf(&b, "%s %s%s  %s\n", styleAccent.Render("▸"), styleCursorRow.Render(name), pad, styleDim.Render(path))
+		} else {
+			fmt.Fprintf(&b, "  %s%s  %s\n", styleNormalRow.Render(name), pad, styleDim.Render(path))
+		}
+	}
+	if hidden := len(p.matches) - (end - start); hidden > 0 {
+		fmt.Fprintf(&b, "%s\n", styleDim.Render(fmt.Sprintf("  … %d more", hidden)))
+	}
+	b.WriteString(styleDim.Render("↑/↓ move · enter switch · esc cancel"))
+	return tea.NewView(b.String())
+}
+
+// RunSwitchPicker runs the picker inline on out (stderr, so the chosen path
+// stays alone on stdout for the shell wrapper) and returns the chosen path,
+// or "" when cancelled.
+func RunSwitchPicker(worktrees []git.Worktree, query string, recent map[string]time.Time, out io.Writer) (string, error) {
+	final, err := tea.NewProgram(NewSwitchPicker(worktrees, query, recent), tea.WithOutput(out)).Run()
+	if err != nil {
+		return "", err
+	}
+	if p, ok := final.(SwitchPicker); ok {
+		return p.Chosen(), nil
+	}
+	return "", nil
+}
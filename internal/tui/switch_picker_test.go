@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/abiswas97/sentei/internal/git"
+)
+
+var pickerWorktrees = []git.Worktree{
+	{Path: "/repo/login", Branch: "refs/heads/feature/login"},
+	{Path: "/repo/logout", Branch: "refs/heads/feature/logout"},
+	{Path: "/repo/api", Branch: "refs/heads/fix/api-timeout"},
+}
+
+func pickerPress(p SwitchPicker, msgs ...tea.KeyPressMsg) SwitchPicker {
+	for _, msg := range msgs {
+		updated, _ := p.Update(msg)
+		p = updated.(SwitchPicker)
+	}
+	return p
+}
+
+func TestSwitchPicker_TypingNarrowsAndEnterChooses(t *testing.T) {
+	p := NewSwitchPicker(pickerWorktrees, "log", nil)
+	if len(p.matches) != 2 {
+		t.Fatalf("matches = %d, want 2", len(p.matches))
+	}
+
+	p = pickerPress(p, tea.KeyPressMsg{Code: 'o', Text: "o"}, tea.KeyPressMsg{Code: 'u', Text: "u"})
+	if len(p.matches) != 1 || p.matches[0].Name() != "feature/logout" {
+		t.Fatalf("typing should narrow to feature/logout, got %d matches", len(p.matches))
+	}
+	view := stripAnsi(p.View().Content)
+	if !strings.Contains(view, "switch to: logou") || !strings.Contains(view, "/repo/logout") {
+		t.Errorf("view should show the query and the match:\n%s", view)
+	}
+
+	p = pickerPress(p, tea.KeyPressMsg{Code: tea.KeyEnter})
+	if p.Chosen() != "/repo/logout" {
+		t.Errorf("Chosen = %q, want /repo/logout", p.Chosen())
+	}
+}
+
+func TestSwitchPicker_ArrowsMoveAndEscCancels(t *testing.T) {
+	p := NewSwitchPicker(pickerWorktrees, "", nil)
+	p = pickerPress(p, tea.KeyPressMsg{Code: tea.KeyDown}, tea.KeyPressMsg{Code: tea.KeyDown}, tea.KeyPressMsg{Code: tea.KeyDown})
+	if p.cursor != len(p.matches)-1 {
+		t.Errorf("cursor = %d, want it clamped at %d", p.cursor, len(p.matches)-1)
+	}
+
+	p = pickerPress(p, tea.KeyPressMsg{Code: tea.KeyEscape})
+	if p.Chosen() != "" {
+		t.Errorf("esc should cancel, got %q", p.Chosen())
+	}
+}
@@ -0,0 +1,211 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/repo"
+)
+
+type unmigrateInfoMsg struct {
+	bareRoot      string
+	candidates    []git.Worktree
+	defaultBranch string
+	err           error
+}
+
+func loadUnmigrateInfo(runner git.CommandRunner, repoPath string) tea.Cmd {
+	return func() tea.Msg {
+		root := repo.ResolveBareRoot(runner, repoPath)
+		candidates, err := repo.UnmigrateCandidates(runner, root)
+		if err != nil {
+			return unmigrateInfoMsg{err: err}
+		}
+		return unmigrateInfoMsg{
+			bareRoot:      root,
+			candidates:    candidates,
+			defaultBranch: git.DetectDefaultBranch(runner, root),
+		}
+	}
+}
+
+func (m Model) updateUnmigrateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	info := &m.repo.unmigrateInfo
+	switch msg := msg.(type) {
+	case unmigrateInfoMsg:
+		if msg.err != nil {
+			m.repo.validationErr = fmt.Sprintf("failed to list worktrees: %v", msg.err)
+			return m, nil
+		}
+		info.BareRoot = msg.bareRoot
+		info.Candidates = msg.candidates
+		info.Loaded = true
+		want := msg.defaultBranch
+		if m.unmigrateOpts != nil && m.unmigrateOpts.Worktree != "" {
+			want = m.unmigrateOpts.Worktree
+		}
+		found := false
+		for i, wt := range msg.candidates {
+			if stripBranchPrefix(wt.Branch) == want || wt.Path == want {
+				info.Cursor, found = i, true
+				break
+			}
+		}
+		switch {
+		case len(msg.candidates) == 0:
+			m.repo.validationErr = "no worktree under the repository root to check out"
+		case !found && m.unmigrateOpts != nil && m.unmigrateOpts.Worktree != "":
+			m.repo.validationErr = fmt.Sprintf("no worktree for %q", m.unmigrateOpts.Worktree)
+		}
+		return m, nil
+
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, keys.Up):
+			if info.Cursor > 0 {
+				info.Cursor--
+			}
+			return m, nil
+		case key.Matches(msg, keys.Down):
+			if info.Cursor < len(info.Candidates)-1 {
+				info.Cursor++
+			}
+			return m, nil
+		}
+
+	case ConfirmProceedMsg:
+		if !info.Loaded || len(info.Candidates) == 0 || m.repo.validationErr != "" {
+			return m, nil
+		}
+		opts := repo.UnmigrateOptions{
+			BareRoot: info.BareRoot,
+			Worktree: info.Candidates[info.Cursor].Path,
+		}
+		m.repo.events = nil
+		m.repo.result = nil
+		m.repo.opType = "unmigrate"
+		m.progressStartedAt = time.Now()
+		m.progressToken++
+		m.view = migrateProgressView
+		return m, m.startRepoPipeline(opts)
+
+	case ConfirmBackMsg:
+		if m.unmigrateOpts != nil {
+			return m, tea.Quit
+		}
+		m.view = menuView
+		return m, nil
+	}
+
+	if cmd := UpdateConfirmation(msg); cmd != nil {
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// chosenUnmigrateWorktree is the worktree under the cursor, or false while
+// the candidates are loading.
+func (m Model) chosenUnmigrateWorktree() (git.Worktree, bool) {
+	info := m.repo.unmigrateInfo
+	if !info.Loaded || info.Cursor >= len(info.Candidates) {
+		return git.Worktree{}, false
+	}
+	return info.Candidates[info.Cursor], true
+}
+
+// unmigrateConfirmationVM builds a ConfirmationViewModel for the unmigrate
+// flow launched from the CLI.
+func (m Model) unmigrateConfirmationVM() ConfirmationViewModel {
+	deleteBackup := "no"
+	flags := make(map[string]string)
+	if m.unmigrateOpts.DeleteBackup {
+		deleteBackup = "yes"
+		flags["delete-backup"] = "true"
+	}
+	if m.unmigrateOpts.Worktree != "" {
+		flags["worktree"] = m.unmigrateOpts.Worktree
+	}
+
+	items := []ConfirmationItem{{Label: "Repo path:", Value: m.repoPath}}
+	switch wt, ok := m.chosenUnmigrateWorktree(); {
+	case m.repo.validationErr != "":
+		items = append(items, ConfirmationItem{Label: "Worktree:", Value: m.repo.validationErr})
+	case !ok:
+		items = append(items, ConfirmationItem{Label: "Worktree:", Value: "reading\u2026"})
+	default:
+		items = append(items, ConfirmationItem{Label: "Worktree:", Value: worktreeChoiceLabel(wt)})
+	}
+	items = append(items, ConfirmationItem{Label: "Delete backup:", Value: deleteBackup})
+
+	return ConfirmationViewModel{
+		Width:      m.width,
+		Title:      titleConfirmUnmigrate,
+		Items:      items,
+		CLICommand: BuildCLICommand("unmigrate", flags),
+	}
+}
+
+func worktreeChoiceLabel(wt git.Worktree) string {
+	if branch := stripBranchPrefix(wt.Branch); branch != "" {
+		return branch + "  " + styleDim.Render(filepath.Base(wt.Path))
+	}
+	return filepath.Base(wt.Path) + "  " + styleDim.Render("detached")
+}
+
+func (m Model) viewUnmigrateConfirm() string {
+	if m.unmigrateOpts != nil {
+		return m.unmigrateConfirmationVM().View()
+	}
+
+	var b strings.Builder
+
+	b.WriteString(viewTitle(titleUnmigrate))
+	b.WriteString("\n\n")
+	b.WriteString(styleDim.Render(fmt.Sprintf("  %s", m.repoPath)))
+	b.WriteString("\n\n")
+	b.WriteString(viewSeparator(m.width))
+	b.WriteString("\n\n")
+
+	if m.repo.validationErr != "" {
+		b.WriteString(styleError.Render("  " + m.repo.validationErr))
+		b.WriteString("\n\n")
+	}
+
+	info := m.repo.unmigrateInfo
+	if !info.Loaded {
+		b.WriteString(styleDim.Render("  reading worktrees\u2026"))
+		b.WriteString("\n")
+	} else if len(info.Candidates) > 0 {
+		b.WriteString("  Check out at the root:\n")
+		for i, wt := range info.Candidates {
+			if i == info.Cursor {
+				b.WriteString(styleAccent.Render("  ▸ ") + worktreeChoiceLabel(wt) + "\n")
+			} else {
+				b.WriteString("    " + worktreeChoiceLabel(wt) + "\n")
+			}
+		}
+
+		chosen := info.Candidates[info.Cursor]
+		b.WriteString("\n")
+		b.WriteString("  This will:\n")
+		fmt.Fprintf(&b, "    %s Back up current layout\n", styleDim.Render("\u25cf"))
+		fmt.Fprintf(&b, "    %s Move .bare to .git\n", styleDim.Render("\u25cf"))
+		fmt.Fprintf(&b, "    %s Move %s's files to the root\n", styleDim.Render("\u25cf"), filepath.Base(chosen.Path))
+		fmt.Fprintf(&b, "    %s Re-point other worktrees at the new .git\n", styleDim.Render("\u25cf"))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(viewSeparator(m.width))
+	b.WriteString("\n\n")
+	b.WriteString(viewFooter(m.width, unmigrateFooter))
+	b.WriteString("\n")
+
+	return b.String()
+}
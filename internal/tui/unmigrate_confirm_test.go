@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/abiswas97/sentei/internal/config"
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/repo"
+)
+
+func makeUnmigrateConfirmModel(opts *UnmigrateOpts) Model {
+	m := NewMenuModel(&stubRunner{}, nil, "/repo", &config.Config{}, repo.ContextBareRepo)
+	m.width = 80
+	m.height = 24
+	if opts != nil {
+		m.SetUnmigrateOpts(opts)
+	} else {
+		m.view = unmigrateConfirmView
+	}
+	return m
+}
+
+var unmigrateCandidates = unmigrateInfoMsg{
+	bareRoot: "/repo",
+	candidates: []git.Worktree{
+		{Path: "/repo/feature", Branch: "refs/heads/feature"},
+		{Path: "/repo/main", Branch: "refs/heads/main"},
+	},
+	defaultBranch: "main",
+}
+
+func TestUpdateUnmigrateConfirm_DefaultsToDefaultBranchAndMoves(t *testing.T) {
+	m := makeUnmigrateConfirmModel(nil)
+	updated, _ := m.Update(unmigrateCandidates)
+	m = updated.(Model)
+	if wt, ok := m.chosenUnmigrateWorktree(); !ok || wt.Path != "/repo/main" {
+		t.Fatalf("chosen = %+v, %v", wt, ok)
+	}
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'k', Text: "k"})
+	m = updated.(Model)
+	if wt, _ := m.chosenUnmigrateWorktree(); wt.Path != "/repo/feature" {
+		t.Fatalf("chosen after up = %s", wt.Path)
+	}
+	out := stripAnsi(m.viewUnmigrateConfirm())
+	for _, want := range []string{"▸ feature", "Move .bare to .git", "Move feature's files to the root"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestUpdateUnmigrateConfirm_ProceedWaitsForWorktrees(t *testing.T) {
+	m := makeUnmigrateConfirmModel(nil)
+	updated, cmd := m.Update(ConfirmProceedMsg{})
+	if updated.(Model).view != unmigrateConfirmView || cmd != nil {
+		t.Fatal("proceeded before the worktrees loaded")
+	}
+
+	updated, _ = updated.(Model).Update(unmigrateCandidates)
+	updated, cmd = updated.(Model).Update(ConfirmProceedMsg{})
+	m = updated.(Model)
+	if m.view != migrateProgressView || m.repo.opType != "unmigrate" || cmd == nil {
+		t.Fatalf("view = %d, opType = %q", m.view, m.repo.opType)
+	}
+}
+
+func TestUnmigrateConfirmationVM_UnknownWorktreeBlocksProceed(t *testing.T) {
+	m := makeUnmigrateConfirmModel(&UnmigrateOpts{Worktree: "nope", DeleteBackup: true})
+	updated, _ := m.Update(unmigrateCandidates)
+	m = updated.(Model)
+
+	out := stripAnsi(m.viewUnmigrateConfirm())
+	for _, want := range []string{`no worktree for "nope"`, "--worktree nope", "--delete-backup"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view missing %q:\n%s", want, out)
+		}
+	}
+	if updated, _ := m.Update(ConfirmProceedMsg{}); updated.(Model).view != unmigrateConfirmView {
+		t.Error("proceeded with an unknown worktree")
+	}
+}
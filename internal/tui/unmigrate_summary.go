@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/repo"
+)
+
+// updateUnmigrateSummary asks whether to delete the backup. After a failure
+// the backup is the way back, so the only key is q.
+func (m Model) updateUnmigrateSummary(msg tea.Msg) (tea.Model, tea.Cmd) {
+	result, ok := m.repo.result.(repo.UnmigrateResult)
+	if !ok {
+		return m, tea.Quit
+	}
+
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	if result.HasFailures() {
+		if key.Matches(keyMsg, keys.Quit) {
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+	switch {
+	case key.Matches(keyMsg, keys.Yes):
+		if result.BackupPath != "" {
+			_ = repo.DeleteBackup(result.BackupPath)
+		}
+		return m, tea.Quit
+	case key.Matches(keyMsg, keys.No), key.Matches(keyMsg, keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m Model) viewUnmigrateSummary() string {
+	var b strings.Builder
+
+	result, ok := m.repo.result.(repo.UnmigrateResult)
+	if !ok {
+		return "  Conversion result unavailable\n"
+	}
+
+	b.WriteString(viewTitle(titleUnmigrateComplete))
+	b.WriteString("\n\n")
+	b.WriteString(viewSeparator(m.width))
+	b.WriteString("\n\n")
+
+	if result.HasFailures() {
+		errMsg := "unknown error"
+		if result.Err != nil {
+			errMsg = result.Err.Error()
+		} else if _, step, ok := progress.FirstFailure(result.Phases); ok && step.Error != nil {
+			errMsg = step.Error.Error()
+		}
+		errWidth := max(m.width-8, 30)
+		fmt.Fprintf(&b, "  %s Conversion failed\n\n",
+			styleIndicatorFailed.Render(indicatorFailed))
+		b.WriteString("    " + styleError.Width(errWidth).Render(errMsg))
+		b.WriteString("\n\n")
+		if result.RolledBack {
+			b.WriteString("  Your bare layout was restored from the backup.\n")
+		} else if result.BackupPath != "" {
+			b.WriteString("  Your bare layout is backed up at:\n")
+			fmt.Fprintf(&b, "    %s\n\n", styleDim.Render(result.BackupPath))
+			b.WriteString("  To restore:\n")
+			fmt.Fprintf(&b, "    %s\n", result.RestoreCommand())
+		}
+		b.WriteString("\n")
+		b.WriteString(viewSeparator(m.width))
+		b.WriteString("\n\n")
+		b.WriteString(viewFooter(m.width, quitOnlyFooter))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "  %s %s is a regular repository\n\n",
+		styleIndicatorDone.Render(indicatorDone), filepath.Base(result.RepoPath))
+
+	fmt.Fprintf(&b, "    %-10s %s\n", styleDim.Render("Path"), result.RepoPath)
+	fmt.Fprintf(&b, "    %-10s %s\n", styleDim.Render("Branch"), result.Branch)
+	if result.BackupPath != "" {
+		sizeHint := ""
+		if result.BackupSize != "" {
+			sizeHint = "  " + styleDim.Render(result.BackupSize)
+		}
+		fmt.Fprintf(&b, "    %-10s %s%s\n", styleDim.Render("Backup"), filepath.Base(result.BackupPath), sizeHint)
+	}
+	if n := len(result.Converted); n > 0 {
+		fmt.Fprintf(&b, "    %-10s %d kept, linked to .git\n", styleDim.Render("Worktrees"), n)
+	}
+	if len(result.Dropped) > 0 {
+		b.WriteString("\n")
+		b.WriteString(styleIndicatorWarning.Render(fmt.Sprintf("  %s Pruned missing worktrees:", indicatorWarning)))
+		b.WriteString("\n")
+		for _, path := range result.Dropped {
+			fmt.Fprintf(&b, "    %s\n", styleDim.Render(path))
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString("  Delete backup?\n")
+	b.WriteString("\n")
+	b.WriteString(viewSeparator(m.width))
+	b.WriteString("\n\n")
+	b.WriteString(viewFooter(m.width, migrateConfirmFooter))
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/abiswas97/sentei/internal/config"
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/repo"
+)
+
+func makeUnmigrateSummaryModel(result repo.UnmigrateResult) Model {
+	m := NewMenuModel(nil, nil, "/repo", &config.Config{}, repo.ContextBareRepo)
+	m.view = unmigrateSummaryView
+	m.repo.result = result
+	m.width = 80
+	m.height = 24
+	return m
+}
+
+func TestViewUnmigrateSummary_Failure_ShowsRestore(t *testing.T) {
+	result := repo.UnmigrateResult{
+		RepoPath:   "/repo/proj",
+		BackupPath: "/repo/proj_backup_1",
+		Phases: []progress.Phase{
+			{Name: "Unmigrate", Steps: []progress.StepResult{{Name: "Move working files to root", Status: progress.StepFailed, Error: errors.New("boom")}}},
+		},
+	}
+	out := stripAnsi(makeUnmigrateSummaryModel(result).viewUnmigrateSummary())
+	for _, want := range []string{"Conversion failed", "boom", `rm -rf "/repo/proj" && mv "/repo/proj_backup_1" "/repo/proj"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestViewUnmigrateSummary_Success_ListsWorktrees(t *testing.T) {
+	result := repo.UnmigrateResult{
+		RepoPath:   "/repo/proj",
+		Branch:     "main",
+		BackupPath: "/repo/proj_backup_1",
+		Converted:  []string{"/repo/proj/feature"},
+		Dropped:    []string{"/repo/proj/gone"},
+	}
+	out := stripAnsi(makeUnmigrateSummaryModel(result).viewUnmigrateSummary())
+	for _, want := range []string{"proj is a regular repository", "1 kept, linked to .git", "/repo/proj/gone", "Delete backup?"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package worktree
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/git"
+)
+
+const (
+	cacheFileName = "sentei-enrich-cache.json"
+	cacheVersion  = 1
+)
+
+// Cache remembers status results between runs so an unchanged worktree skips
+// its status passes. It lives in <git common dir>/sentei-enrich-cache.json,
+// next to sentei.json, keyed by worktree path.
+//
+// A record is reused only while the worktree's HEAD and fingerprint match:
+// the index file's size and mtime (git rewrites the index on every add,
+// commit, checkout and status refresh) and the worktree root's mtime (which
+// moves when a top-level file is created or deleted). Edits that touch
+// neither — a tracked file changed in place without a status since, a new
+// file in a subdirectory — are missed until something else changes, so
+// commands that delete worktrees check status fresh, and the TUI checks a
+// selection whose status the cache served again before removing it.
+//
+// A Cache is safe for concurrent use.
+type Cache struct {
+	path    string
+	mu      sync.Mutex
+	records map[string]CacheRecord
+	dirty   bool
+}
+
+// CacheRecord is one worktree's cached enrichment.
+type CacheRecord struct {
+	Head                  string    `json:"head"`
+	Fingerprint           string    `json:"fingerprint"`
+	LastCommitDate        time.Time `json:"last_commit_date"`
+	LastCommitSubject     string    `json:"last_commit_subject"`
+	HasUncommittedChanges bool      `json:"has_uncommitted_changes"`
+	HasUntrackedFiles     bool      `json:"has_untracked_files"`
+}
+
+type cacheFile struct {
+	Version int                    `json:"version"`
+	Records map[string]CacheRecord `json:"records"`
+}
+
+// CachePath returns the cache file for a git common dir.
+func CachePath(commonDir string) string {
+	return filepath.Join(commonDir, cacheFileName)
+}
+
+// LoadCache reads the cache for a git common dir. A missing file, or one
+// written by another cache version, yields an empty cache.
+func LoadCache(commonDir string) (*Cache, error) {
+	c := &Cache{path: CachePath(commonDir), records: make(map[string]CacheRecord)}
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c, nil
+		}
+		return c, fmt.Errorf("reading enrichment cache: %w", err)
+	}
+	var f cacheFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return c, fmt.Errorf("parsing enrichment cache: %w", err)
+	}
+	if f.Version == cacheVersion && f.Records != nil {
+		c.records = f.Records
+	}
+	return c, nil
+}
+
+// Lookup applies the cached record for wt if its HEAD and fingerprint still
+// match, marking it enriched. It reports whether the record was used.
+func (c *Cache) Lookup(wt *git.Worktree) bool {
+	fp, ok := Fingerprint(wt.Path)
+	if !ok {
+		return false
+	}
+	c.mu.Lock()
+	rec, found := c.records[wt.Path]
+	c.mu.Unlock()
+	if !found || rec.Head != wt.HEAD || rec.Fingerprint != fp {
+		return false
+	}
+	if wt.LastCommitDate.IsZero() {
+		wt.LastCommitDate = rec.LastCommitDate
+		wt.LastCommitSubject = rec.LastCommitSubject
+	}
+	wt.HasUncommittedChanges = rec.HasUncommittedChanges
+	wt.HasUntrackedFiles = rec.HasUntrackedFiles
+	wt.IsEnriched = true
+	return true
+}
+
+// Store records a fully enriched worktree. The fingerprint is taken now,
+// after the status passes, since status may have refreshed the index.
+func (c *Cache) Store(wt git.Worktree) {
+	if !wt.IsEnriched || wt.EnrichmentError != "" {
+		return
+	}
+	fp, ok := Fingerprint(wt.Path)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.records[wt.Path] = CacheRecord{
+		Head:                  wt.HEAD,
+		Fingerprint:           fp,
+		LastCommitDate:        wt.LastCommitDate,
+		LastCommitSubject:     wt.LastCommitSubject,
+		HasUncommittedChanges: wt.HasUncommittedChanges,
+		HasUntrackedFiles:     wt.HasUntrackedFiles,
+	}
+	c.dirty = true
+}
+
+// Save writes the cache, dropping records for worktrees not in live so
+// removed worktrees do not accumulate. It is a no-op when nothing changed.
+func (c *Cache) Save(live []git.Worktree) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keep := make(map[string]bool, len(live))
+	for _, wt := range live {
+		keep[wt.Path] = true
+	}
+	for path := range c.records {
+		if !keep[path] {
+			delete(c.records, path)
+			c.dirty = true
+		}
+	}
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(cacheFile{Version: cacheVersion, Records: c.records})
+	if err != nil {
+		return fmt.Errorf("encoding enrichment cache: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".sentei-enrich-cache-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("writing enrichment cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	if err := os.Rename(tmpName, c.path); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("renaming enrichment cache: %w", err)
+	}
+	c.dirty = false
+	return nil
+}
+
+// Fingerprint summarizes the worktree's index and root directory for cache
+// validation. It reports false when either cannot be read.
+func Fingerprint(wtPath string) (string, bool) {
+	root, err := os.Stat(wtPath)
+	if err != nil {
+		return "", false
+	}
+	gitDir, ok := worktreeGitDir(wtPath)
+	if !ok {
+		return "", false
+	}
+	index, err := os.Stat(filepath.Join(gitDir, "index"))
+	if err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%d:%d:%d", index.Size(), index.ModTime().UnixNano(), root.ModTime().UnixNano()), true
+}
+
+// worktreeGitDir resolves a worktree's private git dir: .git itself in a main
+// worktree, the "gitdir:" target of the .git file in a linked one.
+func worktreeGitDir(wtPath string) (string, bool) {
+	dotGit := filepath.Join(wtPath, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", false
+	}
+	if info.IsDir() {
+		return dotGit, true
+	}
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", false
+	}
+	dir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", false
+	}
+	dir = strings.TrimSpace(dir)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(wtPath, dir)
+	}
+	return dir, true
+}
@@ -0,0 +1,153 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/testutil/mock"
+)
+
+// fakeLinkedWorktree lays out a linked worktree the way git does: a .git file
+// pointing at a private git dir under the common dir, holding the index.
+func fakeLinkedWorktree(t *testing.T, commonDir, name string) string {
+	t.Helper()
+	gitDir := filepath.Join(commonDir, "worktrees", name)
+	if err := os.MkdirAll(gitDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "index"), []byte("index"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wtPath := filepath.Join(t.TempDir(), name)
+	if err := os.MkdirAll(wtPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wtPath, ".git"), []byte("gitdir: "+gitDir+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return wtPath
+}
+
+// touch moves path's mtime forward so the change is visible even on
+// filesystems with coarse timestamps.
+func touch(t *testing.T, path string) {
+	t.Helper()
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCache_LookupInvalidatesOnChange(t *testing.T) {
+	commonDir := t.TempDir()
+	wtPath := fakeLinkedWorktree(t, commonDir, "feature")
+	enriched := git.Worktree{
+		Path:                  wtPath,
+		HEAD:                  "aaa",
+		LastCommitSubject:     "Add feature",
+		LastCommitDate:        time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+		HasUncommittedChanges: true,
+		IsEnriched:            true,
+	}
+
+	cases := []struct {
+		name   string
+		change func(wt *git.Worktree)
+		hit    bool
+	}{
+		{"unchanged", func(*git.Worktree) {}, true},
+		{"new HEAD", func(wt *git.Worktree) { wt.HEAD = "bbb" }, false},
+		{"index rewritten", func(*git.Worktree) { touch(t, filepath.Join(commonDir, "worktrees", "feature", "index")) }, false},
+		{"top-level file added", func(*git.Worktree) { touch(t, wtPath) }, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cache, err := LoadCache(commonDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cache.Store(enriched)
+
+			wt := git.Worktree{Path: wtPath, HEAD: "aaa"}
+			tc.change(&wt)
+			if got := cache.Lookup(&wt); got != tc.hit {
+				t.Fatalf("Lookup = %v, want %v", got, tc.hit)
+			}
+			if tc.hit && (!wt.IsEnriched || !wt.HasUncommittedChanges || wt.LastCommitSubject != "Add feature") {
+				t.Errorf("hit should apply the record, got %+v", wt)
+			}
+		})
+	}
+}
+
+func TestCache_SaveRoundTripPrunesMissingWorktrees(t *testing.T) {
+	commonDir := t.TempDir()
+	kept := fakeLinkedWorktree(t, commonDir, "kept")
+	gone := fakeLinkedWorktree(t, commonDir, "gone")
+
+	cache, err := LoadCache(commonDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Store(git.Worktree{Path: kept, HEAD: "aaa", IsEnriched: true, HasUntrackedFiles: true})
+	cache.Store(git.Worktree{Path: gone, HEAD: "bbb", IsEnriched: true})
+	cache.Store(git.Worktree{Path: gone, HEAD: "ccc", EnrichmentError: "boom"})
+	if err := cache.Save([]git.Worktree{{Path: kept}}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := LoadCache(commonDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt := git.Worktree{Path: kept, HEAD: "aaa"}
+	if !reloaded.Lookup(&wt) || !wt.HasUntrackedFiles {
+		t.Errorf("kept worktree should survive the round trip, got %+v", wt)
+	}
+	if reloaded.Lookup(&git.Worktree{Path: gone, HEAD: "bbb"}) {
+		t.Error("a worktree missing from the live list should be pruned")
+	}
+}
+
+func TestLoadCache_CorruptFileStartsEmpty(t *testing.T) {
+	commonDir := t.TempDir()
+	if err := os.WriteFile(CachePath(commonDir), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cache, err := LoadCache(commonDir)
+	if err == nil {
+		t.Error("expected a parse error")
+	}
+	if cache == nil || cache.Lookup(&git.Worktree{Path: commonDir}) {
+		t.Error("a corrupt cache should still load empty")
+	}
+}
+
+func TestEnrichStatus_CacheHitSkipsGit(t *testing.T) {
+	commonDir := t.TempDir()
+	wtPath := fakeLinkedWorktree(t, commonDir, "feature")
+	cache, err := LoadCache(commonDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Store(git.Worktree{Path: wtPath, HEAD: "aaa", IsEnriched: true, HasUntrackedFiles: true})
+
+	runner := &mock.Runner{}
+	var updates []Update
+	result := EnrichStatus(runner, []git.Worktree{{Path: wtPath, HEAD: "aaa"}}, 1, cache, func(u Update) {
+		updates = append(updates, u)
+	})
+
+	if len(runner.Calls) != 0 {
+		t.Errorf("a cache hit should run no git commands, ran %v", runner.Calls)
+	}
+	if !result[0].IsEnriched || !result[0].HasUntrackedFiles {
+		t.Errorf("cached status not applied: %+v", result[0])
+	}
+	if len(updates) != 1 || !updates[0].Final {
+		t.Errorf("updates = %+v, want one final update", updates)
+	}
+}
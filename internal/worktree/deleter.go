@@ -16,9 +16,53 @@ const RemovalPhaseName = "Removing worktrees"
 
 const RemovalPhaseID progress.PhaseID = "remove-worktrees"
 
+// ArchivePhaseName is the phase that snapshots at-risk worktrees before the
+// removal phase deletes them.
+const ArchivePhaseName = "Archiving worktrees"
+
+const ArchivePhaseID progress.PhaseID = "archive-worktrees"
+
 type RemovalTarget struct {
 	Worktree git.Worktree
 	StepID   progress.StepID
+	// ArchiveStepID, when set, names the target's step in the archive phase;
+	// the worktree is archived before it is removed.
+	ArchiveStepID progress.StepID
+}
+
+// NeedsArchive reports whether removing wt would lose work that exists
+// nowhere else: uncommitted changes, untracked files or unpushed commits.
+func NeedsArchive(wt git.Worktree) bool {
+	return wt.HasUncommittedChanges || wt.HasUntrackedFiles || wt.HasUnpushedCommits
+}
+
+// ArchiveWorktrees runs archiver for every target with an ArchiveStepID,
+// one at a time, and returns the targets that are safe to remove. A target
+// whose archive fails is dropped and its removal step skipped, so a failed
+// snapshot never turns into lost work.
+func ArchiveWorktrees(execution *progress.Execution, phaseID progress.PhaseID, removalPhaseID progress.PhaseID, archiver func(git.Worktree) (string, error), targets []RemovalTarget) ([]RemovalTarget, error) {
+	var kept []RemovalTarget
+	var errs error
+	for _, target := range targets {
+		if target.ArchiveStepID == "" {
+			kept = append(kept, target)
+			continue
+		}
+		result, err := execution.Run(phaseID, target.ArchiveStepID, func() (string, error) {
+			return archiver(target.Worktree)
+		})
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		if result.Error != nil {
+			_, err := execution.Skip(removalPhaseID, target.StepID, "Kept: archive failed")
+			errs = errors.Join(errs, err)
+			continue
+		}
+		kept = append(kept, target)
+	}
+	return kept, errs
 }
 
 type WorktreeOutcome struct {
@@ -35,6 +79,23 @@ type DeletionResult struct {
 	Err          error
 }
 
+// Removed returns the targets' worktrees that r reports as removed.
+func (r DeletionResult) Removed(targets []RemovalTarget) []git.Worktree {
+	removed := make(map[string]bool, len(r.Outcomes))
+	for _, o := range r.Outcomes {
+		if o.Success {
+			removed[o.Path] = true
+		}
+	}
+	var wts []git.Worktree
+	for _, target := range targets {
+		if removed[target.Worktree.Path] {
+			wts = append(wts, target.Worktree)
+		}
+	}
+	return wts
+}
+
 func (r DeletionResult) HasFailures() bool {
 	return r.Err != nil || r.FailureCount > 0 || progress.PhasesHaveFailures(r.Phases)
 }
@@ -52,6 +113,17 @@ func UnlockWorktree(runner git.CommandRunner, repoPath, wtPath string) error {
 	return err
 }
 
+// LockWorktree locks wtPath so git refuses to prune, move or remove it.
+// An empty reason locks without one.
+func LockWorktree(runner git.CommandRunner, repoPath, wtPath, reason string) error {
+	args := []string{"worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	_, err := runner.Run(repoPath, append(args, wtPath)...)
+	return err
+}
+
 func DeleteWorktrees(execution *progress.Execution, phaseID progress.PhaseID, remover func(string) error, targets []RemovalTarget, maxConcurrency int) DeletionResult {
 	if len(targets) == 0 {
 		return DeletionResult{}
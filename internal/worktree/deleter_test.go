@@ -303,6 +303,22 @@ func TestUnlockWorktree_NotLocked_NoError(t *testing.T) {
 	}
 }
 
+func TestLockWorktree_PassesReason(t *testing.T) {
+	runner := &mock.Runner{
+		Responses: map[string]mock.Response{
+			"/repo:[worktree lock --reason on a usb drive /repo/wt]": {Output: ""},
+			"/repo:[worktree lock /repo/other]":                      {Output: ""},
+		},
+	}
+
+	if err := LockWorktree(runner, "/repo", "/repo/wt", "on a usb drive"); err != nil {
+		t.Errorf("LockWorktree() with reason error = %v", err)
+	}
+	if err := LockWorktree(runner, "/repo", "/repo/other", ""); err != nil {
+		t.Errorf("LockWorktree() without reason error = %v", err)
+	}
+}
+
 func TestUnlockWorktree_UnlocksLockedWorktree(t *testing.T) {
 	tmp := t.TempDir()
 
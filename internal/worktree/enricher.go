@@ -1,6 +1,7 @@
 package worktree
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 	"sync"
@@ -9,6 +10,36 @@ import (
 	"github.com/abiswas97/sentei/internal/git"
 )
 
+// Enrichment runs in two stages. EnrichRefs answers everything that lives in
+// refs and objects — commit date and subject, upstream tracking, divergence
+// from the default branch, unpushed commits — with a handful of repo-wide
+// git calls, however many worktrees there are. EnrichStatus then does the
+// only per-worktree work, status, in two passes: tracked changes first
+// (--untracked-files=no, cheap), then the untracked-file scan, reporting each
+// worktree as it settles so a UI can fill rows in progressively.
+
+// DefaultEnrichConcurrency is the default parallelism for worktree enrichment.
+const DefaultEnrichConcurrency = 10
+
+// fieldSep separates fields in batched for-each-ref and log output; commit
+// subjects cannot contain it.
+const fieldSep = "\x1f"
+
+// branchRefFormat is the for-each-ref format for one branch per line:
+// refname, tip, author date, upstream, upstream track, subject.
+const branchRefFormat = "--format=%(refname)%1f%(objectname)%1f%(authordate:iso)%1f%(upstream:short)%1f%(upstream:track,nobracket)%1f%(subject)"
+
+// Update reports one worktree's enrichment progress. Index is its position in
+// the slice given to EnrichStatus. Final is set once nothing more will change:
+// the untracked scan finished, or enrichment failed. Cached is set when the
+// status came from the cache instead of a pass, and may be stale.
+type Update struct {
+	Index    int
+	Worktree git.Worktree
+	Final    bool
+	Cached   bool
+}
+
 func ParseStatusPorcelain(output string) (hasUncommitted bool, hasUntracked bool) {
 	output = strings.TrimSpace(output)
 	if output == "" {
@@ -36,80 +67,122 @@ func ParseCommitDate(output string) (time.Time, error) {
 	return time.Parse("2006-01-02 15:04:05 -0700", output)
 }
 
-func enrichWorktree(runner git.CommandRunner, wt *git.Worktree, repoHasRemotes bool) {
-	if wt.IsBare || wt.IsPrunable {
-		return
-	}
+// EnrichWorktrees runs both enrichment stages, uncached, and returns once
+// every worktree has settled.
+func EnrichWorktrees(runner git.CommandRunner, worktrees []git.Worktree, maxConcurrency int) []git.Worktree {
+	worktrees = EnrichRefs(runner, worktrees)
+	return EnrichStatus(runner, worktrees, maxConcurrency, nil, nil)
+}
 
-	dateOutput, err := runner.Run(wt.Path, "log", "-1", "--format=%ai")
-	if err != nil {
-		wt.EnrichmentError = err.Error()
-		return
-	}
+// branchRef is one line of branchRefFormat output.
+type branchRef struct {
+	sha      string
+	date     string
+	upstream string
+	track    string
+	subject  string
+}
 
-	commitDate, err := ParseCommitDate(dateOutput)
-	if err != nil {
-		wt.EnrichmentError = err.Error()
-		return
+// commitInfo is a commit's author date and subject.
+type commitInfo struct {
+	date    string
+	subject string
+}
+
+// EnrichRefs fills commit date and subject, upstream tracking, default-branch
+// divergence and HasUnpushedCommits for every live worktree. A worktree whose
+// commit cannot be read gets an EnrichmentError, which EnrichStatus respects.
+func EnrichRefs(runner git.CommandRunner, worktrees []git.Worktree) []git.Worktree {
+	var live []int
+	for i := range worktrees {
+		if !worktrees[i].IsBare && !worktrees[i].IsPrunable {
+			live = append(live, i)
+		}
+	}
+	dir := batchDir(worktrees)
+	if len(live) == 0 || dir == "" {
+		return worktrees
 	}
-	wt.LastCommitDate = commitDate
 
-	subject, err := runner.Run(wt.Path, "log", "-1", "--format=%s")
+	refs, err := readBranchRefs(runner, dir)
 	if err != nil {
-		wt.EnrichmentError = err.Error()
-		return
+		for _, i := range live {
+			worktrees[i].EnrichmentError = err.Error()
+		}
+		return worktrees
 	}
-	wt.LastCommitSubject = strings.TrimSpace(subject)
 
-	statusOutput, err := runner.Run(wt.Path, "status", "--porcelain")
-	if err != nil {
-		wt.EnrichmentError = err.Error()
-		return
+	// Detached worktrees (and any whose branch vanished since listing) are
+	// looked up by HEAD in one log call.
+	var loose []string
+	for _, i := range live {
+		if _, ok := refs[worktrees[i].Branch]; !ok && worktrees[i].HEAD != "" {
+			loose = append(loose, worktrees[i].HEAD)
+		}
+	}
+	commits := make(map[string]commitInfo)
+	var looseErr error
+	if len(loose) > 0 {
+		commits, looseErr = readCommits(runner, dir, loose)
 	}
-	wt.HasUncommittedChanges, wt.HasUntrackedFiles = ParseStatusPorcelain(statusOutput)
 
-	// In a repo with remotes, "unpushed" means HEAD has commits that no
-	// remote-tracking branch contains. Counting against every remote ref (not
-	// just @{upstream}) keeps a branch whose upstream was deleted after merge
-	// from being false-flagged: its commits still live in the default branch
-	// on the remote. Without any remote, "unpushed" is meaningless.
-	if repoHasRemotes {
-		if countOutput, err := runner.Run(wt.Path, "rev-list", "--count", "HEAD", "--not", "--remotes"); err != nil {
-			wt.HasUnpushedCommits = true
+	for _, i := range live {
+		wt := &worktrees[i]
+		var info commitInfo
+		if ref, ok := refs[wt.Branch]; ok {
+			info = commitInfo{date: ref.date, subject: ref.subject}
+			if ref.upstream != "" {
+				wt.Upstream = ref.upstream
+				wt.UpstreamGone, wt.Ahead, wt.Behind = ParseTrack(ref.track)
+			}
+		} else if c, ok := commits[wt.HEAD]; ok {
+			info = c
 		} else {
-			wt.HasUnpushedCommits = ParseAheadCount(countOutput) > 0
+			switch {
+			case looseErr != nil:
+				wt.EnrichmentError = looseErr.Error()
+			case wt.HEAD == "":
+				wt.EnrichmentError = "no commit checked out"
+			default:
+				wt.EnrichmentError = fmt.Sprintf("commit %s not found", wt.HEAD)
+			}
+			continue
 		}
+		date, err := ParseCommitDate(info.date)
+		if err != nil {
+			wt.EnrichmentError = err.Error()
+			continue
+		}
+		wt.LastCommitDate = date
+		wt.LastCommitSubject = info.subject
 	}
 
-	wt.IsEnriched = true
-}
+	enrichDefaultDivergence(runner, dir, worktrees, live, detectDefaultBranch(runner, worktrees))
 
-// ParseAheadCount parses `git rev-list --count` output; unparsable output
-// counts as ahead so the safety gate errs toward caution.
-func ParseAheadCount(output string) int {
-	n, err := strconv.Atoi(strings.TrimSpace(output))
-	if err != nil {
-		return 1
+	if out, err := runner.Run(dir, "remote"); err == nil && strings.TrimSpace(out) != "" {
+		enrichUnpushed(runner, dir, worktrees, live)
 	}
-	return n
+	return worktrees
 }
 
-// DefaultEnrichConcurrency is the default parallelism for worktree enrichment.
-const DefaultEnrichConcurrency = 10
-
-func EnrichWorktrees(runner git.CommandRunner, worktrees []git.Worktree, maxConcurrency int) []git.Worktree {
+// EnrichStatus runs the status passes for every live worktree that EnrichRefs
+// did not fail, calling emit (serialized, possibly from several goroutines)
+// after each pass. A worktree is IsEnriched once both passes succeeded. With
+// a cache, a worktree whose record still matches skips both passes and
+// reports once, final; fresh results are stored back. Saving is the caller's.
+func EnrichStatus(runner git.CommandRunner, worktrees []git.Worktree, maxConcurrency int, cache *Cache, emit func(Update)) []git.Worktree {
 	if maxConcurrency <= 0 {
-		maxConcurrency = 10
+		maxConcurrency = DefaultEnrichConcurrency
 	}
 
-	repoHasRemotes := false
-	for i := range worktrees {
-		if worktrees[i].IsBare || worktrees[i].IsPrunable {
-			continue
+	var emitMu sync.Mutex
+	send := func(idx int, final, cached bool) {
+		if emit == nil {
+			return
 		}
-		out, err := runner.Run(worktrees[i].Path, "remote")
-		repoHasRemotes = err == nil && strings.TrimSpace(out) != ""
-		break
+		emitMu.Lock()
+		defer emitMu.Unlock()
+		emit(Update{Index: idx, Worktree: worktrees[idx], Final: final, Cached: cached})
 	}
 
 	sem := make(chan struct{}, maxConcurrency)
@@ -119,6 +192,14 @@ func EnrichWorktrees(runner git.CommandRunner, worktrees []git.Worktree, maxConc
 		if worktrees[i].IsBare || worktrees[i].IsPrunable {
 			continue
 		}
+		if worktrees[i].EnrichmentError != "" {
+			send(i, true, false)
+			continue
+		}
+		if cache != nil && cache.Lookup(&worktrees[i]) {
+			send(i, true, true)
+			continue
+		}
 
 		wg.Add(1)
 		sem <- struct{}{}
@@ -126,10 +207,237 @@ func EnrichWorktrees(runner git.CommandRunner, worktrees []git.Worktree, maxConc
 		go func(idx int) {
 			defer wg.Done()
 			defer func() { <-sem }()
-			enrichWorktree(runner, &worktrees[idx], repoHasRemotes)
+
+			wt := &worktrees[idx]
+			out, err := runner.Run(wt.Path, "status", "--porcelain", "--untracked-files=no")
+			if err != nil {
+				wt.EnrichmentError = err.Error()
+				send(idx, true, false)
+				return
+			}
+			wt.HasUncommittedChanges, _ = ParseStatusPorcelain(out)
+			send(idx, false, false)
+
+			out, err = runner.Run(wt.Path, "ls-files", "--others", "--exclude-standard", "--directory", "--no-empty-directory")
+			if err != nil {
+				wt.EnrichmentError = err.Error()
+				send(idx, true, false)
+				return
+			}
+			wt.HasUntrackedFiles = strings.TrimSpace(out) != ""
+			wt.IsEnriched = true
+			if cache != nil {
+				cache.Store(*wt)
+			}
+			send(idx, true, false)
 		}(i)
 	}
 
 	wg.Wait()
 	return worktrees
 }
+
+// batchDir is where repo-wide calls run: the bare entry, else the first
+// worktree that still exists.
+func batchDir(worktrees []git.Worktree) string {
+	for _, wt := range worktrees {
+		if wt.IsBare {
+			return wt.Path
+		}
+	}
+	for _, wt := range worktrees {
+		if !wt.IsPrunable {
+			return wt.Path
+		}
+	}
+	return ""
+}
+
+// readBranchRefs reads every local branch in one for-each-ref call, keyed by
+// full ref name.
+func readBranchRefs(runner git.CommandRunner, dir string) (map[string]branchRef, error) {
+	out, err := runner.Run(dir, "for-each-ref", branchRefFormat, "refs/heads")
+	if err != nil {
+		return nil, fmt.Errorf("reading branches: %w", err)
+	}
+	refs := make(map[string]branchRef)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, fieldSep, 6)
+		if len(fields) != 6 {
+			continue
+		}
+		refs[fields[0]] = branchRef{
+			sha:      fields[1],
+			date:     fields[2],
+			upstream: fields[3],
+			track:    fields[4],
+			subject:  fields[5],
+		}
+	}
+	return refs, nil
+}
+
+// readCommits reads the author date and subject of each sha in one log call.
+func readCommits(runner git.CommandRunner, dir string, shas []string) (map[string]commitInfo, error) {
+	args := append([]string{"log", "--no-walk=unsorted", "--format=%H%x1f%ai%x1f%s"}, shas...)
+	out, err := runner.Run(dir, args...)
+	if err != nil {
+		return nil, fmt.Errorf("reading commits: %w", err)
+	}
+	commits := make(map[string]commitInfo)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, fieldSep, 3)
+		if len(fields) == 3 {
+			commits[fields[0]] = commitInfo{date: fields[1], subject: fields[2]}
+		}
+	}
+	return commits, nil
+}
+
+// enrichDefaultDivergence fills DefaultAhead/DefaultBehind. Branches are
+// counted in one for-each-ref call with %(ahead-behind) (git 2.41+); on older
+// git, and for detached worktrees, each worktree falls back to rev-list.
+func enrichDefaultDivergence(runner git.CommandRunner, dir string, worktrees []git.Worktree, live []int, defaultBranch string) {
+	if defaultBranch == "" {
+		return
+	}
+	defaultRef := "refs/heads/" + defaultBranch
+
+	counts := make(map[string][2]int)
+	batched := false
+	if out, err := runner.Run(dir, "for-each-ref", "--format=%(refname)%1f%(ahead-behind:"+defaultRef+")", "refs/heads"); err == nil {
+		batched = true
+		for _, line := range strings.Split(out, "\n") {
+			ref, ab, ok := strings.Cut(line, fieldSep)
+			if !ok {
+				continue
+			}
+			if ahead, behind, ok := ParseLeftRightCount(ab); ok {
+				counts[ref] = [2]int{ahead, behind}
+			}
+		}
+	}
+
+	var fallback []int
+	for _, i := range live {
+		wt := &worktrees[i]
+		if wt.EnrichmentError != "" || wt.Branch == defaultRef {
+			continue
+		}
+		if c, ok := counts[wt.Branch]; ok {
+			wt.DefaultAhead, wt.DefaultBehind = c[0], c[1]
+		} else if !batched || wt.Branch == "" {
+			fallback = append(fallback, i)
+		}
+	}
+
+	sem := make(chan struct{}, DefaultEnrichConcurrency)
+	var wg sync.WaitGroup
+	for _, i := range fallback {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(wt *git.Worktree) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			out, err := runner.Run(wt.Path, "rev-list", "--left-right", "--count", "HEAD..."+defaultRef)
+			if err == nil {
+				wt.DefaultAhead, wt.DefaultBehind, _ = ParseLeftRightCount(out)
+			}
+		}(&worktrees[i])
+	}
+	wg.Wait()
+}
+
+// enrichUnpushed sets HasUnpushedCommits in one rev-list call. "Unpushed"
+// means HEAD has commits no remote-tracking branch contains; counting against
+// every remote ref (not just @{upstream}) keeps a branch whose upstream was
+// deleted after merge from being false-flagged, since its commits still live
+// in the default branch on the remote. A HEAD reachable from a remote has
+// all its ancestors there too, so HEAD is unpushed exactly when rev-list
+// lists it. If the call fails, every worktree counts as unpushed so the
+// safety gate errs toward caution.
+func enrichUnpushed(runner git.CommandRunner, dir string, worktrees []git.Worktree, live []int) {
+	var heads []string
+	seen := make(map[string]bool)
+	for _, i := range live {
+		if head := worktrees[i].HEAD; head != "" && !seen[head] {
+			seen[head] = true
+			heads = append(heads, head)
+		}
+	}
+	if len(heads) == 0 {
+		return
+	}
+
+	out, err := runner.Run(dir, append(append([]string{"rev-list"}, heads...), "--not", "--remotes")...)
+	unpushed := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		unpushed[strings.TrimSpace(line)] = true
+	}
+	for _, i := range live {
+		worktrees[i].HasUnpushedCommits = err != nil || unpushed[worktrees[i].HEAD]
+	}
+}
+
+// ParseTrack parses %(upstream:track,nobracket): empty when in sync, "gone",
+// or "ahead N", "behind N" or "ahead N, behind M".
+func ParseTrack(track string) (gone bool, ahead, behind int) {
+	track = strings.TrimSpace(track)
+	if track == "gone" {
+		return true, 0, 0
+	}
+	for _, part := range strings.Split(track, ",") {
+		label, count, ok := strings.Cut(strings.TrimSpace(part), " ")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			continue
+		}
+		switch label {
+		case "ahead":
+			ahead = n
+		case "behind":
+			behind = n
+		}
+	}
+	return false, ahead, behind
+}
+
+// ParseLeftRightCount parses `git rev-list --left-right --count A...B`
+// (or %(ahead-behind)) output into the commits only on A and only on B.
+func ParseLeftRightCount(output string) (left, right int, ok bool) {
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, false
+	}
+	left, lErr := strconv.Atoi(fields[0])
+	right, rErr := strconv.Atoi(fields[1])
+	if lErr != nil || rErr != nil {
+		return 0, 0, false
+	}
+	return left, right, true
+}
+
+// detectDefaultBranch finds the branch DefaultAhead/DefaultBehind compare
+// against. The bare entry's HEAD is the remote's default in a bare clone;
+// in a regular clone, origin/HEAD says the same without depending on what
+// the main worktree has checked out.
+func detectDefaultBranch(runner git.CommandRunner, worktrees []git.Worktree) string {
+	for _, wt := range worktrees {
+		if wt.IsBare {
+			return git.DetectDefaultBranch(runner, wt.Path)
+		}
+	}
+	for _, wt := range worktrees {
+		if wt.IsPrunable {
+			continue
+		}
+		if out, err := runner.Run(wt.Path, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil {
+			return strings.TrimPrefix(strings.TrimSpace(out), "origin/")
+		}
+		return git.DetectDefaultBranch(runner, wt.Path)
+	}
+	return ""
+}
@@ -2,6 +2,7 @@ package worktree
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -125,231 +126,308 @@ func TestParseCommitDate(t *testing.T) {
 	}
 }
 
-func TestEnrichWorktree_Success(t *testing.T) {
-	runner := &mock.Runner{
-		Responses: map[string]mock.Response{
-			"/work/feature:[log -1 --format=%ai]": {Output: "2024-06-01 12:00:00 +0000"},
-			"/work/feature:[log -1 --format=%s]":  {Output: "Add feature X"},
-			"/work/feature:[status --porcelain]":  {Output: " M file.go\n?? new.txt"},
-		},
-	}
+const testRefFormat = "--format=%(refname)%1f%(objectname)%1f%(authordate:iso)%1f%(upstream:short)%1f%(upstream:track,nobracket)%1f%(subject)"
+
+const testAheadBehindFormat = "--format=%(refname)%1f%(ahead-behind:refs/heads/main)"
 
-	wt := &git.Worktree{Path: "/work/feature"}
-	enrichWorktree(runner, wt, false)
+func refLine(fields ...string) string {
+	return strings.Join(fields, "\x1f")
+}
 
-	if wt.EnrichmentError != "" {
-		t.Fatalf("unexpected error: %s", wt.EnrichmentError)
+// refsRunner answers the repo-wide calls EnrichRefs makes from /repo: one
+// for-each-ref over branches, the default branch and the remotes probe.
+func refsRunner(refs string, extra map[string]mock.Response) *mock.Runner {
+	responses := map[string]mock.Response{
+		"/repo:[for-each-ref " + testRefFormat + " refs/heads]":         {Output: refs},
+		"/repo:[symbolic-ref --short HEAD]":                             {Output: "main"},
+		"/repo:[for-each-ref " + testAheadBehindFormat + " refs/heads]": {Output: ""},
+		"/repo:[remote]": {Output: ""},
 	}
-	if !wt.IsEnriched {
-		t.Error("expected IsEnriched=true")
+	for k, v := range extra {
+		responses[k] = v
 	}
-	if wt.LastCommitSubject != "Add feature X" {
-		t.Errorf("LastCommitSubject = %q, want %q", wt.LastCommitSubject, "Add feature X")
+	return &mock.Runner{Responses: responses}
+}
+
+func TestEnrichRefs_ReadsAllBranchesInOneCall(t *testing.T) {
+	refs := strings.Join([]string{
+		refLine("refs/heads/main", "aaa", "2024-06-01 12:00:00 +0000", "origin/main", "", "Release"),
+		refLine("refs/heads/feature", "bbb", "2024-05-01 08:30:00 +0000", "origin/feature", "ahead 1, behind 4", "Add feature X"),
+		refLine("refs/heads/stale", "ccc", "2023-01-01 00:00:00 +0000", "origin/stale", "gone", "Old work"),
+	}, "\n")
+	runner := refsRunner(refs, map[string]mock.Response{
+		"/repo:[for-each-ref " + testAheadBehindFormat + " refs/heads]": {Output: "refs/heads/main\x1f0 0\nrefs/heads/feature\x1f2 7\nrefs/heads/stale\x1f1 9"},
+	})
+
+	worktrees := []git.Worktree{
+		{Path: "/repo", IsBare: true},
+		{Path: "/wt/main", Branch: "refs/heads/main", HEAD: "aaa"},
+		{Path: "/wt/feature", Branch: "refs/heads/feature", HEAD: "bbb"},
+		{Path: "/wt/stale", Branch: "refs/heads/stale", HEAD: "ccc"},
+		{Path: "/wt/gone", IsPrunable: true},
 	}
-	if !wt.HasUncommittedChanges {
-		t.Error("expected HasUncommittedChanges=true")
+	result := EnrichRefs(runner, worktrees)
+
+	feature := result[2]
+	if feature.EnrichmentError != "" {
+		t.Fatalf("unexpected error: %s", feature.EnrichmentError)
 	}
-	if !wt.HasUntrackedFiles {
-		t.Error("expected HasUntrackedFiles=true")
+	if feature.LastCommitSubject != "Add feature X" {
+		t.Errorf("LastCommitSubject = %q, want %q", feature.LastCommitSubject, "Add feature X")
 	}
-	expectedDate := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
-	if !wt.LastCommitDate.Equal(expectedDate) {
-		t.Errorf("LastCommitDate = %v, want %v", wt.LastCommitDate, expectedDate)
+	if want := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC); !feature.LastCommitDate.Equal(want) {
+		t.Errorf("LastCommitDate = %v, want %v", feature.LastCommitDate, want)
 	}
-}
-
-func TestEnrichWorktree_LogCommandFails(t *testing.T) {
-	runner := &mock.Runner{
-		Responses: map[string]mock.Response{
-			"/work/broken:[log -1 --format=%ai]": {Err: fmt.Errorf("git log: fatal: not a git repository")},
-		},
+	if feature.Upstream != "origin/feature" || !feature.HasUpstream() || feature.Ahead != 1 || feature.Behind != 4 {
+		t.Errorf("upstream = %q (%d ahead, %d behind), want origin/feature 1/4", feature.Upstream, feature.Ahead, feature.Behind)
 	}
-
-	wt := &git.Worktree{Path: "/work/broken"}
-	enrichWorktree(runner, wt, false)
-
-	if wt.EnrichmentError == "" {
-		t.Error("expected EnrichmentError to be set")
+	if feature.DefaultAhead != 2 || feature.DefaultBehind != 7 {
+		t.Errorf("default divergence = %d/%d, want 2/7", feature.DefaultAhead, feature.DefaultBehind)
 	}
-	if wt.IsEnriched {
-		t.Error("expected IsEnriched=false")
+	if !result[3].UpstreamGone || result[3].HasUpstream() {
+		t.Errorf("stale upstream should be gone, got %+v", result[3])
 	}
-}
-
-func TestEnrichWorktree_StatusCommandFails(t *testing.T) {
-	runner := &mock.Runner{
-		Responses: map[string]mock.Response{
-			"/work/broken:[log -1 --format=%ai]": {Output: "2024-01-01 00:00:00 +0000"},
-			"/work/broken:[log -1 --format=%s]":  {Output: "Some commit"},
-			"/work/broken:[status --porcelain]":  {Err: fmt.Errorf("git status: permission denied")},
-		},
+	if result[1].Upstream != "origin/main" || result[1].DefaultAhead != 0 || result[1].DefaultBehind != 0 {
+		t.Errorf("main = %+v", result[1])
 	}
-
-	wt := &git.Worktree{Path: "/work/broken"}
-	enrichWorktree(runner, wt, false)
-
-	if wt.EnrichmentError == "" {
-		t.Error("expected EnrichmentError to be set")
+	if result[0].LastCommitSubject != "" || result[4].LastCommitSubject != "" {
+		t.Error("bare and prunable entries should not be enriched")
 	}
-	if wt.IsEnriched {
-		t.Error("expected IsEnriched=false")
+
+	// No per-worktree git process runs in this stage.
+	for _, call := range runner.Calls {
+		if !strings.HasPrefix(call, "/repo:") {
+			t.Errorf("unexpected per-worktree call %s", call)
+		}
 	}
 }
 
-func TestEnrichWorktrees_MixedSlice(t *testing.T) {
-	runner := &mock.Runner{
-		Responses: map[string]mock.Response{
-			"/work/normal:[log -1 --format=%ai]": {Output: "2024-03-15 09:00:00 +0000"},
-			"/work/normal:[log -1 --format=%s]":  {Output: "Normal commit"},
-			"/work/normal:[status --porcelain]":  {Output: ""},
-		},
-	}
+func TestEnrichRefs_DetachedUsesOneLogCall(t *testing.T) {
+	runner := refsRunner("", map[string]mock.Response{
+		"/repo:[log --no-walk=unsorted --format=%H%x1f%ai%x1f%s ddd eee]": {Output: refLine("ddd", "2024-03-15 09:00:00 +0000", "Detached commit")},
+		"/wt/d1:[rev-list --left-right --count HEAD...refs/heads/main]":   {Output: "3\t1"},
+	})
 
 	worktrees := []git.Worktree{
 		{Path: "/repo", IsBare: true},
-		{Path: "/work/normal"},
-		{Path: "/work/gone", IsPrunable: true, PruneReason: "directory not found"},
+		{Path: "/wt/d1", HEAD: "ddd", IsDetached: true},
+		{Path: "/wt/d2", HEAD: "eee", IsDetached: true},
 	}
+	result := EnrichRefs(runner, worktrees)
 
-	result := EnrichWorktrees(runner, worktrees, 5)
-
-	if result[0].IsEnriched {
-		t.Error("bare entry should not be enriched")
-	}
-	if !result[1].IsEnriched {
-		t.Error("normal entry should be enriched")
+	if result[1].LastCommitSubject != "Detached commit" || result[1].EnrichmentError != "" {
+		t.Errorf("d1 = %+v", result[1])
 	}
-	if result[1].LastCommitSubject != "Normal commit" {
-		t.Errorf("LastCommitSubject = %q, want %q", result[1].LastCommitSubject, "Normal commit")
+	if result[1].DefaultAhead != 3 || result[1].DefaultBehind != 1 {
+		t.Errorf("d1 default divergence = %d/%d, want 3/1", result[1].DefaultAhead, result[1].DefaultBehind)
 	}
-	if result[2].IsEnriched {
-		t.Error("prunable entry should not be enriched")
+	if result[2].EnrichmentError == "" {
+		t.Error("a commit missing from the log output should be an enrichment error")
 	}
 }
 
-func TestEnrichWorktrees_PartialFailure(t *testing.T) {
-	runner := &mock.Runner{
-		Responses: map[string]mock.Response{
-			"/work/ok:[log -1 --format=%ai]":     {Output: "2024-03-15 09:00:00 +0000"},
-			"/work/ok:[log -1 --format=%s]":      {Output: "Good commit"},
-			"/work/ok:[status --porcelain]":      {Output: ""},
-			"/work/broken:[log -1 --format=%ai]": {Err: fmt.Errorf("directory missing")},
-		},
-	}
+func TestEnrichRefs_BatchFailureMarksEveryWorktree(t *testing.T) {
+	runner := refsRunner("", map[string]mock.Response{
+		"/repo:[for-each-ref " + testRefFormat + " refs/heads]": {Err: fmt.Errorf("fatal: not a git repository")},
+	})
 
 	worktrees := []git.Worktree{
-		{Path: "/work/ok"},
-		{Path: "/work/broken"},
+		{Path: "/repo", IsBare: true},
+		{Path: "/wt/a", Branch: "refs/heads/a", HEAD: "aaa"},
+		{Path: "/wt/b", Branch: "refs/heads/b", HEAD: "bbb"},
 	}
+	result := EnrichRefs(runner, worktrees)
 
-	result := EnrichWorktrees(runner, worktrees, 5)
-
-	if !result[0].IsEnriched {
-		t.Error("first worktree should be enriched")
+	for _, wt := range result[1:] {
+		if wt.EnrichmentError == "" {
+			t.Errorf("%s should have an enrichment error", wt.Path)
+		}
 	}
 	if result[0].EnrichmentError != "" {
-		t.Errorf("first worktree should have no error, got: %s", result[0].EnrichmentError)
-	}
-	if result[1].IsEnriched {
-		t.Error("second worktree should not be enriched")
-	}
-	if result[1].EnrichmentError == "" {
-		t.Error("second worktree should have enrichment error")
+		t.Error("bare entry should not carry an enrichment error")
 	}
 }
 
-func TestParseAheadCount(t *testing.T) {
-	cases := []struct {
-		output string
-		want   int
-	}{
-		{"0\n", 0},
-		{"3", 3},
-		{"  12  ", 12},
-		{"garbage", 1}, // unparsable errs toward "ahead" so the gate engages
-		{"", 1},
+func TestEnrichRefs_AheadBehindFallback(t *testing.T) {
+	// git before 2.41 has no %(ahead-behind); each worktree falls back to
+	// rev-list.
+	refs := refLine("refs/heads/feature", "bbb", "2024-05-01 08:30:00 +0000", "", "", "Add feature X")
+	runner := refsRunner(refs, map[string]mock.Response{
+		"/repo:[for-each-ref " + testAheadBehindFormat + " refs/heads]":      {Err: fmt.Errorf("fatal: unknown field name: ahead-behind")},
+		"/wt/feature:[rev-list --left-right --count HEAD...refs/heads/main]": {Output: "2\t7\n"},
+	})
+
+	worktrees := []git.Worktree{
+		{Path: "/repo", IsBare: true},
+		{Path: "/wt/feature", Branch: "refs/heads/feature", HEAD: "bbb"},
 	}
-	for _, tc := range cases {
-		if got := ParseAheadCount(tc.output); got != tc.want {
-			t.Errorf("ParseAheadCount(%q) = %d, want %d", tc.output, got, tc.want)
-		}
+	result := EnrichRefs(runner, worktrees)
+
+	if result[1].DefaultAhead != 2 || result[1].DefaultBehind != 7 {
+		t.Errorf("default divergence = %d/%d, want 2/7", result[1].DefaultAhead, result[1].DefaultBehind)
+	}
+	if result[1].HasUpstream() {
+		t.Error("a branch without an upstream should not report one")
 	}
 }
 
-func TestEnrichWorktree_UnpushedDetection(t *testing.T) {
-	base := map[string]mock.Response{
-		"/work/feature:[log -1 --format=%ai]": {Output: "2024-06-01 12:00:00 +0000"},
-		"/work/feature:[log -1 --format=%s]":  {Output: "Add feature X"},
-		"/work/feature:[status --porcelain]":  {Output: ""},
+func TestEnrichRefs_UnpushedDetection(t *testing.T) {
+	refs := strings.Join([]string{
+		refLine("refs/heads/pushed", "aaa", "2024-06-01 12:00:00 +0000", "", "", "Pushed"),
+		refLine("refs/heads/local", "bbb", "2024-06-01 12:00:00 +0000", "", "", "Local"),
+	}, "\n")
+	worktrees := func() []git.Worktree {
+		return []git.Worktree{
+			{Path: "/repo", IsBare: true},
+			{Path: "/wt/pushed", Branch: "refs/heads/pushed", HEAD: "aaa"},
+			{Path: "/wt/local", Branch: "refs/heads/local", HEAD: "bbb"},
+		}
 	}
+	const revList = "/repo:[rev-list aaa bbb --not --remotes]"
+
 	cases := []struct {
-		name         string
-		revList      *mock.Response
-		wantUnpushed bool
+		name       string
+		remotes    string
+		revList    *mock.Response
+		wantPushed bool // HasUnpushedCommits on /wt/pushed
+		wantLocal  bool // HasUnpushedCommits on /wt/local
 	}{
-		{"all commits reachable from a remote", &mock.Response{Output: "0"}, false},
-		{"commits reachable from no remote", &mock.Response{Output: "2"}, true},
-		{"probe failure errs toward caution", nil, true}, // rev-list error -> assume unpushed
+		// A branch whose upstream was deleted after merge still has its commits
+		// on a remote through the default branch, so only commits on no remote
+		// ref count.
+		{"only commits on no remote count", "origin", &mock.Response{Output: "bbb\nbbb-parent"}, false, true},
+		{"probe failure errs toward caution", "origin", nil, true, true},
+		{"no remotes means nothing is unpushed", "", nil, false, false},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			responses := make(map[string]mock.Response, len(base)+1)
-			for k, v := range base {
-				responses[k] = v
-			}
+			extra := map[string]mock.Response{"/repo:[remote]": {Output: tc.remotes}}
 			if tc.revList != nil {
-				responses["/work/feature:[rev-list --count HEAD --not --remotes]"] = *tc.revList
+				extra[revList] = *tc.revList
 			}
-			runner := &mock.Runner{Responses: responses}
+			result := EnrichRefs(refsRunner(refs, extra), worktrees())
 
-			wt := &git.Worktree{Path: "/work/feature"}
-			enrichWorktree(runner, wt, true)
-
-			if !wt.IsEnriched {
-				t.Fatalf("enrichment failed: %s", wt.EnrichmentError)
-			}
-			if wt.HasUnpushedCommits != tc.wantUnpushed {
-				t.Errorf("HasUnpushedCommits = %v, want %v", wt.HasUnpushedCommits, tc.wantUnpushed)
+			if result[1].HasUnpushedCommits != tc.wantPushed || result[2].HasUnpushedCommits != tc.wantLocal {
+				t.Errorf("unpushed = %v/%v, want %v/%v", result[1].HasUnpushedCommits, result[2].HasUnpushedCommits, tc.wantPushed, tc.wantLocal)
 			}
 		})
 	}
 }
 
-func TestEnrichWorktree_NoRemotesNeverUnpushed(t *testing.T) {
-	runner := &mock.Runner{
-		Responses: map[string]mock.Response{
-			"/work/feature:[log -1 --format=%ai]": {Output: "2024-06-01 12:00:00 +0000"},
-			"/work/feature:[log -1 --format=%s]":  {Output: "Add feature X"},
-			"/work/feature:[status --porcelain]":  {Output: ""},
-		},
+func TestEnrichStatus_TwoPassesEmitProgressively(t *testing.T) {
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/wt/dirty:[status --porcelain --untracked-files=no]":                               {Output: " M file.go"},
+		"/wt/dirty:[ls-files --others --exclude-standard --directory --no-empty-directory]": {Output: "new.txt"},
+		"/wt/clean:[status --porcelain --untracked-files=no]":                               {Output: ""},
+		"/wt/clean:[ls-files --others --exclude-standard --directory --no-empty-directory]": {Output: ""},
+	}}
+
+	worktrees := []git.Worktree{
+		{Path: "/repo", IsBare: true},
+		{Path: "/wt/dirty"},
+		{Path: "/wt/clean"},
+		{Path: "/wt/failed", EnrichmentError: "commit not found"},
+	}
+
+	var updates []Update
+	result := EnrichStatus(runner, worktrees, 2, nil, func(u Update) {
+		updates = append(updates, u)
+	})
+
+	if !result[1].IsEnriched || !result[1].HasUncommittedChanges || !result[1].HasUntrackedFiles {
+		t.Errorf("dirty = %+v", result[1])
+	}
+	if !result[2].IsEnriched || result[2].HasUncommittedChanges || result[2].HasUntrackedFiles {
+		t.Errorf("clean = %+v", result[2])
+	}
+	if result[0].IsEnriched || result[3].IsEnriched {
+		t.Error("bare and failed entries should not be enriched")
+	}
+
+	// Each enriched worktree reports twice — after the tracked pass, then
+	// final — and the failed one once, final. Nothing for the bare entry.
+	seen := map[int][]bool{}
+	for _, u := range updates {
+		seen[u.Index] = append(seen[u.Index], u.Final)
+	}
+	for _, idx := range []int{1, 2} {
+		if got := seen[idx]; len(got) != 2 || got[0] || !got[1] {
+			t.Errorf("updates for %d = %v, want [false true]", idx, got)
+		}
+	}
+	if got := seen[3]; len(got) != 1 || !got[0] {
+		t.Errorf("updates for failed = %v, want [true]", got)
+	}
+	if _, ok := seen[0]; ok {
+		t.Error("bare entry should not report")
 	}
 
-	wt := &git.Worktree{Path: "/work/feature"}
-	enrichWorktree(runner, wt, false)
+	// The first update for the dirty worktree already carries the tracked
+	// changes but not yet the untracked scan.
+	for _, u := range updates {
+		if u.Index == 1 && !u.Final {
+			if !u.Worktree.HasUncommittedChanges || u.Worktree.HasUntrackedFiles || u.Worktree.IsEnriched {
+				t.Errorf("first-pass update = %+v", u.Worktree)
+			}
+		}
+	}
+}
+
+func TestEnrichStatus_CommandFails(t *testing.T) {
+	runner := &mock.Runner{Responses: map[string]mock.Response{
+		"/wt/broken:[status --porcelain --untracked-files=no]": {Err: fmt.Errorf("git status: permission denied")},
+	}}
+
+	result := EnrichStatus(runner, []git.Worktree{{Path: "/wt/broken"}}, 1, nil, nil)
 
-	if wt.HasUnpushedCommits {
-		t.Error("a repo without remotes must not flag branches as unpushed")
+	if result[0].EnrichmentError == "" {
+		t.Error("expected EnrichmentError to be set")
+	}
+	if result[0].IsEnriched {
+		t.Error("expected IsEnriched=false")
 	}
 }
 
-// A branch whose upstream was deleted after merge still has all its commits on
-// a remote (via the merge into the default branch), so it must not be flagged
-// unpushed. The old @{upstream}..HEAD probe errored on the missing upstream and
-// false-flagged it; HEAD --not --remotes counts commits on no remote (0 here).
-func TestEnrichWorktree_GoneUpstreamMergedNotUnpushed(t *testing.T) {
-	runner := &mock.Runner{
-		Responses: map[string]mock.Response{
-			"/work/feature:[log -1 --format=%ai]":                   {Output: "2024-06-01 12:00:00 +0000"},
-			"/work/feature:[log -1 --format=%s]":                    {Output: "Add feature X"},
-			"/work/feature:[status --porcelain]":                    {Output: ""},
-			"/work/feature:[rev-list --count HEAD --not --remotes]": {Output: "0"},
-		},
+func TestEnrichWorktrees_RunsBothStages(t *testing.T) {
+	refs := refLine("refs/heads/feature", "bbb", "2024-03-15 09:00:00 +0000", "", "", "Normal commit")
+	runner := refsRunner(refs, map[string]mock.Response{
+		"/wt/feature:[status --porcelain --untracked-files=no]":                               {Output: ""},
+		"/wt/feature:[ls-files --others --exclude-standard --directory --no-empty-directory]": {Output: ""},
+	})
+
+	worktrees := []git.Worktree{
+		{Path: "/repo", IsBare: true},
+		{Path: "/wt/feature", Branch: "refs/heads/feature", HEAD: "bbb"},
+		{Path: "/wt/gone", IsPrunable: true, PruneReason: "directory not found"},
 	}
+	result := EnrichWorktrees(runner, worktrees, 5)
 
-	wt := &git.Worktree{Path: "/work/feature"}
-	enrichWorktree(runner, wt, true)
+	if result[0].IsEnriched || result[2].IsEnriched {
+		t.Error("bare and prunable entries should not be enriched")
+	}
+	if !result[1].IsEnriched || result[1].LastCommitSubject != "Normal commit" {
+		t.Errorf("feature = %+v", result[1])
+	}
+}
 
-	if wt.HasUnpushedCommits {
-		t.Error("a branch fully contained in a remote must not be flagged unpushed even with no upstream tracking")
+func TestParseTrack(t *testing.T) {
+	cases := []struct {
+		track      string
+		wantGone   bool
+		wantAhead  int
+		wantBehind int
+	}{
+		{"", false, 0, 0},
+		{"gone", true, 0, 0},
+		{"ahead 3", false, 3, 0},
+		{"behind 2", false, 0, 2},
+		{"ahead 3, behind 2", false, 3, 2},
+	}
+	for _, tc := range cases {
+		gone, ahead, behind := ParseTrack(tc.track)
+		if gone != tc.wantGone || ahead != tc.wantAhead || behind != tc.wantBehind {
+			t.Errorf("ParseTrack(%q) = (%v, %d, %d), want (%v, %d, %d)", tc.track,
+				gone, ahead, behind, tc.wantGone, tc.wantAhead, tc.wantBehind)
+		}
 	}
 }
@@ -0,0 +1,277 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/integration"
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/state"
+)
+
+const (
+	MovePhaseID             progress.PhaseID = "move-worktree"
+	MoveIntegrationsPhaseID progress.PhaseID = "move-integrations"
+	MoveRollbackPhaseID     progress.PhaseID = "move-rollback"
+)
+
+// MoveOptions describes one move. Branch renames the worktree's branch and,
+// when Path is empty, moves the directory to the name sentei gives that
+// branch; Path moves the directory without renaming the branch.
+type MoveOptions struct {
+	Worktree git.Worktree
+	Branch   string
+	Path     string
+	// RepoPath is where integrations whose setup runs in the repo run it.
+	RepoPath string
+	// Integrations are the enabled integrations; those set up in the
+	// worktree whose setup embeds {path} are set up again at the new path.
+	Integrations []integration.Integration
+}
+
+// MoveResult is the outcome of a move. Err is set when the move could not
+// be planned; step failures are in Phases.
+type MoveResult struct {
+	From      string
+	To        string
+	OldBranch string
+	NewBranch string
+	Phases    []progress.Phase
+	Err       error
+}
+
+func (r MoveResult) HasFailures() bool {
+	return r.Err != nil || progress.PhasesHaveFailures(r.Phases)
+}
+
+// SplitMoveTarget reads a move target as a path when it is absolute or
+// starts with ./ or ../, and as a branch name otherwise.
+func SplitMoveTarget(target string) (branch, path string) {
+	if filepath.IsAbs(target) || strings.HasPrefix(target, "./") || strings.HasPrefix(target, "../") {
+		return "", target
+	}
+	return target, ""
+}
+
+// PreparedMove is a validated move with its plan frozen.
+type PreparedMove struct {
+	opts         MoveOptions
+	commonDir    string
+	result       MoveResult
+	renameBranch bool
+	moveDir      bool
+	updateState  bool
+	integrations integration.PreparedApply
+	plan         progress.Plan
+}
+
+func (p PreparedMove) Plan() progress.Plan { return p.plan.Clone() }
+
+// PrepareMove checks everything that would stop the move halfway before
+// anything changes: the worktree, the new branch name and the destination.
+func PrepareMove(runner git.CommandRunner, opts MoveOptions) (PreparedMove, error) {
+	wt := opts.Worktree
+	switch {
+	case wt.IsBare:
+		return PreparedMove{}, errors.New("the bare repository cannot be moved")
+	case wt.IsPrunable:
+		return PreparedMove{}, fmt.Errorf("%s is missing; prune it instead", wt.Path)
+	case wt.IsLocked:
+		return PreparedMove{}, fmt.Errorf("%s is locked; unlock it first", wt.Path)
+	}
+	if info, err := os.Stat(filepath.Join(wt.Path, ".git")); err == nil && info.IsDir() {
+		return PreparedMove{}, fmt.Errorf("%s is the main worktree and cannot be moved", wt.Path)
+	}
+	commonDir, err := git.CommonDir(runner, wt.Path)
+	if err != nil {
+		return PreparedMove{}, err
+	}
+
+	oldBranch := strings.TrimPrefix(wt.Branch, "refs/heads/")
+	p := PreparedMove{
+		opts:      opts,
+		commonDir: commonDir,
+		result:    MoveResult{From: wt.Path, To: wt.Path, OldBranch: oldBranch, NewBranch: oldBranch},
+	}
+
+	if opts.Branch != "" && opts.Branch != oldBranch {
+		if oldBranch == "" {
+			return PreparedMove{}, fmt.Errorf("%s has no branch to rename; give a path instead", wt.Path)
+		}
+		if _, err := runner.Run(commonDir, "check-ref-format", "--branch", opts.Branch); err != nil {
+			return PreparedMove{}, fmt.Errorf("invalid branch name %q", opts.Branch)
+		}
+		if git.BranchExists(runner, commonDir, opts.Branch) {
+			return PreparedMove{}, fmt.Errorf("branch %q already exists", opts.Branch)
+		}
+		p.renameBranch = true
+		p.result.NewBranch = opts.Branch
+	}
+
+	to := opts.Path
+	if to == "" && p.renameBranch {
+		to = filepath.Join(filepath.Dir(wt.Path), git.WorktreeDirName(opts.Branch))
+	}
+	if to != "" {
+		if to, err = filepath.Abs(to); err != nil {
+			return PreparedMove{}, err
+		}
+		if filepath.Clean(to) != filepath.Clean(wt.Path) {
+			if _, err := os.Lstat(to); err == nil {
+				return PreparedMove{}, fmt.Errorf("%s already exists", to)
+			}
+			if info, err := os.Stat(filepath.Dir(to)); err != nil || !info.IsDir() {
+				return PreparedMove{}, fmt.Errorf("%s is not a directory", filepath.Dir(to))
+			}
+			p.moveDir = true
+			p.result.To = to
+		}
+	}
+	if !p.renameBranch && !p.moveDir {
+		return PreparedMove{}, fmt.Errorf("%s is already there", wt.Path)
+	}
+
+	phase := progress.PlannedPhase{ID: MovePhaseID, Label: "Move worktree"}
+	if p.renameBranch {
+		phase.Steps = append(phase.Steps, progress.PlannedStep{ID: "rename-branch", Label: "Rename branch to " + opts.Branch})
+	}
+	if p.moveDir {
+		phase.Steps = append(phase.Steps, progress.PlannedStep{ID: "move", Label: "Move to " + p.result.To})
+		// The state records worktrees by path; a branch rename alone
+		// leaves them correct.
+		if st, err := state.Load(commonDir); err == nil && st.Records(wt.Path) {
+			p.updateState = true
+			phase.Steps = append(phase.Steps, progress.PlannedStep{ID: "state", Label: "Update sentei state"})
+		}
+	}
+	p.plan.Phases = append(p.plan.Phases, phase)
+
+	if p.moveDir && len(opts.Integrations) > 0 {
+		apply, err := integration.PrepareResetup(opts.RepoPath, opts.Integrations, wt.Path, p.result.To)
+		if err != nil {
+			return PreparedMove{}, err
+		}
+		if apply, err = apply.BindPhase(MoveIntegrationsPhaseID, "Integrations"); err != nil {
+			return PreparedMove{}, err
+		}
+		if !apply.Empty() {
+			p.integrations = apply
+			p.plan.Phases = append(p.plan.Phases, apply.Plan().Phases...)
+		}
+	}
+
+	// A branch renamed for a directory git then refuses to move is named
+	// back, so a failed move leaves the worktree as it found it.
+	if p.renameBranch && p.moveDir {
+		p.plan.Phases = append(p.plan.Phases, progress.PlannedPhase{
+			ID:    MoveRollbackPhaseID,
+			Label: "Rollback",
+			Steps: []progress.PlannedStep{{ID: "restore-branch", Label: "Rename branch back to " + p.result.OldBranch}},
+		})
+	}
+	return p, nil
+}
+
+// Run executes the move. A failed step skips the ones after it, and the
+// integrations are only set up again once the worktree is in place. When
+// the directory move fails after the branch was renamed, the rename is
+// rolled back.
+func (p PreparedMove) Run(runner git.CommandRunner, shell git.ShellRunner, emit func(progress.Event)) MoveResult {
+	result := p.result
+	execution, err := progress.Start(p.plan, emit)
+	if err != nil {
+		result.Err = fmt.Errorf("starting move: %w", err)
+		return result
+	}
+
+	type moveStep struct {
+		id    progress.StepID
+		label string
+		run   func() (string, error)
+	}
+	var steps []moveStep
+	if p.renameBranch {
+		steps = append(steps, moveStep{"rename-branch", "Rename branch", func() (string, error) {
+			_, err := runner.Run(p.commonDir, "branch", "-m", result.OldBranch, result.NewBranch)
+			return result.OldBranch + " → " + result.NewBranch, err
+		}})
+	}
+	if p.moveDir {
+		steps = append(steps, moveStep{"move", "Move worktree", func() (string, error) {
+			_, err := runner.Run(p.commonDir, "worktree", "move", result.From, result.To)
+			return "", err
+		}})
+	}
+	if p.updateState {
+		steps = append(steps, moveStep{"state", "Update sentei state", func() (string, error) {
+			st, err := state.Load(p.commonDir)
+			if err != nil {
+				return "", err
+			}
+			st.MoveWorktree(result.From, result.To)
+			return "", state.Save(p.commonDir, st)
+		}})
+	}
+
+	failedBy := ""
+	renamed, moveFailed := false, false
+	for _, step := range steps {
+		if failedBy != "" {
+			_, err = execution.Skip(MovePhaseID, step.id, "blocked by "+failedBy)
+		} else {
+			var stepResult progress.StepResult
+			stepResult, err = execution.Run(MovePhaseID, step.id, step.run)
+			if err == nil && stepResult.Status == progress.StepFailed {
+				failedBy = step.label
+				moveFailed = step.id == "move"
+			}
+			if err == nil && step.id == "rename-branch" && stepResult.Status != progress.StepFailed {
+				renamed = true
+			}
+		}
+		if err != nil {
+			result.Err = errors.Join(result.Err, fmt.Errorf("executing %s: %w", step.label, err))
+			failedBy = step.label
+		}
+	}
+
+	if !p.integrations.Empty() {
+		if failedBy != "" {
+			err = execution.SkipPending(MoveIntegrationsPhaseID, "blocked by "+failedBy)
+		} else {
+			err = p.integrations.RunIn(execution, shell)
+		}
+		result.Err = errors.Join(result.Err, err)
+	}
+
+	if p.renameBranch && p.moveDir {
+		if renamed && moveFailed {
+			_, err = execution.Run(MoveRollbackPhaseID, "restore-branch", func() (string, error) {
+				_, err := runner.Run(p.commonDir, "branch", "-m", result.NewBranch, result.OldBranch)
+				return result.NewBranch + " → " + result.OldBranch, err
+			})
+		} else {
+			_, err = execution.Skip(MoveRollbackPhaseID, "restore-branch", "rollback not required")
+		}
+		if err != nil {
+			result.Err = errors.Join(result.Err, fmt.Errorf("executing rollback: %w", err))
+		}
+	}
+
+	result.Err = errors.Join(result.Err, execution.Finish("move finished"))
+	result.Phases = execution.Phases()
+	return result
+}
+
+// Move prepares and runs a move.
+func Move(runner git.CommandRunner, shell git.ShellRunner, opts MoveOptions, emit func(progress.Event)) MoveResult {
+	prepared, err := PrepareMove(runner, opts)
+	if err != nil {
+		return MoveResult{From: opts.Worktree.Path, Err: err}
+	}
+	return prepared.Run(runner, shell, emit)
+}
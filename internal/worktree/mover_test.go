@@ -0,0 +1,280 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abiswas97/sentei/internal/git"
+	"github.com/abiswas97/sentei/internal/integration"
+	"github.com/abiswas97/sentei/internal/progress"
+	"github.com/abiswas97/sentei/internal/state"
+)
+
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// moveLayout builds a bare repo at root/.bare with a worktree per branch
+// under root, and returns root and the listed worktrees by branch.
+func moveLayout(t *testing.T, branches ...string) (string, map[string]git.Worktree) {
+	t.Helper()
+	root := t.TempDir()
+	bare := filepath.Join(root, ".bare")
+	gitIn(t, root, "init", "--bare", "--initial-branch=main", bare)
+	head := gitIn(t, bare, "-c", "user.name=t", "-c", "user.email=t@t", "commit-tree", "-m", "init", "4b825dc642cb6eb9a060e54bf8d69288fbee4904")
+	gitIn(t, bare, "update-ref", "refs/heads/main", head)
+	for _, branch := range branches {
+		args := []string{"worktree", "add", filepath.Join(root, git.WorktreeDirName(branch))}
+		if branch == "main" {
+			args = append(args, "main")
+		} else {
+			args = append(args, "-b", branch, "main")
+		}
+		gitIn(t, bare, args...)
+	}
+	wts, err := git.ListWorktrees(&git.GitRunner{}, bare)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byBranch := make(map[string]git.Worktree)
+	for _, wt := range wts {
+		byBranch[strings.TrimPrefix(wt.Branch, "refs/heads/")] = wt
+	}
+	return root, byBranch
+}
+
+func TestMove_RenamesBranchAndFollowsDirectory(t *testing.T) {
+	root, wts := moveLayout(t, "main", "feature/old")
+	bare := filepath.Join(root, ".bare")
+	from := wts["feature/old"].Path
+	used := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	st := &state.State{}
+	st.MarkUsed(from, used)
+	if err := state.Save(bare, st); err != nil {
+		t.Fatal(err)
+	}
+
+	result := Move(&git.GitRunner{}, nil, MoveOptions{Worktree: wts["feature/old"], Branch: "feature/new"}, func(progress.Event) {})
+	if result.HasFailures() {
+		t.Fatalf("move failed: %v %+v", result.Err, result.Phases)
+	}
+	want := filepath.Join(root, "feature-new")
+	if result.To != want || result.NewBranch != "feature/new" {
+		t.Fatalf("result = %+v", result)
+	}
+	if got := gitIn(t, want, "branch", "--show-current"); got != "feature/new" {
+		t.Fatalf("branch at new path = %q", got)
+	}
+	if _, err := os.Stat(from); !os.IsNotExist(err) {
+		t.Fatalf("old directory still there: %v", err)
+	}
+	got, err := state.Load(bare)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.RecentWorktrees[want].Equal(used) || got.Records(from) {
+		t.Fatalf("state not moved: %+v", got)
+	}
+}
+
+func TestMove_PathKeepsBranch(t *testing.T) {
+	root, wts := moveLayout(t, "main", "feature")
+	to := filepath.Join(root, "elsewhere")
+
+	result := Move(&git.GitRunner{}, nil, MoveOptions{Worktree: wts["feature"], Path: to}, func(progress.Event) {})
+	if result.HasFailures() {
+		t.Fatalf("move failed: %v %+v", result.Err, result.Phases)
+	}
+	if got := gitIn(t, to, "branch", "--show-current"); got != "feature" {
+		t.Fatalf("branch = %q", got)
+	}
+	for _, step := range result.Phases[0].Steps {
+		if step.ID == "rename-branch" || step.ID == "state" {
+			t.Errorf("unexpected step %s with nothing to do", step.ID)
+		}
+	}
+}
+
+func TestPrepareMove_RejectsBeforeAnyChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    func(root string, wts map[string]git.Worktree) MoveOptions
+		wantErr string
+	}{
+		{
+			name: "branch exists",
+			opts: func(_ string, wts map[string]git.Worktree) MoveOptions {
+				return MoveOptions{Worktree: wts["feature"], Branch: "main"}
+			},
+			wantErr: `branch "main" already exists`,
+		},
+		{
+			name: "invalid branch",
+			opts: func(_ string, wts map[string]git.Worktree) MoveOptions {
+				return MoveOptions{Worktree: wts["feature"], Branch: "bad..name"}
+			},
+			wantErr: "invalid branch name",
+		},
+		{
+			name: "destination exists",
+			opts: func(root string, wts map[string]git.Worktree) MoveOptions {
+				return MoveOptions{Worktree: wts["feature"], Path: filepath.Join(root, "main")}
+			},
+			wantErr: "already exists",
+		},
+		{
+			name: "locked",
+			opts: func(_ string, wts map[string]git.Worktree) MoveOptions {
+				wt := wts["feature"]
+				wt.IsLocked = true
+				return MoveOptions{Worktree: wt, Branch: "renamed"}
+			},
+			wantErr: "unlock it first",
+		},
+		{
+			name: "detached rename",
+			opts: func(_ string, wts map[string]git.Worktree) MoveOptions {
+				wt := wts["feature"]
+				wt.Branch = ""
+				return MoveOptions{Worktree: wt, Branch: "renamed"}
+			},
+			wantErr: "no branch to rename",
+		},
+		{
+			name: "same place",
+			opts: func(_ string, wts map[string]git.Worktree) MoveOptions {
+				return MoveOptions{Worktree: wts["feature"], Branch: "feature"}
+			},
+			wantErr: "already there",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, wts := moveLayout(t, "main", "feature")
+			_, err := PrepareMove(&git.GitRunner{}, tt.opts(root, wts))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+			if got := gitIn(t, wts["feature"].Path, "branch", "--show-current"); got != "feature" {
+				t.Fatalf("worktree changed: branch %q", got)
+			}
+		})
+	}
+}
+
+type recordingShell struct{ commands []string }
+
+func (s *recordingShell) RunShell(dir, command string) (string, error) {
+	s.commands = append(s.commands, dir+": "+command)
+	return "", nil
+}
+
+func TestMove_SetsUpPathIntegrationsAgain(t *testing.T) {
+	root, wts := moveLayout(t, "main", "feature")
+	from := wts["feature"].Path
+	os.MkdirAll(filepath.Join(from, ".graph"), 0755)
+	os.MkdirAll(filepath.Join(from, ".index"), 0755)
+	integrations := []integration.Integration{
+		{Name: "graph", Setup: integration.SetupSpec{Command: "graph build {path}", WorkingDir: "repo"}, GitignoreEntries: []string{".graph/"}},
+		{Name: "index", Setup: integration.SetupSpec{Command: "index init", WorkingDir: "worktree"}, GitignoreEntries: []string{".index/"}},
+		{Name: "absent", Setup: integration.SetupSpec{Command: "absent {path}"}, GitignoreEntries: []string{".absent/"}},
+	}
+
+	shell := &recordingShell{}
+	result := Move(&git.GitRunner{}, shell, MoveOptions{Worktree: wts["feature"], Branch: "renamed", RepoPath: root, Integrations: integrations}, func(progress.Event) {})
+	if result.HasFailures() {
+		t.Fatalf("move failed: %v %+v", result.Err, result.Phases)
+	}
+	want := root + ": graph build " + git.ShellQuote(filepath.Join(root, "renamed"))
+	if len(shell.commands) != 1 || shell.commands[0] != want {
+		t.Fatalf("shell commands = %v, want [%s]", shell.commands, want)
+	}
+}
+
+func TestPreparedMove_FailureBlocksLaterSteps(t *testing.T) {
+	root, wts := moveLayout(t, "main", "feature")
+	from := wts["feature"].Path
+	os.MkdirAll(filepath.Join(from, ".graph"), 0755)
+	prepared, err := PrepareMove(&git.GitRunner{}, MoveOptions{
+		Worktree: wts["feature"], Path: filepath.Join(root, "moved"), RepoPath: root,
+		Integrations: []integration.Integration{{Name: "graph", Setup: integration.SetupSpec{Command: "graph {path}"}, GitignoreEntries: []string{".graph/"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A file appears at the destination after planning, so git refuses
+	// the move.
+	os.WriteFile(filepath.Join(root, "moved"), []byte("x"), 0644)
+
+	shell := &recordingShell{}
+	result := prepared.Run(&git.GitRunner{}, shell, func(progress.Event) {})
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	if !progress.PhasesHaveFailures(result.Phases) || len(shell.commands) != 0 {
+		t.Fatalf("phases = %+v, shell = %v", result.Phases, shell.commands)
+	}
+	setup := result.Phases[1].Steps[0]
+	if setup.Status != progress.StepSkipped || !strings.Contains(setup.Message, "blocked by") {
+		t.Fatalf("setup step = %+v", setup)
+	}
+}
+
+func TestPreparedMove_FailedMoveRenamesBranchBack(t *testing.T) {
+	root, wts := moveLayout(t, "main", "feature/old")
+	bare := filepath.Join(root, ".bare")
+	prepared, err := PrepareMove(&git.GitRunner{}, MoveOptions{Worktree: wts["feature/old"], Branch: "feature/new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(root, "feature-new"), []byte("x"), 0644)
+
+	result := prepared.Run(&git.GitRunner{}, nil, func(progress.Event) {})
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	rollback := result.Phases[len(result.Phases)-1]
+	if rollback.ID != MoveRollbackPhaseID || rollback.Steps[0].Status != progress.StepDone {
+		t.Fatalf("rollback phase = %+v", rollback)
+	}
+	if got := gitIn(t, wts["feature/old"].Path, "branch", "--show-current"); got != "feature/old" {
+		t.Fatalf("branch = %q, want feature/old restored", got)
+	}
+	if out, err := exec.Command("git", "-C", bare, "show-ref", "--verify", "refs/heads/feature/new").CombinedOutput(); err == nil {
+		t.Fatalf("feature/new still exists: %s", out)
+	}
+}
+
+func TestMove_SuccessSkipsRollback(t *testing.T) {
+	_, wts := moveLayout(t, "main", "feature/old")
+	result := Move(&git.GitRunner{}, nil, MoveOptions{Worktree: wts["feature/old"], Branch: "feature/new"}, func(progress.Event) {})
+	if result.HasFailures() {
+		t.Fatalf("move failed: %v %+v", result.Err, result.Phases)
+	}
+	if step := result.Phases[len(result.Phases)-1].Steps[0]; step.Status != progress.StepSkipped {
+		t.Fatalf("rollback step = %+v, want skipped", step)
+	}
+}
+
+func TestSplitMoveTarget(t *testing.T) {
+	tests := []struct{ target, branch, path string }{
+		{"feature/x", "feature/x", ""},
+		{"./x", "", "./x"},
+		{"../x", "", "../x"},
+		{"/abs/x", "", "/abs/x"},
+	}
+	for _, tt := range tests {
+		if branch, path := SplitMoveTarget(tt.target); branch != tt.branch || path != tt.path {
+			t.Errorf("SplitMoveTarget(%q) = %q, %q; want %q, %q", tt.target, branch, path, tt.branch, tt.path)
+		}
+	}
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"bufio"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
@@ -19,7 +21,9 @@ import (
 	"github.com/abiswas97/sentei/internal/git"
 	"github.com/abiswas97/sentei/internal/integration"
 	"github.com/abiswas97/sentei/internal/playground"
+	"github.com/abiswas97/sentei/internal/pr"
 	"github.com/abiswas97/sentei/internal/repo"
+	"github.com/abiswas97/sentei/internal/report"
 	"github.com/abiswas97/sentei/internal/tui"
 	"github.com/abiswas97/sentei/internal/worktree"
 )
@@ -34,34 +38,98 @@ func buildRegistry() *cli.Registry {
 	r := cli.NewRegistry()
 
 	r.Register(&cli.Command{
-		Name: "ecosystems",
-		Type: cli.Output,
-		RunCLI: func(args []string) error {
-			cmd.RunEcosystems(args)
-			return nil
-		},
+		Name:   "ecosystems",
+		Type:   cli.Output,
+		Flags:  cmd.EcosystemsFlags(),
+		RunCLI: cmd.RunEcosystems,
 	})
 
 	r.Register(&cli.Command{
-		Name: "integrations",
-		Type: cli.Output,
-		RunCLI: func(args []string) error {
-			cmd.RunIntegrations()
-			return nil
-		},
+		Name:   "list",
+		Type:   cli.Output,
+		Flags:  cmd.ListFlags(),
+		RunCLI: cmd.RunList,
+	})
+
+	r.Register(&cli.Command{
+		Name:    "restore",
+		Type:    cli.Decision,
+		Flags:   cmd.RestoreFlags(),
+		RunCLI:  cmd.RunRestore,
+		Confirm: cmd.RestorePrompt,
+	})
+
+	r.Register(&cli.Command{
+		Name:        "undo",
+		Type:        cli.Decision,
+		Destructive: true,
+		Flags:       cmd.UndoFlags(),
+		RunCLI:      cmd.RunUndo,
+		Confirm:     cmd.UndoPrompt,
+	})
+
+	r.Register(&cli.Command{
+		Name:    "integrations",
+		Type:    cli.Decision,
+		Flags:   cmd.IntegrationsFlags(),
+		Args:    cmd.IntegrationsActions,
+		RunCLI:  cmd.RunIntegrations,
+		Confirm: cmd.IntegrationsPrompt,
+	})
+
+	r.Register(&cli.Command{
+		Name:   "config",
+		Type:   cli.Output,
+		Flags:  cmd.ConfigFlags(),
+		Args:   cmd.ConfigActions,
+		RunCLI: cmd.RunConfig,
+	})
+
+	r.Register(&cli.Command{
+		Name:    "move",
+		Type:    cli.Decision,
+		Flags:   cmd.MoveFlags(),
+		RunCLI:  cmd.RunMove,
+		Confirm: cmd.MovePrompt,
+	})
+
+	r.Register(&cli.Command{
+		Name:    "lock",
+		Type:    cli.Decision,
+		Flags:   cmd.LockFlags(),
+		RunCLI:  cmd.RunLock,
+		Confirm: cmd.LockPrompt,
+	})
+
+	r.Register(&cli.Command{
+		Name:    "unlock",
+		Type:    cli.Decision,
+		Flags:   cmd.UnlockFlags(),
+		RunCLI:  cmd.RunUnlock,
+		Confirm: cmd.UnlockPrompt,
+	})
+
+	r.Register(&cli.Command{
+		Name:    "doctor",
+		Type:    cli.Decision,
+		Flags:   cmd.DoctorFlags(),
+		RunCLI:  cmd.RunDoctor,
+		Confirm: cmd.DoctorPrompt,
 	})
 
 	r.Register(&cli.Command{
-		Name: "clone",
-		Type: cli.Decision,
+		Name:  "clone",
+		Type:  cli.Decision,
+		Flags: cmd.CloneFlags(),
 		RunCLI: func(args []string) error {
 			return cmd.RunClone(args)
 		},
 	})
 
 	r.Register(&cli.Command{
-		Name: "create",
-		Type: cli.Decision,
+		Name:  "create",
+		Type:  cli.Decision,
+		Flags: cmd.CreateFlags(),
 		RunCLI: func(args []string) error {
 			return cmd.RunCreate(args)
 		},
@@ -71,6 +139,7 @@ func buildRegistry() *cli.Registry {
 		Name:        "cleanup",
 		Type:        cli.Decision,
 		Destructive: true,
+		Flags:       cmd.CleanupFlags(),
 		RunCLI: func(args []string) error {
 			return cmd.RunCleanup(args)
 		},
@@ -80,23 +149,79 @@ func buildRegistry() *cli.Registry {
 		Name:        "migrate",
 		Type:        cli.Decision,
 		Destructive: true,
+		Flags:       cmd.MigrateFlags(),
 		RunCLI: func(args []string) error {
 			return cmd.RunMigrate(args)
 		},
 	})
 
+	r.Register(&cli.Command{
+		Name:        "unmigrate",
+		Type:        cli.Decision,
+		Destructive: true,
+		Flags:       cmd.UnmigrateFlags(),
+		RunCLI: func(args []string) error {
+			return cmd.RunUnmigrate(args)
+		},
+	})
+
 	r.Register(&cli.Command{
 		Name:        "remove",
 		Type:        cli.Decision,
 		Destructive: true,
+		Flags:       cmd.RemoveFlags(),
 		RunCLI: func(args []string) error {
 			return cmd.RunRemove(args)
 		},
 	})
 
+	r.Register(&cli.Command{
+		Name:  "switch",
+		Type:  cli.Output,
+		Flags: cmd.SwitchFlags(),
+		RunCLI: func(args []string) error {
+			return cmd.RunSwitch(args, switchPicker())
+		},
+	})
+
+	r.Register(&cli.Command{
+		Name:   "shell-init",
+		Type:   cli.Output,
+		Args:   cli.Shells,
+		RunCLI: cmd.RunShellInit,
+	})
+
+	r.Register(&cli.Command{
+		Name: "completion",
+		Type: cli.Output,
+		Args: cli.Shells,
+		RunCLI: func(args []string) error {
+			return cmd.RunCompletion(r, args)
+		},
+	})
+
+	r.SetRootFlags(cli.FlagsOf(newRootFlags().fs))
+
 	return r
 }
 
+// switchPicker returns the TUI picker for an ambiguous switch, or nil when
+// stdin or stderr is not a terminal and there is no one to ask. stdout is
+// not checked: the shell wrapper captures it for the chosen path.
+func switchPicker() cmd.SwitchPicker {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stderr) {
+		return nil
+	}
+	return func(worktrees []git.Worktree, query string, recent map[string]time.Time) (string, error) {
+		return tui.RunSwitchPicker(worktrees, query, recent, os.Stderr)
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
 // runCommand runs a CLI command, exiting 1 on a real error but treating a
 // -h/--help request (flag.ErrHelp) as success — the flag package has already
 // printed usage, so a "help requested" line and non-zero exit are wrong.
@@ -159,6 +284,10 @@ func main() {
 				runCommand(result.Command.RunCLI, args)
 				return
 			}
+			if result.Command.Confirm != nil {
+				confirmAndRun(result.Command, result.Args)
+				return
+			}
 			// Interactive mode: parse flags and launch TUI at the appropriate view.
 			launchInteractiveDecision(*result)
 			return
@@ -169,6 +298,26 @@ func main() {
 	runRoot(result.Args)
 }
 
+// confirmAndRun asks on the terminal before running a decision command
+// that has no TUI view. With no terminal to ask it refuses: --yes or
+// --non-interactive say the change was meant.
+func confirmAndRun(command *cli.Command, args []string) {
+	prompt, needed, err := command.Confirm(args)
+	exitOnFlagError(err)
+	if needed {
+		if !isTerminal(os.Stdin) {
+			exitOnFlagError(fmt.Errorf("%s changes the repository and there is no terminal to confirm on: add --yes", command.Name))
+		}
+		fmt.Fprintf(os.Stderr, "%s\nContinue? [y/N] ", prompt)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			fmt.Fprintln(os.Stderr, "Cancelled.")
+			return
+		}
+	}
+	runCommand(command.RunCLI, args)
+}
+
 func launchInteractiveDecision(result cli.DispatchResult) {
 	repoPath := "."
 	if absPath, err := filepath.Abs(repoPath); err == nil {
@@ -195,7 +344,7 @@ func launchInteractiveDecision(result cli.DispatchResult) {
 		}
 	}
 
-	model := tui.NewMenuModel(runner, shell, repoPath, cfg, context)
+	model := tui.NewMenuModel(runner, shell, repoPath, cfg, context, tui.WithGhRunner(&repo.DefaultGhRunner{}))
 
 	switch result.Command.Name {
 	case "cleanup":
@@ -212,13 +361,28 @@ func launchInteractiveDecision(result cli.DispatchResult) {
 	case "create":
 		opts, err := cmd.ParseCreateFlags(result.Args)
 		exitOnFlagError(err)
+		if opts.FromFile != "" {
+			exitOnFlagError(errors.New("create --from-file runs without the TUI: add --yes"))
+		}
+		if opts.Profile != "" {
+			if cfg == nil {
+				exitOnFlagError(fmt.Errorf("create --profile %s: the repository config could not be loaded", opts.Profile))
+			}
+			profile, err := cfg.Profile(opts.Profile)
+			exitOnFlagError(err)
+			opts = opts.WithProfile(profile)
+		}
 		model.SetCreateOpts(&tui.CreateOpts{
-			Branch:     opts.Branch,
-			Base:       opts.Base,
-			Ecosystems: opts.Ecosystems,
-			MergeBase:  opts.MergeBase,
-			CopyEnv:    opts.CopyEnv,
-			RepoPath:   opts.RepoPath,
+			Branch:       opts.Branch,
+			Base:         opts.Base,
+			Track:        opts.Track,
+			PR:           opts.PR,
+			Ecosystems:   opts.Ecosystems,
+			MergeBase:    opts.MergeBase,
+			CopyEnv:      opts.CopyEnv,
+			Profile:      opts.Profile,
+			Integrations: opts.Integrations,
+			RepoPath:     opts.RepoPath,
 		})
 
 	case "clone":
@@ -232,7 +396,7 @@ func launchInteractiveDecision(result cli.DispatchResult) {
 	case "remove":
 		opts, err := cmd.ParseRemoveFlags(result.Args)
 		exitOnFlagError(err)
-		if opts.Merged || opts.All || opts.Stale > 0 {
+		if opts.HasFilter() {
 			worktrees, err := git.ListWorktrees(runner, repoPath)
 			if err != nil {
 				log.Error(err)
@@ -241,11 +405,24 @@ func launchInteractiveDecision(result cli.DispatchResult) {
 			worktrees = worktree.EnrichWorktrees(runner, worktrees, worktree.DefaultEnrichConcurrency)
 
 			defaultBranch := git.DetectDefaultBranch(runner, repoPath)
-			var isMerged cmd.MergedChecker
+			var isMerged, prMerged cmd.MergedChecker
 			if opts.Merged {
 				isMerged = cmd.CheckMerged(runner, repoPath, defaultBranch)
 			}
-			filtered := cmd.ResolveFilters(worktrees, opts, nil, defaultBranch, isMerged)
+			var prChecker *pr.Checker
+			if opts.PRMerged {
+				prChecker = pr.NewChecker(&repo.DefaultGhRunner{}, repoPath)
+				prMerged = cmd.CheckPRMerged(prChecker, defaultBranch)
+			}
+			var patterns []string
+			if cfg != nil {
+				patterns = cfg.ProtectedBranches
+			}
+			protection := git.NewProtectionPolicy(defaultBranch, patterns)
+			filtered := cmd.ResolveFilters(worktrees, opts, protection, isMerged, prMerged)
+			if prChecker != nil && prChecker.Err() != nil {
+				log.Warn("--pr-merged matched nothing", "err", prChecker.Err())
+			}
 
 			var paths []string
 			for _, wt := range filtered {
@@ -265,6 +442,15 @@ func launchInteractiveDecision(result cli.DispatchResult) {
 			DeleteBackup: opts.DeleteBackup,
 			RepoPath:     opts.RepoPath,
 		})
+
+	case "unmigrate":
+		opts, err := cmd.ParseUnmigrateFlags(result.Args)
+		exitOnFlagError(err)
+		model.SetUnmigrateOpts(&tui.UnmigrateOpts{
+			Worktree:     opts.Worktree,
+			DeleteBackup: opts.DeleteBackup,
+			RepoPath:     opts.RepoPath,
+		})
 	}
 
 	p := tea.NewProgram(model)
@@ -280,16 +466,42 @@ func launchInteractiveDecision(result cli.DispatchResult) {
 	}
 }
 
-func runRoot(args []string) {
+// rootFlags is the flag set for sentei without a command, shared by runRoot
+// and the completion metadata.
+type rootFlags struct {
+	fs         *flag.FlagSet
+	version    *bool
+	playground *bool
+	dryRun     *bool
+	format     *string
+	noCache    *bool
+}
+
+func newRootFlags() *rootFlags {
 	fs := flag.NewFlagSet("sentei", flag.ExitOnError)
-	versionFlag := fs.Bool("version", false, "Print version and exit")
-	playgroundFlag := fs.Bool("playground", false, "Launch with a temporary test repo")
-	dryRunFlag := fs.Bool("dry-run", false, "Print worktree summary and exit (no interactive TUI)")
+	return &rootFlags{
+		fs:         fs,
+		version:    fs.Bool("version", false, "Print version and exit"),
+		playground: fs.Bool("playground", false, "Launch with a temporary test repo"),
+		dryRun:     fs.Bool("dry-run", false, "Print worktree summary and exit (no interactive TUI)"),
+		format:     fs.String("format", string(report.FormatText), "Output format for --dry-run: text, json or ndjson"),
+		noCache:    fs.Bool("no-cache", false, "Check every worktree's status instead of reusing cached results"),
+	}
+}
+
+func runRoot(args []string) {
+	rf := newRootFlags()
+	fs := rf.fs
 	if err := fs.Parse(args); err != nil {
 		os.Exit(1)
 	}
+	format, err := report.ParseFormat(*rf.format)
+	exitOnFlagError(err)
+	if format.Machine() && !*rf.dryRun {
+		exitOnFlagError(errors.New("--format json/ndjson requires --dry-run"))
+	}
 
-	if *versionFlag {
+	if *rf.version {
 		fmt.Printf("sentei %s (%s, %s)\n", version, commit, date)
 		os.Exit(0)
 	}
@@ -302,7 +514,7 @@ func runRoot(args []string) {
 		repoPath = absPath
 	}
 
-	if *playgroundFlag {
+	if *rf.playground {
 		var cleanup func()
 		var err error
 		repoPath, cleanup, err = playground.Setup()
@@ -322,7 +534,7 @@ func runRoot(args []string) {
 		repoPath = repo.ResolveBareRoot(runner, repoPath)
 	}
 
-	if *dryRunFlag {
+	if *rf.dryRun {
 		if context != repo.ContextBareRepo {
 			log.Error("--dry-run requires a bare repository")
 			os.Exit(1)
@@ -343,12 +555,22 @@ func runRoot(args []string) {
 			}
 		}
 
+		protection := cmd.LoadProtectionPolicy(runner, repoPath, git.DetectDefaultBranch(runner, repoPath))
+		if format.Machine() {
+			out := report.NewWriter(os.Stdout, format)
+			if err := out.Write(report.KindWorktrees, report.NewWorktrees(filtered, protection)); err != nil {
+				log.Error(err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
 		if len(filtered) == 0 {
 			fmt.Println("No worktrees found (only the main working tree exists).")
 			os.Exit(0)
 		}
 
-		if err := dryrun.Print(filtered, os.Stdout); err != nil {
+		if err := dryrun.Print(filtered, protection, os.Stdout); err != nil {
 			log.Error(err)
 			os.Exit(1)
 		}
@@ -367,10 +589,13 @@ func runRoot(args []string) {
 		}
 	}
 
-	var menuOpts []tui.ModelOption
-	if *playgroundFlag {
+	menuOpts := []tui.ModelOption{tui.WithGhRunner(&repo.DefaultGhRunner{})}
+	if *rf.playground {
 		menuOpts = append(menuOpts, tui.WithMinProgressDuration(1500*time.Millisecond))
 	}
+	if *rf.noCache {
+		menuOpts = append(menuOpts, tui.WithoutEnrichCache())
+	}
 	model := tui.NewMenuModel(runner, shell, repoPath, cfg, context, menuOpts...)
 	p := tea.NewProgram(model)
 